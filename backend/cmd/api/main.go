@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,13 +12,31 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/cache"
 	"github.com/paulsena/asheville-setlist/internal/config"
 	"github.com/paulsena/asheville-setlist/internal/db"
 	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+	"github.com/paulsena/asheville-setlist/internal/mail"
+	"github.com/paulsena/asheville-setlist/internal/maintenance"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
 	"github.com/paulsena/asheville-setlist/internal/middleware"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/stream"
+	"github.com/paulsena/asheville-setlist/internal/tracing"
+	"github.com/paulsena/asheville-setlist/internal/version"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+	"github.com/paulsena/asheville-setlist/migrations"
+	"go.opentelemetry.io/otel"
 )
 
 func main() {
+	// `api migrate ...` manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	ctx := context.Background()
 
 	// Load configuration
@@ -26,6 +45,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Log as JSON in production, text elsewhere, at the configured level
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogLevel, cfg.Environment))
+
+	// Export traces (a no-op unless TRACING_EXPORTER is set); set up before
+	// the pool so its query tracer uses the configured provider
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingConfig(handlers.ServiceName, version.Version))
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
 	// Initialize database connection pool
 	pool, err := config.NewDatabasePool(ctx, cfg)
 	if err != nil {
@@ -33,11 +62,111 @@ func main() {
 	}
 	defer pool.Close()
 
+	// Compare the schema with the migrations this build embeds
+	if cfg.SchemaCheck != migrations.CheckOff {
+		if err := migrations.Check(ctx, pool); err != nil {
+			if cfg.SchemaCheck == migrations.CheckRequire {
+				log.Fatalf("Database schema is not current (run `api migrate up`): %v", err)
+			}
+			slog.Warn("database schema is not current; run `api migrate up`", "error", err)
+		}
+	}
+
 	// Create database queries
 	queries := db.New(pool)
 
+	// Register request and connection pool metrics for /metrics
+	registry := metrics.NewRegistry()
+	httpMetrics := metrics.NewHTTP(registry)
+	registry.MustRegister(metrics.NewPoolCollector(pool))
+
+	// Create mailer for sign-in links and email notifications
+	mailer, err := mail.New(cfg.MailConfig())
+	if err != nil {
+		log.Fatalf("Failed to create mailer: %v", err)
+	}
+
+	// Create notification channels (Web Push only when VAPID keys are set)
+	channels, err := notify.NewChannels(mailer, cfg.VAPIDConfig())
+	if err != nil {
+		log.Fatalf("Failed to create notification channels: %v", err)
+	}
+	var vapidPublicKey string
+	if push, ok := channels[notify.ChannelWebPush].(*notify.WebPushChannel); ok {
+		vapidPublicKey = push.PublicKey()
+	}
+
+	// Start the live show stream listener (stopped when the server shuts down
+	// so open streams end)
+	streamCtx, stopStream := context.WithCancel(ctx)
+	defer stopStream()
+	hub := stream.NewHub(pool)
+	go hub.Run(streamCtx)
+
 	// Create handlers
-	h := handlers.New(queries)
+	h := handlers.New(queries,
+		handlers.WithSimilarityWeights(cfg.SimilarityWeights),
+		handlers.WithMailer(mailer),
+		handlers.WithAppURL(cfg.AppURL),
+		handlers.WithVAPIDPublicKey(vapidPublicKey),
+		handlers.WithShowStream(hub, cfg.StreamHeartbeat),
+		handlers.WithPool(pool),
+		handlers.WithReadiness(pool, cfg.ScraperStaleAfter),
+	)
+
+	// Start background maintenance (marks past shows completed)
+	maintenanceCtx, stopMaintenance := context.WithCancel(ctx)
+	defer stopMaintenance()
+	if cfg.MaintenanceInterval > 0 {
+		log.Printf("Starting maintenance ticker (interval: %s)", cfg.MaintenanceInterval)
+		go maintenance.Run(maintenanceCtx, queries, cfg.MaintenanceInterval)
+	}
+
+	// Start background notification delivery
+	if cfg.NotifyInterval > 0 {
+		log.Printf("Starting notification dispatcher (interval: %s)", cfg.NotifyInterval)
+		dispatcher := notify.NewDispatcher(queries, cfg.AppURL, channels)
+		go dispatcher.Run(maintenanceCtx, cfg.NotifyInterval)
+	}
+
+	// Start background webhook delivery
+	if cfg.WebhookInterval > 0 {
+		log.Printf("Starting webhook deliverer (interval: %s)", cfg.WebhookInterval)
+		go webhook.NewDeliverer(queries, nil).Run(maintenanceCtx, cfg.WebhookInterval)
+	}
+
+	// Cache show, venue and genre responses, invalidated by tag when the data
+	// they read is written
+	cached := func(tags ...string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if cfg.CacheTTL > 0 {
+		log.Printf("Caching responses (ttl: %s, size: %d)", cfg.CacheTTL, cfg.CacheSize)
+		store := cache.NewLRU(cfg.CacheSize)
+		go cache.Listen(maintenanceCtx, pool, store)
+		cached = func(tags ...string) gin.HandlerFunc {
+			return middleware.Cache(store, cfg.CacheTTL, tags...)
+		}
+	}
+
+	// Conditional GET (ETag, Last-Modified, 304) with per-route Cache-Control
+	conditional := middleware.Conditional(handlers.CachePolicyDefault)
+	conditionalShort := middleware.Conditional(handlers.CachePolicyShort)
+	conditionalLong := middleware.Conditional(handlers.CachePolicyLong)
+
+	// Rate limit API routes per client IP, with stricter limits on show
+	// submissions and on sign-in links, which send email
+	var limited, submissionLimited, magicLinkLimited []gin.HandlerFunc
+	if cfg.RateLimitPerMinute > 0 {
+		log.Printf("Rate limiting API routes (%d/min per IP)", cfg.RateLimitPerMinute)
+		limited = append(limited, middleware.RateLimit(cfg.RateLimitPerMinute, time.Minute, handlers.RateLimited))
+	}
+	if cfg.SubmissionRateLimitPerHour > 0 {
+		submissionLimited = append(submissionLimited, middleware.RateLimit(cfg.SubmissionRateLimitPerHour, time.Hour, handlers.RateLimited))
+	}
+	if cfg.MagicLinkRateLimitPerHour > 0 {
+		magicLinkLimited = append(magicLinkLimited, middleware.RateLimit(cfg.MagicLinkRateLimitPerHour, time.Hour, handlers.RateLimited))
+	}
 
 	// Set Gin mode from configuration
 	gin.SetMode(cfg.GinMode)
@@ -45,43 +174,118 @@ func main() {
 	// Create Gin router (without default middleware)
 	router := gin.New()
 
+	// Believe X-Forwarded-For only from configured proxies; otherwise the
+	// client IP used for rate limiting is the connection's address
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Apply middleware stack
-	router.Use(middleware.Recovery())   // Recover from panics
-	router.Use(middleware.Logger())     // Log requests
-	router.Use(middleware.CORS())       // Handle CORS
-	router.Use(middleware.BodyLimit(1)) // 1MB body limit
-
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"service": "asheville-setlist-api",
-			"version": "0.1.0",
-		})
-	})
+	router.Use(middleware.Tracing(otel.GetTracerProvider())) // Start a span per request
+	router.Use(middleware.RequestID())                       // Tag requests with X-Request-ID and a logger
+	router.Use(middleware.Metrics(httpMetrics))              // Record request metrics
+	router.Use(middleware.Recovery(httpMetrics))             // Recover from panics
+	router.Use(middleware.Logger())                          // Log requests
+	router.Use(middleware.CORS())                            // Handle CORS
+	router.Use(middleware.BodyLimit(1))                      // 1MB body limit
+
+	// Prometheus metrics (bearer token required when METRICS_TOKEN is set)
+	metricsAuth := func(c *gin.Context) { c.Next() }
+	if cfg.MetricsToken != "" {
+		metricsAuth = middleware.AdminAuth(cfg.MetricsToken)
+	}
+	router.GET("/metrics", metricsAuth, gin.WrapH(metrics.Handler(registry)))
+
+	// Liveness and readiness probes (/health is the old liveness path)
+	router.GET("/healthz", h.Healthz)
+	router.GET("/health", h.Healthz)
+	router.GET("/readyz", h.Readyz)
 
 	// API routes
-	api := router.Group("/api")
+	api := router.Group("/api", limited...)
 	{
 		// Shows
-		api.GET("/shows", h.ListShows)
-		api.GET("/shows/:id", h.GetShow)
-		api.POST("/shows", h.CreateShow)
+		api.GET("/shows", conditional, cached(cache.TagShows, cache.TagBands, cache.TagVenues), h.ListShows)
+		api.GET("/shows/:id", conditional, h.GetShow)
+		api.GET("/shows/:id/history", conditional, h.GetShowHistory)
+		api.POST("/shows", append(submissionLimited, h.CreateShow)...)
+		api.GET("/stream/shows", h.StreamShows)
 
 		// Venues
-		api.GET("/venues", h.ListVenues)
-		api.GET("/venues/:slug", h.GetVenue)
+		api.GET("/venues", conditional, cached(cache.TagVenues, cache.TagShows), h.ListVenues)
+		api.GET("/venues/:slug", conditional, cached(cache.TagVenues, cache.TagShows, cache.TagBands), h.GetVenue)
+		api.GET("/venues/:slug/shows", conditional, cached(cache.TagVenues, cache.TagShows, cache.TagBands), h.GetVenueShows)
 
 		// Bands
-		api.GET("/bands", h.ListBands)
-		api.GET("/bands/:slug", h.GetBand)
-		api.GET("/bands/:slug/similar", h.GetSimilarBands)
+		api.GET("/bands", conditional, h.ListBands)
+		api.GET("/bands/:slug", conditional, h.GetBand)
+		api.GET("/bands/:slug/shows", conditional, h.GetBandShows)
+		api.GET("/bands/:slug/similar", conditional, h.GetSimilarBands)
 
 		// Genres
-		api.GET("/genres", h.ListGenres)
+		api.GET("/genres", conditionalLong, cached(cache.TagShows, cache.TagBands, cache.TagVenues), h.ListGenres)
+		api.GET("/genres/:slug", conditionalLong, cached(cache.TagShows, cache.TagBands, cache.TagVenues), h.GetGenre)
+
+		// Recommendations
+		api.GET("/recommendations/shows", conditional, h.GetShowRecommendations)
 
 		// Search
-		api.GET("/search", h.Search)
+		api.GET("/search", conditionalShort, h.Search)
+		api.GET("/autocomplete", conditionalShort, h.Autocomplete)
+
+		// Sign-in
+		api.POST("/auth/magic-link", append(magicLinkLimited, h.RequestMagicLink)...)
+		api.POST("/auth/verify", h.VerifyLogin)
+		api.POST("/auth/logout", middleware.UserAuth(queries), h.Logout)
+
+		// Notifications
+		api.GET("/notifications/web-push-key", h.GetWebPushKey)
+		api.POST("/notifications/unsubscribe", h.Unsubscribe)
+	}
+
+	// Signed-in user routes
+	me := api.Group("/me", middleware.UserAuth(queries))
+	{
+		me.GET("", h.GetMe)
+		me.GET("/feed", h.GetFeed)
+
+		// Saved shows
+		me.GET("/saved-shows", h.ListSavedShows)
+		me.PUT("/saved-shows/:id", h.SaveShow)
+		me.DELETE("/saved-shows/:id", h.UnsaveShow)
+
+		// Follows
+		me.GET("/follows", h.ListFollows)
+		me.PUT("/follows/bands/:slug", h.FollowBand)
+		me.DELETE("/follows/bands/:slug", h.UnfollowBand)
+		me.PUT("/follows/venues/:slug", h.FollowVenue)
+		me.DELETE("/follows/venues/:slug", h.UnfollowVenue)
+
+		// Notifications
+		me.GET("/notifications", h.ListNotifications)
+		me.POST("/notifications/read", h.MarkNotificationsRead)
+		me.GET("/notification-channels", h.ListNotificationChannels)
+		me.POST("/notification-channels", h.CreateNotificationChannel)
+		me.DELETE("/notification-channels/:id", h.DeleteNotificationChannel)
+	}
+
+	// Admin routes (disabled unless ADMIN_TOKEN is set)
+	if cfg.AdminToken != "" {
+		admin := api.Group("/admin", middleware.AdminAuth(cfg.AdminToken))
+		{
+			// Genre suggestions
+			admin.GET("/genre-suggestions", h.ListGenreSuggestions)
+			admin.POST("/genre-suggestions/:band_id/:genre_id/confirm", h.ConfirmGenreSuggestion)
+			admin.DELETE("/genre-suggestions/:band_id/:genre_id", h.RejectGenreSuggestion)
+
+			// Webhooks
+			admin.GET("/webhooks", h.ListWebhookSubscriptions)
+			admin.POST("/webhooks", h.CreateWebhookSubscription)
+			admin.PATCH("/webhooks/:id", h.UpdateWebhookSubscription)
+			admin.DELETE("/webhooks/:id", h.DeleteWebhookSubscription)
+			admin.GET("/webhook-deliveries", h.ListWebhookDeliveries)
+			admin.POST("/webhook-deliveries/:id/replay", h.ReplayWebhookDelivery)
+		}
 	}
 
 	// Create HTTP server with timeouts
@@ -92,10 +296,11 @@ func main() {
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
+	srv.RegisterOnShutdown(stopStream)
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("Starting server on port %s (mode: %s)", cfg.Port, cfg.GinMode)
+		log.Printf("Starting server %s (%s) on port %s (mode: %s)", version.Version, version.Commit, cfg.Port, cfg.GinMode)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -116,5 +321,10 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Flush spans still queued for export
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+
 	log.Println("Server exited")
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/paulsena/asheville-setlist/internal/config"
+	"github.com/paulsena/asheville-setlist/migrations"
+)
+
+const migrateUsage = `Usage: api migrate <command>
+
+Commands:
+  up         Apply all pending migrations
+  down [n]   Roll back the last n migrations (default 1)
+  status     Show the database's schema version and pending migrations`
+
+// runMigrate applies the migrations embedded in the binary to DATABASE_URL.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		m := newMigrator(cfg)
+		defer m.Close()
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			log.Fatalf("Failed to apply migrations: %v", err)
+		}
+		log.Printf("Migrations complete: schema at version %d", migrations.Latest())
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("down expects a positive number of migrations, got %q", args[1])
+			}
+		}
+		m := newMigrator(cfg)
+		defer m.Close()
+		if err := m.Steps(-steps); err != nil {
+			log.Fatalf("Failed to roll back migrations: %v", err)
+		}
+		log.Printf("Rolled back %d migration(s)", steps)
+	case "status":
+		migrateStatus(cfg)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
+
+func newMigrator(cfg *config.Config) *migrate.Migrate {
+	m, err := migrations.New(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open migrations: %v", err)
+	}
+	m.Log = migrateLogger{}
+	return m
+}
+
+// migrateStatus prints the applied version and the migrations still to run.
+func migrateStatus(cfg *config.Config) {
+	ctx := context.Background()
+
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	current, dirty, err := migrations.CurrentVersion(ctx, pool)
+	if err != nil && !errors.Is(err, migrations.ErrNoVersion) {
+		log.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	fmt.Printf("Database version: %d", current)
+	if dirty {
+		fmt.Print(" (dirty: the last migration failed partway and must be fixed by hand)")
+	}
+	fmt.Printf("\nLatest version:   %d\n", migrations.Latest())
+
+	pending := 0
+	for _, m := range migrations.All() {
+		if m.Version > current {
+			if pending == 0 {
+				fmt.Println("Pending:")
+			}
+			fmt.Printf("  %06d_%s\n", m.Version, m.Name)
+			pending++
+		}
+	}
+	if pending == 0 {
+		fmt.Println("Up to date")
+	}
+}
+
+// migrateLogger prints golang-migrate's progress through the standard logger.
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...interface{}) { log.Printf(format, v...) }
+
+func (migrateLogger) Verbose() bool { return false }
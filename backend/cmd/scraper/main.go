@@ -1,7 +1,231 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/config"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/inference"
+	"github.com/paulsena/asheville-setlist/internal/ingest"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+	"github.com/paulsena/asheville-setlist/internal/mail"
+	"github.com/paulsena/asheville-setlist/internal/maintenance"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+const usage = `Usage: scraper <command>
+
+Commands:
+  run        Scrape all configured sources (default)
+  maintain   Run database maintenance jobs once (mark past shows completed)
+  infer      Propose genres for bands that have none
+  similar    Recompute similar-band scores
+  notify     Queue show reminders and deliver pending notifications once
+  webhooks   Deliver queued webhook events once`
+
+// Scraper metrics, exported by finishJob when a command exits. `scraper run`
+// records each source's run by passing scraperMetrics to ingest.WithMetrics.
+var (
+	registry       = metrics.NewRegistry()
+	scraperMetrics = metrics.NewScraper(registry)
+)
 
 func main() {
-	fmt.Println("Asheville Setlist Scraper - Coming soon")
+	command := "run"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	var job func(context.Context, *config.Config) error
+	switch command {
+	case "run":
+		job = runScrape
+	case "maintain":
+		job = runMaintenance
+	case "infer":
+		job = runInference
+	case "similar":
+		job = runSimilarity
+	case "notify":
+		job = runNotify
+	case "webhooks":
+		job = runWebhooks
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	cfg := loadConfig()
+
+	start := time.Now()
+	err := job(ctx, cfg)
+	finishJob(ctx, cfg, command, time.Since(start), err)
+	if err != nil {
+		log.Printf("scraper %s failed: %v", command, err)
+		os.Exit(1)
+	}
+}
+
+// sources are the sites `scraper run` scrapes, each writing its events
+// through the ingest pipeline. Site scrapers register here as they are added.
+var sources []ingest.Source
+
+// loadConfig loads configuration and applies its log settings, exiting on
+// error.
+func loadConfig() *config.Config {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogLevel, cfg.Environment))
+	return cfg
+}
+
+// finishJob records a command's outcome and exports the scraper's metrics to
+// the configured Pushgateway and/or textfile, whether or not the command
+// succeeded. Export failures are logged but don't fail the job.
+func finishJob(ctx context.Context, cfg *config.Config, job string, duration time.Duration, err error) {
+	scraperMetrics.ObserveJob(job, duration, err)
+	if err := metrics.Flush(ctx, registry, "scraper_"+job, cfg.MetricsPushURL, cfg.MetricsTextfile); err != nil {
+		slog.Error("failed to export metrics", "job", job, "error", err)
+	}
+}
+
+// runScrape scrapes every source once and ingests the results.
+func runScrape(ctx context.Context, cfg *config.Config) error {
+	if len(sources) == 0 {
+		log.Printf("No scraper sources registered")
+	}
+
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	pipeline := ingest.New(pool,
+		ingest.WithSimilarityWeights(cfg.SimilarityWeights),
+		ingest.WithMetrics(scraperMetrics),
+	)
+	result, err := pipeline.Run(ctx, sources)
+	if err != nil {
+		return fmt.Errorf("failed to scrape sources: %w", err)
+	}
+
+	log.Printf("Scrape complete: %d sources, %d created, %d updated, %d cancelled, %d failed",
+		len(sources), result.Created, result.Updated, result.Cancelled, result.Failed)
+	return nil
+}
+
+// runMaintenance runs all maintenance jobs once.
+func runMaintenance(ctx context.Context, cfg *config.Config) error {
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	queries := db.New(pool)
+
+	count, err := maintenance.CompletePastShows(ctx, queries)
+	if err != nil {
+		return fmt.Errorf("failed to complete past shows: %w", err)
+	}
+
+	log.Printf("Maintenance complete: %d shows marked completed", count)
+	return nil
+}
+
+// runInference proposes genres for every band without genres.
+func runInference(ctx context.Context, cfg *config.Config) error {
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	count, err := inference.InferMissing(ctx, db.New(pool))
+	if err != nil {
+		return fmt.Errorf("failed to infer band genres: %w", err)
+	}
+
+	log.Printf("Genre inference complete: %d bands received suggestions", count)
+
+	// New genres change similar-band scores
+	if count > 0 {
+		if _, err := similarity.Refresh(ctx, pool, cfg.SimilarityWeights); err != nil {
+			return fmt.Errorf("failed to refresh band similarity: %w", err)
+		}
+	}
+	return nil
+}
+
+// runSimilarity recomputes similar-band scores for all bands.
+func runSimilarity(ctx context.Context, cfg *config.Config) error {
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	count, err := similarity.Refresh(ctx, pool, cfg.SimilarityWeights)
+	if err != nil {
+		return fmt.Errorf("failed to refresh band similarity: %w", err)
+	}
+
+	log.Printf("Similarity refresh complete: %d band pairs stored", count)
+	return nil
+}
+
+// runNotify runs one notification dispatch.
+func runNotify(ctx context.Context, cfg *config.Config) error {
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	mailer, err := mail.New(cfg.MailConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create mailer: %w", err)
+	}
+
+	channels, err := notify.NewChannels(mailer, cfg.VAPIDConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create notification channels: %w", err)
+	}
+
+	count, err := notify.NewDispatcher(db.New(pool), cfg.AppURL, channels).Dispatch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to dispatch notifications: %w", err)
+	}
+
+	log.Printf("Notification dispatch complete: %d digests sent", count)
+	return nil
+}
+
+// runWebhooks delivers every due webhook event once.
+func runWebhooks(ctx context.Context, cfg *config.Config) error {
+	pool, err := config.NewDatabasePool(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	count, err := webhook.NewDeliverer(db.New(pool), nil).Deliver(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhooks: %w", err)
+	}
+
+	log.Printf("Webhook delivery complete: %d delivered", count)
+	return nil
 }
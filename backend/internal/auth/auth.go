@@ -0,0 +1,209 @@
+// Package auth implements passwordless sign-in.
+//
+// A user asks for a magic link; the emailed link carries a single-use token
+// that is exchanged for a bearer session token. Only SHA-256 hashes of either
+// token are stored, so a database leak does not expose live credentials.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	mailer "github.com/paulsena/asheville-setlist/internal/mail"
+)
+
+const (
+	// LoginTokenTTL is how long a magic link stays valid.
+	LoginTokenTTL = 15 * time.Minute
+
+	// SessionTTL is how long a session token stays valid.
+	SessionTTL = 30 * 24 * time.Hour
+
+	// MaxEmailLength is the longest email address accepted.
+	MaxEmailLength = 254
+
+	// tokenBytes is the amount of randomness in each token.
+	tokenBytes = 32
+)
+
+var (
+	// ErrInvalidEmail is returned for malformed email addresses.
+	ErrInvalidEmail = errors.New("invalid email address")
+
+	// ErrInvalidToken is returned for unknown, expired or used tokens.
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Session is a newly issued bearer session.
+type Session struct {
+	Token     string
+	ExpiresAt time.Time
+	User      db.User
+}
+
+// NormalizeEmail validates an email address and returns it lowercased
+// without any display name.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || len(email) > MaxEmailLength {
+		return "", ErrInvalidEmail
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(addr.Address), nil
+}
+
+// NewToken returns a random URL-safe token and the hash to store for it.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the stored form of a token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestLogin stores a magic-link token for email and mails the link.
+// verifyURL is the page that exchanges the token; the token is added as the
+// "token" query parameter. The account itself is created on first verify,
+// so requesting a link never reveals whether an email is registered. While
+// an email has an unused link that hasn't expired, no new link is sent, so
+// repeated requests can't flood an inbox.
+func RequestLogin(ctx context.Context, q *db.Queries, m mailer.Mailer, verifyURL, email string) error {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	token, hash, err := NewToken()
+	if err != nil {
+		return err
+	}
+
+	created, err := q.CreateLoginToken(ctx, db.CreateLoginTokenParams{
+		TokenHash: hash,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(LoginTokenTTL), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store login token: %w", err)
+	}
+	if created == 0 {
+		// The link already sent is still valid
+		return nil
+	}
+
+	link, err := withQueryParam(verifyURL, "token", token)
+	if err != nil {
+		return err
+	}
+
+	err = m.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your Asheville Setlist sign-in link",
+		Body: fmt.Sprintf("Sign in to The Asheville Setlist:\n\n%s\n\nThis link expires in %d minutes and can only be used once. "+
+			"If you didn't ask for it, you can ignore this email.", link, int(LoginTokenTTL.Minutes())),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send login email: %w", err)
+	}
+	return nil
+}
+
+// Verify consumes a magic-link token and starts a session for its email,
+// creating the user on first sign-in.
+func Verify(ctx context.Context, q *db.Queries, token string) (Session, error) {
+	if token == "" {
+		return Session{}, ErrInvalidToken
+	}
+
+	email, err := q.ConsumeLoginToken(ctx, HashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to consume login token: %w", err)
+	}
+
+	user, err := q.UpsertUserLogin(ctx, email)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to upsert user: %w", err)
+	}
+
+	sessionToken, hash, err := NewToken()
+	if err != nil {
+		return Session{}, err
+	}
+
+	expiresAt := time.Now().Add(SessionTTL)
+	err = q.CreateSession(ctx, db.CreateSessionParams{
+		TokenHash: hash,
+		UserID:    user.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return Session{
+		Token:     sessionToken,
+		ExpiresAt: expiresAt,
+		User:      user,
+	}, nil
+}
+
+// Authenticate resolves a session token to its user.
+func Authenticate(ctx context.Context, q *db.Queries, token string) (db.User, error) {
+	if token == "" {
+		return db.User{}, ErrInvalidToken
+	}
+
+	user, err := q.GetSessionUser(ctx, HashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.User{}, ErrInvalidToken
+	}
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to look up session: %w", err)
+	}
+	return user, nil
+}
+
+// Logout ends the session for token.
+func Logout(ctx context.Context, q *db.Queries, token string) error {
+	if err := q.DeleteSession(ctx, HashToken(token)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// withQueryParam adds a query parameter to rawURL.
+func withQueryParam(rawURL, key, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid verify URL %q: %w", rawURL, err)
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
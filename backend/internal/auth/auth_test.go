@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "fan@example.com", want: "fan@example.com"},
+		{in: "  Fan@Example.COM ", want: "fan@example.com"},
+		{in: "", wantErr: true},
+		{in: "not-an-email", wantErr: true},
+		{in: "Fan <fan@example.com>", wantErr: true},
+		{in: strings.Repeat("a", MaxEmailLength) + "@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeEmail(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeEmail(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := NewToken()
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if hash != HashToken(token) {
+		t.Error("hash does not match HashToken(token)")
+	}
+	if hash == token {
+		t.Error("hash must differ from the token")
+	}
+
+	other, _, _ := NewToken()
+	if other == token {
+		t.Error("tokens must be unique")
+	}
+}
+
+func TestWithQueryParam(t *testing.T) {
+	got, err := withQueryParam("http://localhost:3000/auth/verify?next=%2Fme", "token", "abc_-1")
+	if err != nil {
+		t.Fatalf("withQueryParam: %v", err)
+	}
+	if want := "http://localhost:3000/auth/verify?next=%2Fme&token=abc_-1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
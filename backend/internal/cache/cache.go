@@ -0,0 +1,98 @@
+// Package cache caches rendered API responses in process.
+//
+// Responses are stored in a Store under a key built from the request path and
+// its normalized query parameters, labelled with tags naming the data they
+// were built from (shows, bands, venues). The write paths call Invalidate in
+// their transactions, which announces the tags on the cache_invalidate NOTIFY
+// channel when the transaction commits; Listen runs in every API replica and
+// drops the tagged entries from its Store. Entries also expire after a TTL,
+// bounding staleness if a notification is missed.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Channel is the Postgres NOTIFY channel invalidated tags are announced on.
+const Channel = "cache_invalidate"
+
+// Tags label cached responses with the tables they read.
+const (
+	TagShows  = "shows"
+	TagBands  = "bands"
+	TagVenues = "venues"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Store is a cache backend. The in-process LRU is the default; a shared store
+// (e.g. Redis) can implement the same interface. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Get returns the unexpired entry for key.
+	Get(ctx context.Context, key string) (Entry, bool, error)
+
+	// Set stores an entry for ttl, labelled with tags.
+	Set(ctx context.Context, key string, entry Entry, ttl time.Duration, tags []string) error
+
+	// InvalidateTags removes every entry labelled with any of the tags.
+	InvalidateTags(ctx context.Context, tags ...string) error
+
+	// Purge removes every entry.
+	Purge(ctx context.Context) error
+}
+
+// Key builds a cache key from a request path and query. Parameters are
+// sorted by name, repeated values are sorted and de-duplicated and empty
+// values dropped, so equivalent queries share a key.
+func Key(path string, query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString(path)
+	sep := byte('?')
+	for _, name := range names {
+		values := slices.Clone(query[name])
+		slices.Sort(values)
+		for _, v := range slices.Compact(values) {
+			if v == "" {
+				continue
+			}
+			b.WriteByte(sep)
+			b.WriteString(url.QueryEscape(name))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+			sep = '&'
+		}
+	}
+	return b.String()
+}
+
+// Invalidate announces that data with the tags changed. Call it with the
+// transaction's queries so replicas only drop entries once the change is
+// visible; repeated tags in one transaction are sent once.
+func Invalidate(ctx context.Context, q *db.Queries, tags ...string) error {
+	for _, tag := range tags {
+		if err := q.NotifyCacheInvalidation(ctx, tag); err != nil {
+			return fmt.Errorf("failed to invalidate %s cache: %w", tag, err)
+		}
+	}
+	return nil
+}
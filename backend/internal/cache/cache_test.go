@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		query string
+		want  string
+	}{
+		{"no query", "/api/genres", "", "/api/genres"},
+		{"sorted params", "/api/shows", "per_page=10&page=2", "/api/shows?page=2&per_page=10"},
+		{"sorted values", "/api/shows", "venue=orange-peel&venue=grey-eagle", "/api/shows?venue=grey-eagle&venue=orange-peel"},
+		{"duplicate values", "/api/shows", "genre=rock&genre=rock", "/api/shows?genre=rock"},
+		{"empty values dropped", "/api/shows", "q=&filter=tonight&venue=", "/api/shows?filter=tonight"},
+		{"escaped", "/api/shows", "q=a%26b", "/api/shows?q=a%26b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Key(tt.path, query); got != tt.want {
+				t.Errorf("Key = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reconnectDelay is how long Listen waits before listening again after
+// losing its connection.
+const reconnectDelay = 5 * time.Second
+
+// Listen applies invalidations announced on Channel to the store until the
+// context is cancelled, reconnecting after errors. The store is purged
+// whenever listening (re)starts, since announcements may have been missed.
+func Listen(ctx context.Context, pool *pgxpool.Pool, store Store) {
+	for {
+		err := listen(ctx, pool, store)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("cache invalidation listener failed", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// listen takes a connection out of the pool, LISTENs on Channel and
+// invalidates every announced tag until an error occurs.
+func listen(ctx context.Context, pool *pgxpool.Pool, store Store) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// LISTEN state must not leak back into the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	if err := store.Purge(ctx); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if err := store.InvalidateTags(ctx, n.Payload); err != nil {
+			slog.Error("failed to invalidate cache", "tag", n.Payload, "error", err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process Store holding at most a fixed number of entries,
+// evicting the least recently used first. Expired entries are dropped when
+// read or evicted.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is the most recently used
+	items    map[string]*list.Element
+	tagged   map[string]map[string]struct{} // Tag to keys
+	now      func() time.Time
+}
+
+// lruItem is an entry with its key, expiry and tags.
+type lruItem struct {
+	key     string
+	entry   Entry
+	expires time.Time
+	tags    []string
+}
+
+// NewLRU creates an LRU holding up to capacity entries.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		tagged:   make(map[string]map[string]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Get returns the unexpired entry for key. The entry's body is shared and
+// must not be modified.
+func (c *LRU) Get(_ context.Context, key string) (Entry, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	item := el.Value.(*lruItem)
+	if !c.now().Before(item.expires) {
+		c.remove(el)
+		return Entry{}, false, nil
+	}
+	c.order.MoveToFront(el)
+	return item.entry, true, nil
+}
+
+// Set stores an entry, replacing any entry with the same key.
+func (c *LRU) Set(_ context.Context, key string, entry Entry, ttl time.Duration, tags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+
+	item := &lruItem{
+		key:     key,
+		entry:   entry,
+		expires: c.now().Add(ttl),
+		tags:    tags,
+	}
+	c.items[key] = c.order.PushFront(item)
+	for _, tag := range tags {
+		keys, ok := c.tagged[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tagged[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// InvalidateTags removes every entry labelled with any of the tags.
+func (c *LRU) InvalidateTags(_ context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tagged[tag] {
+			c.remove(c.items[key])
+		}
+	}
+	return nil
+}
+
+// Purge removes every entry.
+func (c *LRU) Purge(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+	clear(c.tagged)
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet dropped.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops an element and its tag links. The caller holds the lock.
+func (c *LRU) remove(el *list.Element) {
+	item := c.order.Remove(el).(*lruItem)
+	delete(c.items, item.key)
+	for _, tag := range item.tags {
+		keys := c.tagged[tag]
+		delete(keys, item.key)
+		if len(keys) == 0 {
+			delete(c.tagged, tag)
+		}
+	}
+}
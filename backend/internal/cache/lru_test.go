@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func entry(body string) Entry {
+	return Entry{Status: 200, Body: []byte(body)}
+}
+
+func TestLRU_GetSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	c.Set(ctx, "a", entry("one"), time.Minute, nil)
+	got, ok, err := c.Get(ctx, "a")
+	if err != nil || !ok || string(got.Body) != "one" {
+		t.Fatalf("Get = %q, %v, %v; want one, true, nil", got.Body, ok, err)
+	}
+
+	c.Set(ctx, "a", entry("two"), time.Minute, nil)
+	if got, _, _ := c.Get(ctx, "a"); string(got.Body) != "two" {
+		t.Errorf("expected replaced entry, got %q", got.Body)
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected 1 entry, got %d", c.Len())
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", entry("one"), time.Minute, []string{TagShows})
+
+	now = now.Add(59 * time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("expected hit before TTL")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("expected miss at TTL")
+	}
+	if c.Len() != 0 || len(c.tagged) != 0 {
+		t.Errorf("expected expired entry and its tags dropped, got %d entries, %d tags", c.Len(), len(c.tagged))
+	}
+}
+
+func TestLRU_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", entry("a"), time.Minute, nil)
+	c.Set(ctx, "b", entry("b"), time.Minute, nil)
+	c.Get(ctx, "a") // b is now least recently used
+	c.Set(ctx, "c", entry("c"), time.Minute, nil)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := c.Get(ctx, key); ok != want {
+			t.Errorf("Get(%q) hit = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestLRU_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	c.Set(ctx, "/api/shows", entry("shows"), time.Minute, []string{TagShows, TagBands, TagVenues})
+	c.Set(ctx, "/api/venues", entry("venues"), time.Minute, []string{TagVenues, TagShows})
+	c.Set(ctx, "/api/genres", entry("genres"), time.Minute, []string{TagBands})
+
+	c.InvalidateTags(ctx, TagShows)
+
+	for key, want := range map[string]bool{"/api/shows": false, "/api/venues": false, "/api/genres": true} {
+		if _, ok, _ := c.Get(ctx, key); ok != want {
+			t.Errorf("Get(%q) hit = %v, want %v", key, ok, want)
+		}
+	}
+	if _, ok := c.tagged[TagVenues]; ok {
+		t.Error("expected tag index emptied with its entries")
+	}
+
+	c.InvalidateTags(ctx, "unknown")
+	c.Purge(ctx)
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after Purge, got %d", c.Len())
+	}
+}
@@ -3,6 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/mail"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/tracing"
+	"github.com/paulsena/asheville-setlist/migrations"
 )
 
 // Config holds all application configuration
@@ -19,6 +28,86 @@ type Config struct {
 
 	// Environment
 	Environment string
+
+	// Maintenance configuration
+	// MaintenanceInterval controls how often the API marks past shows completed (0 disables)
+	MaintenanceInterval time.Duration
+
+	// Admin configuration
+	// AdminToken is the bearer token for /api/admin routes (empty disables them)
+	AdminToken string
+
+	// Similar bands configuration
+	// SimilarityWeights blends genre, co-bill, venue and hometown overlap
+	SimilarityWeights similarity.Weights
+
+	// User account configuration
+	// AppURL is the frontend base URL that emailed sign-in links point to
+	AppURL string
+	// Mailer selects how email is delivered: "log", "file" or "smtp"
+	Mailer string
+	// MailerFile is the file the "file" mailer appends messages to
+	MailerFile string
+	// SMTP settings for the "smtp" mailer
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	// MailFrom is the sender address for SMTP mail
+	MailFrom string
+
+	// Notification configuration
+	// NotifyInterval controls how often the API delivers notifications (0 disables)
+	NotifyInterval time.Duration
+	// VAPID keys for Web Push (base64url; empty private key disables Web Push)
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	// VAPIDSubject is the mailto: or https: contact sent to push services
+	VAPIDSubject string
+
+	// Webhook configuration
+	// WebhookInterval controls how often the API delivers queued webhooks (0 disables)
+	WebhookInterval time.Duration
+
+	// Live stream configuration
+	// StreamHeartbeat is how often idle /api/stream/shows connections get a keep-alive comment
+	StreamHeartbeat time.Duration
+
+	// Response cache configuration
+	// CacheTTL is how long show, venue and genre responses are cached (0 disables)
+	CacheTTL time.Duration
+	// CacheSize is the most responses the in-process cache holds
+	CacheSize int
+
+	// Rate limiting configuration
+	// RateLimitPerMinute is how many requests a client IP may make to each API route per minute (0 disables)
+	RateLimitPerMinute int
+	// SubmissionRateLimitPerHour is how many shows a client IP may submit per hour (0 disables)
+	SubmissionRateLimitPerHour int
+	// MagicLinkRateLimitPerHour is how many sign-in links a client IP may request per hour (0 disables)
+	MagicLinkRateLimitPerHour int
+	// TrustedProxies are the proxy IPs/CIDRs whose X-Forwarded-For is believed (empty trusts none)
+	TrustedProxies []string
+
+	// Metrics configuration
+	// MetricsToken is the bearer token required for /metrics (empty leaves it open)
+	MetricsToken string
+	// MetricsPushURL is the Pushgateway scraper commands push their metrics to (empty disables)
+	MetricsPushURL string
+	// MetricsTextfile is the file scraper commands write their metrics to (empty disables)
+	MetricsTextfile string
+
+	// Tracing configuration
+	// TracingExporter selects where spans go: "none", "stdout" or "otlp"
+	TracingExporter string
+	// TracingSampleRatio is the fraction of new traces recorded (0 to 1)
+	TracingSampleRatio float64
+
+	// Readiness configuration
+	// ScraperStaleAfter is how old the last successful scrape may be before /readyz reports degraded (0 disables)
+	ScraperStaleAfter time.Duration
+	// SchemaCheck decides what the API does at startup when the database is missing migrations: "off", "warn" or "require" (refuse to start)
+	SchemaCheck string
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -29,8 +118,108 @@ func LoadConfig() (*Config, error) {
 		DatabaseURL: os.Getenv("DATABASE_URL"),
 		LogLevel:    getEnvWithDefault("LOG_LEVEL", "info"),
 		Environment: getEnvWithDefault("ENV", "development"),
+		AdminToken:  os.Getenv("ADMIN_TOKEN"),
+		AppURL:      getEnvWithDefault("APP_URL", "http://localhost:3000"),
+		Mailer:      getEnvWithDefault("MAILER", mail.KindLog),
+		MailerFile:  getEnvWithDefault("MAILER_FILE", "tmp/mail.txt"),
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnvWithDefault("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     os.Getenv("MAIL_FROM"),
+
+		VAPIDPublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
+		VAPIDPrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
+		VAPIDSubject:    os.Getenv("VAPID_SUBJECT"),
+
+		MetricsToken:    os.Getenv("METRICS_TOKEN"),
+		MetricsPushURL:  os.Getenv("METRICS_PUSHGATEWAY_URL"),
+		MetricsTextfile: os.Getenv("METRICS_TEXTFILE"),
+
+		TracingExporter: getEnvWithDefault("TRACING_EXPORTER", tracing.ExporterNone),
+
+		SchemaCheck: getEnvWithDefault("SCHEMA_CHECK", migrations.CheckWarn),
+	}
+
+	maintenanceInterval, err := time.ParseDuration(getEnvWithDefault("MAINTENANCE_INTERVAL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("MAINTENANCE_INTERVAL must be a duration like '15m', got '%s'", os.Getenv("MAINTENANCE_INTERVAL"))
+	}
+	cfg.MaintenanceInterval = maintenanceInterval
+
+	notifyInterval, err := time.ParseDuration(getEnvWithDefault("NOTIFY_INTERVAL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("NOTIFY_INTERVAL must be a duration like '5m', got '%s'", os.Getenv("NOTIFY_INTERVAL"))
+	}
+	cfg.NotifyInterval = notifyInterval
+
+	webhookInterval, err := time.ParseDuration(getEnvWithDefault("WEBHOOK_INTERVAL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("WEBHOOK_INTERVAL must be a duration like '30s', got '%s'", os.Getenv("WEBHOOK_INTERVAL"))
+	}
+	cfg.WebhookInterval = webhookInterval
+
+	streamHeartbeat, err := time.ParseDuration(getEnvWithDefault("STREAM_HEARTBEAT", "15s"))
+	if err != nil {
+		return nil, fmt.Errorf("STREAM_HEARTBEAT must be a duration like '15s', got '%s'", os.Getenv("STREAM_HEARTBEAT"))
+	}
+	cfg.StreamHeartbeat = streamHeartbeat
+
+	cacheTTL, err := time.ParseDuration(getEnvWithDefault("CACHE_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("CACHE_TTL must be a duration like '1m', got '%s'", os.Getenv("CACHE_TTL"))
+	}
+	cfg.CacheTTL = cacheTTL
+
+	cacheSize, err := strconv.Atoi(getEnvWithDefault("CACHE_SIZE", "1000"))
+	if err != nil {
+		return nil, fmt.Errorf("CACHE_SIZE must be an integer, got '%s'", os.Getenv("CACHE_SIZE"))
+	}
+	cfg.CacheSize = cacheSize
+
+	rateLimit, err := strconv.Atoi(getEnvWithDefault("RATE_LIMIT_PER_MINUTE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_PER_MINUTE must be an integer, got '%s'", os.Getenv("RATE_LIMIT_PER_MINUTE"))
+	}
+	cfg.RateLimitPerMinute = rateLimit
+
+	submissionRateLimit, err := strconv.Atoi(getEnvWithDefault("SUBMISSION_RATE_LIMIT_PER_HOUR", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("SUBMISSION_RATE_LIMIT_PER_HOUR must be an integer, got '%s'", os.Getenv("SUBMISSION_RATE_LIMIT_PER_HOUR"))
+	}
+	cfg.SubmissionRateLimitPerHour = submissionRateLimit
+
+	magicLinkRateLimit, err := strconv.Atoi(getEnvWithDefault("MAGIC_LINK_RATE_LIMIT_PER_HOUR", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("MAGIC_LINK_RATE_LIMIT_PER_HOUR must be an integer, got '%s'", os.Getenv("MAGIC_LINK_RATE_LIMIT_PER_HOUR"))
+	}
+	cfg.MagicLinkRateLimitPerHour = magicLinkRateLimit
+
+	scraperStaleAfter, err := time.ParseDuration(getEnvWithDefault("SCRAPER_STALE_AFTER", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("SCRAPER_STALE_AFTER must be a duration like '24h', got '%s'", os.Getenv("SCRAPER_STALE_AFTER"))
+	}
+	cfg.ScraperStaleAfter = scraperStaleAfter
+
+	sampleRatio, err := strconv.ParseFloat(getEnvWithDefault("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("TRACING_SAMPLE_RATIO must be a number, got '%s'", os.Getenv("TRACING_SAMPLE_RATIO"))
+	}
+	cfg.TracingSampleRatio = sampleRatio
+
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			cfg.TrustedProxies = append(cfg.TrustedProxies, proxy)
+		}
 	}
 
+	weights, err := similarity.ParseWeights(os.Getenv("SIMILARITY_WEIGHTS"))
+	if err != nil {
+		return nil, fmt.Errorf("SIMILARITY_WEIGHTS: %w", err)
+	}
+	cfg.SimilarityWeights = weights
+
 	// Validate required fields
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -61,9 +250,111 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("LOG_LEVEL must be one of: debug, info, warn, error, got '%s'", c.LogLevel)
 	}
 
+	if c.MaintenanceInterval < 0 {
+		return fmt.Errorf("MAINTENANCE_INTERVAL must not be negative, got '%s'", c.MaintenanceInterval)
+	}
+
+	if c.NotifyInterval < 0 {
+		return fmt.Errorf("NOTIFY_INTERVAL must not be negative, got '%s'", c.NotifyInterval)
+	}
+
+	if c.WebhookInterval < 0 {
+		return fmt.Errorf("WEBHOOK_INTERVAL must not be negative, got '%s'", c.WebhookInterval)
+	}
+
+	if c.StreamHeartbeat <= 0 {
+		return fmt.Errorf("STREAM_HEARTBEAT must be positive, got '%s'", c.StreamHeartbeat)
+	}
+
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("CACHE_TTL must not be negative, got '%s'", c.CacheTTL)
+	}
+
+	if c.CacheSize < 1 {
+		return fmt.Errorf("CACHE_SIZE must be at least 1, got %d", c.CacheSize)
+	}
+
+	if c.ScraperStaleAfter < 0 {
+		return fmt.Errorf("SCRAPER_STALE_AFTER must not be negative, got '%s'", c.ScraperStaleAfter)
+	}
+
+	switch c.SchemaCheck {
+	case migrations.CheckOff, migrations.CheckWarn, migrations.CheckRequire:
+	default:
+		return fmt.Errorf("SCHEMA_CHECK must be one of: off, warn, require, got '%s'", c.SchemaCheck)
+	}
+
+	if c.RateLimitPerMinute < 0 {
+		return fmt.Errorf("RATE_LIMIT_PER_MINUTE must not be negative, got %d", c.RateLimitPerMinute)
+	}
+
+	if c.SubmissionRateLimitPerHour < 0 {
+		return fmt.Errorf("SUBMISSION_RATE_LIMIT_PER_HOUR must not be negative, got %d", c.SubmissionRateLimitPerHour)
+	}
+
+	if c.MagicLinkRateLimitPerHour < 0 {
+		return fmt.Errorf("MAGIC_LINK_RATE_LIMIT_PER_HOUR must not be negative, got %d", c.MagicLinkRateLimitPerHour)
+	}
+
+	switch c.TracingExporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be one of: none, stdout, otlp, got '%s'", c.TracingExporter)
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", c.TracingSampleRatio)
+	}
+
+	switch c.Mailer {
+	case mail.KindLog, mail.KindFile:
+	case mail.KindSMTP:
+		if c.SMTPHost == "" || c.MailFrom == "" {
+			return fmt.Errorf("MAILER=smtp requires SMTP_HOST and MAIL_FROM")
+		}
+	default:
+		return fmt.Errorf("MAILER must be one of: %s, %s, %s, got '%s'", mail.KindLog, mail.KindFile, mail.KindSMTP, c.Mailer)
+	}
+
+	if c.VAPIDPrivateKey != "" && c.VAPIDSubject == "" {
+		return fmt.Errorf("VAPID_SUBJECT is required when VAPID_PRIVATE_KEY is set")
+	}
+
 	return nil
 }
 
+// MailConfig returns the mailer settings
+func (c *Config) MailConfig() mail.Config {
+	return mail.Config{
+		Kind:         c.Mailer,
+		FilePath:     c.MailerFile,
+		SMTPHost:     c.SMTPHost,
+		SMTPPort:     c.SMTPPort,
+		SMTPUsername: c.SMTPUsername,
+		SMTPPassword: c.SMTPPassword,
+		From:         c.MailFrom,
+	}
+}
+
+// TracingConfig returns the tracing settings for the named service
+func (c *Config) TracingConfig(service, version string) tracing.Config {
+	return tracing.Config{
+		Exporter:       c.TracingExporter,
+		SampleRatio:    c.TracingSampleRatio,
+		ServiceName:    service,
+		ServiceVersion: version,
+	}
+}
+
+// VAPIDConfig returns the Web Push keys
+func (c *Config) VAPIDConfig() notify.VAPIDConfig {
+	return notify.VAPIDConfig{
+		PublicKey:  c.VAPIDPublicKey,
+		PrivateKey: c.VAPIDPrivateKey,
+		Subject:    c.VAPIDSubject,
+	}
+}
+
 // getEnvWithDefault returns the value of an environment variable or a default if not set
 func getEnvWithDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/paulsena/asheville-setlist/internal/tracing"
+	"go.opentelemetry.io/otel"
 )
 
 // NewDatabasePool creates a new PostgreSQL connection pool
@@ -24,6 +26,9 @@ func NewDatabasePool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
 	config.MaxConnIdleTime = 30 * time.Minute // Close idle connections after 30 minutes
 	config.HealthCheckPeriod = time.Minute    // Check connection health every minute
 
+	// Trace queries run inside a traced request
+	config.ConnConfig.Tracer = tracing.NewQueryTracer(otel.GetTracerProvider())
+
 	// Create pool
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -41,14 +41,19 @@ func (q *Queries) BandExists(ctx context.Context, slug string) (bool, error) {
 }
 
 const countBandsByGenre = `-- name: CountBandsByGenre :one
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT COUNT(DISTINCT b.id)
 FROM bands b
 JOIN band_genres bg ON b.id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
-WHERE g.slug = ANY($1::text[])
+WHERE bg.genre_id IN (SELECT id FROM genre_tree)
 `
 
-// Count bands by genre (for pagination)
+// Count bands by genre, including subgenres (for pagination)
 func (q *Queries) CountBandsByGenre(ctx context.Context, dollar_1 []string) (int64, error) {
 	row := q.db.QueryRow(ctx, countBandsByGenre, dollar_1)
 	var count int64
@@ -337,6 +342,42 @@ func (q *Queries) GetBandGenresBatch(ctx context.Context, dollar_1 []int32) ([]G
 	return items, nil
 }
 
+const getBandShowSummary = `-- name: GetBandShowSummary :one
+SELECT
+    COUNT(*) AS total_shows,
+    COUNT(*) FILTER (WHERE sb.is_headliner) AS headliner_count,
+    COUNT(*) FILTER (WHERE NOT COALESCE(sb.is_headliner, FALSE)) AS support_count,
+    MIN(s.date)::timestamptz AS first_seen,
+    MAX(s.date)::timestamptz AS last_seen
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+`
+
+type GetBandShowSummaryRow struct {
+	TotalShows     int64              `json:"total_shows"`
+	HeadlinerCount int64              `json:"headliner_count"`
+	SupportCount   int64              `json:"support_count"`
+	FirstSeen      pgtype.Timestamptz `json:"first_seen"`
+	LastSeen       pgtype.Timestamptz `json:"last_seen"`
+}
+
+// Get play history totals for a band (past shows only)
+func (q *Queries) GetBandShowSummary(ctx context.Context, bandID int32) (GetBandShowSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getBandShowSummary, bandID)
+	var i GetBandShowSummaryRow
+	err := row.Scan(
+		&i.TotalShows,
+		&i.HeadlinerCount,
+		&i.SupportCount,
+		&i.FirstSeen,
+		&i.LastSeen,
+	)
+	return i, err
+}
+
 const getBandUpcomingShows = `-- name: GetBandUpcomingShows :many
 SELECT
     s.id,
@@ -394,53 +435,47 @@ func (q *Queries) GetBandUpcomingShows(ctx context.Context, bandID int32) ([]Get
 	return items, nil
 }
 
-const getSimilarBands = `-- name: GetSimilarBands :many
+const getBandVenuePlayCounts = `-- name: GetBandVenuePlayCounts :many
 SELECT
-    b.id,
-    b.name,
-    b.slug,
-    b.image_url,
-    COUNT(bg1.genre_id) AS shared_genre_count
-FROM bands b
-JOIN band_genres bg1 ON b.id = bg1.band_id
-JOIN band_genres bg2 ON bg1.genre_id = bg2.genre_id
-WHERE bg2.band_id = $1
-  AND b.id != $1
-GROUP BY b.id, b.name, b.slug, b.image_url
-ORDER BY shared_genre_count DESC, b.name ASC
-LIMIT $2
+    v.id,
+    v.name,
+    v.slug,
+    COUNT(*) AS show_count,
+    MAX(s.date)::timestamptz AS last_played
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+GROUP BY v.id, v.name, v.slug
+ORDER BY show_count DESC, v.name ASC
 `
 
-type GetSimilarBandsParams struct {
-	BandID int32 `json:"band_id"`
-	Limit  int32 `json:"limit"`
-}
-
-type GetSimilarBandsRow struct {
-	ID               int32   `json:"id"`
-	Name             string  `json:"name"`
-	Slug             string  `json:"slug"`
-	ImageUrl         *string `json:"image_url"`
-	SharedGenreCount int64   `json:"shared_genre_count"`
+type GetBandVenuePlayCountsRow struct {
+	ID         int32              `json:"id"`
+	Name       string             `json:"name"`
+	Slug       string             `json:"slug"`
+	ShowCount  int64              `json:"show_count"`
+	LastPlayed pgtype.Timestamptz `json:"last_played"`
 }
 
-// Find bands with shared genres (similar bands)
-// Excludes the source band and orders by number of shared genres
-func (q *Queries) GetSimilarBands(ctx context.Context, arg GetSimilarBandsParams) ([]GetSimilarBandsRow, error) {
-	rows, err := q.db.Query(ctx, getSimilarBands, arg.BandID, arg.Limit)
+// Count past shows per venue for a band
+func (q *Queries) GetBandVenuePlayCounts(ctx context.Context, bandID int32) ([]GetBandVenuePlayCountsRow, error) {
+	rows, err := q.db.Query(ctx, getBandVenuePlayCounts, bandID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetSimilarBandsRow{}
+	items := []GetBandVenuePlayCountsRow{}
 	for rows.Next() {
-		var i GetSimilarBandsRow
+		var i GetBandVenuePlayCountsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.Slug,
-			&i.ImageUrl,
-			&i.SharedGenreCount,
+			&i.ShowCount,
+			&i.LastPlayed,
 		); err != nil {
 			return nil, err
 		}
@@ -519,6 +554,146 @@ func (q *Queries) GetSimilarBandsWithGenres(ctx context.Context, arg GetSimilarB
 	return items, nil
 }
 
+const listBandPastShows = `-- name: ListBandPastShows :many
+SELECT
+    s.id,
+    s.date,
+    s.title,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    sb.is_headliner,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+ORDER BY s.date DESC, s.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListBandPastShowsParams struct {
+	BandID int32 `json:"band_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListBandPastShowsRow struct {
+	ID          int32              `json:"id"`
+	Date        pgtype.Timestamptz `json:"date"`
+	Title       *string            `json:"title"`
+	Status      *string            `json:"status"`
+	VenueID     int32              `json:"venue_id"`
+	VenueName   string             `json:"venue_name"`
+	VenueSlug   string             `json:"venue_slug"`
+	IsHeadliner *bool              `json:"is_headliner"`
+	TotalCount  int64              `json:"total_count"`
+}
+
+// List past shows for a band with pagination (most recent first)
+func (q *Queries) ListBandPastShows(ctx context.Context, arg ListBandPastShowsParams) ([]ListBandPastShowsRow, error) {
+	rows, err := q.db.Query(ctx, listBandPastShows, arg.BandID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBandPastShowsRow{}
+	for rows.Next() {
+		var i ListBandPastShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.Title,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.IsHeadliner,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBandUpcomingShows = `-- name: ListBandUpcomingShows :many
+SELECT
+    s.id,
+    s.date,
+    s.title,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    sb.is_headliner,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status = 'scheduled'
+  AND s.date >= NOW()
+ORDER BY s.date ASC, s.id ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListBandUpcomingShowsParams struct {
+	BandID int32 `json:"band_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListBandUpcomingShowsRow struct {
+	ID          int32              `json:"id"`
+	Date        pgtype.Timestamptz `json:"date"`
+	Title       *string            `json:"title"`
+	Status      *string            `json:"status"`
+	VenueID     int32              `json:"venue_id"`
+	VenueName   string             `json:"venue_name"`
+	VenueSlug   string             `json:"venue_slug"`
+	IsHeadliner *bool              `json:"is_headliner"`
+	TotalCount  int64              `json:"total_count"`
+}
+
+// List upcoming shows for a band with pagination
+func (q *Queries) ListBandUpcomingShows(ctx context.Context, arg ListBandUpcomingShowsParams) ([]ListBandUpcomingShowsRow, error) {
+	rows, err := q.db.Query(ctx, listBandUpcomingShows, arg.BandID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBandUpcomingShowsRow{}
+	for rows.Next() {
+		var i ListBandUpcomingShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.Title,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.IsHeadliner,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBands = `-- name: ListBands :many
 SELECT
     b.id,
@@ -578,6 +753,12 @@ func (q *Queries) ListBands(ctx context.Context, arg ListBandsParams) ([]ListBan
 }
 
 const listBandsByGenre = `-- name: ListBandsByGenre :many
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT DISTINCT ON (b.name, b.id)
     b.id,
     b.name,
@@ -587,8 +768,7 @@ SELECT DISTINCT ON (b.name, b.id)
     b.image_url
 FROM bands b
 JOIN band_genres bg ON b.id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
-WHERE g.slug = ANY($1::text[])
+WHERE bg.genre_id IN (SELECT id FROM genre_tree)
 ORDER BY b.name, b.id
 LIMIT $2 OFFSET $3
 `
@@ -608,7 +788,7 @@ type ListBandsByGenreRow struct {
 	ImageUrl *string `json:"image_url"`
 }
 
-// List bands filtered by genre slug(s) with pagination
+// List bands filtered by genre slug(s), including subgenres, with pagination
 func (q *Queries) ListBandsByGenre(ctx context.Context, arg ListBandsByGenreParams) ([]ListBandsByGenreRow, error) {
 	rows, err := q.db.Query(ctx, listBandsByGenre, arg.Column1, arg.Limit, arg.Offset)
 	if err != nil {
@@ -646,15 +826,20 @@ SELECT
     image_url,
     COUNT(*) OVER() AS total_count
 FROM bands
-WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ plainto_tsquery('english', $1)
-ORDER BY name
-LIMIT $2 OFFSET $3
+WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ to_tsquery('english', $1)
+ORDER BY ts_rank(
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', COALESCE(bio, '')), 'D'),
+        to_tsquery('english', $1)
+    ) DESC,
+    name
+LIMIT $3 OFFSET $2
 `
 
 type SearchBandsParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
-	Offset         int32  `json:"offset"`
+	Query  string `json:"query"`
+	Offset int32  `json:"offset"`
+	Limit  int32  `json:"limit"`
 }
 
 type SearchBandsRow struct {
@@ -667,9 +852,10 @@ type SearchBandsRow struct {
 	TotalCount int64   `json:"total_count"`
 }
 
-// Full-text search on band name and bio
+// Full-text search on band name and bio, best match first.
+// The query is a prefix-aware to_tsquery expression built by the handler.
 func (q *Queries) SearchBands(ctx context.Context, arg SearchBandsParams) ([]SearchBandsRow, error) {
-	rows, err := q.db.Query(ctx, searchBands, arg.PlaintoTsquery, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, searchBands, arg.Query, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
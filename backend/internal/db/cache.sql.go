@@ -0,0 +1,25 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: cache.sql
+
+package db
+
+import (
+	"context"
+)
+
+const notifyCacheInvalidation = `-- name: NotifyCacheInvalidation :exec
+
+SELECT pg_notify('cache_invalidate', $1::text)
+`
+
+// ============================================
+// CACHE QUERIES
+// ============================================
+// Tell every API replica to drop cached responses with the tag. The
+// notification is delivered when the surrounding transaction commits.
+func (q *Queries) NotifyCacheInvalidation(ctx context.Context, tag string) error {
+	_, err := q.db.Exec(ctx, notifyCacheInvalidation, tag)
+	return err
+}
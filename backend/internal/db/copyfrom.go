@@ -0,0 +1,52 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: copyfrom.go
+
+package db
+
+import (
+	"context"
+)
+
+// iteratorForInsertBandSimilarities implements pgx.CopyFromSource.
+type iteratorForInsertBandSimilarities struct {
+	rows                 []InsertBandSimilaritiesParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForInsertBandSimilarities) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForInsertBandSimilarities) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].BandID,
+		r.rows[0].SimilarBandID,
+		r.rows[0].Score,
+		r.rows[0].GenreScore,
+		r.rows[0].CoBillScore,
+		r.rows[0].VenueScore,
+		r.rows[0].HometownScore,
+		r.rows[0].SharedGenreCount,
+		r.rows[0].SharedShowCount,
+		r.rows[0].SharedVenueCount,
+	}, nil
+}
+
+func (r iteratorForInsertBandSimilarities) Err() error {
+	return nil
+}
+
+// Bulk load precomputed similarities
+func (q *Queries) InsertBandSimilarities(ctx context.Context, arg []InsertBandSimilaritiesParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"band_similarity"}, []string{"band_id", "similar_band_id", "score", "genre_score", "co_bill_score", "venue_score", "hometown_score", "shared_genre_count", "shared_show_count", "shared_venue_count"}, &iteratorForInsertBandSimilarities{rows: arg})
+}
@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countBandsInGenre = `-- name: CountBandsInGenre :one
@@ -104,7 +106,7 @@ func (q *Queries) GetBandsByGenre(ctx context.Context, arg GetBandsByGenreParams
 
 const getGenre = `-- name: GetGenre :one
 
-SELECT id, name, slug, description, created_at FROM genres
+SELECT id, name, slug, description, created_at, parent_id FROM genres
 WHERE id = $1 LIMIT 1
 `
 
@@ -121,35 +123,91 @@ func (q *Queries) GetGenre(ctx context.Context, id int32) (Genre, error) {
 		&i.Slug,
 		&i.Description,
 		&i.CreatedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const getGenreBySlug = `-- name: GetGenreBySlug :one
 SELECT
-    id,
-    name,
-    slug,
-    description,
-    created_at
-FROM genres
-WHERE slug = $1 LIMIT 1
+    g.id,
+    g.name,
+    g.slug,
+    g.description,
+    g.parent_id,
+    p.name AS parent_name,
+    p.slug AS parent_slug,
+    g.created_at
+FROM genres g
+LEFT JOIN genres p ON g.parent_id = p.id
+WHERE g.slug = $1 LIMIT 1
 `
 
-// Get genre by slug
-func (q *Queries) GetGenreBySlug(ctx context.Context, slug string) (Genre, error) {
+type GetGenreBySlugRow struct {
+	ID          int32              `json:"id"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description *string            `json:"description"`
+	ParentID    *int32             `json:"parent_id"`
+	ParentName  *string            `json:"parent_name"`
+	ParentSlug  *string            `json:"parent_slug"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+// Get genre by slug with its parent genre
+func (q *Queries) GetGenreBySlug(ctx context.Context, slug string) (GetGenreBySlugRow, error) {
 	row := q.db.QueryRow(ctx, getGenreBySlug, slug)
-	var i Genre
+	var i GetGenreBySlugRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Slug,
 		&i.Description,
+		&i.ParentID,
+		&i.ParentName,
+		&i.ParentSlug,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const listGenreChildren = `-- name: ListGenreChildren :many
+SELECT
+    id,
+    name,
+    slug
+FROM genres
+WHERE parent_id = $1
+ORDER BY name
+`
+
+type ListGenreChildrenRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// List direct subgenres of a genre
+func (q *Queries) ListGenreChildren(ctx context.Context, parentID *int32) ([]ListGenreChildrenRow, error) {
+	rows, err := q.db.Query(ctx, listGenreChildren, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListGenreChildrenRow{}
+	for rows.Next() {
+		var i ListGenreChildrenRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listGenres = `-- name: ListGenres :many
 SELECT
     id,
@@ -161,16 +219,24 @@ FROM genres
 ORDER BY name
 `
 
+type ListGenresRow struct {
+	ID          int32              `json:"id"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description *string            `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 // List all genres ordered by name
-func (q *Queries) ListGenres(ctx context.Context) ([]Genre, error) {
+func (q *Queries) ListGenres(ctx context.Context) ([]ListGenresRow, error) {
 	rows, err := q.db.Query(ctx, listGenres)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Genre{}
+	items := []ListGenresRow{}
 	for rows.Next() {
-		var i Genre
+		var i ListGenresRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -242,6 +308,7 @@ SELECT
     g.name,
     g.slug,
     g.description,
+    g.parent_id,
     COUNT(DISTINCT s.id) AS show_count
 FROM genres g
 LEFT JOIN band_genres bg ON g.id = bg.genre_id
@@ -258,10 +325,11 @@ type ListGenresWithShowCountRow struct {
 	Name        string  `json:"name"`
 	Slug        string  `json:"slug"`
 	Description *string `json:"description"`
+	ParentID    *int32  `json:"parent_id"`
 	ShowCount   int64   `json:"show_count"`
 }
 
-// List genres with count of upcoming shows
+// List genres with count of upcoming shows (direct genre only, not subgenres)
 func (q *Queries) ListGenresWithShowCount(ctx context.Context) ([]ListGenresWithShowCountRow, error) {
 	rows, err := q.db.Query(ctx, listGenresWithShowCount)
 	if err != nil {
@@ -276,6 +344,7 @@ func (q *Queries) ListGenresWithShowCount(ctx context.Context) ([]ListGenresWith
 			&i.Name,
 			&i.Slug,
 			&i.Description,
+			&i.ParentID,
 			&i.ShowCount,
 		); err != nil {
 			return nil, err
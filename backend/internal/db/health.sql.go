@@ -0,0 +1,32 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: health.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getLastScrapeAt = `-- name: GetLastScrapeAt :one
+
+SELECT GREATEST(
+    (SELECT MAX(last_success_at) FROM venue_scrapers WHERE is_active = TRUE),
+    (SELECT MAX(last_seen_at) FROM shows)
+)::timestamptz AS last_scrape_at
+`
+
+// ============================================
+// HEALTH QUERIES
+// ============================================
+// When a scrape last succeeded: the newest venue scraper success or show
+// sighting. NULL when nothing has been scraped yet.
+func (q *Queries) GetLastScrapeAt(ctx context.Context) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLastScrapeAt)
+	var last_scrape_at pgtype.Timestamptz
+	err := row.Scan(&last_scrape_at)
+	return last_scrape_at, err
+}
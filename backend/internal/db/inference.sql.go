@@ -0,0 +1,391 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: inference.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const confirmBandGenre = `-- name: ConfirmBandGenre :one
+UPDATE band_genres
+SET confirmed_at = COALESCE(confirmed_at, NOW()),
+    confirmed_by = COALESCE(confirmed_by, $1)
+WHERE band_id = $2
+  AND genre_id = $3
+  AND source <> 'manual'
+RETURNING band_id, genre_id, source, confidence, confirmed_at, confirmed_by
+`
+
+type ConfirmBandGenreParams struct {
+	ConfirmedBy *string `json:"confirmed_by"`
+	BandID      int32   `json:"band_id"`
+	GenreID     int32   `json:"genre_id"`
+}
+
+type ConfirmBandGenreRow struct {
+	BandID      int32              `json:"band_id"`
+	GenreID     int32              `json:"genre_id"`
+	Source      string             `json:"source"`
+	Confidence  pgtype.Numeric     `json:"confidence"`
+	ConfirmedAt pgtype.Timestamptz `json:"confirmed_at"`
+	ConfirmedBy *string            `json:"confirmed_by"`
+}
+
+// Accept an inferred genre. Confirming twice keeps the first confirmation.
+func (q *Queries) ConfirmBandGenre(ctx context.Context, arg ConfirmBandGenreParams) (ConfirmBandGenreRow, error) {
+	row := q.db.QueryRow(ctx, confirmBandGenre, arg.ConfirmedBy, arg.BandID, arg.GenreID)
+	var i ConfirmBandGenreRow
+	err := row.Scan(
+		&i.BandID,
+		&i.GenreID,
+		&i.Source,
+		&i.Confidence,
+		&i.ConfirmedAt,
+		&i.ConfirmedBy,
+	)
+	return i, err
+}
+
+const getBandInferenceInput = `-- name: GetBandInferenceInput :one
+
+SELECT
+    b.id,
+    b.bio,
+    (
+        SELECT COUNT(*)
+        FROM band_genres bg
+        WHERE bg.band_id = b.id
+          AND (bg.source = 'manual' OR bg.confirmed_at IS NOT NULL)
+    ) AS trusted_genre_count
+FROM bands b
+WHERE b.id = $1
+`
+
+type GetBandInferenceInputRow struct {
+	ID                int32   `json:"id"`
+	Bio               *string `json:"bio"`
+	TrustedGenreCount int64   `json:"trusted_genre_count"`
+}
+
+// ============================================
+// GENRE INFERENCE QUERIES
+// ============================================
+// Get the band fields the classifier reads and how many trusted genres it already has
+func (q *Queries) GetBandInferenceInput(ctx context.Context, id int32) (GetBandInferenceInputRow, error) {
+	row := q.db.QueryRow(ctx, getBandInferenceInput, id)
+	var i GetBandInferenceInputRow
+	err := row.Scan(&i.ID, &i.Bio, &i.TrustedGenreCount)
+	return i, err
+}
+
+const getCoBilledGenreShares = `-- name: GetCoBilledGenreShares :many
+WITH co_billed AS (
+    SELECT DISTINCT other.band_id
+    FROM show_bands mine
+    JOIN show_bands other ON other.show_id = mine.show_id AND other.band_id <> mine.band_id
+    WHERE mine.band_id = $1
+),
+trusted AS (
+    SELECT bg.band_id, bg.genre_id
+    FROM band_genres bg
+    JOIN co_billed cb ON cb.band_id = bg.band_id
+    WHERE bg.source = 'manual' OR bg.confirmed_at IS NOT NULL
+)
+SELECT
+    genre_id,
+    (COUNT(*)::float8 / (SELECT COUNT(DISTINCT band_id) FROM trusted))::float8 AS share
+FROM trusted
+GROUP BY genre_id
+`
+
+type GetCoBilledGenreSharesRow struct {
+	GenreID int32   `json:"genre_id"`
+	Share   float64 `json:"share"`
+}
+
+// Share of the band's co-billed bands (with trusted genres) carrying each genre.
+// Trusted genres are manual or admin-confirmed, so unconfirmed inferences
+// never feed back into later ones.
+func (q *Queries) GetCoBilledGenreShares(ctx context.Context, bandID int32) ([]GetCoBilledGenreSharesRow, error) {
+	rows, err := q.db.Query(ctx, getCoBilledGenreShares, bandID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCoBilledGenreSharesRow{}
+	for rows.Next() {
+		var i GetCoBilledGenreSharesRow
+		if err := rows.Scan(&i.GenreID, &i.Share); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenueGenreShares = `-- name: GetVenueGenreShares :many
+WITH band_venues AS (
+    SELECT DISTINCT s.venue_id
+    FROM shows s
+    JOIN show_bands sb ON sb.show_id = s.id
+    WHERE sb.band_id = $1
+),
+venue_bands AS (
+    SELECT DISTINCT sb.band_id
+    FROM shows s
+    JOIN band_venues bv ON bv.venue_id = s.venue_id
+    JOIN show_bands sb ON sb.show_id = s.id
+    WHERE sb.band_id <> $1
+),
+trusted AS (
+    SELECT bg.band_id, bg.genre_id
+    FROM band_genres bg
+    JOIN venue_bands vb ON vb.band_id = bg.band_id
+    WHERE bg.source = 'manual' OR bg.confirmed_at IS NOT NULL
+)
+SELECT
+    genre_id,
+    (COUNT(*)::float8 / (SELECT COUNT(DISTINCT band_id) FROM trusted))::float8 AS share
+FROM trusted
+GROUP BY genre_id
+`
+
+type GetVenueGenreSharesRow struct {
+	GenreID int32   `json:"genre_id"`
+	Share   float64 `json:"share"`
+}
+
+// Share of other bands (with trusted genres) playing the band's venues carrying each genre
+func (q *Queries) GetVenueGenreShares(ctx context.Context, bandID int32) ([]GetVenueGenreSharesRow, error) {
+	rows, err := q.db.Query(ctx, getVenueGenreShares, bandID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenueGenreSharesRow{}
+	for rows.Next() {
+		var i GetVenueGenreSharesRow
+		if err := rows.Scan(&i.GenreID, &i.Share); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBandsWithoutGenres = `-- name: ListBandsWithoutGenres :many
+SELECT b.id
+FROM bands b
+WHERE NOT EXISTS (SELECT 1 FROM band_genres bg WHERE bg.band_id = b.id)
+  AND NOT EXISTS (SELECT 1 FROM band_genre_rejections r WHERE r.band_id = b.id)
+ORDER BY b.id
+`
+
+// List bands that have no genres at all (inference backfill). Bands with
+// rejected suggestions have already been reviewed and are skipped.
+func (q *Queries) ListBandsWithoutGenres(ctx context.Context) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listBandsWithoutGenres)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenreSuggestions = `-- name: ListGenreSuggestions :many
+SELECT
+    b.id AS band_id,
+    b.name AS band_name,
+    b.slug AS band_slug,
+    g.id AS genre_id,
+    g.name AS genre_name,
+    g.slug AS genre_slug,
+    bg.source,
+    bg.confidence,
+    bg.created_at,
+    COUNT(*) OVER() AS total_count
+FROM band_genres bg
+JOIN bands b ON b.id = bg.band_id
+JOIN genres g ON g.id = bg.genre_id
+WHERE bg.source <> 'manual'
+  AND bg.confirmed_at IS NULL
+ORDER BY bg.confidence DESC, bg.created_at, b.id, g.id
+LIMIT $1 OFFSET $2
+`
+
+type ListGenreSuggestionsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListGenreSuggestionsRow struct {
+	BandID     int32              `json:"band_id"`
+	BandName   string             `json:"band_name"`
+	BandSlug   string             `json:"band_slug"`
+	GenreID    int32              `json:"genre_id"`
+	GenreName  string             `json:"genre_name"`
+	GenreSlug  string             `json:"genre_slug"`
+	Source     string             `json:"source"`
+	Confidence pgtype.Numeric     `json:"confidence"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	TotalCount int64              `json:"total_count"`
+}
+
+// List unconfirmed inferred genres with pagination (most confident first)
+func (q *Queries) ListGenreSuggestions(ctx context.Context, arg ListGenreSuggestionsParams) ([]ListGenreSuggestionsRow, error) {
+	rows, err := q.db.Query(ctx, listGenreSuggestions, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListGenreSuggestionsRow{}
+	for rows.Next() {
+		var i ListGenreSuggestionsRow
+		if err := rows.Scan(
+			&i.BandID,
+			&i.BandName,
+			&i.BandSlug,
+			&i.GenreID,
+			&i.GenreName,
+			&i.GenreSlug,
+			&i.Source,
+			&i.Confidence,
+			&i.CreatedAt,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInferenceGenres = `-- name: ListInferenceGenres :many
+SELECT
+    id,
+    name,
+    slug
+FROM genres
+ORDER BY id
+`
+
+type ListInferenceGenresRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// List genres the classifier can propose
+func (q *Queries) ListInferenceGenres(ctx context.Context) ([]ListInferenceGenresRow, error) {
+	rows, err := q.db.Query(ctx, listInferenceGenres)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInferenceGenresRow{}
+	for rows.Next() {
+		var i ListInferenceGenresRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rejectBandGenre = `-- name: RejectBandGenre :execrows
+WITH rejected AS (
+    DELETE FROM band_genres bg
+    WHERE bg.band_id = $2
+      AND bg.genre_id = $3
+      AND bg.source <> 'manual'
+      AND bg.confirmed_at IS NULL
+    RETURNING bg.band_id, bg.genre_id
+)
+INSERT INTO band_genre_rejections (band_id, genre_id, rejected_by)
+SELECT rejected.band_id, rejected.genre_id, $1 FROM rejected
+ON CONFLICT (band_id, genre_id) DO UPDATE
+SET rejected_at = NOW(),
+    rejected_by = EXCLUDED.rejected_by
+`
+
+type RejectBandGenreParams struct {
+	RejectedBy *string `json:"rejected_by"`
+	BandID     int32   `json:"band_id"`
+	GenreID    int32   `json:"genre_id"`
+}
+
+// Remove an unconfirmed inferred genre and remember the rejection so it is
+// not proposed again
+func (q *Queries) RejectBandGenre(ctx context.Context, arg RejectBandGenreParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rejectBandGenre, arg.RejectedBy, arg.BandID, arg.GenreID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertInferredBandGenre = `-- name: UpsertInferredBandGenre :execrows
+INSERT INTO band_genres (band_id, genre_id, source, confidence)
+SELECT $1::int, $2::int, $3::text, $4::numeric
+WHERE NOT EXISTS (
+    SELECT 1 FROM band_genre_rejections r
+    WHERE r.band_id = $1::int AND r.genre_id = $2::int
+)
+ON CONFLICT (band_id, genre_id) DO UPDATE
+SET source = EXCLUDED.source,
+    confidence = EXCLUDED.confidence
+WHERE band_genres.source <> 'manual'
+  AND band_genres.confirmed_at IS NULL
+`
+
+type UpsertInferredBandGenreParams struct {
+	BandID     int32          `json:"band_id"`
+	GenreID    int32          `json:"genre_id"`
+	Source     string         `json:"source"`
+	Confidence pgtype.Numeric `json:"confidence"`
+}
+
+// Write an inferred genre, refreshing earlier unconfirmed inferences.
+// Manual and confirmed genres are never overwritten, and genres an admin
+// rejected for the band are never proposed again.
+func (q *Queries) UpsertInferredBandGenre(ctx context.Context, arg UpsertInferredBandGenreParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertInferredBandGenre,
+		arg.BandID,
+		arg.GenreID,
+		arg.Source,
+		arg.Confidence,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,316 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: ingest.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelVanishedShows = `-- name: CancelVanishedShows :many
+UPDATE shows
+SET status = 'cancelled',
+    updated_at = NOW()
+WHERE external_source = $1
+  AND status = 'scheduled'
+  AND date >= $2
+  AND date <= $3
+  AND NOT (external_id = ANY($4::text[]))
+RETURNING id
+`
+
+type CancelVanishedShowsParams struct {
+	ExternalSource *string            `json:"external_source"`
+	Date           pgtype.Timestamptz `json:"date"`
+	Date_2         pgtype.Timestamptz `json:"date_2"`
+	Column4        []string           `json:"column_4"`
+}
+
+// Cancel scheduled shows from a source that were not seen in a scrape window
+func (q *Queries) CancelVanishedShows(ctx context.Context, arg CancelVanishedShowsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, cancelVanishedShows,
+		arg.ExternalSource,
+		arg.Date,
+		arg.Date_2,
+		arg.Column4,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createScrapedShow = `-- name: CreateScrapedShow :one
+INSERT INTO shows (
+    venue_id,
+    title,
+    description,
+    image_url,
+    date,
+    price_min,
+    price_max,
+    ticket_url,
+    status,
+    source,
+    scraped_data,
+    external_source,
+    external_id,
+    last_seen_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, 'scraped', $10, $11, $12, NOW()
+) RETURNING id, status, created_at
+`
+
+type CreateScrapedShowParams struct {
+	VenueID        int32              `json:"venue_id"`
+	Title          *string            `json:"title"`
+	Description    *string            `json:"description"`
+	ImageUrl       *string            `json:"image_url"`
+	Date           pgtype.Timestamptz `json:"date"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	Status         *string            `json:"status"`
+	ScrapedData    []byte             `json:"scraped_data"`
+	ExternalSource *string            `json:"external_source"`
+	ExternalID     *string            `json:"external_id"`
+}
+
+type CreateScrapedShowRow struct {
+	ID        int32              `json:"id"`
+	Status    *string            `json:"status"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// Create a show from a scraped source event
+func (q *Queries) CreateScrapedShow(ctx context.Context, arg CreateScrapedShowParams) (CreateScrapedShowRow, error) {
+	row := q.db.QueryRow(ctx, createScrapedShow,
+		arg.VenueID,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.Date,
+		arg.PriceMin,
+		arg.PriceMax,
+		arg.TicketUrl,
+		arg.Status,
+		arg.ScrapedData,
+		arg.ExternalSource,
+		arg.ExternalID,
+	)
+	var i CreateScrapedShowRow
+	err := row.Scan(&i.ID, &i.Status, &i.CreatedAt)
+	return i, err
+}
+
+const deleteShowBands = `-- name: DeleteShowBands :exec
+DELETE FROM show_bands
+WHERE show_id = $1
+`
+
+// Remove a show's lineup (before relinking bands)
+func (q *Queries) DeleteShowBands(ctx context.Context, showID int32) error {
+	_, err := q.db.Exec(ctx, deleteShowBands, showID)
+	return err
+}
+
+const findRescheduleCandidate = `-- name: FindRescheduleCandidate :one
+SELECT s.id, s.status
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+WHERE s.venue_id = $1
+  AND sb.band_id = $2
+  AND sb.is_headliner = TRUE
+  AND s.status IN ('cancelled', 'postponed')
+  AND s.rescheduled_to IS NULL
+  AND s.id <> $3
+  AND s.date BETWEEN $4 AND $5
+  AND s.updated_at >= $6
+ORDER BY s.date DESC
+LIMIT 1
+`
+
+type FindRescheduleCandidateParams struct {
+	VenueID      int32              `json:"venue_id"`
+	BandID       int32              `json:"band_id"`
+	ID           int32              `json:"id"`
+	EarliestDate pgtype.Timestamptz `json:"earliest_date"`
+	LatestDate   pgtype.Timestamptz `json:"latest_date"`
+	ChangedSince pgtype.Timestamptz `json:"changed_since"`
+}
+
+type FindRescheduleCandidateRow struct {
+	ID     int32   `json:"id"`
+	Status *string `json:"status"`
+}
+
+// Find a cancelled/postponed show at the same venue with the same headliner
+// that has not yet been linked to a new date. Only shows dated within the
+// reschedule window of the new date and cancelled or postponed recently
+// qualify, so old cancellations are never relinked.
+func (q *Queries) FindRescheduleCandidate(ctx context.Context, arg FindRescheduleCandidateParams) (FindRescheduleCandidateRow, error) {
+	row := q.db.QueryRow(ctx, findRescheduleCandidate,
+		arg.VenueID,
+		arg.BandID,
+		arg.ID,
+		arg.EarliestDate,
+		arg.LatestDate,
+		arg.ChangedSince,
+	)
+	var i FindRescheduleCandidateRow
+	err := row.Scan(&i.ID, &i.Status)
+	return i, err
+}
+
+const getShowByExternalID = `-- name: GetShowByExternalID :one
+
+SELECT
+    id,
+    venue_id,
+    title,
+    date,
+    price_min,
+    price_max,
+    ticket_url,
+    status,
+    rescheduled_to
+FROM shows
+WHERE external_source = $1
+  AND external_id = $2
+LIMIT 1
+`
+
+type GetShowByExternalIDParams struct {
+	ExternalSource *string `json:"external_source"`
+	ExternalID     *string `json:"external_id"`
+}
+
+type GetShowByExternalIDRow struct {
+	ID            int32              `json:"id"`
+	VenueID       int32              `json:"venue_id"`
+	Title         *string            `json:"title"`
+	Date          pgtype.Timestamptz `json:"date"`
+	PriceMin      pgtype.Numeric     `json:"price_min"`
+	PriceMax      pgtype.Numeric     `json:"price_max"`
+	TicketUrl     *string            `json:"ticket_url"`
+	Status        *string            `json:"status"`
+	RescheduledTo *int32             `json:"rescheduled_to"`
+}
+
+// ============================================
+// INGESTION QUERIES
+// ============================================
+// Find a previously ingested show by its source identity
+func (q *Queries) GetShowByExternalID(ctx context.Context, arg GetShowByExternalIDParams) (GetShowByExternalIDRow, error) {
+	row := q.db.QueryRow(ctx, getShowByExternalID, arg.ExternalSource, arg.ExternalID)
+	var i GetShowByExternalIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		&i.Title,
+		&i.Date,
+		&i.PriceMin,
+		&i.PriceMax,
+		&i.TicketUrl,
+		&i.Status,
+		&i.RescheduledTo,
+	)
+	return i, err
+}
+
+const linkRescheduledShow = `-- name: LinkRescheduledShow :exec
+UPDATE shows
+SET status = 'postponed',
+    rescheduled_to = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND rescheduled_to IS NULL
+`
+
+type LinkRescheduledShowParams struct {
+	ID            int32  `json:"id"`
+	RescheduledTo *int32 `json:"rescheduled_to"`
+}
+
+// Mark a show as postponed and point it at its new date
+func (q *Queries) LinkRescheduledShow(ctx context.Context, arg LinkRescheduledShowParams) error {
+	_, err := q.db.Exec(ctx, linkRescheduledShow, arg.ID, arg.RescheduledTo)
+	return err
+}
+
+const updateScrapedShow = `-- name: UpdateScrapedShow :exec
+UPDATE shows
+SET title = $2,
+    description = $3,
+    image_url = $4,
+    date = $5,
+    price_min = $6,
+    price_max = $7,
+    ticket_url = $8,
+    scraped_data = $9,
+    last_seen_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateScrapedShowParams struct {
+	ID          int32              `json:"id"`
+	Title       *string            `json:"title"`
+	Description *string            `json:"description"`
+	ImageUrl    *string            `json:"image_url"`
+	Date        pgtype.Timestamptz `json:"date"`
+	PriceMin    pgtype.Numeric     `json:"price_min"`
+	PriceMax    pgtype.Numeric     `json:"price_max"`
+	TicketUrl   *string            `json:"ticket_url"`
+	ScrapedData []byte             `json:"scraped_data"`
+}
+
+// Refresh a scraped show with the latest source data
+func (q *Queries) UpdateScrapedShow(ctx context.Context, arg UpdateScrapedShowParams) error {
+	_, err := q.db.Exec(ctx, updateScrapedShow,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.Date,
+		arg.PriceMin,
+		arg.PriceMax,
+		arg.TicketUrl,
+		arg.ScrapedData,
+	)
+	return err
+}
+
+const updateShowStatus = `-- name: UpdateShowStatus :exec
+UPDATE shows
+SET status = $2,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateShowStatusParams struct {
+	ID     int32   `json:"id"`
+	Status *string `json:"status"`
+}
+
+// Set a show's status
+func (q *Queries) UpdateShowStatus(ctx context.Context, arg UpdateShowStatusParams) error {
+	_, err := q.db.Exec(ctx, updateShowStatus, arg.ID, arg.Status)
+	return err
+}
@@ -42,8 +42,45 @@ type Band struct {
 }
 
 type BandGenre struct {
+	BandID      int32              `json:"band_id"`
+	GenreID     int32              `json:"genre_id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	Source      string             `json:"source"`
+	Confidence  pgtype.Numeric     `json:"confidence"`
+	ConfirmedAt pgtype.Timestamptz `json:"confirmed_at"`
+	ConfirmedBy *string            `json:"confirmed_by"`
+}
+
+type BandGenreRejection struct {
+	BandID     int32              `json:"band_id"`
+	GenreID    int32              `json:"genre_id"`
+	RejectedAt pgtype.Timestamptz `json:"rejected_at"`
+	RejectedBy *string            `json:"rejected_by"`
+}
+
+type BandSimilarity struct {
+	BandID           int32              `json:"band_id"`
+	SimilarBandID    int32              `json:"similar_band_id"`
+	Score            float64            `json:"score"`
+	GenreScore       float64            `json:"genre_score"`
+	CoBillScore      float64            `json:"co_bill_score"`
+	VenueScore       float64            `json:"venue_score"`
+	HometownScore    float64            `json:"hometown_score"`
+	SharedGenreCount int32              `json:"shared_genre_count"`
+	SharedShowCount  int32              `json:"shared_show_count"`
+	SharedVenueCount int32              `json:"shared_venue_count"`
+	ComputedAt       pgtype.Timestamptz `json:"computed_at"`
+}
+
+type FollowedBand struct {
+	UserID    int32              `json:"user_id"`
 	BandID    int32              `json:"band_id"`
-	GenreID   int32              `json:"genre_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type FollowedVenue struct {
+	UserID    int32              `json:"user_id"`
+	VenueID   int32              `json:"venue_id"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
@@ -53,6 +90,54 @@ type Genre struct {
 	Slug        string             `json:"slug"`
 	Description *string            `json:"description"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ParentID    *int32             `json:"parent_id"`
+}
+
+type LoginToken struct {
+	TokenHash string             `json:"token_hash"`
+	Email     string             `json:"email"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Notification struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Kind      string             `json:"kind"`
+	ShowID    int32              `json:"show_id"`
+	BandID    *int32             `json:"band_id"`
+	VenueID   *int32             `json:"venue_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+}
+
+type NotificationChannel struct {
+	ID                 int32              `json:"id"`
+	UserID             int32              `json:"user_id"`
+	Kind               string             `json:"kind"`
+	Target             string             `json:"target"`
+	P256dh             *string            `json:"p256dh"`
+	AuthSecret         *string            `json:"auth_secret"`
+	Frequency          string             `json:"frequency"`
+	UnsubscribeToken   string             `json:"unsubscribe_token"`
+	LastNotificationID int32              `json:"last_notification_id"`
+	LastSentAt         pgtype.Timestamptz `json:"last_sent_at"`
+	DisabledAt         pgtype.Timestamptz `json:"disabled_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+type SavedShow struct {
+	UserID    int32              `json:"user_id"`
+	ShowID    int32              `json:"show_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Session struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    int32              `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Show struct {
@@ -73,6 +158,10 @@ type Show struct {
 	ScrapedData    []byte             `json:"scraped_data"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ExternalSource *string            `json:"external_source"`
+	ExternalID     *string            `json:"external_id"`
+	LastSeenAt     pgtype.Timestamptz `json:"last_seen_at"`
+	RescheduledTo  *int32             `json:"rescheduled_to"`
 }
 
 type ShowBand struct {
@@ -83,6 +172,31 @@ type ShowBand struct {
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 }
 
+type ShowRevision struct {
+	ID        int32              `json:"id"`
+	ShowID    int32              `json:"show_id"`
+	Field     string             `json:"field"`
+	OldValue  []byte             `json:"old_value"`
+	NewValue  []byte             `json:"new_value"`
+	Actor     *string            `json:"actor"`
+	Source    string             `json:"source"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ShowStreamEvent struct {
+	ID        int64              `json:"id"`
+	ShowID    int32              `json:"show_id"`
+	EventType string             `json:"event_type"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type User struct {
+	ID          int32              `json:"id"`
+	Email       string             `json:"email"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at"`
+}
+
 type Venue struct {
 	ID        int32              `json:"id"`
 	Name      string             `json:"name"`
@@ -117,3 +231,36 @@ type VenueScraper struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	SubscriptionID int32              `json:"subscription_id"`
+	EventID        int64              `json:"event_id"`
+	Status         string             `json:"status"`
+	AttemptCount   int32              `json:"attempt_count"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
+	ResponseStatus *int32             `json:"response_status"`
+	LastError      *string            `json:"last_error"`
+	ReplayOf       *int64             `json:"replay_of"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+}
+
+type WebhookEvent struct {
+	ID        int64              `json:"id"`
+	EventType string             `json:"event_type"`
+	Payload   json.RawMessage    `json:"payload"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID          int32              `json:"id"`
+	Url         string             `json:"url"`
+	Description *string            `json:"description"`
+	Events      []string           `json:"events"`
+	Secret      string             `json:"secret"`
+	Active      bool               `json:"active"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
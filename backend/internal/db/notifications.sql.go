@@ -0,0 +1,558 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: notifications.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advanceNotificationChannel = `-- name: AdvanceNotificationChannel :exec
+UPDATE notification_channels
+SET last_notification_id = $1,
+    last_sent_at = NOW()
+WHERE id = $2
+`
+
+type AdvanceNotificationChannelParams struct {
+	LastNotificationID int32 `json:"last_notification_id"`
+	ID                 int32 `json:"id"`
+}
+
+// Record a successful delivery up to and including a notification
+func (q *Queries) AdvanceNotificationChannel(ctx context.Context, arg AdvanceNotificationChannelParams) error {
+	_, err := q.db.Exec(ctx, advanceNotificationChannel, arg.LastNotificationID, arg.ID)
+	return err
+}
+
+const createSavedShowNotifications = `-- name: CreateSavedShowNotifications :execrows
+INSERT INTO notifications (user_id, kind, show_id)
+SELECT ss.user_id, $1::text, ss.show_id
+FROM saved_shows ss
+WHERE ss.show_id = ANY($2::int[])
+ON CONFLICT (user_id, kind, show_id) DO NOTHING
+`
+
+type CreateSavedShowNotificationsParams struct {
+	Kind    string  `json:"kind"`
+	ShowIds []int32 `json:"show_ids"`
+}
+
+// Notify everyone who saved the given shows (cancellations, postponements)
+func (q *Queries) CreateSavedShowNotifications(ctx context.Context, arg CreateSavedShowNotificationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createSavedShowNotifications, arg.Kind, arg.ShowIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createShowAnnouncedNotifications = `-- name: CreateShowAnnouncedNotifications :execrows
+
+WITH target AS (
+    SELECT id, venue_id
+    FROM shows
+    WHERE id = $1
+      AND status = 'scheduled'
+      AND date >= NOW()
+),
+followers AS (
+    SELECT
+        fb.user_id,
+        sb.band_id,
+        NULL::int AS venue_id,
+        0 AS rank,
+        COALESCE(sb.performance_order, 0) AS billing
+    FROM target t
+    JOIN show_bands sb ON sb.show_id = t.id
+    JOIN followed_bands fb ON fb.band_id = sb.band_id
+    UNION ALL
+    SELECT
+        fv.user_id,
+        NULL::int AS band_id,
+        fv.venue_id,
+        1 AS rank,
+        0 AS billing
+    FROM target t
+    JOIN followed_venues fv ON fv.venue_id = t.venue_id
+)
+INSERT INTO notifications (user_id, kind, show_id, band_id, venue_id)
+SELECT DISTINCT ON (f.user_id)
+    f.user_id,
+    'show_announced',
+    $1,
+    f.band_id,
+    f.venue_id
+FROM followers f
+ORDER BY f.user_id, f.rank, f.billing DESC
+ON CONFLICT (user_id, kind, show_id) DO NOTHING
+`
+
+// ============================================
+// NOTIFICATION QUERIES
+// ============================================
+// Notify followers of the show's bands and venue about an upcoming scheduled
+// show. Each user gets one notification, attributed to the top-billed band
+// they follow, else to the venue. Safe to call again after lineup changes.
+func (q *Queries) CreateShowAnnouncedNotifications(ctx context.Context, showID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, createShowAnnouncedNotifications, showID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createShowReminders = `-- name: CreateShowReminders :execrows
+INSERT INTO notifications (user_id, kind, show_id)
+SELECT ss.user_id, 'show_reminder', s.id
+FROM saved_shows ss
+JOIN shows s ON s.id = ss.show_id
+WHERE s.status = 'scheduled'
+  AND s.date > NOW()
+  AND s.date <= NOW() + INTERVAL '1 day'
+ON CONFLICT (user_id, kind, show_id) DO NOTHING
+`
+
+// Remind users about saved shows starting within the next day
+func (q *Queries) CreateShowReminders(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, createShowReminders)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteNotificationChannel = `-- name: DeleteNotificationChannel :execrows
+DELETE FROM notification_channels
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeleteNotificationChannelParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+// Remove one of a user's channels
+func (q *Queries) DeleteNotificationChannel(ctx context.Context, arg DeleteNotificationChannelParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteNotificationChannel, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const disableNotificationChannel = `-- name: DisableNotificationChannel :exec
+UPDATE notification_channels
+SET disabled_at = NOW()
+WHERE id = $1
+`
+
+// Disable a channel whose destination no longer exists (e.g. an expired push subscription)
+func (q *Queries) DisableNotificationChannel(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, disableNotificationChannel, id)
+	return err
+}
+
+const disableNotificationChannelByToken = `-- name: DisableNotificationChannelByToken :one
+UPDATE notification_channels
+SET disabled_at = COALESCE(disabled_at, NOW())
+WHERE unsubscribe_token = $1
+RETURNING kind, target
+`
+
+type DisableNotificationChannelByTokenRow struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
+
+// Unsubscribe a channel from its unsubscribe token (idempotent)
+func (q *Queries) DisableNotificationChannelByToken(ctx context.Context, unsubscribeToken string) (DisableNotificationChannelByTokenRow, error) {
+	row := q.db.QueryRow(ctx, disableNotificationChannelByToken, unsubscribeToken)
+	var i DisableNotificationChannelByTokenRow
+	err := row.Scan(&i.Kind, &i.Target)
+	return i, err
+}
+
+const listDueNotificationChannels = `-- name: ListDueNotificationChannels :many
+
+SELECT
+    c.id,
+    c.user_id,
+    c.kind,
+    c.target,
+    c.p256dh,
+    c.auth_secret,
+    c.unsubscribe_token,
+    c.last_notification_id
+FROM notification_channels c
+WHERE c.disabled_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM notifications n
+      WHERE n.user_id = c.user_id
+        AND n.id > c.last_notification_id
+  )
+  AND (
+      c.frequency = 'instant'
+      OR COALESCE(c.last_sent_at, c.created_at) <= NOW() - INTERVAL '1 day'
+  )
+ORDER BY c.id
+`
+
+type ListDueNotificationChannelsRow struct {
+	ID                 int32   `json:"id"`
+	UserID             int32   `json:"user_id"`
+	Kind               string  `json:"kind"`
+	Target             string  `json:"target"`
+	P256dh             *string `json:"p256dh"`
+	AuthSecret         *string `json:"auth_secret"`
+	UnsubscribeToken   string  `json:"unsubscribe_token"`
+	LastNotificationID int32   `json:"last_notification_id"`
+}
+
+// ============================================
+// DELIVERY
+// ============================================
+// Enabled channels with undelivered notifications: instant channels always,
+// daily channels once a day since their last digest (or creation)
+func (q *Queries) ListDueNotificationChannels(ctx context.Context) ([]ListDueNotificationChannelsRow, error) {
+	rows, err := q.db.Query(ctx, listDueNotificationChannels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDueNotificationChannelsRow{}
+	for rows.Next() {
+		var i ListDueNotificationChannelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Target,
+			&i.P256dh,
+			&i.AuthSecret,
+			&i.UnsubscribeToken,
+			&i.LastNotificationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationChannels = `-- name: ListNotificationChannels :many
+SELECT
+    id,
+    kind,
+    target,
+    frequency,
+    last_sent_at,
+    created_at
+FROM notification_channels
+WHERE user_id = $1
+  AND disabled_at IS NULL
+ORDER BY id
+`
+
+type ListNotificationChannelsRow struct {
+	ID         int32              `json:"id"`
+	Kind       string             `json:"kind"`
+	Target     string             `json:"target"`
+	Frequency  string             `json:"frequency"`
+	LastSentAt pgtype.Timestamptz `json:"last_sent_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+// A user's enabled channels
+func (q *Queries) ListNotificationChannels(ctx context.Context, userID int32) ([]ListNotificationChannelsRow, error) {
+	rows, err := q.db.Query(ctx, listNotificationChannels, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListNotificationChannelsRow{}
+	for rows.Next() {
+		var i ListNotificationChannelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.Target,
+			&i.Frequency,
+			&i.LastSentAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingNotifications = `-- name: ListPendingNotifications :many
+SELECT
+    n.id,
+    n.kind,
+    s.id AS show_id,
+    s.title AS show_title,
+    s.date AS show_date,
+    v.name AS venue_name,
+    b.name AS band_name,
+    COALESCE((
+        SELECT hb.name
+        FROM show_bands sb
+        JOIN bands hb ON hb.id = sb.band_id
+        WHERE sb.show_id = s.id
+        ORDER BY sb.is_headliner DESC NULLS LAST, sb.performance_order DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS headliner_name
+FROM notifications n
+JOIN shows s ON s.id = n.show_id
+JOIN venues v ON v.id = s.venue_id
+LEFT JOIN bands b ON b.id = n.band_id
+WHERE n.user_id = $1
+  AND n.id > $2
+ORDER BY n.id ASC
+LIMIT $3
+`
+
+type ListPendingNotificationsParams struct {
+	UserID  int32 `json:"user_id"`
+	AfterID int32 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+type ListPendingNotificationsRow struct {
+	ID            int32              `json:"id"`
+	Kind          string             `json:"kind"`
+	ShowID        int32              `json:"show_id"`
+	ShowTitle     *string            `json:"show_title"`
+	ShowDate      pgtype.Timestamptz `json:"show_date"`
+	VenueName     string             `json:"venue_name"`
+	BandName      *string            `json:"band_name"`
+	HeadlinerName string             `json:"headliner_name"`
+}
+
+// A user's notifications after a channel's cursor, oldest first
+func (q *Queries) ListPendingNotifications(ctx context.Context, arg ListPendingNotificationsParams) ([]ListPendingNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingNotifications, arg.UserID, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPendingNotificationsRow{}
+	for rows.Next() {
+		var i ListPendingNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.ShowID,
+			&i.ShowTitle,
+			&i.ShowDate,
+			&i.VenueName,
+			&i.BandName,
+			&i.HeadlinerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserNotifications = `-- name: ListUserNotifications :many
+SELECT
+    n.id,
+    n.kind,
+    n.created_at,
+    n.read_at,
+    s.id AS show_id,
+    s.title AS show_title,
+    s.date AS show_date,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    b.name AS band_name,
+    b.slug AS band_slug,
+    COALESCE((
+        SELECT hb.name
+        FROM show_bands sb
+        JOIN bands hb ON hb.id = sb.band_id
+        WHERE sb.show_id = s.id
+        ORDER BY sb.is_headliner DESC NULLS LAST, sb.performance_order DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS headliner_name,
+    COUNT(*) OVER() AS total_count
+FROM notifications n
+JOIN shows s ON s.id = n.show_id
+JOIN venues v ON v.id = s.venue_id
+LEFT JOIN bands b ON b.id = n.band_id
+WHERE n.user_id = $1
+ORDER BY n.id DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListUserNotificationsParams struct {
+	UserID int32 `json:"user_id"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListUserNotificationsRow struct {
+	ID            int32              `json:"id"`
+	Kind          string             `json:"kind"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ReadAt        pgtype.Timestamptz `json:"read_at"`
+	ShowID        int32              `json:"show_id"`
+	ShowTitle     *string            `json:"show_title"`
+	ShowDate      pgtype.Timestamptz `json:"show_date"`
+	VenueName     string             `json:"venue_name"`
+	VenueSlug     string             `json:"venue_slug"`
+	BandName      *string            `json:"band_name"`
+	BandSlug      *string            `json:"band_slug"`
+	HeadlinerName string             `json:"headliner_name"`
+	TotalCount    int64              `json:"total_count"`
+}
+
+// A user's notifications, newest first
+func (q *Queries) ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]ListUserNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listUserNotifications, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserNotificationsRow{}
+	for rows.Next() {
+		var i ListUserNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.CreatedAt,
+			&i.ReadAt,
+			&i.ShowID,
+			&i.ShowTitle,
+			&i.ShowDate,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.BandName,
+			&i.BandSlug,
+			&i.HeadlinerName,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationsRead = `-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1
+  AND read_at IS NULL
+`
+
+// Mark all of a user's unread notifications read
+func (q *Queries) MarkNotificationsRead(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationsRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertNotificationChannel = `-- name: UpsertNotificationChannel :one
+
+INSERT INTO notification_channels (
+    user_id,
+    kind,
+    target,
+    p256dh,
+    auth_secret,
+    frequency,
+    unsubscribe_token,
+    last_notification_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    COALESCE((SELECT MAX(id) FROM notifications WHERE user_id = $1), 0)::int
+)
+ON CONFLICT (user_id, kind, target) DO UPDATE SET
+    p256dh = EXCLUDED.p256dh,
+    auth_secret = EXCLUDED.auth_secret,
+    frequency = EXCLUDED.frequency,
+    last_notification_id = CASE
+        WHEN notification_channels.disabled_at IS NOT NULL THEN EXCLUDED.last_notification_id
+        ELSE notification_channels.last_notification_id
+    END,
+    disabled_at = NULL
+RETURNING id, kind, target, frequency, last_sent_at, created_at
+`
+
+type UpsertNotificationChannelParams struct {
+	UserID           int32   `json:"user_id"`
+	Kind             string  `json:"kind"`
+	Target           string  `json:"target"`
+	P256dh           *string `json:"p256dh"`
+	AuthSecret       *string `json:"auth_secret"`
+	Frequency        string  `json:"frequency"`
+	UnsubscribeToken string  `json:"unsubscribe_token"`
+}
+
+type UpsertNotificationChannelRow struct {
+	ID         int32              `json:"id"`
+	Kind       string             `json:"kind"`
+	Target     string             `json:"target"`
+	Frequency  string             `json:"frequency"`
+	LastSentAt pgtype.Timestamptz `json:"last_sent_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+// ============================================
+// CHANNEL MANAGEMENT
+// ============================================
+// Add a channel, or update an existing one with the same target. A new or
+// re-enabled channel starts after the user's latest notification, so it does
+// not replay old ones.
+func (q *Queries) UpsertNotificationChannel(ctx context.Context, arg UpsertNotificationChannelParams) (UpsertNotificationChannelRow, error) {
+	row := q.db.QueryRow(ctx, upsertNotificationChannel,
+		arg.UserID,
+		arg.Kind,
+		arg.Target,
+		arg.P256dh,
+		arg.AuthSecret,
+		arg.Frequency,
+		arg.UnsubscribeToken,
+	)
+	var i UpsertNotificationChannelRow
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Target,
+		&i.Frequency,
+		&i.LastSentAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -6,27 +6,140 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	// Add a genre to a band
 	AddBandGenre(ctx context.Context, arg AddBandGenreParams) error
+	// Record a successful delivery up to and including a notification
+	AdvanceNotificationChannel(ctx context.Context, arg AdvanceNotificationChannelParams) error
+	// Typo-tolerant suggestions across upcoming shows, bands, venues and genres.
+	// A name matches when it starts with the input (or has a word that does) or is
+	// trigram-similar to it (pg_trgm word_similarity, so "orang peel" finds
+	// "The Orange Peel"). @prefix is the LIKE-escaped input with a trailing '%'.
+	// Shows match on their display title: the title, or for untitled (e.g.
+	// band-submitted) shows the lineup, headliner first, or else the venue name.
+	// Each type is capped at @per_type rows; prefix matches score above fuzzy ones.
+	Autocomplete(ctx context.Context, arg AutocompleteParams) ([]AutocompleteRow, error)
 	// Check if band exists by slug
 	BandExists(ctx context.Context, slug string) (bool, error)
-	// Count bands by genre (for pagination)
+	// Cancel scheduled shows from a source that were not seen in a scrape window
+	CancelVanishedShows(ctx context.Context, arg CancelVanishedShowsParams) ([]int32, error)
+	// ============================================
+	// DELIVERY
+	// ============================================
+	// Lease pending deliveries that are due so concurrent workers (API and
+	// scraper) skip them. An unfinished lease expires after five minutes and the
+	// delivery is picked up again.
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int32) ([]ClaimDueWebhookDeliveriesRow, error)
+	// Mark scheduled shows as completed once their venue-local date has passed,
+	// recording a status revision for each. Returns the number of shows completed.
+	// SKIP LOCKED lets concurrent runs (API ticker + scraper job) proceed without blocking.
+	CompletePastShows(ctx context.Context) (int64, error)
+	// Accept an inferred genre. Confirming twice keeps the first confirmation.
+	ConfirmBandGenre(ctx context.Context, arg ConfirmBandGenreParams) (ConfirmBandGenreRow, error)
+	// Mark an unused, unexpired magic-link token used and return its email.
+	// Returns no row when the token is unknown, expired or already used.
+	ConsumeLoginToken(ctx context.Context, tokenHash string) (string, error)
+	// Count bands by genre, including subgenres (for pagination)
 	CountBandsByGenre(ctx context.Context, dollar_1 []string) (int64, error)
 	// Count bands in a genre (for pagination)
 	CountBandsInGenre(ctx context.Context, genreID int32) (int64, error)
-	// Count upcoming shows by genre (for genre filter)
+	// Count upcoming shows by genre, including subgenres (for genre filter)
 	CountShowsByGenre(ctx context.Context, dollar_1 []string) (int64, error)
 	// Create a new band
 	CreateBand(ctx context.Context, arg CreateBandParams) (CreateBandRow, error)
 	// Create a new band with all fields
 	CreateBandFull(ctx context.Context, arg CreateBandFullParams) (Band, error)
+	// Record a band event with a snapshot of the band and queue deliveries, like
+	// CreateShowWebhookEvent
+	CreateBandWebhookEvent(ctx context.Context, arg CreateBandWebhookEventParams) (int64, error)
+	// ============================================
+	// USER ACCOUNT QUERIES
+	// ============================================
+	// Store a magic-link token (hashed) for an email address, unless the email
+	// already has an unused, unexpired one. Returns 0 rows affected in that case.
+	CreateLoginToken(ctx context.Context, arg CreateLoginTokenParams) (int64, error)
+	// Notify everyone who saved the given shows (cancellations, postponements)
+	CreateSavedShowNotifications(ctx context.Context, arg CreateSavedShowNotificationsParams) (int64, error)
+	// Create a show from a scraped source event
+	CreateScrapedShow(ctx context.Context, arg CreateScrapedShowParams) (CreateScrapedShowRow, error)
+	// Store a bearer session (hashed) for a user
+	CreateSession(ctx context.Context, arg CreateSessionParams) error
 	// Create a new show (band submission)
 	CreateShow(ctx context.Context, arg CreateShowParams) (CreateShowRow, error)
+	// ============================================
+	// NOTIFICATION QUERIES
+	// ============================================
+	// Notify followers of the show's bands and venue about an upcoming scheduled
+	// show. Each user gets one notification, attributed to the top-billed band
+	// they follow, else to the venue. Safe to call again after lineup changes.
+	CreateShowAnnouncedNotifications(ctx context.Context, showID int32) (int64, error)
 	// Link a band to a show
 	CreateShowBand(ctx context.Context, arg CreateShowBandParams) error
+	// Remind users about saved shows starting within the next day
+	CreateShowReminders(ctx context.Context) (int64, error)
+	// ============================================
+	// SHOW REVISION QUERIES
+	// ============================================
+	// Record a single field change on a show
+	CreateShowRevision(ctx context.Context, arg CreateShowRevisionParams) error
+	// ============================================
+	// SHOW STREAM QUERIES
+	// ============================================
+	// Record a show event and wake stream listeners on every API replica. The
+	// notification is delivered when the surrounding transaction commits.
+	CreateShowStreamEvent(ctx context.Context, arg CreateShowStreamEventParams) error
+	// ============================================
+	// WEBHOOK QUERIES
+	// ============================================
+	// Record a show event with a snapshot of the show and queue a delivery to
+	// every active subscription to the event type. Nothing is stored when no one
+	// subscribes. Returns the number of deliveries queued.
+	CreateShowWebhookEvent(ctx context.Context, arg CreateShowWebhookEventParams) (int64, error)
+	// Record the same status change for many shows (bulk cancellations)
+	CreateStatusRevisions(ctx context.Context, arg CreateStatusRevisionsParams) error
+	// ============================================
+	// SUBSCRIPTION MANAGEMENT
+	// ============================================
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (CreateWebhookSubscriptionRow, error)
+	// Clear precomputed similarities before a full refresh
+	DeleteBandSimilarities(ctx context.Context) error
+	// Purge expired magic-link tokens and sessions, returning how many were removed
+	DeleteExpiredAuthTokens(ctx context.Context) (int64, error)
+	// Remove one of a user's channels
+	DeleteNotificationChannel(ctx context.Context, arg DeleteNotificationChannelParams) (int64, error)
+	// Events older than the resume window are no longer needed
+	DeleteOldShowStreamEvents(ctx context.Context, before pgtype.Timestamptz) (int64, error)
+	// Drop events (and their delivery log) older than the retention window once
+	// nothing is left to deliver
+	DeleteOldWebhookEvents(ctx context.Context, before pgtype.Timestamptz) (int64, error)
+	// Sign out a single session
+	DeleteSession(ctx context.Context, tokenHash string) error
+	// Remove a show's lineup (before relinking bands)
+	DeleteShowBands(ctx context.Context, showID int32) error
+	DeleteWebhookSubscription(ctx context.Context, id int32) (int64, error)
+	// Disable a channel whose destination no longer exists (e.g. an expired push subscription)
+	DisableNotificationChannel(ctx context.Context, id int32) error
+	// Unsubscribe a channel from its unsubscribe token (idempotent)
+	DisableNotificationChannelByToken(ctx context.Context, unsubscribeToken string) (DisableNotificationChannelByTokenRow, error)
+	// Find a show at the venue on a venue-local day (@day) sharing a band
+	// (case-insensitive) with a submission, to reject duplicate submissions
+	FindDuplicateShow(ctx context.Context, arg FindDuplicateShowParams) (int32, error)
+	// Find a cancelled/postponed show at the same venue with the same headliner
+	// that has not yet been linked to a new date. Only shows dated within the
+	// reschedule window of the new date and cancelled or postponed recently
+	// qualify, so old cancellations are never relinked.
+	FindRescheduleCandidate(ctx context.Context, arg FindRescheduleCandidateParams) (FindRescheduleCandidateRow, error)
+	// ============================================
+	// FOLLOWS
+	// ============================================
+	// Follow a band by slug (idempotent). Returns no row when the band does not exist.
+	FollowBand(ctx context.Context, arg FollowBandParams) (int32, error)
+	// Follow a venue by slug (idempotent). Returns no row when the venue does not exist.
+	FollowVenue(ctx context.Context, arg FollowVenueParams) (int32, error)
 	// Check if genre exists by ID
 	GenreExists(ctx context.Context, id int32) (bool, error)
 	// Check if genre exists by slug
@@ -46,31 +159,71 @@ type Querier interface {
 	GetBandGenresBatch(ctx context.Context, dollar_1 []int32) ([]GetBandGenresBatchRow, error)
 	// Get genres for a band (used when fetching show details)
 	GetBandGenresForShow(ctx context.Context, bandID int32) ([]GetBandGenresForShowRow, error)
+	// ============================================
+	// GENRE INFERENCE QUERIES
+	// ============================================
+	// Get the band fields the classifier reads and how many trusted genres it already has
+	GetBandInferenceInput(ctx context.Context, id int32) (GetBandInferenceInputRow, error)
+	// Get play history totals for a band (past shows only)
+	GetBandShowSummary(ctx context.Context, bandID int32) (GetBandShowSummaryRow, error)
 	// Get upcoming shows for a band
 	GetBandUpcomingShows(ctx context.Context, bandID int32) ([]GetBandUpcomingShowsRow, error)
+	// Count past shows per venue for a band
+	GetBandVenuePlayCounts(ctx context.Context, bandID int32) ([]GetBandVenuePlayCountsRow, error)
 	// Get bands for a specific genre (for genre detail page)
 	GetBandsByGenre(ctx context.Context, arg GetBandsByGenreParams) ([]GetBandsByGenreRow, error)
 	// ============================================
+	// RECOMMENDATION QUERIES
+	// ============================================
+	// Resolve seed band slugs
+	GetBandsBySlugs(ctx context.Context, slugs []string) ([]GetBandsBySlugsRow, error)
+	// Newest updated_at across all shows, venues and bands, for responses that
+	// aggregate the whole catalog (e.g. genre show counts)
+	GetCatalogLastModified(ctx context.Context) (pgtype.Timestamptz, error)
+	// Share of the band's co-billed bands (with trusted genres) carrying each genre.
+	// Trusted genres are manual or admin-confirmed, so unconfirmed inferences
+	// never feed back into later ones.
+	GetCoBilledGenreShares(ctx context.Context, bandID int32) ([]GetCoBilledGenreSharesRow, error)
+	// ============================================
 	// GENRES QUERIES
 	// ============================================
 	// Get genre by ID
 	GetGenre(ctx context.Context, id int32) (Genre, error)
-	// Get genre by slug
-	GetGenreBySlug(ctx context.Context, slug string) (Genre, error)
+	// Get genre by slug with its parent genre
+	GetGenreBySlug(ctx context.Context, slug string) (GetGenreBySlugRow, error)
+	// Resolve seed genre slugs
+	GetGenresBySlugs(ctx context.Context, slugs []string) ([]GetGenresBySlugsRow, error)
+	// ============================================
+	// HEALTH QUERIES
+	// ============================================
+	// When a scrape last succeeded: the newest venue scraper success or show
+	// sighting. NULL when nothing has been scraped yet.
+	GetLastScrapeAt(ctx context.Context) (pgtype.Timestamptz, error)
+	// Cursor for a client that starts without Last-Event-ID
+	GetLatestShowStreamEventID(ctx context.Context) (int64, error)
+	// Resolve an unexpired session to its user
+	GetSessionUser(ctx context.Context, tokenHash string) (User, error)
 	// Get all bands for a show with their genres
 	GetShowBands(ctx context.Context, showID int32) ([]GetShowBandsRow, error)
 	// Get bands for shows at a venue (batch load for venue detail)
 	GetShowBandsForVenue(ctx context.Context, dollar_1 []int32) ([]GetShowBandsForVenueRow, error)
 	// ============================================
+	// INGESTION QUERIES
+	// ============================================
+	// Find a previously ingested show by its source identity
+	GetShowByExternalID(ctx context.Context, arg GetShowByExternalIDParams) (GetShowByExternalIDRow, error)
+	// ============================================
 	// SHOWS QUERIES
 	// ============================================
 	// Get single show with venue info
 	GetShowByID(ctx context.Context, id int32) (GetShowByIDRow, error)
-	// Find bands with shared genres (similar bands)
-	// Excludes the source band and orders by number of shared genres
-	GetSimilarBands(ctx context.Context, arg GetSimilarBandsParams) ([]GetSimilarBandsRow, error)
+	// Newest updated_at across shows, their venues and their lineups (for the
+	// Last-Modified header). NULL when no shows are given.
+	GetShowsLastModified(ctx context.Context, ids []int32) (pgtype.Timestamptz, error)
 	// Find similar bands with their shared genre names
 	GetSimilarBandsWithGenres(ctx context.Context, arg GetSimilarBandsWithGenresParams) ([]GetSimilarBandsWithGenresRow, error)
+	// Get a user with counts of what they save and follow
+	GetUser(ctx context.Context, id int32) (GetUserRow, error)
 	// ============================================
 	// VENUES QUERIES
 	// ============================================
@@ -78,30 +231,101 @@ type Querier interface {
 	GetVenue(ctx context.Context, id int32) (Venue, error)
 	// Get venue by slug for detail page
 	GetVenueBySlug(ctx context.Context, slug string) (Venue, error)
+	// Count distinct shows per genre at a venue
+	GetVenueGenreMix(ctx context.Context, venueID int32) ([]GetVenueGenreMixRow, error)
+	// Share of other bands (with trusted genres) playing the band's venues carrying each genre
+	GetVenueGenreShares(ctx context.Context, bandID int32) ([]GetVenueGenreSharesRow, error)
+	// Get show totals and average ticket price for a venue (for venue stats)
+	GetVenueShowSummary(ctx context.Context, venueID int32) (GetVenueShowSummaryRow, error)
+	// Count shows per calendar month (venue-local time) since the given date
+	GetVenueShowsPerMonth(ctx context.Context, arg GetVenueShowsPerMonthParams) ([]GetVenueShowsPerMonthRow, error)
+	// Get the bands that have played a venue most often
+	GetVenueTopBands(ctx context.Context, arg GetVenueTopBandsParams) ([]GetVenueTopBandsRow, error)
 	// Get upcoming shows for a venue (for venue detail page)
 	GetVenueUpcomingShows(ctx context.Context, arg GetVenueUpcomingShowsParams) ([]GetVenueUpcomingShowsRow, error)
-	// Search bands only (for search endpoint's bands section)
+	// Newest updated_at across venues, their shows and the bands on them (for the
+	// Last-Modified header). NULL when no venues are given.
+	GetVenuesLastModified(ctx context.Context, ids []int32) (pgtype.Timestamptz, error)
+	// Search published articles by title, excerpt and content, best match first.
+	// With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
+	GlobalSearchArticles(ctx context.Context, arg GlobalSearchArticlesParams) ([]GlobalSearchArticlesRow, error)
+	// Search bands by name and bio, best match first (name matches outrank bio matches).
+	// With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
 	GlobalSearchBands(ctx context.Context, arg GlobalSearchBandsParams) ([]GlobalSearchBandsRow, error)
-	// Search shows only (for search endpoint's shows section)
+	// Search upcoming shows by title, lineup band names, venue name and description, best match first.
+	// The query is a to_tsquery expression with prefix terms (e.g. 'avet:*') built by the handler.
+	// With @highlight, also returns (otherwise empty strings) the first field that
+	// matches any query term and a ts_headline snippet of it. A row can match with
+	// its terms spread across fields, so no single field need match the whole query.
 	GlobalSearchShows(ctx context.Context, arg GlobalSearchShowsParams) ([]GlobalSearchShowsRow, error)
-	// Search venues only (for search endpoint's venues section)
+	// Search venues by name, best match first.
+	// With @highlight, also returns (otherwise empty strings) the field and a ts_headline snippet.
 	GlobalSearchVenues(ctx context.Context, arg GlobalSearchVenuesParams) ([]GlobalSearchVenuesRow, error)
+	// Bulk load precomputed similarities
+	InsertBandSimilarities(ctx context.Context, arg []InsertBandSimilaritiesParams) (int64, error)
+	// Mark a show as postponed and point it at its new date
+	LinkRescheduledShow(ctx context.Context, arg LinkRescheduledShowParams) error
+	// List past shows for a band with pagination (most recent first)
+	ListBandPastShows(ctx context.Context, arg ListBandPastShowsParams) ([]ListBandPastShowsRow, error)
+	// Get a band's precomputed similar bands, best match first
+	ListBandSimilarities(ctx context.Context, arg ListBandSimilaritiesParams) ([]ListBandSimilaritiesRow, error)
+	// List upcoming shows for a band with pagination
+	ListBandUpcomingShows(ctx context.Context, arg ListBandUpcomingShowsParams) ([]ListBandUpcomingShowsRow, error)
 	// List bands with pagination
 	ListBands(ctx context.Context, arg ListBandsParams) ([]ListBandsRow, error)
-	// List bands filtered by genre slug(s) with pagination
+	// List bands filtered by genre slug(s), including subgenres, with pagination
 	ListBandsByGenre(ctx context.Context, arg ListBandsByGenreParams) ([]ListBandsByGenreRow, error)
+	// List bands that have no genres at all (inference backfill). Bands with
+	// rejected suggestions have already been reviewed and are skipped.
+	ListBandsWithoutGenres(ctx context.Context) ([]int32, error)
+	// ============================================
+	// DELIVERY
+	// ============================================
+	// Enabled channels with undelivered notifications: instant channels always,
+	// daily channels once a day since their last digest (or creation)
+	ListDueNotificationChannels(ctx context.Context) ([]ListDueNotificationChannelsRow, error)
+	// List the bands a user follows, by name
+	ListFollowedBands(ctx context.Context, userID int32) ([]ListFollowedBandsRow, error)
+	// List the venues a user follows, by name
+	ListFollowedVenues(ctx context.Context, userID int32) ([]ListFollowedVenuesRow, error)
 	// Shows that are free (price_min is NULL or 0)
 	ListFreeShows(ctx context.Context, arg ListFreeShowsParams) ([]ListFreeShowsRow, error)
+	// List direct subgenres of a genre
+	ListGenreChildren(ctx context.Context, parentID *int32) ([]ListGenreChildrenRow, error)
+	// List unconfirmed inferred genres with pagination (most confident first)
+	ListGenreSuggestions(ctx context.Context, arg ListGenreSuggestionsParams) ([]ListGenreSuggestionsRow, error)
 	// List all genres ordered by name
-	ListGenres(ctx context.Context) ([]Genre, error)
+	ListGenres(ctx context.Context) ([]ListGenresRow, error)
 	// List genres with count of bands
 	ListGenresWithBandCount(ctx context.Context) ([]ListGenresWithBandCountRow, error)
-	// List genres with count of upcoming shows
+	// List genres with count of upcoming shows (direct genre only, not subgenres)
 	ListGenresWithShowCount(ctx context.Context) ([]ListGenresWithShowCountRow, error)
+	// List genres the classifier can propose
+	ListInferenceGenres(ctx context.Context) ([]ListInferenceGenresRow, error)
+	// A user's enabled channels
+	ListNotificationChannels(ctx context.Context, userID int32) ([]ListNotificationChannelsRow, error)
+	// A user's notifications after a channel's cursor, oldest first
+	ListPendingNotifications(ctx context.Context, arg ListPendingNotificationsParams) ([]ListPendingNotificationsRow, error)
+	// Collect why each upcoming show matches the seeds, one row per piece of evidence:
+	//   seed_band    - a seed band is on the bill
+	//   similar_band - a band similar to a seed band (band_similarity) is on the bill
+	//   genre        - a band on the bill plays a seed genre or one of its subgenres
+	// The handler combines evidence into a score and reasons per show.
+	ListRecommendationEvidence(ctx context.Context, arg ListRecommendationEvidenceParams) ([]ListRecommendationEvidenceRow, error)
+	// List a user's saved shows, soonest first (past shows included)
+	ListSavedShows(ctx context.Context, arg ListSavedShowsParams) ([]ListSavedShowsRow, error)
+	// List changes to a show with pagination (most recent first)
+	ListShowRevisions(ctx context.Context, arg ListShowRevisionsParams) ([]ListShowRevisionsRow, error)
+	// Events after a cursor with the current state of their shows, optionally
+	// limited to venue slugs, regions and genre slugs (including subgenres).
+	// Empty filters match everything.
+	ListShowStreamEvents(ctx context.Context, arg ListShowStreamEventsParams) ([]ListShowStreamEventsRow, error)
 	// Filter shows by date range (inclusive)
 	ListShowsByDateRange(ctx context.Context, arg ListShowsByDateRangeParams) ([]ListShowsByDateRangeRow, error)
-	// Filter shows by genre slug(s) - shows with bands matching any of the genres
+	// Filter shows by genre slug(s) - shows with bands matching any of the genres or their subgenres
 	ListShowsByGenre(ctx context.Context, arg ListShowsByGenreParams) ([]ListShowsByGenreRow, error)
+	// Get shows by ID (e.g. ranked recommendations); the caller restores its own order
+	ListShowsByIDs(ctx context.Context, ids []int32) ([]ListShowsByIDsRow, error)
 	// Filter shows by price range
 	ListShowsByPriceRange(ctx context.Context, arg ListShowsByPriceRangeParams) ([]ListShowsByPriceRangeRow, error)
 	// Filter shows by region(s)
@@ -115,30 +339,106 @@ type Querier interface {
 	// List upcoming scheduled shows with pagination
 	// Used for homepage and general show listing
 	ListUpcomingShows(ctx context.Context, arg ListUpcomingShowsParams) ([]ListUpcomingShowsRow, error)
+	// ============================================
+	// FEED
+	// ============================================
+	// Upcoming scheduled shows the user saved, or featuring a band or at a venue
+	// they follow, soonest first. Flags say why each show is in the feed.
+	ListUserFeed(ctx context.Context, arg ListUserFeedParams) ([]ListUserFeedRow, error)
+	// A user's notifications, newest first
+	ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]ListUserNotificationsRow, error)
+	// List past shows for a venue with pagination (most recent first)
+	ListVenuePastShows(ctx context.Context, arg ListVenuePastShowsParams) ([]ListVenuePastShowsRow, error)
+	// List upcoming shows for a venue with pagination
+	ListVenueUpcomingShows(ctx context.Context, arg ListVenueUpcomingShowsParams) ([]ListVenueUpcomingShowsRow, error)
 	// List all venues ordered by name
 	ListVenues(ctx context.Context) ([]Venue, error)
 	// List venues filtered by region(s)
 	ListVenuesByRegion(ctx context.Context, dollar_1 []string) ([]ListVenuesByRegionRow, error)
 	// List venues with count of upcoming scheduled shows
 	ListVenuesWithShowCount(ctx context.Context) ([]ListVenuesWithShowCountRow, error)
+	// The delivery log, newest first, optionally filtered by subscription and status
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error)
+	// All subscriptions with their delivery backlog
+	ListWebhookSubscriptions(ctx context.Context) ([]ListWebhookSubscriptionsRow, error)
+	// Serialize full refreshes: the lock is held until the transaction ends, so a
+	// concurrent refresh waits and then scores the committed data
+	LockBandSimilarityRefresh(ctx context.Context) error
+	// Mark all of a user's unread notifications read
+	MarkNotificationsRead(ctx context.Context, userID int32) (int64, error)
+	// ============================================
+	// CACHE QUERIES
+	// ============================================
+	// Tell every API replica to drop cached responses with the tag. The
+	// notification is delivered when the surrounding transaction commits.
+	NotifyCacheInvalidation(ctx context.Context, tag string) error
+	// Record a failed attempt and schedule the next one, or give up
+	RecordWebhookDeliveryFailure(ctx context.Context, arg RecordWebhookDeliveryFailureParams) error
+	RecordWebhookDeliverySuccess(ctx context.Context, arg RecordWebhookDeliverySuccessParams) error
+	// Remove an unconfirmed inferred genre and remember the rejection so it is
+	// not proposed again
+	RejectBandGenre(ctx context.Context, arg RejectBandGenreParams) (int64, error)
+	// Queue a fresh delivery of the same event to the same subscription
+	ReplayWebhookDelivery(ctx context.Context, id int64) (ReplayWebhookDeliveryRow, error)
+	// ============================================
+	// SAVED SHOWS
+	// ============================================
+	// Save a show for a user (idempotent). Returns no row when the show does not exist.
+	SaveShow(ctx context.Context, arg SaveShowParams) (int32, error)
+	// ============================================
+	// BAND SIMILARITY QUERIES
+	// ============================================
+	// Score similar bands for the given bands (all bands when band_ids is empty).
+	// Candidates share at least one genre or show; venue and hometown overlap
+	// only re-rank them. Weights are applied here so the ranking and per-band
+	// cap use the blended score.
+	ScoreSimilarBands(ctx context.Context, arg ScoreSimilarBandsParams) ([]ScoreSimilarBandsRow, error)
 	// ============================================
 	// GLOBAL SEARCH QUERIES
 	// ============================================
-	// Unified search across shows, bands, and venues
-	// Returns results with a type discriminator
+	// Unified, paginated search across shows, bands, venues and articles.
+	// Returns a single relevance-ranked list with a type discriminator, limited to
+	// the entity types in @types. The query is a prefix-aware to_tsquery expression.
+	// With @highlight, also returns (otherwise empty strings) the first field that
+	// matches any query term and a ts_headline snippet of it.
 	// Note: This uses UNION ALL for efficiency (no deduplication needed)
 	SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchAllRow, error)
-	// Full-text search on band name and bio
+	// Full-text search on band name and bio, best match first.
+	// The query is a prefix-aware to_tsquery expression built by the handler.
 	SearchBands(ctx context.Context, arg SearchBandsParams) ([]SearchBandsRow, error)
 	// Simple search returning minimal fields (for global search)
 	SearchBandsSimple(ctx context.Context, arg SearchBandsSimpleParams) ([]SearchBandsSimpleRow, error)
 	// Full-text search on show titles
 	SearchShows(ctx context.Context, arg SearchShowsParams) ([]SearchShowsRow, error)
-	// Search shows including band names in the search
-	// This finds shows where either the title OR any band name matches
-	SearchShowsWithBands(ctx context.Context, arg SearchShowsWithBandsParams) ([]SearchShowsWithBandsRow, error)
 	// Full-text search on venue names
 	SearchVenues(ctx context.Context, arg SearchVenuesParams) ([]SearchVenuesRow, error)
+	// Check if show exists by ID
+	ShowExists(ctx context.Context, id int32) (bool, error)
+	// Unfollow a band by slug. Returns no row when the band does not exist.
+	UnfollowBand(ctx context.Context, arg UnfollowBandParams) (int32, error)
+	// Unfollow a venue by slug. Returns no row when the venue does not exist.
+	UnfollowVenue(ctx context.Context, arg UnfollowVenueParams) (int32, error)
+	// Remove a saved show (no-op when not saved)
+	UnsaveShow(ctx context.Context, arg UnsaveShowParams) error
+	// Refresh a scraped show with the latest source data
+	UpdateScrapedShow(ctx context.Context, arg UpdateScrapedShowParams) error
+	// Set a show's status
+	UpdateShowStatus(ctx context.Context, arg UpdateShowStatusParams) error
+	// Change a subscription; omitted fields keep their value
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (UpdateWebhookSubscriptionRow, error)
+	// Write an inferred genre, refreshing earlier unconfirmed inferences.
+	// Manual and confirmed genres are never overwritten, and genres an admin
+	// rejected for the band are never proposed again.
+	UpsertInferredBandGenre(ctx context.Context, arg UpsertInferredBandGenreParams) (int64, error)
+	// ============================================
+	// CHANNEL MANAGEMENT
+	// ============================================
+	// Add a channel, or update an existing one with the same target. A new or
+	// re-enabled channel starts after the user's latest notification, so it does
+	// not replay old ones.
+	UpsertNotificationChannel(ctx context.Context, arg UpsertNotificationChannelParams) (UpsertNotificationChannelRow, error)
+	// Create the user on first sign-in and record the login time
+	UpsertUserLogin(ctx context.Context, email string) (User, error)
 	// Check if venue exists by ID (for validation)
 	VenueExists(ctx context.Context, id int32) (bool, error)
 }
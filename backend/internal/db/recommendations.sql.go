@@ -0,0 +1,215 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: recommendations.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getBandsBySlugs = `-- name: GetBandsBySlugs :many
+
+SELECT
+    id,
+    name,
+    slug
+FROM bands
+WHERE slug = ANY($1::text[])
+`
+
+type GetBandsBySlugsRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// ============================================
+// RECOMMENDATION QUERIES
+// ============================================
+// Resolve seed band slugs
+func (q *Queries) GetBandsBySlugs(ctx context.Context, slugs []string) ([]GetBandsBySlugsRow, error) {
+	rows, err := q.db.Query(ctx, getBandsBySlugs, slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetBandsBySlugsRow{}
+	for rows.Next() {
+		var i GetBandsBySlugsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGenresBySlugs = `-- name: GetGenresBySlugs :many
+SELECT
+    id,
+    name,
+    slug
+FROM genres
+WHERE slug = ANY($1::text[])
+`
+
+type GetGenresBySlugsRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Resolve seed genre slugs
+func (q *Queries) GetGenresBySlugs(ctx context.Context, slugs []string) ([]GetGenresBySlugsRow, error) {
+	rows, err := q.db.Query(ctx, getGenresBySlugs, slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetGenresBySlugsRow{}
+	for rows.Next() {
+		var i GetGenresBySlugsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecommendationEvidence = `-- name: ListRecommendationEvidence :many
+WITH RECURSIVE genre_tree AS (
+    SELECT id FROM genres WHERE id = ANY($1::int[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+),
+upcoming AS (
+    SELECT id
+    FROM shows
+    WHERE date >= NOW()
+      AND status = 'scheduled'
+)
+SELECT
+    sb.show_id,
+    'seed_band'::text AS kind,
+    1::float8 AS score,
+    seed.name AS seed_name,
+    seed.name AS band_name,
+    COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+    ''::text AS genre_name,
+    0::int AS shared_show_count
+FROM show_bands sb
+JOIN upcoming u ON u.id = sb.show_id
+JOIN bands seed ON seed.id = sb.band_id
+WHERE sb.band_id = ANY($2::int[])
+
+UNION ALL
+
+SELECT
+    sb.show_id,
+    'similar_band'::text AS kind,
+    bs.score,
+    seed.name AS seed_name,
+    b.name AS band_name,
+    COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+    COALESCE((
+        SELECT g.name
+        FROM band_genres sg
+        JOIN band_genres og ON og.genre_id = sg.genre_id
+        JOIN genres g ON g.id = sg.genre_id
+        WHERE sg.band_id = bs.band_id
+          AND og.band_id = bs.similar_band_id
+        ORDER BY g.name
+        LIMIT 1
+    ), '')::text AS genre_name,
+    bs.shared_show_count
+FROM band_similarity bs
+JOIN show_bands sb ON sb.band_id = bs.similar_band_id
+JOIN upcoming u ON u.id = sb.show_id
+JOIN bands seed ON seed.id = bs.band_id
+JOIN bands b ON b.id = bs.similar_band_id
+WHERE bs.band_id = ANY($2::int[])
+  AND NOT (bs.similar_band_id = ANY($2::int[]))
+
+UNION ALL
+
+(
+    SELECT DISTINCT ON (sb.show_id, sb.band_id)
+        sb.show_id,
+        'genre'::text AS kind,
+        0::float8 AS score,
+        ''::text AS seed_name,
+        b.name AS band_name,
+        COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+        g.name AS genre_name,
+        0::int AS shared_show_count
+    FROM show_bands sb
+    JOIN upcoming u ON u.id = sb.show_id
+    JOIN bands b ON b.id = sb.band_id
+    JOIN band_genres bg ON bg.band_id = sb.band_id
+    JOIN genre_tree t ON t.id = bg.genre_id
+    JOIN genres g ON g.id = bg.genre_id
+    ORDER BY sb.show_id, sb.band_id, g.name
+)
+ORDER BY show_id
+`
+
+type ListRecommendationEvidenceParams struct {
+	GenreIds []int32 `json:"genre_ids"`
+	BandIds  []int32 `json:"band_ids"`
+}
+
+type ListRecommendationEvidenceRow struct {
+	ShowID          int32   `json:"show_id"`
+	Kind            string  `json:"kind"`
+	Score           float64 `json:"score"`
+	SeedName        string  `json:"seed_name"`
+	BandName        string  `json:"band_name"`
+	IsHeadliner     bool    `json:"is_headliner"`
+	GenreName       string  `json:"genre_name"`
+	SharedShowCount int32   `json:"shared_show_count"`
+}
+
+// Collect why each upcoming show matches the seeds, one row per piece of evidence:
+//
+//	seed_band    - a seed band is on the bill
+//	similar_band - a band similar to a seed band (band_similarity) is on the bill
+//	genre        - a band on the bill plays a seed genre or one of its subgenres
+//
+// The handler combines evidence into a score and reasons per show.
+func (q *Queries) ListRecommendationEvidence(ctx context.Context, arg ListRecommendationEvidenceParams) ([]ListRecommendationEvidenceRow, error) {
+	rows, err := q.db.Query(ctx, listRecommendationEvidence, arg.GenreIds, arg.BandIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecommendationEvidenceRow{}
+	for rows.Next() {
+		var i ListRecommendationEvidenceRow
+		if err := rows.Scan(
+			&i.ShowID,
+			&i.Kind,
+			&i.Score,
+			&i.SeedName,
+			&i.BandName,
+			&i.IsHeadliner,
+			&i.GenreName,
+			&i.SharedShowCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
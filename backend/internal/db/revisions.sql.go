@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: revisions.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createShowRevision = `-- name: CreateShowRevision :exec
+
+INSERT INTO show_revisions (
+    show_id,
+    field,
+    old_value,
+    new_value,
+    actor,
+    source
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type CreateShowRevisionParams struct {
+	ShowID   int32   `json:"show_id"`
+	Field    string  `json:"field"`
+	OldValue []byte  `json:"old_value"`
+	NewValue []byte  `json:"new_value"`
+	Actor    *string `json:"actor"`
+	Source   string  `json:"source"`
+}
+
+// ============================================
+// SHOW REVISION QUERIES
+// ============================================
+// Record a single field change on a show
+func (q *Queries) CreateShowRevision(ctx context.Context, arg CreateShowRevisionParams) error {
+	_, err := q.db.Exec(ctx, createShowRevision,
+		arg.ShowID,
+		arg.Field,
+		arg.OldValue,
+		arg.NewValue,
+		arg.Actor,
+		arg.Source,
+	)
+	return err
+}
+
+const createStatusRevisions = `-- name: CreateStatusRevisions :exec
+INSERT INTO show_revisions (show_id, field, old_value, new_value, actor, source)
+SELECT
+    unnest($1::int[]),
+    'status',
+    to_jsonb($2::text),
+    to_jsonb($3::text),
+    $4,
+    $5
+`
+
+type CreateStatusRevisionsParams struct {
+	ShowIds   []int32 `json:"show_ids"`
+	OldStatus string  `json:"old_status"`
+	NewStatus string  `json:"new_status"`
+	Actor     *string `json:"actor"`
+	Source    string  `json:"source"`
+}
+
+// Record the same status change for many shows (bulk cancellations)
+func (q *Queries) CreateStatusRevisions(ctx context.Context, arg CreateStatusRevisionsParams) error {
+	_, err := q.db.Exec(ctx, createStatusRevisions,
+		arg.ShowIds,
+		arg.OldStatus,
+		arg.NewStatus,
+		arg.Actor,
+		arg.Source,
+	)
+	return err
+}
+
+const listShowRevisions = `-- name: ListShowRevisions :many
+SELECT
+    id,
+    field,
+    old_value,
+    new_value,
+    actor,
+    source,
+    created_at,
+    COUNT(*) OVER() AS total_count
+FROM show_revisions
+WHERE show_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListShowRevisionsParams struct {
+	ShowID int32 `json:"show_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListShowRevisionsRow struct {
+	ID         int32              `json:"id"`
+	Field      string             `json:"field"`
+	OldValue   []byte             `json:"old_value"`
+	NewValue   []byte             `json:"new_value"`
+	Actor      *string            `json:"actor"`
+	Source     string             `json:"source"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	TotalCount int64              `json:"total_count"`
+}
+
+// List changes to a show with pagination (most recent first)
+func (q *Queries) ListShowRevisions(ctx context.Context, arg ListShowRevisionsParams) ([]ListShowRevisionsRow, error) {
+	rows, err := q.db.Query(ctx, listShowRevisions, arg.ShowID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShowRevisionsRow{}
+	for rows.Next() {
+		var i ListShowRevisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Field,
+			&i.OldValue,
+			&i.NewValue,
+			&i.Actor,
+			&i.Source,
+			&i.CreatedAt,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,31 +11,296 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const autocomplete = `-- name: Autocomplete :many
+(
+    SELECT
+        'show'::text AS entity_type,
+        s.id,
+        display.title AS name,
+        NULL::text AS slug,
+        s.date,
+        (CASE
+            WHEN display.title ILIKE $1::text THEN 1.0
+            WHEN display.title ILIKE '% ' || $1::text THEN 0.9
+            ELSE word_similarity($2::text, display.title)
+        END)::real AS score
+    FROM shows s
+    JOIN venues v ON s.venue_id = v.id
+    CROSS JOIN LATERAL (
+        SELECT COALESCE(s.title, NULLIF(string_agg(b.name, ', ' ORDER BY sb.performance_order DESC NULLS LAST), ''), v.name)::text AS title
+        FROM show_bands sb
+        JOIN bands b ON sb.band_id = b.id
+        WHERE sb.show_id = s.id
+    ) display
+    WHERE s.status = 'scheduled'
+      AND s.date >= NOW()
+      AND (
+        display.title ILIKE $1::text
+        OR display.title ILIKE '% ' || $1::text
+        OR $2::text <% display.title
+      )
+    ORDER BY score DESC, s.date
+    LIMIT $3::int
+)
+UNION ALL
+(
+    SELECT
+        'band'::text,
+        b.id,
+        b.name,
+        b.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN b.name ILIKE $1::text THEN 1.0
+            WHEN b.name ILIKE '% ' || $1::text THEN 0.9
+            ELSE word_similarity($2::text, b.name)
+        END)::real AS score
+    FROM bands b
+    WHERE b.name ILIKE $1::text
+       OR b.name ILIKE '% ' || $1::text
+       OR $2::text <% b.name
+    ORDER BY score DESC, b.name
+    LIMIT $3::int
+)
+UNION ALL
+(
+    SELECT
+        'venue'::text,
+        v.id,
+        v.name,
+        v.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN v.name ILIKE $1::text THEN 1.0
+            WHEN v.name ILIKE '% ' || $1::text THEN 0.9
+            ELSE word_similarity($2::text, v.name)
+        END)::real AS score
+    FROM venues v
+    WHERE v.name ILIKE $1::text
+       OR v.name ILIKE '% ' || $1::text
+       OR $2::text <% v.name
+    ORDER BY score DESC, v.name
+    LIMIT $3::int
+)
+UNION ALL
+(
+    SELECT
+        'genre'::text,
+        g.id,
+        g.name,
+        g.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN g.name ILIKE $1::text THEN 1.0
+            WHEN g.name ILIKE '% ' || $1::text THEN 0.9
+            ELSE word_similarity($2::text, g.name)
+        END)::real AS score
+    FROM genres g
+    WHERE g.name ILIKE $1::text
+       OR g.name ILIKE '% ' || $1::text
+       OR $2::text <% g.name
+    ORDER BY score DESC, g.name
+    LIMIT $3::int
+)
+ORDER BY score DESC, entity_type, name
+`
+
+type AutocompleteParams struct {
+	Prefix  string `json:"prefix"`
+	Q       string `json:"q"`
+	PerType int32  `json:"per_type"`
+}
+
+type AutocompleteRow struct {
+	EntityType string             `json:"entity_type"`
+	ID         int32              `json:"id"`
+	Name       string             `json:"name"`
+	Slug       *string            `json:"slug"`
+	Date       pgtype.Timestamptz `json:"date"`
+	Score      float32            `json:"score"`
+}
+
+// Typo-tolerant suggestions across upcoming shows, bands, venues and genres.
+// A name matches when it starts with the input (or has a word that does) or is
+// trigram-similar to it (pg_trgm word_similarity, so "orang peel" finds
+// "The Orange Peel"). @prefix is the LIKE-escaped input with a trailing '%'.
+// Shows match on their display title: the title, or for untitled (e.g.
+// band-submitted) shows the lineup, headliner first, or else the venue name.
+// Each type is capped at @per_type rows; prefix matches score above fuzzy ones.
+func (q *Queries) Autocomplete(ctx context.Context, arg AutocompleteParams) ([]AutocompleteRow, error) {
+	rows, err := q.db.Query(ctx, autocomplete, arg.Prefix, arg.Q, arg.PerType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AutocompleteRow{}
+	for rows.Next() {
+		var i AutocompleteRow
+		if err := rows.Scan(
+			&i.EntityType,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Date,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const globalSearchArticles = `-- name: GlobalSearchArticles :many
+SELECT
+    id,
+    title,
+    slug,
+    excerpt,
+    published_at,
+    ts_rank(
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', COALESCE(excerpt, '')), 'B') ||
+        setweight(to_tsvector('english', content), 'C'),
+        to_tsquery('english', $1)
+    )::real AS rank,
+    (CASE WHEN $2::boolean THEN
+        CASE
+            WHEN to_tsvector('english', title) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'title'
+            WHEN to_tsvector('english', COALESCE(excerpt, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'excerpt'
+            WHEN to_tsvector('english', content) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'content'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN $2::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', title) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN title
+                WHEN to_tsvector('english', COALESCE(excerpt, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN excerpt
+                WHEN to_tsvector('english', content) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN content
+            END),
+            to_tsquery('english', replace($1, ' & ', ' | ')),
+            $3::text)
+    ELSE '' END)::text AS snippet
+FROM articles
+WHERE is_published = TRUE
+  AND to_tsvector('english', title || ' ' || COALESCE(excerpt, '') || ' ' || content) @@ to_tsquery('english', $1)
+ORDER BY rank DESC, published_at DESC NULLS LAST
+LIMIT $4
+`
+
+type GlobalSearchArticlesParams struct {
+	Query           string `json:"query"`
+	Highlight       bool   `json:"highlight"`
+	HeadlineOptions string `json:"headline_options"`
+	Limit           int32  `json:"limit"`
+}
+
+type GlobalSearchArticlesRow struct {
+	ID           int32              `json:"id"`
+	Title        string             `json:"title"`
+	Slug         string             `json:"slug"`
+	Excerpt      *string            `json:"excerpt"`
+	PublishedAt  pgtype.Timestamptz `json:"published_at"`
+	Rank         float32            `json:"rank"`
+	MatchedField string             `json:"matched_field"`
+	Snippet      string             `json:"snippet"`
+}
+
+// Search published articles by title, excerpt and content, best match first.
+// With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
+func (q *Queries) GlobalSearchArticles(ctx context.Context, arg GlobalSearchArticlesParams) ([]GlobalSearchArticlesRow, error) {
+	rows, err := q.db.Query(ctx, globalSearchArticles,
+		arg.Query,
+		arg.Highlight,
+		arg.HeadlineOptions,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GlobalSearchArticlesRow{}
+	for rows.Next() {
+		var i GlobalSearchArticlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Excerpt,
+			&i.PublishedAt,
+			&i.Rank,
+			&i.MatchedField,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const globalSearchBands = `-- name: GlobalSearchBands :many
 SELECT
     id,
     name,
-    slug
+    slug,
+    ts_rank(
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', COALESCE(bio, '')), 'D'),
+        to_tsquery('english', $1)
+    )::real AS rank,
+    (CASE WHEN $2::boolean THEN
+        CASE
+            WHEN to_tsvector('english', name) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'name'
+            WHEN to_tsvector('english', COALESCE(bio, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'bio'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN $2::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', name) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN name
+                WHEN to_tsvector('english', COALESCE(bio, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN bio
+            END),
+            to_tsquery('english', replace($1, ' & ', ' | ')),
+            $3::text)
+    ELSE '' END)::text AS snippet
 FROM bands
-WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ plainto_tsquery('english', $1)
-ORDER BY name ASC
-LIMIT $2
+WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ to_tsquery('english', $1)
+ORDER BY rank DESC, name ASC
+LIMIT $4
 `
 
 type GlobalSearchBandsParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
+	Query           string `json:"query"`
+	Highlight       bool   `json:"highlight"`
+	HeadlineOptions string `json:"headline_options"`
+	Limit           int32  `json:"limit"`
 }
 
 type GlobalSearchBandsRow struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID           int32   `json:"id"`
+	Name         string  `json:"name"`
+	Slug         string  `json:"slug"`
+	Rank         float32 `json:"rank"`
+	MatchedField string  `json:"matched_field"`
+	Snippet      string  `json:"snippet"`
 }
 
-// Search bands only (for search endpoint's bands section)
+// Search bands by name and bio, best match first (name matches outrank bio matches).
+// With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
 func (q *Queries) GlobalSearchBands(ctx context.Context, arg GlobalSearchBandsParams) ([]GlobalSearchBandsRow, error) {
-	rows, err := q.db.Query(ctx, globalSearchBands, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.Query(ctx, globalSearchBands,
+		arg.Query,
+		arg.Highlight,
+		arg.HeadlineOptions,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +308,14 @@ func (q *Queries) GlobalSearchBands(ctx context.Context, arg GlobalSearchBandsPa
 	items := []GlobalSearchBandsRow{}
 	for rows.Next() {
 		var i GlobalSearchBandsRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Rank,
+			&i.MatchedField,
+			&i.Snippet,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -59,31 +331,87 @@ SELECT
     s.id,
     s.title,
     s.date,
-    v.name AS venue_name
+    v.name AS venue_name,
+    lineup.band_names::text[] AS band_names,
+    ts_rank(
+        setweight(to_tsvector('english', COALESCE(s.title, '')), 'A') ||
+        setweight(to_tsvector('english', lineup.names), 'A') ||
+        setweight(to_tsvector('english', v.name), 'B') ||
+        setweight(to_tsvector('english', COALESCE(s.description, '')), 'C'),
+        to_tsquery('english', $1)
+    )::real AS rank,
+    (CASE WHEN $2::boolean THEN
+        CASE
+            WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'title'
+            WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'lineup'
+            WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'venue'
+            WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN 'description'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN $2::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN s.title
+                WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN lineup.names
+                WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN v.name
+                WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace($1, ' & ', ' | ')) THEN s.description
+            END),
+            to_tsquery('english', replace($1, ' & ', ' | ')),
+            $3::text)
+    ELSE '' END)::text AS snippet
 FROM shows s
 JOIN venues v ON s.venue_id = v.id
+CROSS JOIN LATERAL (
+    SELECT
+        COALESCE(string_agg(b.name, ' '), '') AS names,
+        COALESCE(array_agg(b.name ORDER BY sb.performance_order DESC NULLS LAST)
+            FILTER (WHERE b.id IS NOT NULL), '{}') AS band_names
+    FROM show_bands sb
+    JOIN bands b ON sb.band_id = b.id
+    WHERE sb.show_id = s.id
+) lineup
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
-ORDER BY s.date ASC
-LIMIT $2
+  AND (
+    to_tsvector('english', COALESCE(s.title, '')) ||
+    to_tsvector('english', lineup.names) ||
+    to_tsvector('english', v.name) ||
+    to_tsvector('english', COALESCE(s.description, ''))
+  ) @@ to_tsquery('english', $1)
+ORDER BY rank DESC, s.date ASC, s.id ASC
+LIMIT $4
 `
 
 type GlobalSearchShowsParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
+	Query           string `json:"query"`
+	Highlight       bool   `json:"highlight"`
+	HeadlineOptions string `json:"headline_options"`
+	Limit           int32  `json:"limit"`
 }
 
 type GlobalSearchShowsRow struct {
-	ID        int32              `json:"id"`
-	Title     *string            `json:"title"`
-	Date      pgtype.Timestamptz `json:"date"`
-	VenueName string             `json:"venue_name"`
+	ID           int32              `json:"id"`
+	Title        *string            `json:"title"`
+	Date         pgtype.Timestamptz `json:"date"`
+	VenueName    string             `json:"venue_name"`
+	BandNames    []string           `json:"band_names"`
+	Rank         float32            `json:"rank"`
+	MatchedField string             `json:"matched_field"`
+	Snippet      string             `json:"snippet"`
 }
 
-// Search shows only (for search endpoint's shows section)
+// Search upcoming shows by title, lineup band names, venue name and description, best match first.
+// The query is a to_tsquery expression with prefix terms (e.g. 'avet:*') built by the handler.
+// With @highlight, also returns (otherwise empty strings) the first field that
+// matches any query term and a ts_headline snippet of it. A row can match with
+// its terms spread across fields, so no single field need match the whole query.
 func (q *Queries) GlobalSearchShows(ctx context.Context, arg GlobalSearchShowsParams) ([]GlobalSearchShowsRow, error) {
-	rows, err := q.db.Query(ctx, globalSearchShows, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.Query(ctx, globalSearchShows,
+		arg.Query,
+		arg.Highlight,
+		arg.HeadlineOptions,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +424,10 @@ func (q *Queries) GlobalSearchShows(ctx context.Context, arg GlobalSearchShowsPa
 			&i.Title,
 			&i.Date,
 			&i.VenueName,
+			&i.BandNames,
+			&i.Rank,
+			&i.MatchedField,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -111,27 +443,43 @@ const globalSearchVenues = `-- name: GlobalSearchVenues :many
 SELECT
     id,
     name,
-    slug
+    slug,
+    ts_rank(to_tsvector('english', name), to_tsquery('english', $1))::real AS rank,
+    (CASE WHEN $2::boolean THEN 'name' ELSE '' END)::text AS matched_field,
+    (CASE WHEN $2::boolean THEN
+        ts_headline('english', html_escape(name), to_tsquery('english', $1), $3::text)
+    ELSE '' END)::text AS snippet
 FROM venues
-WHERE to_tsvector('english', name) @@ plainto_tsquery('english', $1)
-ORDER BY name ASC
-LIMIT $2
+WHERE to_tsvector('english', name) @@ to_tsquery('english', $1)
+ORDER BY rank DESC, name ASC
+LIMIT $4
 `
 
 type GlobalSearchVenuesParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
+	Query           string `json:"query"`
+	Highlight       bool   `json:"highlight"`
+	HeadlineOptions string `json:"headline_options"`
+	Limit           int32  `json:"limit"`
 }
 
 type GlobalSearchVenuesRow struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID           int32   `json:"id"`
+	Name         string  `json:"name"`
+	Slug         string  `json:"slug"`
+	Rank         float32 `json:"rank"`
+	MatchedField string  `json:"matched_field"`
+	Snippet      string  `json:"snippet"`
 }
 
-// Search venues only (for search endpoint's venues section)
+// Search venues by name, best match first.
+// With @highlight, also returns (otherwise empty strings) the field and a ts_headline snippet.
 func (q *Queries) GlobalSearchVenues(ctx context.Context, arg GlobalSearchVenuesParams) ([]GlobalSearchVenuesRow, error) {
-	rows, err := q.db.Query(ctx, globalSearchVenues, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.Query(ctx, globalSearchVenues,
+		arg.Query,
+		arg.Highlight,
+		arg.HeadlineOptions,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +487,14 @@ func (q *Queries) GlobalSearchVenues(ctx context.Context, arg GlobalSearchVenues
 	items := []GlobalSearchVenuesRow{}
 	for rows.Next() {
 		var i GlobalSearchVenuesRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Rank,
+			&i.MatchedField,
+			&i.Snippet,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -152,64 +507,202 @@ func (q *Queries) GlobalSearchVenues(ctx context.Context, arg GlobalSearchVenues
 
 const searchAll = `-- name: SearchAll :many
 
-SELECT
-    'show' AS entity_type,
-    s.id,
-    COALESCE(s.title, '') AS name,
-    v.name AS extra_info,
-    s.date::text AS date_info
-FROM shows s
-JOIN venues v ON s.venue_id = v.id
-WHERE s.date >= NOW()
-  AND s.status = 'scheduled'
-  AND to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
+WITH results AS (
+    SELECT
+        'show'::text AS entity_type,
+        s.id,
+        COALESCE(s.title, NULLIF(lineup.names, ''), v.name) AS name,
+        NULL::text AS slug,
+        v.name AS extra_info,
+        s.date AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', COALESCE(s.title, '')), 'A') ||
+            setweight(to_tsvector('english', lineup.names), 'A') ||
+            setweight(to_tsvector('english', v.name), 'B') ||
+            setweight(to_tsvector('english', COALESCE(s.description, '')), 'C'),
+            to_tsquery('english', $3)
+        ) AS rank,
+        (CASE WHEN $4::boolean THEN
+            CASE
+                WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'title'
+                WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'lineup'
+                WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'venue'
+                WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'description'
+            END
+        ELSE '' END)::text AS matched_field,
+        (CASE WHEN $4::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN s.title
+                    WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN lineup.names
+                    WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN v.name
+                    WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN s.description
+                END),
+                to_tsquery('english', replace($3, ' & ', ' | ')),
+                $5::text)
+        ELSE '' END)::text AS snippet
+    FROM shows s
+    LEFT JOIN venues v ON s.venue_id = v.id  -- always matches; LEFT keeps extra_info nullable across the union
+    CROSS JOIN LATERAL (
+        SELECT COALESCE(string_agg(b.name, ', ' ORDER BY sb.performance_order DESC NULLS LAST), '') AS names
+        FROM show_bands sb
+        JOIN bands b ON sb.band_id = b.id
+        WHERE sb.show_id = s.id
+    ) lineup
+    WHERE 'show' = ANY($6::text[])
+      AND s.date >= NOW()
+      AND s.status = 'scheduled'
+      AND (
+        to_tsvector('english', COALESCE(s.title, '')) ||
+        to_tsvector('english', lineup.names) ||
+        to_tsvector('english', v.name) ||
+        to_tsvector('english', COALESCE(s.description, ''))
+      ) @@ to_tsquery('english', $3)
 
-UNION ALL
+    UNION ALL
 
-SELECT
-    'band' AS entity_type,
-    b.id,
-    b.name,
-    b.slug AS extra_info,
-    NULL AS date_info
-FROM bands b
-WHERE to_tsvector('english', b.name || ' ' || COALESCE(b.bio, '')) @@ plainto_tsquery('english', $1)
+    SELECT
+        'band' AS entity_type,
+        b.id,
+        b.name,
+        b.slug,
+        b.hometown AS extra_info,
+        NULL::timestamptz AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', b.name), 'A') ||
+            setweight(to_tsvector('english', COALESCE(b.bio, '')), 'D'),
+            to_tsquery('english', $3)
+        ) AS rank,
+        (CASE WHEN $4::boolean THEN
+            CASE
+                WHEN to_tsvector('english', b.name) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'name'
+                WHEN to_tsvector('english', COALESCE(b.bio, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'bio'
+            END
+        ELSE '' END)::text,
+        (CASE WHEN $4::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', b.name) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN b.name
+                    WHEN to_tsvector('english', COALESCE(b.bio, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN b.bio
+                END),
+                to_tsquery('english', replace($3, ' & ', ' | ')),
+                $5::text)
+        ELSE '' END)::text
+    FROM bands b
+    WHERE 'band' = ANY($6::text[])
+      AND to_tsvector('english', b.name || ' ' || COALESCE(b.bio, '')) @@ to_tsquery('english', $3)
 
-UNION ALL
+    UNION ALL
 
+    SELECT
+        'venue' AS entity_type,
+        v.id,
+        v.name,
+        v.slug,
+        v.region AS extra_info,
+        NULL::timestamptz AS date_info,
+        ts_rank(setweight(to_tsvector('english', v.name), 'A'), to_tsquery('english', $3)) AS rank,
+        (CASE WHEN $4::boolean THEN 'name' ELSE '' END)::text,
+        (CASE WHEN $4::boolean THEN
+            ts_headline('english', html_escape(v.name), to_tsquery('english', $3), $5::text)
+        ELSE '' END)::text
+    FROM venues v
+    WHERE 'venue' = ANY($6::text[])
+      AND to_tsvector('english', v.name) @@ to_tsquery('english', $3)
+
+    UNION ALL
+
+    SELECT
+        'article' AS entity_type,
+        a.id,
+        a.title,
+        a.slug,
+        a.excerpt AS extra_info,
+        a.published_at AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', a.title), 'A') ||
+            setweight(to_tsvector('english', COALESCE(a.excerpt, '')), 'B') ||
+            setweight(to_tsvector('english', a.content), 'C'),
+            to_tsquery('english', $3)
+        ) AS rank,
+        (CASE WHEN $4::boolean THEN
+            CASE
+                WHEN to_tsvector('english', a.title) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'title'
+                WHEN to_tsvector('english', COALESCE(a.excerpt, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'excerpt'
+                WHEN to_tsvector('english', a.content) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN 'content'
+            END
+        ELSE '' END)::text,
+        (CASE WHEN $4::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', a.title) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN a.title
+                    WHEN to_tsvector('english', COALESCE(a.excerpt, '')) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN a.excerpt
+                    WHEN to_tsvector('english', a.content) @@ to_tsquery('english', replace($3, ' & ', ' | ')) THEN a.content
+                END),
+                to_tsquery('english', replace($3, ' & ', ' | ')),
+                $5::text)
+        ELSE '' END)::text
+    FROM articles a
+    WHERE 'article' = ANY($6::text[])
+      AND a.is_published = TRUE
+      AND to_tsvector('english', a.title || ' ' || COALESCE(a.excerpt, '') || ' ' || a.content) @@ to_tsquery('english', $3)
+)
 SELECT
-    'venue' AS entity_type,
-    v.id,
-    v.name,
-    v.slug AS extra_info,
-    NULL AS date_info
-FROM venues v
-WHERE to_tsvector('english', v.name) @@ plainto_tsquery('english', $1)
-
-LIMIT $2
+    entity_type,
+    id,
+    name,
+    slug,
+    extra_info,
+    date_info,
+    rank::real AS rank,
+    matched_field,
+    snippet,
+    COUNT(*) OVER() AS total_count
+FROM results
+ORDER BY rank DESC, entity_type, id
+LIMIT $2 OFFSET $1
 `
 
 type SearchAllParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
+	Offset          int32    `json:"offset"`
+	Limit           int32    `json:"limit"`
+	Query           string   `json:"query"`
+	Highlight       bool     `json:"highlight"`
+	HeadlineOptions string   `json:"headline_options"`
+	Types           []string `json:"types"`
 }
 
 type SearchAllRow struct {
-	EntityType string `json:"entity_type"`
-	ID         int32  `json:"id"`
-	Name       string `json:"name"`
-	ExtraInfo  string `json:"extra_info"`
-	DateInfo   string `json:"date_info"`
+	EntityType   string             `json:"entity_type"`
+	ID           int32              `json:"id"`
+	Name         string             `json:"name"`
+	Slug         *string            `json:"slug"`
+	ExtraInfo    *string            `json:"extra_info"`
+	DateInfo     pgtype.Timestamptz `json:"date_info"`
+	Rank         float32            `json:"rank"`
+	MatchedField string             `json:"matched_field"`
+	Snippet      string             `json:"snippet"`
+	TotalCount   int64              `json:"total_count"`
 }
 
 // ============================================
 // GLOBAL SEARCH QUERIES
 // ============================================
-// Unified search across shows, bands, and venues
-// Returns results with a type discriminator
+// Unified, paginated search across shows, bands, venues and articles.
+// Returns a single relevance-ranked list with a type discriminator, limited to
+// the entity types in @types. The query is a prefix-aware to_tsquery expression.
+// With @highlight, also returns (otherwise empty strings) the first field that
+// matches any query term and a ts_headline snippet of it.
 // Note: This uses UNION ALL for efficiency (no deduplication needed)
 func (q *Queries) SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchAllRow, error) {
-	rows, err := q.db.Query(ctx, searchAll, arg.PlaintoTsquery, arg.Limit)
+	rows, err := q.db.Query(ctx, searchAll,
+		arg.Offset,
+		arg.Limit,
+		arg.Query,
+		arg.Highlight,
+		arg.HeadlineOptions,
+		arg.Types,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -221,67 +714,13 @@ func (q *Queries) SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchA
 			&i.EntityType,
 			&i.ID,
 			&i.Name,
+			&i.Slug,
 			&i.ExtraInfo,
 			&i.DateInfo,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const searchShowsWithBands = `-- name: SearchShowsWithBands :many
-SELECT DISTINCT ON (s.date, s.id)
-    s.id,
-    s.title,
-    s.date,
-    v.name AS venue_name
-FROM shows s
-JOIN venues v ON s.venue_id = v.id
-LEFT JOIN show_bands sb ON s.id = sb.show_id
-LEFT JOIN bands b ON sb.band_id = b.id
-WHERE s.date >= NOW()
-  AND s.status = 'scheduled'
-  AND (
-    to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
-    OR to_tsvector('english', COALESCE(b.name, '')) @@ plainto_tsquery('english', $1)
-  )
-ORDER BY s.date ASC, s.id ASC
-LIMIT $2
-`
-
-type SearchShowsWithBandsParams struct {
-	PlaintoTsquery string `json:"plainto_tsquery"`
-	Limit          int32  `json:"limit"`
-}
-
-type SearchShowsWithBandsRow struct {
-	ID        int32              `json:"id"`
-	Title     *string            `json:"title"`
-	Date      pgtype.Timestamptz `json:"date"`
-	VenueName string             `json:"venue_name"`
-}
-
-// Search shows including band names in the search
-// This finds shows where either the title OR any band name matches
-func (q *Queries) SearchShowsWithBands(ctx context.Context, arg SearchShowsWithBandsParams) ([]SearchShowsWithBandsRow, error) {
-	rows, err := q.db.Query(ctx, searchShowsWithBands, arg.PlaintoTsquery, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []SearchShowsWithBandsRow{}
-	for rows.Next() {
-		var i SearchShowsWithBandsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Date,
-			&i.VenueName,
+			&i.Rank,
+			&i.MatchedField,
+			&i.Snippet,
+			&i.TotalCount,
 		); err != nil {
 			return nil, err
 		}
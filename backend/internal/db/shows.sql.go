@@ -11,18 +11,55 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const completePastShows = `-- name: CompletePastShows :execrows
+WITH due AS (
+    SELECT id
+    FROM shows
+    WHERE status = 'scheduled'
+      AND (date AT TIME ZONE 'America/New_York')::date < (NOW() AT TIME ZONE 'America/New_York')::date
+    FOR UPDATE SKIP LOCKED
+),
+completed AS (
+    UPDATE shows s
+    SET status = 'completed',
+        updated_at = NOW()
+    FROM due
+    WHERE s.id = due.id
+    RETURNING s.id
+)
+INSERT INTO show_revisions (show_id, field, old_value, new_value, actor, source)
+SELECT id, 'status', '"scheduled"', '"completed"', 'maintenance', 'system'
+FROM completed
+`
+
+// Mark scheduled shows as completed once their venue-local date has passed,
+// recording a status revision for each. Returns the number of shows completed.
+// SKIP LOCKED lets concurrent runs (API ticker + scraper job) proceed without blocking.
+func (q *Queries) CompletePastShows(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, completePastShows)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const countShowsByGenre = `-- name: CountShowsByGenre :one
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT COUNT(DISTINCT s.id)
 FROM shows s
 JOIN show_bands sb ON s.id = sb.show_id
 JOIN band_genres bg ON sb.band_id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND g.slug = ANY($1::text[])
+  AND bg.genre_id IN (SELECT id FROM genre_tree)
 `
 
-// Count upcoming shows by genre (for genre filter)
+// Count upcoming shows by genre, including subgenres (for genre filter)
 func (q *Queries) CountShowsByGenre(ctx context.Context, dollar_1 []string) (int64, error) {
 	row := q.db.QueryRow(ctx, countShowsByGenre, dollar_1)
 	var count int64
@@ -114,6 +151,34 @@ func (q *Queries) CreateShowBand(ctx context.Context, arg CreateShowBandParams)
 	return err
 }
 
+const findDuplicateShow = `-- name: FindDuplicateShow :one
+SELECT s.id
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN bands b ON sb.band_id = b.id
+WHERE s.venue_id = $1
+  AND (s.date AT TIME ZONE 'America/New_York')::date = $2::date
+  AND s.status <> 'cancelled'
+  AND LOWER(b.name) = ANY($3::text[])
+ORDER BY s.id
+LIMIT 1
+`
+
+type FindDuplicateShowParams struct {
+	VenueID   int32       `json:"venue_id"`
+	Day       pgtype.Date `json:"day"`
+	BandNames []string    `json:"band_names"`
+}
+
+// Find a show at the venue on a venue-local day (@day) sharing a band
+// (case-insensitive) with a submission, to reject duplicate submissions
+func (q *Queries) FindDuplicateShow(ctx context.Context, arg FindDuplicateShowParams) (int32, error) {
+	row := q.db.QueryRow(ctx, findDuplicateShow, arg.VenueID, arg.Day, arg.BandNames)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getBandGenresForShow = `-- name: GetBandGenresForShow :many
 SELECT
     g.id,
@@ -152,6 +217,23 @@ func (q *Queries) GetBandGenresForShow(ctx context.Context, bandID int32) ([]Get
 	return items, nil
 }
 
+const getCatalogLastModified = `-- name: GetCatalogLastModified :one
+SELECT GREATEST(
+    (SELECT MAX(updated_at) FROM shows),
+    (SELECT MAX(updated_at) FROM venues),
+    (SELECT MAX(updated_at) FROM bands)
+)::timestamptz AS last_modified
+`
+
+// Newest updated_at across all shows, venues and bands, for responses that
+// aggregate the whole catalog (e.g. genre show counts)
+func (q *Queries) GetCatalogLastModified(ctx context.Context) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getCatalogLastModified)
+	var last_modified pgtype.Timestamptz
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
 const getShowBands = `-- name: GetShowBands :many
 SELECT
     b.id,
@@ -236,35 +318,40 @@ SELECT
     v.address AS venue_address,
     v.region AS venue_region,
     v.website AS venue_website,
-    v.image_url AS venue_image_url
+    v.image_url AS venue_image_url,
+    s.rescheduled_to,
+    rs.date AS rescheduled_date
 FROM shows s
 JOIN venues v ON s.venue_id = v.id
+LEFT JOIN shows rs ON s.rescheduled_to = rs.id
 WHERE s.id = $1
 `
 
 type GetShowByIDRow struct {
-	ID             int32              `json:"id"`
-	Title          *string            `json:"title"`
-	Description    *string            `json:"description"`
-	ImageUrl       *string            `json:"image_url"`
-	Date           pgtype.Timestamptz `json:"date"`
-	DoorsTime      pgtype.Time        `json:"doors_time"`
-	ShowTime       pgtype.Time        `json:"show_time"`
-	PriceMin       pgtype.Numeric     `json:"price_min"`
-	PriceMax       pgtype.Numeric     `json:"price_max"`
-	TicketUrl      *string            `json:"ticket_url"`
-	AgeRestriction *string            `json:"age_restriction"`
-	Status         *string            `json:"status"`
-	Source         *string            `json:"source"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	VenueID        int32              `json:"venue_id"`
-	VenueName      string             `json:"venue_name"`
-	VenueSlug      string             `json:"venue_slug"`
-	VenueAddress   *string            `json:"venue_address"`
-	VenueRegion    *string            `json:"venue_region"`
-	VenueWebsite   *string            `json:"venue_website"`
-	VenueImageUrl  *string            `json:"venue_image_url"`
+	ID              int32              `json:"id"`
+	Title           *string            `json:"title"`
+	Description     *string            `json:"description"`
+	ImageUrl        *string            `json:"image_url"`
+	Date            pgtype.Timestamptz `json:"date"`
+	DoorsTime       pgtype.Time        `json:"doors_time"`
+	ShowTime        pgtype.Time        `json:"show_time"`
+	PriceMin        pgtype.Numeric     `json:"price_min"`
+	PriceMax        pgtype.Numeric     `json:"price_max"`
+	TicketUrl       *string            `json:"ticket_url"`
+	AgeRestriction  *string            `json:"age_restriction"`
+	Status          *string            `json:"status"`
+	Source          *string            `json:"source"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	VenueID         int32              `json:"venue_id"`
+	VenueName       string             `json:"venue_name"`
+	VenueSlug       string             `json:"venue_slug"`
+	VenueAddress    *string            `json:"venue_address"`
+	VenueRegion     *string            `json:"venue_region"`
+	VenueWebsite    *string            `json:"venue_website"`
+	VenueImageUrl   *string            `json:"venue_image_url"`
+	RescheduledTo   *int32             `json:"rescheduled_to"`
+	RescheduledDate pgtype.Timestamptz `json:"rescheduled_date"`
 }
 
 // ============================================
@@ -297,10 +384,29 @@ func (q *Queries) GetShowByID(ctx context.Context, id int32) (GetShowByIDRow, er
 		&i.VenueRegion,
 		&i.VenueWebsite,
 		&i.VenueImageUrl,
+		&i.RescheduledTo,
+		&i.RescheduledDate,
 	)
 	return i, err
 }
 
+const getShowsLastModified = `-- name: GetShowsLastModified :one
+SELECT GREATEST(
+    (SELECT MAX(s.updated_at) FROM shows s WHERE s.id = ANY($1::int[])),
+    (SELECT MAX(v.updated_at) FROM venues v JOIN shows s ON s.venue_id = v.id WHERE s.id = ANY($1::int[])),
+    (SELECT MAX(b.updated_at) FROM bands b JOIN show_bands sb ON sb.band_id = b.id WHERE sb.show_id = ANY($1::int[]))
+)::timestamptz AS last_modified
+`
+
+// Newest updated_at across shows, their venues and their lineups (for the
+// Last-Modified header). NULL when no shows are given.
+func (q *Queries) GetShowsLastModified(ctx context.Context, ids []int32) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getShowsLastModified, ids)
+	var last_modified pgtype.Timestamptz
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
 const listFreeShows = `-- name: ListFreeShows :many
 SELECT
     s.id,
@@ -499,6 +605,12 @@ func (q *Queries) ListShowsByDateRange(ctx context.Context, arg ListShowsByDateR
 }
 
 const listShowsByGenre = `-- name: ListShowsByGenre :many
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT DISTINCT ON (s.date, s.id)
     s.id,
     s.title,
@@ -521,10 +633,9 @@ FROM shows s
 JOIN venues v ON s.venue_id = v.id
 JOIN show_bands sb ON s.id = sb.show_id
 JOIN band_genres bg ON sb.band_id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND g.slug = ANY($1::text[])
+  AND bg.genre_id IN (SELECT id FROM genre_tree)
 ORDER BY s.date ASC, s.id ASC
 LIMIT $2 OFFSET $3
 `
@@ -555,7 +666,7 @@ type ListShowsByGenreRow struct {
 	VenueImageUrl  *string            `json:"venue_image_url"`
 }
 
-// Filter shows by genre slug(s) - shows with bands matching any of the genres
+// Filter shows by genre slug(s) - shows with bands matching any of the genres or their subgenres
 func (q *Queries) ListShowsByGenre(ctx context.Context, arg ListShowsByGenreParams) ([]ListShowsByGenreRow, error) {
 	rows, err := q.db.Query(ctx, listShowsByGenre, arg.Column1, arg.Limit, arg.Offset)
 	if err != nil {
@@ -594,6 +705,89 @@ func (q *Queries) ListShowsByGenre(ctx context.Context, arg ListShowsByGenrePara
 	return items, nil
 }
 
+const listShowsByIDs = `-- name: ListShowsByIDs :many
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url
+FROM shows s
+JOIN venues v ON s.venue_id = v.id
+WHERE s.id = ANY($1::int[])
+`
+
+type ListShowsByIDsRow struct {
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	ImageUrl       *string            `json:"image_url"`
+	Date           pgtype.Timestamptz `json:"date"`
+	DoorsTime      pgtype.Time        `json:"doors_time"`
+	ShowTime       pgtype.Time        `json:"show_time"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	VenueID        int32              `json:"venue_id"`
+	VenueName      string             `json:"venue_name"`
+	VenueSlug      string             `json:"venue_slug"`
+	VenueRegion    *string            `json:"venue_region"`
+	VenueAddress   *string            `json:"venue_address"`
+	VenueImageUrl  *string            `json:"venue_image_url"`
+}
+
+// Get shows by ID (e.g. ranked recommendations); the caller restores its own order
+func (q *Queries) ListShowsByIDs(ctx context.Context, ids []int32) ([]ListShowsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, listShowsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShowsByIDsRow{}
+	for rows.Next() {
+		var i ListShowsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Date,
+			&i.DoorsTime,
+			&i.ShowTime,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.VenueRegion,
+			&i.VenueAddress,
+			&i.VenueImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listShowsByPriceRange = `-- name: ListShowsByPriceRange :many
 SELECT
     s.id,
@@ -1207,3 +1401,15 @@ func (q *Queries) SearchShows(ctx context.Context, arg SearchShowsParams) ([]Sea
 	}
 	return items, nil
 }
+
+const showExists = `-- name: ShowExists :one
+SELECT EXISTS(SELECT 1 FROM shows WHERE id = $1)
+`
+
+// Check if show exists by ID
+func (q *Queries) ShowExists(ctx context.Context, id int32) (bool, error) {
+	row := q.db.QueryRow(ctx, showExists, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
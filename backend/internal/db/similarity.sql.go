@@ -0,0 +1,343 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: similarity.sql
+
+package db
+
+import (
+	"context"
+)
+
+const deleteBandSimilarities = `-- name: DeleteBandSimilarities :exec
+DELETE FROM band_similarity
+`
+
+// Clear precomputed similarities before a full refresh
+func (q *Queries) DeleteBandSimilarities(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteBandSimilarities)
+	return err
+}
+
+type InsertBandSimilaritiesParams struct {
+	BandID           int32   `json:"band_id"`
+	SimilarBandID    int32   `json:"similar_band_id"`
+	Score            float64 `json:"score"`
+	GenreScore       float64 `json:"genre_score"`
+	CoBillScore      float64 `json:"co_bill_score"`
+	VenueScore       float64 `json:"venue_score"`
+	HometownScore    float64 `json:"hometown_score"`
+	SharedGenreCount int32   `json:"shared_genre_count"`
+	SharedShowCount  int32   `json:"shared_show_count"`
+	SharedVenueCount int32   `json:"shared_venue_count"`
+}
+
+const listBandSimilarities = `-- name: ListBandSimilarities :many
+SELECT
+    b.id,
+    b.name,
+    b.slug,
+    b.image_url,
+    bs.score,
+    bs.genre_score,
+    bs.co_bill_score,
+    bs.venue_score,
+    bs.hometown_score,
+    bs.shared_genre_count,
+    bs.shared_show_count,
+    bs.shared_venue_count
+FROM band_similarity bs
+JOIN bands b ON b.id = bs.similar_band_id
+WHERE bs.band_id = $1
+ORDER BY bs.score DESC, b.name ASC
+LIMIT $2
+`
+
+type ListBandSimilaritiesParams struct {
+	BandID int32 `json:"band_id"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListBandSimilaritiesRow struct {
+	ID               int32   `json:"id"`
+	Name             string  `json:"name"`
+	Slug             string  `json:"slug"`
+	ImageUrl         *string `json:"image_url"`
+	Score            float64 `json:"score"`
+	GenreScore       float64 `json:"genre_score"`
+	CoBillScore      float64 `json:"co_bill_score"`
+	VenueScore       float64 `json:"venue_score"`
+	HometownScore    float64 `json:"hometown_score"`
+	SharedGenreCount int32   `json:"shared_genre_count"`
+	SharedShowCount  int32   `json:"shared_show_count"`
+	SharedVenueCount int32   `json:"shared_venue_count"`
+}
+
+// Get a band's precomputed similar bands, best match first
+func (q *Queries) ListBandSimilarities(ctx context.Context, arg ListBandSimilaritiesParams) ([]ListBandSimilaritiesRow, error) {
+	rows, err := q.db.Query(ctx, listBandSimilarities, arg.BandID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBandSimilaritiesRow{}
+	for rows.Next() {
+		var i ListBandSimilaritiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.ImageUrl,
+			&i.Score,
+			&i.GenreScore,
+			&i.CoBillScore,
+			&i.VenueScore,
+			&i.HometownScore,
+			&i.SharedGenreCount,
+			&i.SharedShowCount,
+			&i.SharedVenueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockBandSimilarityRefresh = `-- name: LockBandSimilarityRefresh :exec
+SELECT pg_advisory_xact_lock(hashtext('band_similarity_refresh'))
+`
+
+// Serialize full refreshes: the lock is held until the transaction ends, so a
+// concurrent refresh waits and then scores the committed data
+func (q *Queries) LockBandSimilarityRefresh(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockBandSimilarityRefresh)
+	return err
+}
+
+const scoreSimilarBands = `-- name: ScoreSimilarBands :many
+
+WITH src AS (
+    SELECT id
+    FROM bands
+    WHERE cardinality($2::int[]) = 0
+       OR id = ANY($2::int[])
+),
+genre_counts AS (
+    SELECT band_id, COUNT(*) AS n
+    FROM band_genres
+    GROUP BY band_id
+),
+show_counts AS (
+    SELECT band_id, COUNT(DISTINCT show_id) AS n
+    FROM show_bands
+    GROUP BY band_id
+),
+band_venues AS (
+    SELECT DISTINCT sb.band_id, s.venue_id
+    FROM show_bands sb
+    JOIN shows s ON s.id = sb.show_id
+),
+venue_counts AS (
+    SELECT band_id, COUNT(*) AS n
+    FROM band_venues
+    GROUP BY band_id
+),
+genre_pairs AS (
+    SELECT a.band_id, b.band_id AS other_id, COUNT(*) AS shared
+    FROM band_genres a
+    JOIN band_genres b ON b.genre_id = a.genre_id AND b.band_id <> a.band_id
+    WHERE a.band_id IN (SELECT id FROM src)
+    GROUP BY a.band_id, b.band_id
+),
+show_pairs AS (
+    SELECT a.band_id, b.band_id AS other_id, COUNT(DISTINCT a.show_id) AS shared
+    FROM show_bands a
+    JOIN show_bands b ON b.show_id = a.show_id AND b.band_id <> a.band_id
+    WHERE a.band_id IN (SELECT id FROM src)
+    GROUP BY a.band_id, b.band_id
+),
+candidates AS (
+    SELECT band_id, other_id FROM genre_pairs
+    UNION
+    SELECT band_id, other_id FROM show_pairs
+),
+components AS (
+    SELECT
+        c.band_id,
+        c.other_id,
+        COALESCE(gp.shared, 0)::int AS shared_genre_count,
+        COALESCE(sp.shared, 0)::int AS shared_show_count,
+        (
+            SELECT COUNT(*)
+            FROM band_venues va
+            JOIN band_venues vb ON vb.venue_id = va.venue_id
+            WHERE va.band_id = c.band_id AND vb.band_id = c.other_id
+        )::int AS shared_venue_count,
+        COALESCE(gp.shared::float8 / NULLIF(ga.n + gb.n - gp.shared, 0), 0)::float8 AS genre_score,
+        COALESCE(sp.shared::float8 / NULLIF(LEAST(sa.n, sb.n), 0), 0)::float8 AS co_bill_score,
+        (
+            CASE
+                WHEN ba.hometown IS NOT NULL
+                 AND LOWER(TRIM(ba.hometown)) <> ''
+                 AND LOWER(TRIM(ba.hometown)) = LOWER(TRIM(bb.hometown))
+                THEN 1
+                ELSE 0
+            END
+        )::float8 AS hometown_score,
+        COALESCE(va.n, 0) AS venues_a,
+        COALESCE(vb.n, 0) AS venues_b
+    FROM candidates c
+    JOIN bands ba ON ba.id = c.band_id
+    JOIN bands bb ON bb.id = c.other_id
+    LEFT JOIN genre_pairs gp ON gp.band_id = c.band_id AND gp.other_id = c.other_id
+    LEFT JOIN show_pairs sp ON sp.band_id = c.band_id AND sp.other_id = c.other_id
+    LEFT JOIN genre_counts ga ON ga.band_id = c.band_id
+    LEFT JOIN genre_counts gb ON gb.band_id = c.other_id
+    LEFT JOIN show_counts sa ON sa.band_id = c.band_id
+    LEFT JOIN show_counts sb ON sb.band_id = c.other_id
+    LEFT JOIN venue_counts va ON va.band_id = c.band_id
+    LEFT JOIN venue_counts vb ON vb.band_id = c.other_id
+),
+scored AS (
+    SELECT
+        band_id,
+        other_id,
+        shared_genre_count,
+        shared_show_count,
+        shared_venue_count,
+        genre_score,
+        co_bill_score,
+        COALESCE(shared_venue_count::float8 / NULLIF(venues_a + venues_b - shared_venue_count, 0), 0)::float8 AS venue_score,
+        hometown_score
+    FROM components
+),
+weighted AS (
+    SELECT
+        s.band_id,
+        s.other_id,
+        s.shared_genre_count,
+        s.shared_show_count,
+        s.shared_venue_count,
+        s.genre_score,
+        s.co_bill_score,
+        s.venue_score,
+        s.hometown_score,
+        (
+            $3::float8 * s.genre_score +
+            $4::float8 * s.co_bill_score +
+            $5::float8 * s.venue_score +
+            $6::float8 * s.hometown_score
+        )::float8 AS score
+    FROM scored s
+),
+ranked AS (
+    SELECT
+        w.band_id,
+        w.other_id,
+        w.shared_genre_count,
+        w.shared_show_count,
+        w.shared_venue_count,
+        w.genre_score,
+        w.co_bill_score,
+        w.venue_score,
+        w.hometown_score,
+        w.score,
+        ROW_NUMBER() OVER (PARTITION BY w.band_id ORDER BY w.score DESC, w.other_id) AS position
+    FROM weighted w
+)
+SELECT
+    r.band_id,
+    r.other_id AS similar_band_id,
+    b.name,
+    b.slug,
+    b.image_url,
+    r.score,
+    r.genre_score,
+    r.co_bill_score,
+    r.venue_score,
+    r.hometown_score,
+    r.shared_genre_count,
+    r.shared_show_count,
+    r.shared_venue_count
+FROM ranked r
+JOIN bands b ON b.id = r.other_id
+WHERE r.position <= $1::int
+ORDER BY r.band_id, r.position
+`
+
+type ScoreSimilarBandsParams struct {
+	PerBandLimit   int32   `json:"per_band_limit"`
+	BandIds        []int32 `json:"band_ids"`
+	GenreWeight    float64 `json:"genre_weight"`
+	CoBillWeight   float64 `json:"co_bill_weight"`
+	VenueWeight    float64 `json:"venue_weight"`
+	HometownWeight float64 `json:"hometown_weight"`
+}
+
+type ScoreSimilarBandsRow struct {
+	BandID           int32   `json:"band_id"`
+	SimilarBandID    int32   `json:"similar_band_id"`
+	Name             string  `json:"name"`
+	Slug             string  `json:"slug"`
+	ImageUrl         *string `json:"image_url"`
+	Score            float64 `json:"score"`
+	GenreScore       float64 `json:"genre_score"`
+	CoBillScore      float64 `json:"co_bill_score"`
+	VenueScore       float64 `json:"venue_score"`
+	HometownScore    float64 `json:"hometown_score"`
+	SharedGenreCount int32   `json:"shared_genre_count"`
+	SharedShowCount  int32   `json:"shared_show_count"`
+	SharedVenueCount int32   `json:"shared_venue_count"`
+}
+
+// ============================================
+// BAND SIMILARITY QUERIES
+// ============================================
+// Score similar bands for the given bands (all bands when band_ids is empty).
+// Candidates share at least one genre or show; venue and hometown overlap
+// only re-rank them. Weights are applied here so the ranking and per-band
+// cap use the blended score.
+func (q *Queries) ScoreSimilarBands(ctx context.Context, arg ScoreSimilarBandsParams) ([]ScoreSimilarBandsRow, error) {
+	rows, err := q.db.Query(ctx, scoreSimilarBands,
+		arg.PerBandLimit,
+		arg.BandIds,
+		arg.GenreWeight,
+		arg.CoBillWeight,
+		arg.VenueWeight,
+		arg.HometownWeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScoreSimilarBandsRow{}
+	for rows.Next() {
+		var i ScoreSimilarBandsRow
+		if err := rows.Scan(
+			&i.BandID,
+			&i.SimilarBandID,
+			&i.Name,
+			&i.Slug,
+			&i.ImageUrl,
+			&i.Score,
+			&i.GenreScore,
+			&i.CoBillScore,
+			&i.VenueScore,
+			&i.HometownScore,
+			&i.SharedGenreCount,
+			&i.SharedShowCount,
+			&i.SharedVenueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
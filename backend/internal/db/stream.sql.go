@@ -0,0 +1,185 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: stream.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createShowStreamEvent = `-- name: CreateShowStreamEvent :exec
+
+WITH event AS (
+    INSERT INTO show_stream_events (show_id, event_type)
+    VALUES ($1, $2)
+    RETURNING id
+)
+SELECT pg_notify('show_stream', id::text) FROM event
+`
+
+type CreateShowStreamEventParams struct {
+	ShowID    int32  `json:"show_id"`
+	EventType string `json:"event_type"`
+}
+
+// ============================================
+// SHOW STREAM QUERIES
+// ============================================
+// Record a show event and wake stream listeners on every API replica. The
+// notification is delivered when the surrounding transaction commits.
+func (q *Queries) CreateShowStreamEvent(ctx context.Context, arg CreateShowStreamEventParams) error {
+	_, err := q.db.Exec(ctx, createShowStreamEvent, arg.ShowID, arg.EventType)
+	return err
+}
+
+const deleteOldShowStreamEvents = `-- name: DeleteOldShowStreamEvents :execrows
+DELETE FROM show_stream_events
+WHERE created_at < $1
+`
+
+// Events older than the resume window are no longer needed
+func (q *Queries) DeleteOldShowStreamEvents(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldShowStreamEvents, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getLatestShowStreamEventID = `-- name: GetLatestShowStreamEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint FROM show_stream_events
+`
+
+// Cursor for a client that starts without Last-Event-ID
+func (q *Queries) GetLatestShowStreamEventID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getLatestShowStreamEventID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listShowStreamEvents = `-- name: ListShowStreamEvents :many
+WITH RECURSIVE genre_tree AS (
+    SELECT id FROM genres WHERE slug = ANY($4::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
+SELECT
+    e.id AS event_id,
+    e.event_type,
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url
+FROM show_stream_events e
+JOIN shows s ON s.id = e.show_id
+JOIN venues v ON v.id = s.venue_id
+WHERE e.id > $1
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR v.slug = ANY($2::text[]))
+  AND (COALESCE(cardinality($3::text[]), 0) = 0 OR v.region = ANY($3::text[]))
+  AND (COALESCE(cardinality($4::text[]), 0) = 0 OR EXISTS (
+      SELECT 1
+      FROM show_bands sb
+      JOIN band_genres bg ON bg.band_id = sb.band_id
+      WHERE sb.show_id = s.id
+        AND bg.genre_id IN (SELECT id FROM genre_tree)
+  ))
+ORDER BY e.id
+LIMIT $5
+`
+
+type ListShowStreamEventsParams struct {
+	AfterID int64    `json:"after_id"`
+	Venues  []string `json:"venues"`
+	Regions []string `json:"regions"`
+	Genres  []string `json:"genres"`
+	Limit   int32    `json:"limit"`
+}
+
+type ListShowStreamEventsRow struct {
+	EventID        int64              `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	ImageUrl       *string            `json:"image_url"`
+	Date           pgtype.Timestamptz `json:"date"`
+	DoorsTime      pgtype.Time        `json:"doors_time"`
+	ShowTime       pgtype.Time        `json:"show_time"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	VenueID        int32              `json:"venue_id"`
+	VenueName      string             `json:"venue_name"`
+	VenueSlug      string             `json:"venue_slug"`
+	VenueRegion    *string            `json:"venue_region"`
+	VenueAddress   *string            `json:"venue_address"`
+	VenueImageUrl  *string            `json:"venue_image_url"`
+}
+
+// Events after a cursor with the current state of their shows, optionally
+// limited to venue slugs, regions and genre slugs (including subgenres).
+// Empty filters match everything.
+func (q *Queries) ListShowStreamEvents(ctx context.Context, arg ListShowStreamEventsParams) ([]ListShowStreamEventsRow, error) {
+	rows, err := q.db.Query(ctx, listShowStreamEvents,
+		arg.AfterID,
+		arg.Venues,
+		arg.Regions,
+		arg.Genres,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShowStreamEventsRow{}
+	for rows.Next() {
+		var i ListShowStreamEventsRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.EventType,
+			&i.ID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Date,
+			&i.DoorsTime,
+			&i.ShowTime,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.VenueRegion,
+			&i.VenueAddress,
+			&i.VenueImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
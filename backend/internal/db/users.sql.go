@@ -0,0 +1,649 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: users.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const consumeLoginToken = `-- name: ConsumeLoginToken :one
+UPDATE login_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > NOW()
+RETURNING email
+`
+
+// Mark an unused, unexpired magic-link token used and return its email.
+// Returns no row when the token is unknown, expired or already used.
+func (q *Queries) ConsumeLoginToken(ctx context.Context, tokenHash string) (string, error) {
+	row := q.db.QueryRow(ctx, consumeLoginToken, tokenHash)
+	var email string
+	err := row.Scan(&email)
+	return email, err
+}
+
+const createLoginToken = `-- name: CreateLoginToken :execrows
+
+INSERT INTO login_tokens (token_hash, email, expires_at)
+SELECT $1, $2, $3
+WHERE NOT EXISTS (
+    SELECT 1 FROM login_tokens
+    WHERE email = $2
+      AND used_at IS NULL
+      AND expires_at > NOW()
+)
+`
+
+type CreateLoginTokenParams struct {
+	TokenHash string             `json:"token_hash"`
+	Email     string             `json:"email"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+// ============================================
+// USER ACCOUNT QUERIES
+// ============================================
+// Store a magic-link token (hashed) for an email address, unless the email
+// already has an unused, unexpired one. Returns 0 rows affected in that case.
+func (q *Queries) CreateLoginToken(ctx context.Context, arg CreateLoginTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createLoginToken, arg.TokenHash, arg.Email, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateSessionParams struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    int32              `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+// Store a bearer session (hashed) for a user
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.Exec(ctx, createSession, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const deleteExpiredAuthTokens = `-- name: DeleteExpiredAuthTokens :one
+WITH expired_tokens AS (
+    DELETE FROM login_tokens WHERE expires_at <= NOW() RETURNING 1
+),
+expired_sessions AS (
+    DELETE FROM sessions WHERE expires_at <= NOW() RETURNING 1
+)
+SELECT ((SELECT COUNT(*) FROM expired_tokens) + (SELECT COUNT(*) FROM expired_sessions))::bigint AS deleted
+`
+
+// Purge expired magic-link tokens and sessions, returning how many were removed
+func (q *Queries) DeleteExpiredAuthTokens(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteExpiredAuthTokens)
+	var deleted int64
+	err := row.Scan(&deleted)
+	return deleted, err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions WHERE token_hash = $1
+`
+
+// Sign out a single session
+func (q *Queries) DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := q.db.Exec(ctx, deleteSession, tokenHash)
+	return err
+}
+
+const followBand = `-- name: FollowBand :one
+
+WITH target AS (
+    SELECT id FROM bands WHERE slug = $1
+),
+followed AS (
+    INSERT INTO followed_bands (user_id, band_id)
+    SELECT $2, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target
+`
+
+type FollowBandParams struct {
+	Slug   string `json:"slug"`
+	UserID int32  `json:"user_id"`
+}
+
+// ============================================
+// FOLLOWS
+// ============================================
+// Follow a band by slug (idempotent). Returns no row when the band does not exist.
+func (q *Queries) FollowBand(ctx context.Context, arg FollowBandParams) (int32, error) {
+	row := q.db.QueryRow(ctx, followBand, arg.Slug, arg.UserID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const followVenue = `-- name: FollowVenue :one
+WITH target AS (
+    SELECT id FROM venues WHERE slug = $1
+),
+followed AS (
+    INSERT INTO followed_venues (user_id, venue_id)
+    SELECT $2, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target
+`
+
+type FollowVenueParams struct {
+	Slug   string `json:"slug"`
+	UserID int32  `json:"user_id"`
+}
+
+// Follow a venue by slug (idempotent). Returns no row when the venue does not exist.
+func (q *Queries) FollowVenue(ctx context.Context, arg FollowVenueParams) (int32, error) {
+	row := q.db.QueryRow(ctx, followVenue, arg.Slug, arg.UserID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getSessionUser = `-- name: GetSessionUser :one
+SELECT
+    u.id,
+    u.email,
+    u.created_at,
+    u.last_login_at
+FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.token_hash = $1
+  AND s.expires_at > NOW()
+`
+
+// Resolve an unexpired session to its user
+func (q *Queries) GetSessionUser(ctx context.Context, tokenHash string) (User, error) {
+	row := q.db.QueryRow(ctx, getSessionUser, tokenHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT
+    u.id,
+    u.email,
+    u.created_at,
+    u.last_login_at,
+    (SELECT COUNT(*) FROM saved_shows ss WHERE ss.user_id = u.id)::int AS saved_show_count,
+    (SELECT COUNT(*) FROM followed_bands fb WHERE fb.user_id = u.id)::int AS followed_band_count,
+    (SELECT COUNT(*) FROM followed_venues fv WHERE fv.user_id = u.id)::int AS followed_venue_count
+FROM users u
+WHERE u.id = $1
+`
+
+type GetUserRow struct {
+	ID                 int32              `json:"id"`
+	Email              string             `json:"email"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	LastLoginAt        pgtype.Timestamptz `json:"last_login_at"`
+	SavedShowCount     int32              `json:"saved_show_count"`
+	FollowedBandCount  int32              `json:"followed_band_count"`
+	FollowedVenueCount int32              `json:"followed_venue_count"`
+}
+
+// Get a user with counts of what they save and follow
+func (q *Queries) GetUser(ctx context.Context, id int32) (GetUserRow, error) {
+	row := q.db.QueryRow(ctx, getUser, id)
+	var i GetUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+		&i.SavedShowCount,
+		&i.FollowedBandCount,
+		&i.FollowedVenueCount,
+	)
+	return i, err
+}
+
+const listFollowedBands = `-- name: ListFollowedBands :many
+SELECT
+    b.id,
+    b.name,
+    b.slug
+FROM followed_bands fb
+JOIN bands b ON b.id = fb.band_id
+WHERE fb.user_id = $1
+ORDER BY b.name ASC
+`
+
+type ListFollowedBandsRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// List the bands a user follows, by name
+func (q *Queries) ListFollowedBands(ctx context.Context, userID int32) ([]ListFollowedBandsRow, error) {
+	rows, err := q.db.Query(ctx, listFollowedBands, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFollowedBandsRow{}
+	for rows.Next() {
+		var i ListFollowedBandsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowedVenues = `-- name: ListFollowedVenues :many
+SELECT
+    v.id,
+    v.name,
+    v.slug
+FROM followed_venues fv
+JOIN venues v ON v.id = fv.venue_id
+WHERE fv.user_id = $1
+ORDER BY v.name ASC
+`
+
+type ListFollowedVenuesRow struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// List the venues a user follows, by name
+func (q *Queries) ListFollowedVenues(ctx context.Context, userID int32) ([]ListFollowedVenuesRow, error) {
+	rows, err := q.db.Query(ctx, listFollowedVenues, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFollowedVenuesRow{}
+	for rows.Next() {
+		var i ListFollowedVenuesRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSavedShows = `-- name: ListSavedShows :many
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url,
+    COUNT(*) OVER() AS total_count
+FROM saved_shows ss
+JOIN shows s ON s.id = ss.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE ss.user_id = $1
+ORDER BY s.date ASC, s.id ASC
+LIMIT $3 OFFSET $2
+`
+
+type ListSavedShowsParams struct {
+	UserID int32 `json:"user_id"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListSavedShowsRow struct {
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	ImageUrl       *string            `json:"image_url"`
+	Date           pgtype.Timestamptz `json:"date"`
+	DoorsTime      pgtype.Time        `json:"doors_time"`
+	ShowTime       pgtype.Time        `json:"show_time"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	VenueID        int32              `json:"venue_id"`
+	VenueName      string             `json:"venue_name"`
+	VenueSlug      string             `json:"venue_slug"`
+	VenueRegion    *string            `json:"venue_region"`
+	VenueAddress   *string            `json:"venue_address"`
+	VenueImageUrl  *string            `json:"venue_image_url"`
+	TotalCount     int64              `json:"total_count"`
+}
+
+// List a user's saved shows, soonest first (past shows included)
+func (q *Queries) ListSavedShows(ctx context.Context, arg ListSavedShowsParams) ([]ListSavedShowsRow, error) {
+	rows, err := q.db.Query(ctx, listSavedShows, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSavedShowsRow{}
+	for rows.Next() {
+		var i ListSavedShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Date,
+			&i.DoorsTime,
+			&i.ShowTime,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.VenueRegion,
+			&i.VenueAddress,
+			&i.VenueImageUrl,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserFeed = `-- name: ListUserFeed :many
+
+WITH matches AS (
+    SELECT show_id FROM saved_shows WHERE user_id = $1
+    UNION
+    SELECT sb.show_id
+    FROM show_bands sb
+    JOIN followed_bands fb ON fb.band_id = sb.band_id
+    WHERE fb.user_id = $1
+    UNION
+    SELECT s.id
+    FROM shows s
+    JOIN followed_venues fv ON fv.venue_id = s.venue_id
+    WHERE fv.user_id = $1
+)
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url,
+    EXISTS(
+        SELECT 1 FROM saved_shows ss
+        WHERE ss.user_id = $1 AND ss.show_id = s.id
+    )::boolean AS saved,
+    EXISTS(
+        SELECT 1 FROM followed_venues fv
+        WHERE fv.user_id = $1 AND fv.venue_id = s.venue_id
+    )::boolean AS followed_venue,
+    ARRAY(
+        SELECT b.name
+        FROM show_bands sb
+        JOIN followed_bands fb ON fb.band_id = sb.band_id
+        JOIN bands b ON b.id = sb.band_id
+        WHERE fb.user_id = $1 AND sb.show_id = s.id
+        ORDER BY sb.performance_order, b.name
+    )::text[] AS followed_bands,
+    COUNT(*) OVER() AS total_count
+FROM matches m
+JOIN shows s ON s.id = m.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE s.date >= NOW()
+  AND s.status = 'scheduled'
+ORDER BY s.date ASC, s.id ASC
+LIMIT $3 OFFSET $2
+`
+
+type ListUserFeedParams struct {
+	UserID int32 `json:"user_id"`
+	Offset int32 `json:"offset"`
+	Limit  int32 `json:"limit"`
+}
+
+type ListUserFeedRow struct {
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	ImageUrl       *string            `json:"image_url"`
+	Date           pgtype.Timestamptz `json:"date"`
+	DoorsTime      pgtype.Time        `json:"doors_time"`
+	ShowTime       pgtype.Time        `json:"show_time"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	VenueID        int32              `json:"venue_id"`
+	VenueName      string             `json:"venue_name"`
+	VenueSlug      string             `json:"venue_slug"`
+	VenueRegion    *string            `json:"venue_region"`
+	VenueAddress   *string            `json:"venue_address"`
+	VenueImageUrl  *string            `json:"venue_image_url"`
+	Saved          bool               `json:"saved"`
+	FollowedVenue  bool               `json:"followed_venue"`
+	FollowedBands  []string           `json:"followed_bands"`
+	TotalCount     int64              `json:"total_count"`
+}
+
+// ============================================
+// FEED
+// ============================================
+// Upcoming scheduled shows the user saved, or featuring a band or at a venue
+// they follow, soonest first. Flags say why each show is in the feed.
+func (q *Queries) ListUserFeed(ctx context.Context, arg ListUserFeedParams) ([]ListUserFeedRow, error) {
+	rows, err := q.db.Query(ctx, listUserFeed, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserFeedRow{}
+	for rows.Next() {
+		var i ListUserFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ImageUrl,
+			&i.Date,
+			&i.DoorsTime,
+			&i.ShowTime,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.VenueID,
+			&i.VenueName,
+			&i.VenueSlug,
+			&i.VenueRegion,
+			&i.VenueAddress,
+			&i.VenueImageUrl,
+			&i.Saved,
+			&i.FollowedVenue,
+			&i.FollowedBands,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveShow = `-- name: SaveShow :one
+
+WITH target AS (
+    SELECT id FROM shows WHERE id = $1
+),
+saved AS (
+    INSERT INTO saved_shows (user_id, show_id)
+    SELECT $2, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target
+`
+
+type SaveShowParams struct {
+	ShowID int32 `json:"show_id"`
+	UserID int32 `json:"user_id"`
+}
+
+// ============================================
+// SAVED SHOWS
+// ============================================
+// Save a show for a user (idempotent). Returns no row when the show does not exist.
+func (q *Queries) SaveShow(ctx context.Context, arg SaveShowParams) (int32, error) {
+	row := q.db.QueryRow(ctx, saveShow, arg.ShowID, arg.UserID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const unfollowBand = `-- name: UnfollowBand :one
+WITH target AS (
+    SELECT id FROM bands WHERE slug = $1
+),
+unfollowed AS (
+    DELETE FROM followed_bands fb
+    USING target
+    WHERE fb.user_id = $2
+      AND fb.band_id = target.id
+)
+SELECT id FROM target
+`
+
+type UnfollowBandParams struct {
+	Slug   string `json:"slug"`
+	UserID int32  `json:"user_id"`
+}
+
+// Unfollow a band by slug. Returns no row when the band does not exist.
+func (q *Queries) UnfollowBand(ctx context.Context, arg UnfollowBandParams) (int32, error) {
+	row := q.db.QueryRow(ctx, unfollowBand, arg.Slug, arg.UserID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const unfollowVenue = `-- name: UnfollowVenue :one
+WITH target AS (
+    SELECT id FROM venues WHERE slug = $1
+),
+unfollowed AS (
+    DELETE FROM followed_venues fv
+    USING target
+    WHERE fv.user_id = $2
+      AND fv.venue_id = target.id
+)
+SELECT id FROM target
+`
+
+type UnfollowVenueParams struct {
+	Slug   string `json:"slug"`
+	UserID int32  `json:"user_id"`
+}
+
+// Unfollow a venue by slug. Returns no row when the venue does not exist.
+func (q *Queries) UnfollowVenue(ctx context.Context, arg UnfollowVenueParams) (int32, error) {
+	row := q.db.QueryRow(ctx, unfollowVenue, arg.Slug, arg.UserID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const unsaveShow = `-- name: UnsaveShow :exec
+DELETE FROM saved_shows
+WHERE user_id = $1
+  AND show_id = $2
+`
+
+type UnsaveShowParams struct {
+	UserID int32 `json:"user_id"`
+	ShowID int32 `json:"show_id"`
+}
+
+// Remove a saved show (no-op when not saved)
+func (q *Queries) UnsaveShow(ctx context.Context, arg UnsaveShowParams) error {
+	_, err := q.db.Exec(ctx, unsaveShow, arg.UserID, arg.ShowID)
+	return err
+}
+
+const upsertUserLogin = `-- name: UpsertUserLogin :one
+INSERT INTO users (email, last_login_at)
+VALUES ($1, NOW())
+ON CONFLICT (email) DO UPDATE SET last_login_at = NOW()
+RETURNING id, email, created_at, last_login_at
+`
+
+// Create the user on first sign-in and record the login time
+func (q *Queries) UpsertUserLogin(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, upsertUserLogin, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+	)
+	return i, err
+}
@@ -143,6 +143,186 @@ func (q *Queries) GetVenueBySlug(ctx context.Context, slug string) (Venue, error
 	return i, err
 }
 
+const getVenueGenreMix = `-- name: GetVenueGenreMix :many
+SELECT
+    g.id,
+    g.name,
+    g.slug,
+    COUNT(DISTINCT s.id) AS show_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN band_genres bg ON sb.band_id = bg.band_id
+JOIN genres g ON bg.genre_id = g.id
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+GROUP BY g.id, g.name, g.slug
+ORDER BY show_count DESC, g.name ASC
+`
+
+type GetVenueGenreMixRow struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ShowCount int64  `json:"show_count"`
+}
+
+// Count distinct shows per genre at a venue
+func (q *Queries) GetVenueGenreMix(ctx context.Context, venueID int32) ([]GetVenueGenreMixRow, error) {
+	rows, err := q.db.Query(ctx, getVenueGenreMix, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenueGenreMixRow{}
+	for rows.Next() {
+		var i GetVenueGenreMixRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.ShowCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenueShowSummary = `-- name: GetVenueShowSummary :one
+SELECT
+    COUNT(*) AS total_shows,
+    COUNT(*) FILTER (WHERE s.date >= NOW()) AS upcoming_shows,
+    COUNT(*) FILTER (WHERE s.date < NOW()) AS past_shows,
+    AVG(s.price_min)::numeric AS avg_price_min,
+    AVG(s.price_max)::numeric AS avg_price_max
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+`
+
+type GetVenueShowSummaryRow struct {
+	TotalShows    int64          `json:"total_shows"`
+	UpcomingShows int64          `json:"upcoming_shows"`
+	PastShows     int64          `json:"past_shows"`
+	AvgPriceMin   pgtype.Numeric `json:"avg_price_min"`
+	AvgPriceMax   pgtype.Numeric `json:"avg_price_max"`
+}
+
+// Get show totals and average ticket price for a venue (for venue stats)
+func (q *Queries) GetVenueShowSummary(ctx context.Context, venueID int32) (GetVenueShowSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getVenueShowSummary, venueID)
+	var i GetVenueShowSummaryRow
+	err := row.Scan(
+		&i.TotalShows,
+		&i.UpcomingShows,
+		&i.PastShows,
+		&i.AvgPriceMin,
+		&i.AvgPriceMax,
+	)
+	return i, err
+}
+
+const getVenueShowsPerMonth = `-- name: GetVenueShowsPerMonth :many
+SELECT
+    TO_CHAR(DATE_TRUNC('month', s.date AT TIME ZONE 'America/New_York'), 'YYYY-MM') AS month,
+    COUNT(*) AS show_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date >= $2
+GROUP BY month
+ORDER BY month ASC
+`
+
+type GetVenueShowsPerMonthParams struct {
+	VenueID int32              `json:"venue_id"`
+	Date    pgtype.Timestamptz `json:"date"`
+}
+
+type GetVenueShowsPerMonthRow struct {
+	Month     string `json:"month"`
+	ShowCount int64  `json:"show_count"`
+}
+
+// Count shows per calendar month (venue-local time) since the given date
+func (q *Queries) GetVenueShowsPerMonth(ctx context.Context, arg GetVenueShowsPerMonthParams) ([]GetVenueShowsPerMonthRow, error) {
+	rows, err := q.db.Query(ctx, getVenueShowsPerMonth, arg.VenueID, arg.Date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenueShowsPerMonthRow{}
+	for rows.Next() {
+		var i GetVenueShowsPerMonthRow
+		if err := rows.Scan(&i.Month, &i.ShowCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenueTopBands = `-- name: GetVenueTopBands :many
+SELECT
+    b.id,
+    b.name,
+    b.slug,
+    COUNT(*) AS show_count
+FROM show_bands sb
+JOIN shows s ON sb.show_id = s.id
+JOIN bands b ON sb.band_id = b.id
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+GROUP BY b.id, b.name, b.slug
+ORDER BY show_count DESC, b.name ASC
+LIMIT $2
+`
+
+type GetVenueTopBandsParams struct {
+	VenueID int32 `json:"venue_id"`
+	Limit   int32 `json:"limit"`
+}
+
+type GetVenueTopBandsRow struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ShowCount int64  `json:"show_count"`
+}
+
+// Get the bands that have played a venue most often
+func (q *Queries) GetVenueTopBands(ctx context.Context, arg GetVenueTopBandsParams) ([]GetVenueTopBandsRow, error) {
+	rows, err := q.db.Query(ctx, getVenueTopBands, arg.VenueID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenueTopBandsRow{}
+	for rows.Next() {
+		var i GetVenueTopBandsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.ShowCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVenueUpcomingShows = `-- name: GetVenueUpcomingShows :many
 SELECT
     s.id,
@@ -207,6 +387,163 @@ func (q *Queries) GetVenueUpcomingShows(ctx context.Context, arg GetVenueUpcomin
 	return items, nil
 }
 
+const getVenuesLastModified = `-- name: GetVenuesLastModified :one
+SELECT GREATEST(
+    (SELECT MAX(v.updated_at) FROM venues v WHERE v.id = ANY($1::int[])),
+    (SELECT MAX(s.updated_at) FROM shows s WHERE s.venue_id = ANY($1::int[])),
+    (SELECT MAX(b.updated_at)
+     FROM bands b
+     JOIN show_bands sb ON sb.band_id = b.id
+     JOIN shows s ON s.id = sb.show_id
+     WHERE s.venue_id = ANY($1::int[]))
+)::timestamptz AS last_modified
+`
+
+// Newest updated_at across venues, their shows and the bands on them (for the
+// Last-Modified header). NULL when no venues are given.
+func (q *Queries) GetVenuesLastModified(ctx context.Context, ids []int32) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getVenuesLastModified, ids)
+	var last_modified pgtype.Timestamptz
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
+const listVenuePastShows = `-- name: ListVenuePastShows :many
+SELECT
+    s.id,
+    s.title,
+    s.date,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+ORDER BY s.date DESC, s.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListVenuePastShowsParams struct {
+	VenueID int32 `json:"venue_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+type ListVenuePastShowsRow struct {
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	Date           pgtype.Timestamptz `json:"date"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	TotalCount     int64              `json:"total_count"`
+}
+
+// List past shows for a venue with pagination (most recent first)
+func (q *Queries) ListVenuePastShows(ctx context.Context, arg ListVenuePastShowsParams) ([]ListVenuePastShowsRow, error) {
+	rows, err := q.db.Query(ctx, listVenuePastShows, arg.VenueID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVenuePastShowsRow{}
+	for rows.Next() {
+		var i ListVenuePastShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVenueUpcomingShows = `-- name: ListVenueUpcomingShows :many
+SELECT
+    s.id,
+    s.title,
+    s.date,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status = 'scheduled'
+  AND s.date >= NOW()
+ORDER BY s.date ASC, s.id ASC
+LIMIT $2 OFFSET $3
+`
+
+type ListVenueUpcomingShowsParams struct {
+	VenueID int32 `json:"venue_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+type ListVenueUpcomingShowsRow struct {
+	ID             int32              `json:"id"`
+	Title          *string            `json:"title"`
+	Date           pgtype.Timestamptz `json:"date"`
+	PriceMin       pgtype.Numeric     `json:"price_min"`
+	PriceMax       pgtype.Numeric     `json:"price_max"`
+	TicketUrl      *string            `json:"ticket_url"`
+	AgeRestriction *string            `json:"age_restriction"`
+	Status         *string            `json:"status"`
+	TotalCount     int64              `json:"total_count"`
+}
+
+// List upcoming shows for a venue with pagination
+func (q *Queries) ListVenueUpcomingShows(ctx context.Context, arg ListVenueUpcomingShowsParams) ([]ListVenueUpcomingShowsRow, error) {
+	rows, err := q.db.Query(ctx, listVenueUpcomingShows, arg.VenueID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVenueUpcomingShowsRow{}
+	for rows.Next() {
+		var i ListVenueUpcomingShowsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.TicketUrl,
+			&i.AgeRestriction,
+			&i.Status,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVenues = `-- name: ListVenues :many
 SELECT id, name, slug, address, city, state, zip_code, region, latitude, longitude, capacity, website, phone, image_url, metadata, created_at, updated_at FROM venues
 ORDER BY name
@@ -0,0 +1,584 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimDueWebhookDeliveries = `-- name: ClaimDueWebhookDeliveries :many
+
+WITH due AS (
+    SELECT d.id
+    FROM webhook_deliveries d
+    JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+    WHERE d.status = 'pending'
+      AND d.next_attempt_at <= NOW()
+      AND ws.active
+    ORDER BY d.next_attempt_at, d.id
+    LIMIT $1
+    FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = NOW() + INTERVAL '5 minutes'
+FROM due, webhook_subscriptions ws, webhook_events e
+WHERE d.id = due.id
+  AND ws.id = d.subscription_id
+  AND e.id = d.event_id
+RETURNING
+    d.id,
+    d.attempt_count,
+    ws.url,
+    ws.secret,
+    e.id AS event_id,
+    e.event_type,
+    e.payload,
+    e.created_at AS event_created_at
+`
+
+type ClaimDueWebhookDeliveriesRow struct {
+	ID             int64              `json:"id"`
+	AttemptCount   int32              `json:"attempt_count"`
+	Url            string             `json:"url"`
+	Secret         string             `json:"secret"`
+	EventID        int64              `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Payload        json.RawMessage    `json:"payload"`
+	EventCreatedAt pgtype.Timestamptz `json:"event_created_at"`
+}
+
+// ============================================
+// DELIVERY
+// ============================================
+// Lease pending deliveries that are due so concurrent workers (API and
+// scraper) skip them. An unfinished lease expires after five minutes and the
+// delivery is picked up again.
+func (q *Queries) ClaimDueWebhookDeliveries(ctx context.Context, limit int32) ([]ClaimDueWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimDueWebhookDeliveries, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClaimDueWebhookDeliveriesRow{}
+	for rows.Next() {
+		var i ClaimDueWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AttemptCount,
+			&i.Url,
+			&i.Secret,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.EventCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createBandWebhookEvent = `-- name: CreateBandWebhookEvent :execrows
+WITH subscribers AS (
+    SELECT id
+    FROM webhook_subscriptions
+    WHERE active
+      AND $1::text = ANY(events)
+),
+event AS (
+    INSERT INTO webhook_events (event_type, payload)
+    SELECT
+        $1::text,
+        jsonb_build_object(
+            'id', b.id,
+            'name', b.name,
+            'slug', b.slug,
+            'bio', b.bio,
+            'hometown', b.hometown,
+            'image_url', b.image_url,
+            'website', b.website,
+            'created_at', b.created_at
+        )
+    FROM bands b
+    WHERE b.id = $2
+      AND EXISTS (SELECT 1 FROM subscribers)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (subscription_id, event_id)
+SELECT sub.id, e.id
+FROM subscribers sub
+CROSS JOIN event e
+`
+
+type CreateBandWebhookEventParams struct {
+	EventType string `json:"event_type"`
+	BandID    int32  `json:"band_id"`
+}
+
+// Record a band event with a snapshot of the band and queue deliveries, like
+// CreateShowWebhookEvent
+func (q *Queries) CreateBandWebhookEvent(ctx context.Context, arg CreateBandWebhookEventParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createBandWebhookEvent, arg.EventType, arg.BandID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createShowWebhookEvent = `-- name: CreateShowWebhookEvent :execrows
+
+WITH subscribers AS (
+    SELECT id
+    FROM webhook_subscriptions
+    WHERE active
+      AND $1::text = ANY(events)
+),
+event AS (
+    INSERT INTO webhook_events (event_type, payload)
+    SELECT
+        $1::text,
+        jsonb_build_object(
+            'id', s.id,
+            'title', s.title,
+            'description', s.description,
+            'image_url', s.image_url,
+            'date', s.date,
+            'doors_time', s.doors_time,
+            'show_time', s.show_time,
+            'price_min', s.price_min,
+            'price_max', s.price_max,
+            'ticket_url', s.ticket_url,
+            'age_restriction', s.age_restriction,
+            'status', s.status,
+            'source', s.source,
+            'rescheduled_to', s.rescheduled_to,
+            'created_at', s.created_at,
+            'updated_at', s.updated_at,
+            'venue', jsonb_build_object(
+                'id', v.id,
+                'name', v.name,
+                'slug', v.slug,
+                'region', v.region
+            ),
+            'bands', COALESCE((
+                SELECT jsonb_agg(jsonb_build_object(
+                    'id', b.id,
+                    'name', b.name,
+                    'slug', b.slug,
+                    'is_headliner', COALESCE(sb.is_headliner, FALSE)
+                ) ORDER BY sb.performance_order DESC NULLS LAST, b.name)
+                FROM show_bands sb
+                JOIN bands b ON b.id = sb.band_id
+                WHERE sb.show_id = s.id
+            ), '[]'::jsonb)
+        )
+    FROM shows s
+    JOIN venues v ON v.id = s.venue_id
+    WHERE s.id = $2
+      AND EXISTS (SELECT 1 FROM subscribers)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (subscription_id, event_id)
+SELECT sub.id, e.id
+FROM subscribers sub
+CROSS JOIN event e
+`
+
+type CreateShowWebhookEventParams struct {
+	EventType string `json:"event_type"`
+	ShowID    int32  `json:"show_id"`
+}
+
+// ============================================
+// WEBHOOK QUERIES
+// ============================================
+// Record a show event with a snapshot of the show and queue a delivery to
+// every active subscription to the event type. Nothing is stored when no one
+// subscribes. Returns the number of deliveries queued.
+func (q *Queries) CreateShowWebhookEvent(ctx context.Context, arg CreateShowWebhookEventParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createShowWebhookEvent, arg.EventType, arg.ShowID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+
+INSERT INTO webhook_subscriptions (url, description, events, secret)
+VALUES ($1, $2, $3::text[], $4)
+RETURNING id, url, description, events, active, created_at, updated_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	Url         string   `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret"`
+}
+
+type CreateWebhookSubscriptionRow struct {
+	ID          int32              `json:"id"`
+	Url         string             `json:"url"`
+	Description *string            `json:"description"`
+	Events      []string           `json:"events"`
+	Active      bool               `json:"active"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+// ============================================
+// SUBSCRIPTION MANAGEMENT
+// ============================================
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (CreateWebhookSubscriptionRow, error) {
+	row := q.db.QueryRow(ctx, createWebhookSubscription,
+		arg.Url,
+		arg.Description,
+		arg.Events,
+		arg.Secret,
+	)
+	var i CreateWebhookSubscriptionRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOldWebhookEvents = `-- name: DeleteOldWebhookEvents :one
+WITH deleted AS (
+    DELETE FROM webhook_events e
+    WHERE e.created_at < $1
+      AND NOT EXISTS (
+          SELECT 1 FROM webhook_deliveries d
+          WHERE d.event_id = e.id
+            AND d.status = 'pending'
+      )
+    RETURNING 1
+)
+SELECT COUNT(*)::bigint AS deleted FROM deleted
+`
+
+// Drop events (and their delivery log) older than the retention window once
+// nothing is left to deliver
+func (q *Queries) DeleteOldWebhookEvents(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteOldWebhookEvents, before)
+	var deleted int64
+	err := row.Scan(&deleted)
+	return deleted, err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhookSubscription, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT
+    d.id,
+    d.subscription_id,
+    d.event_id,
+    e.event_type,
+    d.status,
+    d.attempt_count,
+    d.next_attempt_at,
+    d.last_attempt_at,
+    d.response_status,
+    d.last_error,
+    d.replay_of,
+    d.created_at,
+    d.delivered_at,
+    COUNT(*) OVER() AS total_count
+FROM webhook_deliveries d
+JOIN webhook_events e ON e.id = d.event_id
+WHERE ($1::int IS NULL OR d.subscription_id = $1::int)
+  AND ($2::text IS NULL OR d.status = $2::text)
+ORDER BY d.id DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID *int32  `json:"subscription_id"`
+	Status         *string `json:"status"`
+	Offset         int32   `json:"offset"`
+	Limit          int32   `json:"limit"`
+}
+
+type ListWebhookDeliveriesRow struct {
+	ID             int64              `json:"id"`
+	SubscriptionID int32              `json:"subscription_id"`
+	EventID        int64              `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Status         string             `json:"status"`
+	AttemptCount   int32              `json:"attempt_count"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
+	ResponseStatus *int32             `json:"response_status"`
+	LastError      *string            `json:"last_error"`
+	ReplayOf       *int64             `json:"replay_of"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	TotalCount     int64              `json:"total_count"`
+}
+
+// The delivery log, newest first, optionally filtered by subscription and status
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]ListWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries,
+		arg.SubscriptionID,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWebhookDeliveriesRow{}
+	for rows.Next() {
+		var i ListWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Status,
+			&i.AttemptCount,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.ReplayOf,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT
+    ws.id,
+    ws.url,
+    ws.description,
+    ws.events,
+    ws.active,
+    ws.created_at,
+    ws.updated_at,
+    (SELECT COUNT(*) FROM webhook_deliveries d WHERE d.subscription_id = ws.id AND d.status = 'pending')::int AS pending_count,
+    (SELECT COUNT(*) FROM webhook_deliveries d WHERE d.subscription_id = ws.id AND d.status = 'failed')::int AS failed_count,
+    (SELECT MAX(d.delivered_at) FROM webhook_deliveries d WHERE d.subscription_id = ws.id)::timestamptz AS last_delivered_at
+FROM webhook_subscriptions ws
+ORDER BY ws.id
+`
+
+type ListWebhookSubscriptionsRow struct {
+	ID              int32              `json:"id"`
+	Url             string             `json:"url"`
+	Description     *string            `json:"description"`
+	Events          []string           `json:"events"`
+	Active          bool               `json:"active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	PendingCount    int32              `json:"pending_count"`
+	FailedCount     int32              `json:"failed_count"`
+	LastDeliveredAt pgtype.Timestamptz `json:"last_delivered_at"`
+}
+
+// All subscriptions with their delivery backlog
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]ListWebhookSubscriptionsRow, error) {
+	rows, err := q.db.Query(ctx, listWebhookSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWebhookSubscriptionsRow{}
+	for rows.Next() {
+		var i ListWebhookSubscriptionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Description,
+			&i.Events,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PendingCount,
+			&i.FailedCount,
+			&i.LastDeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryFailure = `-- name: RecordWebhookDeliveryFailure :exec
+UPDATE webhook_deliveries
+SET status = CASE WHEN $1::bool THEN 'failed' ELSE 'pending' END,
+    attempt_count = attempt_count + 1,
+    last_attempt_at = NOW(),
+    next_attempt_at = $2,
+    response_status = $3,
+    last_error = $4
+WHERE id = $5
+`
+
+type RecordWebhookDeliveryFailureParams struct {
+	GiveUp         bool               `json:"give_up"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	ResponseStatus *int32             `json:"response_status"`
+	LastError      *string            `json:"last_error"`
+	ID             int64              `json:"id"`
+}
+
+// Record a failed attempt and schedule the next one, or give up
+func (q *Queries) RecordWebhookDeliveryFailure(ctx context.Context, arg RecordWebhookDeliveryFailureParams) error {
+	_, err := q.db.Exec(ctx, recordWebhookDeliveryFailure,
+		arg.GiveUp,
+		arg.NextAttemptAt,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const recordWebhookDeliverySuccess = `-- name: RecordWebhookDeliverySuccess :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded',
+    attempt_count = attempt_count + 1,
+    last_attempt_at = NOW(),
+    delivered_at = NOW(),
+    response_status = $1,
+    last_error = NULL
+WHERE id = $2
+`
+
+type RecordWebhookDeliverySuccessParams struct {
+	ResponseStatus *int32 `json:"response_status"`
+	ID             int64  `json:"id"`
+}
+
+func (q *Queries) RecordWebhookDeliverySuccess(ctx context.Context, arg RecordWebhookDeliverySuccessParams) error {
+	_, err := q.db.Exec(ctx, recordWebhookDeliverySuccess, arg.ResponseStatus, arg.ID)
+	return err
+}
+
+const replayWebhookDelivery = `-- name: ReplayWebhookDelivery :one
+INSERT INTO webhook_deliveries AS nd (subscription_id, event_id, replay_of)
+SELECT src.subscription_id, src.event_id, src.id
+FROM webhook_deliveries src
+WHERE src.id = $1
+RETURNING nd.id, nd.subscription_id, nd.event_id, nd.status, nd.attempt_count, nd.next_attempt_at, nd.created_at
+`
+
+type ReplayWebhookDeliveryRow struct {
+	ID             int64              `json:"id"`
+	SubscriptionID int32              `json:"subscription_id"`
+	EventID        int64              `json:"event_id"`
+	Status         string             `json:"status"`
+	AttemptCount   int32              `json:"attempt_count"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+// Queue a fresh delivery of the same event to the same subscription
+func (q *Queries) ReplayWebhookDelivery(ctx context.Context, id int64) (ReplayWebhookDeliveryRow, error) {
+	row := q.db.QueryRow(ctx, replayWebhookDelivery, id)
+	var i ReplayWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.Status,
+		&i.AttemptCount,
+		&i.NextAttemptAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateWebhookSubscription = `-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = COALESCE($1, url),
+    description = COALESCE($2, description),
+    events = COALESCE($3::text[], events),
+    active = COALESCE($4, active),
+    updated_at = NOW()
+WHERE id = $5
+RETURNING id, url, description, events, active, created_at, updated_at
+`
+
+type UpdateWebhookSubscriptionParams struct {
+	Url         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+	ID          int32    `json:"id"`
+}
+
+type UpdateWebhookSubscriptionRow struct {
+	ID          int32              `json:"id"`
+	Url         string             `json:"url"`
+	Description *string            `json:"description"`
+	Events      []string           `json:"events"`
+	Active      bool               `json:"active"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Change a subscription; omitted fields keep their value
+func (q *Queries) UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (UpdateWebhookSubscriptionRow, error) {
+	row := q.db.QueryRow(ctx, updateWebhookSubscription,
+		arg.Url,
+		arg.Description,
+		arg.Events,
+		arg.Active,
+		arg.ID,
+	)
+	var i UpdateWebhookSubscriptionRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
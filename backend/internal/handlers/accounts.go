@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/auth"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/middleware"
+)
+
+// RequestMagicLink handles POST /api/auth/magic-link.
+// Emails a single-use sign-in link. The response is the same whether or not
+// the email has an account.
+func (h *Handler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	verifyURL := strings.TrimRight(h.appURL, "/") + VerifyLoginPath
+	err := auth.RequestLogin(c.Request.Context(), h.queries, h.mailer, verifyURL, req.Email)
+	if errors.Is(err, auth.ErrInvalidEmail) {
+		respondValidationError(c, "Invalid email", map[string]any{
+			"email": "must be a valid email address",
+		})
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to send magic link", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusAccepted, gin.H{
+		"message": "Check your email for a sign-in link",
+	})
+}
+
+// VerifyLogin handles POST /api/auth/verify.
+// Exchanges a sign-in link token for a bearer session token.
+func (h *Handler) VerifyLogin(c *gin.Context) {
+	var req VerifyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	session, err := auth.Verify(c.Request.Context(), h.queries, req.Token)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Sign-in link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to verify login", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, SessionResponse{
+		Token:     session.Token,
+		ExpiresAt: session.ExpiresAt.Format(time.RFC3339),
+		User:      convertUserToItem(session.User),
+	})
+}
+
+// Logout handles POST /api/auth/logout.
+// Ends the current session.
+func (h *Handler) Logout(c *gin.Context) {
+	if err := auth.Logout(c.Request.Context(), h.queries, middleware.SessionToken(c)); err != nil {
+		logger(c).Error("failed to log out", "error", err)
+		respondInternalError(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetMe handles GET /api/me.
+// Returns the signed-in user with counts of saved shows and follows.
+func (h *Handler) GetMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.queries.GetUser(c.Request.Context(), user.ID)
+	if err != nil {
+		logger(c).Error("failed to get user", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, UserProfile{
+		UserItem: convertUserToItem(db.User{
+			ID:          profile.ID,
+			Email:       profile.Email,
+			CreatedAt:   profile.CreatedAt,
+			LastLoginAt: profile.LastLoginAt,
+		}),
+		SavedShowCount:     profile.SavedShowCount,
+		FollowedBandCount:  profile.FollowedBandCount,
+		FollowedVenueCount: profile.FollowedVenueCount,
+	})
+}
+
+// ListSavedShows handles GET /api/me/saved-shows.
+// Lists the user's saved shows, soonest first, with pagination.
+func (h *Handler) ListSavedShows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	rows, err := h.queries.ListSavedShows(ctx, db.ListSavedShowsParams{
+		UserID: user.ID,
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list saved shows", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	shows, total := convertSavedShowsToListItems(rows)
+	if len(shows) > 0 {
+		h.attachBandsToShows(ctx, shows)
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, shows, &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	})
+}
+
+// SaveShow handles PUT /api/me/saved-shows/:id.
+// Saving an already saved show is a no-op.
+func (h *Handler) SaveShow(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	_, err = h.queries.SaveShow(c.Request.Context(), db.SaveShowParams{
+		UserID: user.ID,
+		ShowID: int32(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, "Show")
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to save show", "error", err, "user_id", user.ID, "show_id", id)
+		respondInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnsaveShow handles DELETE /api/me/saved-shows/:id.
+// Removing a show that is not saved is a no-op.
+func (h *Handler) UnsaveShow(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	err = h.queries.UnsaveShow(c.Request.Context(), db.UnsaveShowParams{
+		UserID: user.ID,
+		ShowID: int32(id),
+	})
+	if err != nil {
+		logger(c).Error("failed to unsave show", "error", err, "user_id", user.ID, "show_id", id)
+		respondInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListFollows handles GET /api/me/follows.
+// Lists the bands and venues the user follows.
+func (h *Handler) ListFollows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	bandRows, err := h.queries.ListFollowedBands(ctx, user.ID)
+	if err != nil {
+		logger(c).Error("failed to list followed bands", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	venueRows, err := h.queries.ListFollowedVenues(ctx, user.ID)
+	if err != nil {
+		logger(c).Error("failed to list followed venues", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	follows := FollowsResponse{
+		Bands:  make([]BandRef, len(bandRows)),
+		Venues: make([]VenueRef, len(venueRows)),
+	}
+	for i, b := range bandRows {
+		follows.Bands[i] = BandRef{ID: b.ID, Name: b.Name, Slug: b.Slug}
+	}
+	for i, v := range venueRows {
+		follows.Venues[i] = VenueRef{ID: v.ID, Name: v.Name, Slug: v.Slug}
+	}
+
+	respondJSON(c, http.StatusOK, follows)
+}
+
+// FollowBand handles PUT /api/me/follows/bands/:slug.
+func (h *Handler) FollowBand(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.queries.FollowBand(c.Request.Context(), db.FollowBandParams{
+		UserID: user.ID,
+		Slug:   c.Param("slug"),
+	})
+	respondFollowResult(c, err, "Band", "failed to follow band")
+}
+
+// UnfollowBand handles DELETE /api/me/follows/bands/:slug.
+func (h *Handler) UnfollowBand(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.queries.UnfollowBand(c.Request.Context(), db.UnfollowBandParams{
+		UserID: user.ID,
+		Slug:   c.Param("slug"),
+	})
+	respondFollowResult(c, err, "Band", "failed to unfollow band")
+}
+
+// FollowVenue handles PUT /api/me/follows/venues/:slug.
+func (h *Handler) FollowVenue(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.queries.FollowVenue(c.Request.Context(), db.FollowVenueParams{
+		UserID: user.ID,
+		Slug:   c.Param("slug"),
+	})
+	respondFollowResult(c, err, "Venue", "failed to follow venue")
+}
+
+// UnfollowVenue handles DELETE /api/me/follows/venues/:slug.
+func (h *Handler) UnfollowVenue(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.queries.UnfollowVenue(c.Request.Context(), db.UnfollowVenueParams{
+		UserID: user.ID,
+		Slug:   c.Param("slug"),
+	})
+	respondFollowResult(c, err, "Venue", "failed to unfollow venue")
+}
+
+// GetFeed handles GET /api/me/feed.
+// Lists upcoming shows the user saved or that feature a band or venue they
+// follow, soonest first, with pagination.
+func (h *Handler) GetFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	rows, err := h.queries.ListUserFeed(ctx, db.ListUserFeedParams{
+		UserID: user.ID,
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list feed", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	shows, total := convertFeedToListItems(rows)
+	if len(shows) > 0 {
+		h.attachBandsToShows(ctx, shows)
+	}
+
+	feed := make([]FeedItem, len(shows))
+	for i, show := range shows {
+		feed[i] = FeedItem{
+			ShowListItem:  show,
+			Saved:         rows[i].Saved,
+			FollowedVenue: rows[i].FollowedVenue,
+			FollowedBands: rows[i].FollowedBands,
+		}
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, feed, &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	})
+}
+
+// currentUser returns the user signed in by middleware.UserAuth, responding
+// 401 when the route is not behind it.
+func currentUser(c *gin.Context) (db.User, bool) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "A valid session token is required")
+		return db.User{}, false
+	}
+	return user, true
+}
+
+// respondFollowResult responds to a follow or unfollow by slug: 204 on
+// success, 404 when the band or venue does not exist.
+func respondFollowResult(c *gin.Context, err error, resource, logMessage string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, resource)
+		return
+	}
+	if err != nil {
+		logger(c).Error(logMessage, "error", err, "slug", c.Param("slug"))
+		respondInternalError(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,371 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/mail"
+	"github.com/paulsena/asheville-setlist/internal/middleware"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// outbox is a mailer that keeps sent messages in memory
+type outbox struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+func (o *outbox) Send(_ context.Context, msg mail.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`https?://\S+`)
+
+// lastToken extracts the token from the most recently sent sign-in link
+func (o *outbox) lastToken(t *testing.T) string {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.messages) == 0 {
+		t.Fatal("no email sent")
+	}
+	link := linkPattern.FindString(o.messages[len(o.messages)-1].Body)
+	u, err := url.Parse(link)
+	if err != nil || u.Query().Get("token") == "" {
+		t.Fatalf("no sign-in link in email: %q", o.messages[len(o.messages)-1].Body)
+	}
+	return u.Query().Get("token")
+}
+
+// setupAccountsTestRouter creates a test router with the account handlers
+func setupAccountsTestRouter(tdb *testutil.TestDB, mailer mail.Mailer) *gin.Engine {
+	h := handlers.New(tdb.Queries, handlers.WithMailer(mailer), handlers.WithAppURL("http://app.test"))
+	router := gin.New()
+	router.POST("/api/auth/magic-link", h.RequestMagicLink)
+	router.POST("/api/auth/verify", h.VerifyLogin)
+	router.POST("/api/auth/logout", middleware.UserAuth(tdb.Queries), h.Logout)
+
+	me := router.Group("/api/me", middleware.UserAuth(tdb.Queries))
+	me.GET("", h.GetMe)
+	me.GET("/feed", h.GetFeed)
+	me.GET("/saved-shows", h.ListSavedShows)
+	me.PUT("/saved-shows/:id", h.SaveShow)
+	me.DELETE("/saved-shows/:id", h.UnsaveShow)
+	me.GET("/follows", h.ListFollows)
+	me.PUT("/follows/bands/:slug", h.FollowBand)
+	me.DELETE("/follows/bands/:slug", h.UnfollowBand)
+	me.PUT("/follows/venues/:slug", h.FollowVenue)
+	me.DELETE("/follows/venues/:slug", h.UnfollowVenue)
+	return router
+}
+
+// doJSON performs a request with an optional JSON body and bearer token
+func doJSON(router *gin.Engine, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// signIn runs the magic-link flow and returns a session token
+func signIn(t *testing.T, router *gin.Engine, box *outbox, email string) string {
+	t.Helper()
+
+	w := doJSON(router, http.MethodPost, "/api/auth/magic-link", `{"email":"`+email+`"}`, "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("magic-link: expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, http.MethodPost, "/api/auth/verify", `{"token":"`+box.lastToken(t)+`"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+			User  struct {
+				Email string `json:"email"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Data.Token == "" {
+		t.Fatal("expected a session token")
+	}
+	if resp.Data.User.Email != strings.ToLower(email) {
+		t.Errorf("expected user email %q, got %q", strings.ToLower(email), resp.Data.User.Email)
+	}
+	return resp.Data.Token
+}
+
+func TestMagicLink_SignInAndOut(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	box := &outbox{}
+	router := setupAccountsTestRouter(tdb, box)
+
+	token := signIn(t, router, box, "Fan"+testutil.TestEmailDomain)
+
+	box.mu.Lock()
+	if !strings.HasPrefix(linkPattern.FindString(box.messages[0].Body), "http://app.test/auth/verify?token=") {
+		t.Errorf("unexpected sign-in link in %q", box.messages[0].Body)
+	}
+	box.mu.Unlock()
+
+	// Links are single use
+	w := doJSON(router, http.MethodPost, "/api/auth/verify", `{"token":"`+box.lastToken(t)+`"}`, "")
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("reused link: expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = doJSON(router, http.MethodGet, "/api/me", "", token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("me: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, http.MethodPost, "/api/auth/logout", "", token)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("logout: expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	w = doJSON(router, http.MethodGet, "/api/me", "", token)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("after logout: expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestMagicLink_PendingLink(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	box := &outbox{}
+	router := setupAccountsTestRouter(tdb, box)
+
+	email := "pending" + testutil.TestEmailDomain
+	for i := 0; i < 3; i++ {
+		w := doJSON(router, http.MethodPost, "/api/auth/magic-link", `{"email":"`+email+`"}`, "")
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("request %d: expected status %d, got %d: %s", i+1, http.StatusAccepted, w.Code, w.Body.String())
+		}
+	}
+
+	// Only the first request sends a link while it is unused
+	box.mu.Lock()
+	sent := len(box.messages)
+	box.mu.Unlock()
+	if sent != 1 {
+		t.Fatalf("expected 1 email while a link is pending, got %d", sent)
+	}
+
+	// Once used, a new link can be requested
+	w := doJSON(router, http.MethodPost, "/api/auth/verify", `{"token":"`+box.lastToken(t)+`"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	doJSON(router, http.MethodPost, "/api/auth/magic-link", `{"email":"`+email+`"}`, "")
+
+	box.mu.Lock()
+	sent = len(box.messages)
+	box.mu.Unlock()
+	if sent != 2 {
+		t.Errorf("expected a new email after the link was used, got %d emails", sent)
+	}
+}
+
+func TestMagicLink_Validation(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	box := &outbox{}
+	router := setupAccountsTestRouter(tdb, box)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing email", "/api/auth/magic-link", `{}`, http.StatusBadRequest},
+		{"invalid email", "/api/auth/magic-link", `{"email":"not-an-email"}`, http.StatusBadRequest},
+		{"missing token", "/api/auth/verify", `{}`, http.StatusBadRequest},
+		{"unknown token", "/api/auth/verify", `{"token":"nope"}`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(router, http.MethodPost, tt.path, tt.body, "")
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(box.messages) != 0 {
+		t.Errorf("expected no email sent, got %d", len(box.messages))
+	}
+}
+
+func TestMe_RequiresSession(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupAccountsTestRouter(tdb, &outbox{})
+
+	for _, token := range []string{"", "not-a-session"} {
+		w := doJSON(router, http.MethodGet, "/api/me/feed", "", token)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected status %d, got %d", token, http.StatusUnauthorized, w.Code)
+		}
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	var venueID, otherVenueID int32
+	var venueSlug string
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id, slug FROM venues ORDER BY id LIMIT 1`).Scan(&venueID, &venueSlug); err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id FROM venues WHERE id <> $1 ORDER BY id LIMIT 1`, venueID).Scan(&otherVenueID); err != nil {
+		t.Skip("need at least 2 venues in database")
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Feed", "test-band-feed")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+
+	show := func(venue int32, days int, title string) int32 {
+		id, err := tdb.InsertTestShow(ctx, venue, time.Now().AddDate(0, 0, days), title)
+		if err != nil {
+			t.Fatalf("failed to insert show: %v", err)
+		}
+		return id
+	}
+	bandShow := show(otherVenueID, 2, "Feed Band Show")
+	tdb.LinkBandToShow(ctx, bandShow, bandID, true, 1)
+	savedShow := show(otherVenueID, 4, "Feed Saved Show")
+	pastShow := show(otherVenueID, -4, "Feed Past Band Show")
+	tdb.LinkBandToShow(ctx, pastShow, bandID, true, 1)
+	unrelatedShow := show(otherVenueID, 6, "Feed Unrelated Show")
+
+	box := &outbox{}
+	router := setupAccountsTestRouter(tdb, box)
+	token := signIn(t, router, box, "feed"+testutil.TestEmailDomain)
+
+	for _, path := range []string{
+		"/api/me/follows/bands/test-band-feed",
+		"/api/me/follows/bands/test-band-feed", // idempotent
+		"/api/me/follows/venues/" + venueSlug,
+		fmt.Sprintf("/api/me/saved-shows/%d", savedShow),
+	} {
+		if w := doJSON(router, http.MethodPut, path, "", token); w.Code != http.StatusNoContent {
+			t.Fatalf("PUT %s: expected status %d, got %d: %s", path, http.StatusNoContent, w.Code, w.Body.String())
+		}
+	}
+
+	for _, path := range []string{"/api/me/follows/bands/no-such-band", "/api/me/saved-shows/999999999"} {
+		if w := doJSON(router, http.MethodPut, path, "", token); w.Code != http.StatusNotFound {
+			t.Errorf("PUT %s: expected status %d, got %d", path, http.StatusNotFound, w.Code)
+		}
+	}
+
+	w := doJSON(router, http.MethodGet, "/api/me/feed?per_page=100", "", token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("feed: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			ID            int32    `json:"id"`
+			Saved         bool     `json:"saved"`
+			FollowedVenue bool     `json:"followed_venue"`
+			FollowedBands []string `json:"followed_bands"`
+			Venue         struct {
+				ID int32 `json:"id"`
+			} `json:"venue"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	seen := map[int32]bool{}
+	for _, item := range resp.Data {
+		seen[item.ID] = true
+		switch {
+		case item.ID == bandShow:
+			if len(item.FollowedBands) != 1 || item.FollowedBands[0] != "Test Band Feed" {
+				t.Errorf("band show: expected followed_bands [Test Band Feed], got %v", item.FollowedBands)
+			}
+		case item.ID == savedShow:
+			if !item.Saved {
+				t.Error("saved show: expected saved=true")
+			}
+		case item.Venue.ID == venueID:
+			if !item.FollowedVenue {
+				t.Errorf("show %d: expected followed_venue=true", item.ID)
+			}
+		}
+	}
+
+	if !seen[bandShow] || !seen[savedShow] {
+		t.Errorf("feed missing followed band or saved show: %v", seen)
+	}
+	if seen[pastShow] || seen[unrelatedShow] {
+		t.Errorf("feed should not include past or unrelated shows: %v", seen)
+	}
+
+	// Unfollow and unsave drop shows from the feed
+	doJSON(router, http.MethodDelete, "/api/me/follows/bands/test-band-feed", "", token)
+	doJSON(router, http.MethodDelete, fmt.Sprintf("/api/me/saved-shows/%d", savedShow), "", token)
+
+	w = doJSON(router, http.MethodGet, "/api/me/feed?per_page=100", "", token)
+	resp.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	for _, item := range resp.Data {
+		if item.ID == bandShow || item.ID == savedShow {
+			t.Errorf("show %d still in feed after unfollow/unsave", item.ID)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// adminActor is recorded as confirmed_by and rejected_by for admin-token requests.
+const adminActor = "admin"
+
+// ListGenreSuggestions handles GET /api/admin/genre-suggestions.
+// Lists inferred band genres awaiting review, most confident first.
+func (h *Handler) ListGenreSuggestions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	rows, err := h.queries.ListGenreSuggestions(ctx, db.ListGenreSuggestionsParams{
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list genre suggestions", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	suggestions, total := convertGenreSuggestionsToItems(rows)
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, suggestions, meta)
+}
+
+// ConfirmGenreSuggestion handles POST /api/admin/genre-suggestions/:band_id/:genre_id/confirm.
+// Marks an inferred band genre as confirmed.
+func (h *Handler) ConfirmGenreSuggestion(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	bandID, genreID, ok := parseSuggestionIDs(c)
+	if !ok {
+		return
+	}
+
+	confirmedBy := adminActor
+	row, err := h.queries.ConfirmBandGenre(ctx, db.ConfirmBandGenreParams{
+		ConfirmedBy: &confirmedBy,
+		BandID:      bandID,
+		GenreID:     genreID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondNotFound(c, "Genre suggestion")
+			return
+		}
+		logger(c).Error("failed to confirm band genre", "band_id", bandID, "genre_id", genreID, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	if err := cache.Invalidate(ctx, h.queries, cache.TagBands); err != nil {
+		logger(c).Error("failed to invalidate cache", "error", err)
+	}
+
+	respondJSON(c, http.StatusOK, GenreConfirmation{
+		BandID:      row.BandID,
+		GenreID:     row.GenreID,
+		Source:      row.Source,
+		Confidence:  numericToFloat(row.Confidence),
+		ConfirmedAt: formatTimestamp(row.ConfirmedAt),
+		ConfirmedBy: row.ConfirmedBy,
+	})
+}
+
+// RejectGenreSuggestion handles DELETE /api/admin/genre-suggestions/:band_id/:genre_id.
+// Removes an unconfirmed inferred band genre; inference won't propose it again.
+func (h *Handler) RejectGenreSuggestion(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	bandID, genreID, ok := parseSuggestionIDs(c)
+	if !ok {
+		return
+	}
+
+	rejectedBy := adminActor
+	count, err := h.queries.RejectBandGenre(ctx, db.RejectBandGenreParams{
+		BandID:     bandID,
+		GenreID:    genreID,
+		RejectedBy: &rejectedBy,
+	})
+	if err != nil {
+		logger(c).Error("failed to reject band genre", "band_id", bandID, "genre_id", genreID, "error", err)
+		respondInternalError(c)
+		return
+	}
+	if count == 0 {
+		respondNotFound(c, "Genre suggestion")
+		return
+	}
+
+	if err := cache.Invalidate(ctx, h.queries, cache.TagBands); err != nil {
+		logger(c).Error("failed to invalidate cache", "error", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseSuggestionIDs parses the band_id and genre_id path parameters,
+// responding with 400 and returning false if either is invalid.
+func parseSuggestionIDs(c *gin.Context) (int32, int32, bool) {
+	bandID, err := strconv.ParseInt(c.Param("band_id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "band_id", "must be a valid integer")
+		return 0, 0, false
+	}
+	genreID, err := strconv.ParseInt(c.Param("genre_id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "genre_id", "must be a valid integer")
+		return 0, 0, false
+	}
+	return int32(bandID), int32(genreID), true
+}
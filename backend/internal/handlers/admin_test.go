@@ -0,0 +1,192 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/middleware"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+const testAdminToken = "test-admin-token"
+
+// setupAdminTestRouter creates a test router with the admin handlers
+func setupAdminTestRouter(tdb *testutil.TestDB) *gin.Engine {
+	h := handlers.New(tdb.Queries)
+	router := gin.New()
+	admin := router.Group("/api/admin", middleware.AdminAuth(testAdminToken))
+	admin.GET("/genre-suggestions", h.ListGenreSuggestions)
+	admin.POST("/genre-suggestions/:band_id/:genre_id/confirm", h.ConfirmGenreSuggestion)
+	admin.DELETE("/genre-suggestions/:band_id/:genre_id", h.RejectGenreSuggestion)
+	admin.GET("/webhooks", h.ListWebhookSubscriptions)
+	admin.POST("/webhooks", h.CreateWebhookSubscription)
+	admin.PATCH("/webhooks/:id", h.UpdateWebhookSubscription)
+	admin.DELETE("/webhooks/:id", h.DeleteWebhookSubscription)
+	admin.GET("/webhook-deliveries", h.ListWebhookDeliveries)
+	admin.POST("/webhook-deliveries/:id/replay", h.ReplayWebhookDelivery)
+	return router
+}
+
+func TestGenreSuggestions_RequireToken(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupAdminTestRouter(tdb)
+
+	for _, header := range []string{"", "Bearer wrong-token", testAdminToken} {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/genre-suggestions", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: expected status %d, got %d", header, http.StatusUnauthorized, w.Code)
+		}
+	}
+}
+
+func TestGenreSuggestions_ConfirmAndReject(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	router := setupAdminTestRouter(tdb)
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Suggestions", "test-band-suggestions")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+
+	var genreIDs []int32
+	rows, err := tdb.Pool.Query(ctx, `SELECT id FROM genres ORDER BY id LIMIT 3`)
+	if err != nil {
+		t.Fatalf("failed to list genres: %v", err)
+	}
+	for rows.Next() {
+		var id int32
+		rows.Scan(&id)
+		genreIDs = append(genreIDs, id)
+	}
+	rows.Close()
+	if len(genreIDs) < 3 {
+		t.Skip("need at least 3 genres in database")
+	}
+	confirmID, rejectID, manualID := genreIDs[0], genreIDs[1], genreIDs[2]
+
+	var confidence pgtype.Numeric
+	confidence.Scan("0.9")
+	for _, genreID := range []int32{confirmID, rejectID} {
+		if _, err := tdb.Queries.UpsertInferredBandGenre(ctx, db.UpsertInferredBandGenreParams{
+			BandID:     bandID,
+			GenreID:    genreID,
+			Source:     "co_bill",
+			Confidence: confidence,
+		}); err != nil {
+			t.Fatalf("failed to insert inferred genre: %v", err)
+		}
+	}
+	if err := tdb.AddGenreToBand(ctx, bandID, manualID); err != nil {
+		t.Fatalf("failed to add genre: %v", err)
+	}
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	suggestionPath := func(genreID int32) string {
+		return fmt.Sprintf("/api/admin/genre-suggestions/%d/%d", bandID, genreID)
+	}
+
+	// Both inferred genres are queued for review; the manual one is not
+	w := do(http.MethodGet, "/api/admin/genre-suggestions?per_page=100")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var list struct {
+		Data []struct {
+			Band struct {
+				ID int32 `json:"id"`
+			} `json:"band"`
+			Genre struct {
+				ID int32 `json:"id"`
+			} `json:"genre"`
+			Source     string   `json:"source"`
+			Confidence *float64 `json:"confidence"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	queued := map[int32]bool{}
+	for _, s := range list.Data {
+		if s.Band.ID == bandID {
+			queued[s.Genre.ID] = true
+			if s.Source != "co_bill" || s.Confidence == nil || *s.Confidence != 0.9 {
+				t.Errorf("unexpected suggestion %+v", s)
+			}
+		}
+	}
+	if !queued[confirmID] || !queued[rejectID] || queued[manualID] {
+		t.Errorf("unexpected review queue for band: %v", queued)
+	}
+
+	// Confirm is idempotent
+	for i := 0; i < 2; i++ {
+		w = do(http.MethodPost, suggestionPath(confirmID)+"/confirm")
+		if w.Code != http.StatusOK {
+			t.Fatalf("confirm: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+
+	// Confirmed and manual genres cannot be rejected
+	if w = do(http.MethodDelete, suggestionPath(confirmID)); w.Code != http.StatusNotFound {
+		t.Errorf("reject confirmed: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w = do(http.MethodDelete, suggestionPath(manualID)); w.Code != http.StatusNotFound {
+		t.Errorf("reject manual: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w = do(http.MethodPost, suggestionPath(manualID)+"/confirm"); w.Code != http.StatusNotFound {
+		t.Errorf("confirm manual: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	if w = do(http.MethodDelete, suggestionPath(rejectID)); w.Code != http.StatusNoContent {
+		t.Errorf("reject: expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	var remaining int
+	tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM band_genres WHERE band_id = $1`, bandID).Scan(&remaining)
+	if remaining != 2 {
+		t.Errorf("expected confirmed and manual genres to remain, got %d rows", remaining)
+	}
+
+	// A rejected genre is not proposed again
+	written, err := tdb.Queries.UpsertInferredBandGenre(ctx, db.UpsertInferredBandGenreParams{
+		BandID:     bandID,
+		GenreID:    rejectID,
+		Source:     "co_bill",
+		Confidence: confidence,
+	})
+	if err != nil || written != 0 {
+		t.Errorf("expected rejected genre to be skipped, wrote %d rows (err %v)", written, err)
+	}
+
+	if w = do(http.MethodPost, "/api/admin/genre-suggestions/abc/1/confirm"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid band_id: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// likeEscaper escapes LIKE wildcards so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Autocomplete handles GET /api/autocomplete for as-you-type suggestions.
+// Returns a mixed, score-ordered list of band, venue, genre and show suggestions
+// from a single typo-tolerant query, capped per type.
+func (h *Handler) Autocomplete(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondMissingParam(c, "q")
+		return
+	}
+
+	if len(query) > MaxAutocompleteQueryLength {
+		respondInvalidParam(c, "q", "must be at most "+strconv.Itoa(MaxAutocompleteQueryLength)+" characters")
+		return
+	}
+
+	limit := DefaultAutocompleteLimit
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			respondInvalidParam(c, "limit", "must be a positive integer")
+			return
+		}
+		if parsed > MaxAutocompleteLimit {
+			parsed = MaxAutocompleteLimit
+		}
+		limit = parsed
+	}
+
+	// Suggestions are only useful while the user is still typing
+	ctx, cancel := context.WithTimeout(c.Request.Context(), AutocompleteTimeout)
+	defer cancel()
+
+	rows, err := h.queries.Autocomplete(ctx, db.AutocompleteParams{
+		Prefix:  likeEscaper.Replace(query) + "%",
+		Q:       query,
+		PerType: int32(limit),
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger(c).Warn("autocomplete timed out", "q", query, "timeout", AutocompleteTimeout)
+			respondJSON(c, http.StatusOK, []AutocompleteItem{})
+			return
+		}
+		logger(c).Error("failed to autocomplete", "q", query, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, convertAutocompleteRowsToItems(rows))
+}
@@ -0,0 +1,195 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// setupAutocompleteTestRouter creates a test router with the autocomplete handler
+func setupAutocompleteTestRouter(tdb *testutil.TestDB) *gin.Engine {
+	h := handlers.New(tdb.Queries)
+	router := gin.New()
+	router.GET("/api/autocomplete", h.Autocomplete)
+	return router
+}
+
+type autocompleteResponse struct {
+	Data []struct {
+		Type  string  `json:"type"`
+		ID    int32   `json:"id"`
+		Name  string  `json:"name"`
+		Slug  *string `json:"slug"`
+		Score float32 `json:"score"`
+	} `json:"data"`
+}
+
+func TestAutocomplete_Validation(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupAutocompleteTestRouter(tdb)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"missing q", "", http.StatusBadRequest},
+		{"blank q", "?q=+", http.StatusBadRequest},
+		{"single character", "?q=o", http.StatusOK},
+		{"invalid limit", "?q=orange&limit=0", http.StatusBadRequest},
+		{"limit above max is capped", "?q=orange&limit=500", http.StatusOK},
+		{"like wildcards are literal", "?q=%25_", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/autocomplete"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAutocomplete_Misspelling(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupAutocompleteTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/autocomplete?q=orang+peel", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp autocompleteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	for _, item := range resp.Data {
+		if item.Type == "venue" && item.Slug != nil && *item.Slug == "the-orange-peel" {
+			return
+		}
+	}
+	t.Log("The Orange Peel not suggested for 'orang peel' - seed data may differ")
+}
+
+func TestAutocomplete_PrefixAndCap(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	for i := 1; i <= 3; i++ {
+		name := fmt.Sprintf("Test Band Xylophonic %d", i)
+		if _, err := tdb.InsertTestBand(ctx, name, fmt.Sprintf("test-band-xylophonic-%d", i)); err != nil {
+			t.Fatalf("failed to insert test band: %v", err)
+		}
+	}
+
+	router := setupAutocompleteTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/autocomplete?q=xyloph&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp autocompleteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	bands := 0
+	for i, item := range resp.Data {
+		if item.Type == "band" {
+			bands++
+		}
+		if i > 0 && item.Score > resp.Data[i-1].Score {
+			t.Errorf("suggestions not ordered by score: %v after %v", item.Score, resp.Data[i-1].Score)
+		}
+	}
+
+	// Word-prefix matches on all three bands, capped at the per-type limit
+	if bands != 2 {
+		t.Errorf("expected 2 band suggestions, got %d", bands)
+	}
+}
+
+func TestAutocomplete_UntitledShow(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	// Band-submitted shows have no title and are named by their lineup
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Untitled Show")
+	if err != nil {
+		t.Fatalf("failed to insert test show: %v", err)
+	}
+	if _, err := tdb.Pool.Exec(ctx, `UPDATE shows SET title = NULL WHERE id = $1`, showID); err != nil {
+		t.Fatalf("failed to clear show title: %v", err)
+	}
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Quillfeather", "test-band-quillfeather")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	if err := tdb.LinkBandToShow(ctx, showID, bandID, true, 1); err != nil {
+		t.Fatalf("failed to link band to show: %v", err)
+	}
+
+	router := setupAutocompleteTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/autocomplete?q=quillfeath", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp autocompleteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	for _, item := range resp.Data {
+		if item.Type == "show" && item.ID == showID {
+			if item.Name != "Test Band Quillfeather" {
+				t.Errorf("expected show named by its lineup, got %q", item.Name)
+			}
+			return
+		}
+	}
+	t.Errorf("expected untitled show %d to be suggested by its lineup, got %+v", showID, resp.Data)
+}
@@ -3,13 +3,14 @@ package handlers
 import (
 	"context"
 	"errors"
-	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
 )
 
 // ListBands handles GET /api/bands with optional genre filter and search.
@@ -35,12 +36,12 @@ func (h *Handler) ListBands(c *gin.Context) {
 
 	if query != "" {
 		rows, err := h.queries.SearchBands(ctx, db.SearchBandsParams{
-			PlaintoTsquery: query,
-			Limit:          int32(perPage),
-			Offset:         int32(offset),
+			Query:  prefixTSQuery(query),
+			Limit:  int32(perPage),
+			Offset: int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to search bands", "error", err)
+			logger(c).Error("failed to search bands", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -53,14 +54,14 @@ func (h *Handler) ListBands(c *gin.Context) {
 			Offset:  int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list bands by genre", "error", err)
+			logger(c).Error("failed to list bands by genre", "error", err)
 			respondInternalError(c)
 			return
 		}
 
 		count, err := h.queries.CountBandsByGenre(ctx, genres)
 		if err != nil {
-			slog.Error("failed to count bands by genre", "error", err)
+			logger(c).Error("failed to count bands by genre", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -74,7 +75,7 @@ func (h *Handler) ListBands(c *gin.Context) {
 			Offset: int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list bands", "error", err)
+			logger(c).Error("failed to list bands", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -105,7 +106,7 @@ func (h *Handler) attachGenresToBands(ctx context.Context, bands []BandListItem)
 
 	genresMap, err := h.loadGenresForBands(ctx, bandIDs)
 	if err != nil {
-		slog.Error("failed to load genres for bands", "error", err)
+		logging.FromContext(ctx).Error("failed to load genres for bands", "error", err)
 		return
 	}
 
@@ -132,7 +133,7 @@ func (h *Handler) GetBand(c *gin.Context) {
 			respondNotFound(c, "Band")
 			return
 		}
-		slog.Error("failed to get band", "slug", slug, "error", err)
+		logger(c).Error("failed to get band", "slug", slug, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -140,7 +141,7 @@ func (h *Handler) GetBand(c *gin.Context) {
 	// Get genres for this band
 	genreRows, err := h.queries.GetBandGenres(ctx, band.ID)
 	if err != nil {
-		slog.Error("failed to get band genres", "band_id", band.ID, "error", err)
+		logger(c).Error("failed to get band genres", "band_id", band.ID, "error", err)
 		genreRows = []db.GetBandGenresRow{}
 	}
 
@@ -156,7 +157,7 @@ func (h *Handler) GetBand(c *gin.Context) {
 	// Get upcoming shows for this band
 	showRows, err := h.queries.GetBandUpcomingShows(ctx, band.ID)
 	if err != nil {
-		slog.Error("failed to get band shows", "band_id", band.ID, "error", err)
+		logger(c).Error("failed to get band shows", "band_id", band.ID, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -164,8 +165,10 @@ func (h *Handler) GetBand(c *gin.Context) {
 	upcomingShows := make([]BandShowItem, len(showRows))
 	for i, s := range showRows {
 		upcomingShows[i] = BandShowItem{
-			ID:   s.ID,
-			Date: formatTimestamp(s.Date),
+			ID:     s.ID,
+			Title:  s.Title,
+			Date:   formatTimestamp(s.Date),
+			Status: "scheduled",
 			Venue: VenueBasic{
 				ID:   s.VenueID,
 				Name: s.VenueName,
@@ -175,6 +178,11 @@ func (h *Handler) GetBand(c *gin.Context) {
 		}
 	}
 
+	history, err := h.loadBandHistory(ctx, band.ID)
+	if err != nil {
+		logger(c).Error("failed to load band history", "band_id", band.ID, "error", err)
+	}
+
 	detail := BandDetail{
 		ID:            band.ID,
 		Name:          band.Name,
@@ -189,11 +197,124 @@ func (h *Handler) GetBand(c *gin.Context) {
 		BandcampURL:   band.BandcampUrl,
 		Genres:        genres,
 		UpcomingShows: upcomingShows,
+		History:       history,
 	}
 
 	respondJSON(c, http.StatusOK, detail)
 }
 
+// GetBandShows handles GET /api/bands/:slug/shows with when=upcoming|past and pagination.
+func (h *Handler) GetBandShows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	slug := c.Param("slug")
+	if slug == "" {
+		respondInvalidParam(c, "slug", "band slug is required")
+		return
+	}
+
+	when := c.DefaultQuery("when", "upcoming")
+	if when != "upcoming" && when != "past" {
+		respondInvalidParam(c, "when", "must be one of: upcoming, past")
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	offset := calculateOffset(page, perPage)
+
+	band, err := h.queries.GetBandBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondNotFound(c, "Band")
+			return
+		}
+		logger(c).Error("failed to get band", "slug", slug, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	var shows []BandShowItem
+	var total int
+
+	if when == "past" {
+		rows, err := h.queries.ListBandPastShows(ctx, db.ListBandPastShowsParams{
+			BandID: band.ID,
+			Limit:  int32(perPage),
+			Offset: int32(offset),
+		})
+		if err != nil {
+			logger(c).Error("failed to list band past shows", "band_id", band.ID, "error", err)
+			respondInternalError(c)
+			return
+		}
+		shows, total = convertBandShowsToItems(bandPastShowRows(rows))
+	} else {
+		rows, err := h.queries.ListBandUpcomingShows(ctx, db.ListBandUpcomingShowsParams{
+			BandID: band.ID,
+			Limit:  int32(perPage),
+			Offset: int32(offset),
+		})
+		if err != nil {
+			logger(c).Error("failed to list band upcoming shows", "band_id", band.ID, "error", err)
+			respondInternalError(c)
+			return
+		}
+		shows, total = convertBandShowsToItems(bandUpcomingShowRows(rows))
+	}
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, shows, meta)
+}
+
+// loadBandHistory loads the play history summary for a band.
+// On error the partially filled history is returned alongside the error.
+func (h *Handler) loadBandHistory(ctx context.Context, bandID int32) (BandHistory, error) {
+	history := BandHistory{
+		VenuePlayCounts: []VenuePlayCount{},
+	}
+
+	summary, err := h.queries.GetBandShowSummary(ctx, bandID)
+	if err != nil {
+		return history, err
+	}
+	history.TotalShows = summary.TotalShows
+	history.HeadlinerCount = summary.HeadlinerCount
+	history.SupportCount = summary.SupportCount
+	history.FirstSeen = formatTimestampPtr(summary.FirstSeen)
+	history.LastSeen = formatTimestampPtr(summary.LastSeen)
+
+	venueRows, err := h.queries.GetBandVenuePlayCounts(ctx, bandID)
+	if err != nil {
+		return history, err
+	}
+	for _, v := range venueRows {
+		history.VenuePlayCounts = append(history.VenuePlayCounts, VenuePlayCount{
+			ID:         v.ID,
+			Name:       v.Name,
+			Slug:       v.Slug,
+			ShowCount:  v.ShowCount,
+			LastPlayed: formatTimestampPtr(v.LastPlayed),
+		})
+	}
+
+	return history, nil
+}
+
 // GetSimilarBands handles GET /api/bands/:slug/similar
 func (h *Handler) GetSimilarBands(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -223,7 +344,7 @@ func (h *Handler) GetSimilarBands(c *gin.Context) {
 			respondNotFound(c, "Band")
 			return
 		}
-		slog.Error("failed to get band", "slug", slug, "error", err)
+		logger(c).Error("failed to get band", "slug", slug, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -231,7 +352,7 @@ func (h *Handler) GetSimilarBands(c *gin.Context) {
 	// Get source band's genres for comparison
 	sourceBandGenres, err := h.queries.GetBandGenres(ctx, band.ID)
 	if err != nil {
-		slog.Error("failed to get source band genres", "band_id", band.ID, "error", err)
+		logger(c).Error("failed to get source band genres", "band_id", band.ID, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -241,12 +362,9 @@ func (h *Handler) GetSimilarBands(c *gin.Context) {
 		sourceGenreIDs[g.ID] = g
 	}
 
-	rows, err := h.queries.GetSimilarBands(ctx, db.GetSimilarBandsParams{
-		BandID: band.ID,
-		Limit:  int32(limit),
-	})
+	rows, err := h.loadSimilarBands(ctx, band.ID, limit)
 	if err != nil {
-		slog.Error("failed to get similar bands", "band_id", band.ID, "error", err)
+		logger(c).Error("failed to get similar bands", "band_id", band.ID, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -261,7 +379,7 @@ func (h *Handler) GetSimilarBands(c *gin.Context) {
 	if len(bandIDs) > 0 {
 		genresMap, err = h.loadGenresForBands(ctx, bandIDs)
 		if err != nil {
-			slog.Error("failed to load genres for similar bands", "error", err)
+			logger(c).Error("failed to load genres for similar bands", "error", err)
 		}
 	}
 
@@ -276,19 +394,33 @@ func (h *Handler) GetSimilarBands(c *gin.Context) {
 			}
 		}
 
-		similar[i] = SimilarBandItem{
-			ID:               r.ID,
-			Name:             r.Name,
-			Slug:             r.Slug,
-			ImageURL:         r.ImageUrl,
-			SharedGenreCount: r.SharedGenreCount,
-			SharedGenres:     sharedGenres,
-		}
+		similar[i] = convertSimilarBandToItem(r, sharedGenres, band.Hometown)
 	}
 
 	respondJSON(c, http.StatusOK, similar)
 }
 
+// loadSimilarBands reads a band's precomputed similar bands. Bands created
+// since the last refresh have none yet, so they are scored live instead.
+func (h *Handler) loadSimilarBands(ctx context.Context, bandID int32, limit int) ([]similarBandData, error) {
+	stored, err := h.queries.ListBandSimilarities(ctx, db.ListBandSimilaritiesParams{
+		BandID: bandID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) > 0 {
+		return convertStoredSimilarBands(stored), nil
+	}
+
+	scored, err := similarity.Score(ctx, h.queries, bandID, h.similarityWeights, limit)
+	if err != nil {
+		return nil, err
+	}
+	return convertScoredSimilarBands(scored), nil
+}
+
 // loadGenresForBands loads genres for multiple bands using batch query.
 func (h *Handler) loadGenresForBands(ctx context.Context, bandIDs []int32) (map[int32][]GenreBasic, error) {
 	result := make(map[int32][]GenreBasic)
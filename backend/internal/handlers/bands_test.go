@@ -20,6 +20,7 @@ func setupBandsTestRouter(tdb *testutil.TestDB) *gin.Engine {
 	router := gin.New()
 	router.GET("/api/bands", h.ListBands)
 	router.GET("/api/bands/:slug", h.GetBand)
+	router.GET("/api/bands/:slug/shows", h.GetBandShows)
 	router.GET("/api/bands/:slug/similar", h.GetSimilarBands)
 	return router
 }
@@ -634,7 +635,7 @@ func TestGetBand_ResponseStructure(t *testing.T) {
 	expectedFields := []string{
 		"id", "name", "slug", "bio", "hometown", "image_url",
 		"website", "spotify_url", "instagram", "facebook", "bandcamp_url",
-		"genres", "upcoming_shows",
+		"genres", "upcoming_shows", "history",
 	}
 
 	for _, field := range expectedFields {
@@ -643,3 +644,247 @@ func TestGetBand_ResponseStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestGetBandShows_PastHistory(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band History", "test-band-history")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+
+	// Two past shows (one headlining, one support) and one upcoming show
+	shows := []struct {
+		daysFromNow int
+		headliner   bool
+	}{
+		{-60, true},
+		{-30, false},
+		{30, true},
+	}
+	var showIDs []int32
+	for i, s := range shows {
+		showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, s.daysFromNow), fmt.Sprintf("Band History Show %d", i))
+		if err != nil {
+			t.Fatalf("failed to insert test show: %v", err)
+		}
+		if err := tdb.LinkBandToShow(ctx, showID, bandID, s.headliner, 1); err != nil {
+			t.Fatalf("failed to link band to show: %v", err)
+		}
+		showIDs = append(showIDs, showID)
+	}
+
+	router := setupBandsTestRouter(tdb)
+
+	// Past shows are returned most recent first
+	req := httptest.NewRequest(http.MethodGet, "/api/bands/test-band-history/shows?when=past", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var listResp struct {
+		Data []struct {
+			ID          int32 `json:"id"`
+			IsHeadliner bool  `json:"is_headliner"`
+		} `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listResp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if listResp.Meta.Total != 2 {
+		t.Errorf("expected 2 past shows, got %d", listResp.Meta.Total)
+	}
+	if len(listResp.Data) == 2 && listResp.Data[0].ID != showIDs[1] {
+		t.Errorf("expected most recent past show %d first, got %d", showIDs[1], listResp.Data[0].ID)
+	}
+
+	// Band detail includes the history summary
+	req = httptest.NewRequest(http.MethodGet, "/api/bands/test-band-history", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var detailResp struct {
+		Data struct {
+			History struct {
+				TotalShows      int64   `json:"total_shows"`
+				HeadlinerCount  int64   `json:"headliner_count"`
+				SupportCount    int64   `json:"support_count"`
+				FirstSeen       *string `json:"first_seen"`
+				LastSeen        *string `json:"last_seen"`
+				VenuePlayCounts []struct {
+					ID        int32 `json:"id"`
+					ShowCount int64 `json:"show_count"`
+				} `json:"venue_play_counts"`
+			} `json:"history"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &detailResp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	history := detailResp.Data.History
+	if history.TotalShows != 2 {
+		t.Errorf("expected 2 total shows, got %d", history.TotalShows)
+	}
+	if history.HeadlinerCount != 1 || history.SupportCount != 1 {
+		t.Errorf("expected 1 headliner and 1 support, got %d and %d", history.HeadlinerCount, history.SupportCount)
+	}
+	if history.FirstSeen == nil || history.LastSeen == nil {
+		t.Error("expected first_seen and last_seen to be set")
+	}
+	if len(history.VenuePlayCounts) != 1 || history.VenuePlayCounts[0].ShowCount != 2 {
+		t.Errorf("expected one venue with 2 plays, got %+v", history.VenuePlayCounts)
+	}
+}
+
+func TestGetBandShows_InvalidParams(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupBandsTestRouter(tdb)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "invalid when",
+			path:           "/api/bands/test-band-history/shows?when=later",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown band",
+			path:           "/api/bands/non-existent-band/shows",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetSimilarBands_ExplainsScore(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+	genreID, err := tdb.GetFirstGenreID(ctx)
+	if err != nil {
+		t.Skipf("no genres in database: %v", err)
+	}
+
+	sourceID, err := tdb.InsertTestBand(ctx, "Test Band Explain Source", "test-band-explain-source")
+	if err != nil {
+		t.Fatalf("failed to insert source band: %v", err)
+	}
+	mateID, err := tdb.InsertTestBand(ctx, "Test Band Explain Mate", "test-band-explain-mate")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+	for _, id := range []int32{sourceID, mateID} {
+		if err := tdb.AddGenreToBand(ctx, id, genreID); err != nil {
+			t.Fatalf("failed to add genre: %v", err)
+		}
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Explain Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	tdb.LinkBandToShow(ctx, showID, sourceID, true, 2)
+	tdb.LinkBandToShow(ctx, showID, mateID, false, 1)
+
+	router := setupBandsTestRouter(tdb)
+
+	// No refresh has run, so the band is scored live
+	req := httptest.NewRequest(http.MethodGet, "/api/bands/test-band-explain-source/similar", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			ID         int32   `json:"id"`
+			Score      float64 `json:"score"`
+			Components struct {
+				Genre  float64 `json:"genre"`
+				CoBill float64 `json:"co_bill"`
+				Venue  float64 `json:"venue"`
+			} `json:"components"`
+			SharedShowCount  int64    `json:"shared_show_count"`
+			SharedVenueCount int64    `json:"shared_venue_count"`
+			Reasons          []string `json:"reasons"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(resp.Data) == 0 || resp.Data[0].ID != mateID {
+		t.Fatalf("expected co-billed band to rank first, got %+v", resp.Data)
+	}
+
+	mate := resp.Data[0]
+	if mate.Components.CoBill != 1 || mate.Components.Venue != 1 || mate.Components.Genre != 1 {
+		t.Errorf("expected full genre, co-bill and venue overlap, got %+v", mate.Components)
+	}
+	if mate.Score <= 0 || mate.Score > 1 {
+		t.Errorf("expected score in (0, 1], got %f", mate.Score)
+	}
+	if mate.SharedShowCount != 1 || mate.SharedVenueCount != 1 {
+		t.Errorf("expected 1 shared show and venue, got %d and %d", mate.SharedShowCount, mate.SharedVenueCount)
+	}
+
+	want := map[string]bool{"Played 1 show together": false, "Played the same venue": false}
+	for _, r := range mate.Reasons {
+		if _, ok := want[r]; ok {
+			want[r] = true
+		}
+	}
+	for reason, found := range want {
+		if !found {
+			t.Errorf("expected reason %q in %v", reason, mate.Reasons)
+		}
+	}
+}
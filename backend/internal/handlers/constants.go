@@ -1,5 +1,7 @@
 package handlers
 
+import "time"
+
 // API configuration constants.
 const (
 	// DefaultSimilarBandsLimit is the default number of similar bands to return.
@@ -14,6 +16,87 @@ const (
 	// MaxSearchLimit is the maximum number of search results per entity type.
 	MaxSearchLimit = 50
 
+	// SearchHeadlineOptions configures ts_headline snippets for highlighted search results.
+	SearchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" ... \""
+
+	// DefaultAutocompleteLimit is the default number of suggestions per entity type.
+	DefaultAutocompleteLimit = 5
+
+	// MaxAutocompleteLimit is the maximum number of suggestions per entity type.
+	MaxAutocompleteLimit = 10
+
+	// MaxAutocompleteQueryLength is the longest autocomplete input accepted.
+	MaxAutocompleteQueryLength = 100
+
+	// AutocompleteTimeout is the latency budget for an autocomplete query.
+	AutocompleteTimeout = 250 * time.Millisecond
+
+	// DefaultRecommendationsLimit is the default number of recommended shows to return.
+	DefaultRecommendationsLimit = 20
+
+	// MaxRecommendationsLimit is the maximum number of recommended shows that can be requested.
+	MaxRecommendationsLimit = 50
+
+	// MaxRecommendationSeeds is the maximum number of seed bands (and of seed genres) per request.
+	MaxRecommendationSeeds = 10
+
+	// MaxRecommendationReasons is the maximum number of reasons given per recommended show.
+	MaxRecommendationReasons = 3
+
+	// DefaultAppURL is the frontend base URL used in emailed links when none is configured.
+	DefaultAppURL = "http://localhost:3000"
+
+	// VerifyLoginPath is the frontend page that exchanges a sign-in link token for a session.
+	VerifyLoginPath = "/auth/verify"
+
 	// VenueUpcomingShowsLimit is the max number of upcoming shows to return for a venue.
 	VenueUpcomingShowsLimit = 50
+
+	// GenreUpcomingShowsLimit is the max number of upcoming shows to return for a genre.
+	GenreUpcomingShowsLimit = 20
+
+	// VenueStatsTopBandsLimit is the number of most frequent bands in venue stats.
+	VenueStatsTopBandsLimit = 10
+
+	// VenueStatsMonths is the number of trailing months covered by venue shows-per-month stats.
+	VenueStatsMonths = 12
+
+	// ServiceName identifies the API in health responses and traces.
+	ServiceName = "asheville-setlist-api"
+
+	// ReadinessTimeout bounds all readiness checks together.
+	ReadinessTimeout = 2 * time.Second
+)
+
+// Health and readiness check statuses.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+	HealthStatusFail        = "fail"
+	HealthStatusSkipped     = "skipped"
+	HealthStatusUnknown     = "unknown"
+)
+
+// Search response modes: results grouped by entity type, or one paginated,
+// relevance-ranked list.
+const (
+	SearchModeGrouped = "grouped"
+	SearchModeUnified = "unified"
+)
+
+// SearchTypes are the entity types accepted by the search type filter.
+var SearchTypes = []string{"band", "venue", "show", "article"}
+
+// Cache-Control policies for public responses, set per route or by handlers
+// whose freshness depends on the query.
+const (
+	// CachePolicyShort suits listings that change within minutes, like tonight's shows.
+	CachePolicyShort = "public, max-age=60, stale-while-revalidate=60"
+
+	// CachePolicyDefault suits show, venue and band listings and pages.
+	CachePolicyDefault = "public, max-age=300, stale-while-revalidate=600"
+
+	// CachePolicyLong suits rarely changing data like the genre list.
+	CachePolicyLong = "public, max-age=3600, stale-while-revalidate=86400"
 )
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/revision"
 )
 
 // showRowData holds common fields from all show list query results.
@@ -190,6 +196,36 @@ func convertDateRangeShowsToListItems(rows []db.ListShowsByDateRangeRow) ([]Show
 	return items, int(rows[0].TotalCount)
 }
 
+func convertStreamEventsToListItems(rows []db.ListShowStreamEventsRow) []ShowListItem {
+	items := make([]ShowListItem, len(rows))
+	for i, r := range rows {
+		items[i] = convertShowRowToListItem(showRowData{
+			ID: r.ID, Title: r.Title, ImageUrl: r.ImageUrl, Date: r.Date,
+			DoorsTime: r.DoorsTime, ShowTime: r.ShowTime, PriceMin: r.PriceMin,
+			PriceMax: r.PriceMax, TicketUrl: r.TicketUrl, AgeRestriction: r.AgeRestriction,
+			Status: r.Status, VenueID: r.VenueID, VenueName: r.VenueName,
+			VenueSlug: r.VenueSlug, VenueRegion: r.VenueRegion,
+			VenueAddress: r.VenueAddress, VenueImageUrl: r.VenueImageUrl,
+		})
+	}
+	return items
+}
+
+func convertShowsByIDsToListItems(rows []db.ListShowsByIDsRow) []ShowListItem {
+	items := make([]ShowListItem, len(rows))
+	for i, r := range rows {
+		items[i] = convertShowRowToListItem(showRowData{
+			ID: r.ID, Title: r.Title, ImageUrl: r.ImageUrl, Date: r.Date,
+			DoorsTime: r.DoorsTime, ShowTime: r.ShowTime, PriceMin: r.PriceMin,
+			PriceMax: r.PriceMax, TicketUrl: r.TicketUrl, AgeRestriction: r.AgeRestriction,
+			Status: r.Status, VenueID: r.VenueID, VenueName: r.VenueName,
+			VenueSlug: r.VenueSlug, VenueRegion: r.VenueRegion,
+			VenueAddress: r.VenueAddress, VenueImageUrl: r.VenueImageUrl,
+		})
+	}
+	return items
+}
+
 // Band list conversion functions.
 
 func convertBandsToListItems(rows []db.ListBandsRow) ([]BandListItem, int) {
@@ -246,6 +282,59 @@ func convertGenreBandsToListItems(rows []db.ListBandsByGenreRow) []BandListItem
 	return items
 }
 
+// Band show conversion functions.
+
+// bandShowRow holds the columns shared by a band's upcoming and past show rows.
+type bandShowRow struct {
+	ID          int32
+	Date        pgtype.Timestamptz
+	Title       *string
+	Status      *string
+	VenueID     int32
+	VenueName   string
+	VenueSlug   string
+	IsHeadliner *bool
+	TotalCount  int64
+}
+
+func bandUpcomingShowRows(rows []db.ListBandUpcomingShowsRow) []bandShowRow {
+	data := make([]bandShowRow, len(rows))
+	for i, r := range rows {
+		data[i] = bandShowRow(r)
+	}
+	return data
+}
+
+func bandPastShowRows(rows []db.ListBandPastShowsRow) []bandShowRow {
+	data := make([]bandShowRow, len(rows))
+	for i, r := range rows {
+		data[i] = bandShowRow(r)
+	}
+	return data
+}
+
+func convertBandShowsToItems(rows []bandShowRow) ([]BandShowItem, int) {
+	if len(rows) == 0 {
+		return []BandShowItem{}, 0
+	}
+	items := make([]BandShowItem, len(rows))
+	for i, r := range rows {
+		items[i] = BandShowItem{
+			ID:     r.ID,
+			Title:  r.Title,
+			Date:   formatTimestamp(r.Date),
+			Status: stringValue(r.Status),
+			Venue: VenueBasic{
+				ID:   r.VenueID,
+				Name: r.VenueName,
+				Slug: r.VenueSlug,
+			},
+			IsHeadliner: boolValue(r.IsHeadliner),
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
 // Venue list conversion functions.
 
 func convertVenuesToListItems(rows []db.ListVenuesWithShowCountRow) []VenueListItem {
@@ -284,6 +373,264 @@ func convertRegionVenuesToListItems(rows []db.ListVenuesByRegionRow) []VenueList
 	return items
 }
 
+// Venue show conversion functions.
+
+// venueShowRow holds the columns shared by a venue's upcoming and past show rows.
+type venueShowRow struct {
+	ID             int32
+	Title          *string
+	Date           pgtype.Timestamptz
+	PriceMin       pgtype.Numeric
+	PriceMax       pgtype.Numeric
+	TicketUrl      *string
+	AgeRestriction *string
+	Status         *string
+	TotalCount     int64
+}
+
+func venueUpcomingShowRows(rows []db.ListVenueUpcomingShowsRow) []venueShowRow {
+	data := make([]venueShowRow, len(rows))
+	for i, r := range rows {
+		data[i] = venueShowRow(r)
+	}
+	return data
+}
+
+func venuePastShowRows(rows []db.ListVenuePastShowsRow) []venueShowRow {
+	data := make([]venueShowRow, len(rows))
+	for i, r := range rows {
+		data[i] = venueShowRow(r)
+	}
+	return data
+}
+
+func convertVenueShowsToItems(rows []venueShowRow) ([]VenueShowItem, int) {
+	if len(rows) == 0 {
+		return []VenueShowItem{}, 0
+	}
+	items := make([]VenueShowItem, len(rows))
+	for i, r := range rows {
+		items[i] = VenueShowItem{
+			ID:       r.ID,
+			Title:    r.Title,
+			Date:     formatTimestamp(r.Date),
+			PriceMin: numericToFloat(r.PriceMin),
+			PriceMax: numericToFloat(r.PriceMax),
+			Status:   stringValue(r.Status),
+			Bands:    []BandBasic{},
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+// Search conversion functions.
+
+func convertSearchAllRowsToItems(rows []db.SearchAllRow, highlight bool) ([]SearchResultItem, int) {
+	if len(rows) == 0 {
+		return []SearchResultItem{}, 0
+	}
+	items := make([]SearchResultItem, len(rows))
+	for i, r := range rows {
+		items[i] = SearchResultItem{
+			Type:      r.EntityType,
+			ID:        r.ID,
+			Name:      r.Name,
+			Slug:      r.Slug,
+			Subtitle:  r.ExtraInfo,
+			Date:      formatTimestampPtr(r.DateInfo),
+			Rank:      r.Rank,
+			Highlight: searchHighlight(highlight, r.MatchedField, r.Snippet),
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+// Autocomplete conversion functions.
+
+func convertAutocompleteRowsToItems(rows []db.AutocompleteRow) []AutocompleteItem {
+	items := make([]AutocompleteItem, len(rows))
+	for i, r := range rows {
+		items[i] = AutocompleteItem{
+			Type:  r.EntityType,
+			ID:    r.ID,
+			Name:  r.Name,
+			Slug:  r.Slug,
+			Date:  formatTimestampPtr(r.Date),
+			Score: r.Score,
+		}
+	}
+	return items
+}
+
+// Show revision conversion functions.
+
+func convertShowRevisionsToItems(rows []db.ListShowRevisionsRow) ([]ShowRevisionItem, int) {
+	if len(rows) == 0 {
+		return []ShowRevisionItem{}, 0
+	}
+	items := make([]ShowRevisionItem, len(rows))
+	for i, r := range rows {
+		items[i] = ShowRevisionItem{
+			ID:        r.ID,
+			Field:     r.Field,
+			OldValue:  r.OldValue,
+			NewValue:  r.NewValue,
+			Actor:     r.Actor,
+			Source:    r.Source,
+			CreatedAt: formatTimestamp(r.CreatedAt),
+		}
+		// Submitters are anonymous; older revisions recorded their IP address
+		if r.Source == revision.SourceBandSubmitted {
+			items[i].Actor = nil
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+// Similar band conversion functions.
+
+// similarBandData holds the fields shared by precomputed and live similar-band rows.
+type similarBandData struct {
+	ID               int32
+	Name             string
+	Slug             string
+	ImageUrl         *string
+	Score            float64
+	GenreScore       float64
+	CoBillScore      float64
+	VenueScore       float64
+	HometownScore    float64
+	SharedGenreCount int32
+	SharedShowCount  int32
+	SharedVenueCount int32
+}
+
+func convertStoredSimilarBands(rows []db.ListBandSimilaritiesRow) []similarBandData {
+	data := make([]similarBandData, len(rows))
+	for i, r := range rows {
+		data[i] = similarBandData{
+			ID:               r.ID,
+			Name:             r.Name,
+			Slug:             r.Slug,
+			ImageUrl:         r.ImageUrl,
+			Score:            r.Score,
+			GenreScore:       r.GenreScore,
+			CoBillScore:      r.CoBillScore,
+			VenueScore:       r.VenueScore,
+			HometownScore:    r.HometownScore,
+			SharedGenreCount: r.SharedGenreCount,
+			SharedShowCount:  r.SharedShowCount,
+			SharedVenueCount: r.SharedVenueCount,
+		}
+	}
+	return data
+}
+
+func convertScoredSimilarBands(rows []db.ScoreSimilarBandsRow) []similarBandData {
+	data := make([]similarBandData, len(rows))
+	for i, r := range rows {
+		data[i] = similarBandData{
+			ID:               r.SimilarBandID,
+			Name:             r.Name,
+			Slug:             r.Slug,
+			ImageUrl:         r.ImageUrl,
+			Score:            r.Score,
+			GenreScore:       r.GenreScore,
+			CoBillScore:      r.CoBillScore,
+			VenueScore:       r.VenueScore,
+			HometownScore:    r.HometownScore,
+			SharedGenreCount: r.SharedGenreCount,
+			SharedShowCount:  r.SharedShowCount,
+			SharedVenueCount: r.SharedVenueCount,
+		}
+	}
+	return data
+}
+
+// convertSimilarBandToItem converts a similar-band row to SimilarBandItem,
+// explaining the score in plain language.
+func convertSimilarBandToItem(r similarBandData, sharedGenres []GenreBasic, hometown *string) SimilarBandItem {
+	reasons := []string{}
+	if len(sharedGenres) > 0 {
+		names := make([]string, len(sharedGenres))
+		for i, g := range sharedGenres {
+			names[i] = g.Name
+		}
+		reasons = append(reasons, fmt.Sprintf("Shares %s: %s", pluralize(len(sharedGenres), "genre"), strings.Join(names, ", ")))
+	}
+	if r.SharedShowCount > 0 {
+		reasons = append(reasons, fmt.Sprintf("Played %s together", pluralize(int(r.SharedShowCount), "show")))
+	}
+	switch {
+	case r.SharedVenueCount == 1:
+		reasons = append(reasons, "Played the same venue")
+	case r.SharedVenueCount > 1:
+		reasons = append(reasons, fmt.Sprintf("Played %d of the same venues", r.SharedVenueCount))
+	}
+	if r.HometownScore > 0 && hometown != nil {
+		reasons = append(reasons, "Both from "+strings.TrimSpace(*hometown))
+	}
+
+	return SimilarBandItem{
+		ID:       r.ID,
+		Name:     r.Name,
+		Slug:     r.Slug,
+		ImageURL: r.ImageUrl,
+		Score:    roundScore(r.Score),
+		Components: SimilarityComponents{
+			Genre:    roundScore(r.GenreScore),
+			CoBill:   roundScore(r.CoBillScore),
+			Venue:    roundScore(r.VenueScore),
+			Hometown: roundScore(r.HometownScore),
+		},
+		SharedGenreCount: int64(r.SharedGenreCount),
+		SharedGenres:     sharedGenres,
+		SharedShowCount:  int64(r.SharedShowCount),
+		SharedVenueCount: int64(r.SharedVenueCount),
+		Reasons:          reasons,
+	}
+}
+
+// pluralize formats a count with a singular or plural noun, e.g. "1 show", "3 shows".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// roundScore rounds a score to 3 decimal places for display.
+func roundScore(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+// Genre suggestion conversion functions.
+
+func convertGenreSuggestionsToItems(rows []db.ListGenreSuggestionsRow) ([]GenreSuggestionItem, int) {
+	if len(rows) == 0 {
+		return []GenreSuggestionItem{}, 0
+	}
+	items := make([]GenreSuggestionItem, len(rows))
+	for i, r := range rows {
+		items[i] = GenreSuggestionItem{
+			Band: BandRef{
+				ID:   r.BandID,
+				Name: r.BandName,
+				Slug: r.BandSlug,
+			},
+			Genre: GenreBasic{
+				ID:   r.GenreID,
+				Name: r.GenreName,
+				Slug: r.GenreSlug,
+			},
+			Source:     r.Source,
+			Confidence: numericToFloat(r.Confidence),
+			CreatedAt:  formatTimestamp(r.CreatedAt),
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
 // Genre conversion functions.
 
 func convertGenreRows(rows []db.GetBandGenresForShowRow) []GenreBasic {
@@ -297,3 +644,110 @@ func convertGenreRows(rows []db.GetBandGenresForShowRow) []GenreBasic {
 	}
 	return genres
 }
+
+// User account conversion functions.
+
+func convertUserToItem(u db.User) UserItem {
+	return UserItem{
+		ID:          u.ID,
+		Email:       u.Email,
+		CreatedAt:   formatTimestamp(u.CreatedAt),
+		LastLoginAt: formatTimestampPtr(u.LastLoginAt),
+	}
+}
+
+func convertSavedShowsToListItems(rows []db.ListSavedShowsRow) ([]ShowListItem, int) {
+	if len(rows) == 0 {
+		return []ShowListItem{}, 0
+	}
+	items := make([]ShowListItem, len(rows))
+	for i, r := range rows {
+		items[i] = convertShowRowToListItem(showRowData{
+			ID: r.ID, Title: r.Title, ImageUrl: r.ImageUrl, Date: r.Date,
+			DoorsTime: r.DoorsTime, ShowTime: r.ShowTime, PriceMin: r.PriceMin,
+			PriceMax: r.PriceMax, TicketUrl: r.TicketUrl, AgeRestriction: r.AgeRestriction,
+			Status: r.Status, VenueID: r.VenueID, VenueName: r.VenueName,
+			VenueSlug: r.VenueSlug, VenueRegion: r.VenueRegion,
+			VenueAddress: r.VenueAddress, VenueImageUrl: r.VenueImageUrl,
+		})
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+func convertFeedToListItems(rows []db.ListUserFeedRow) ([]ShowListItem, int) {
+	if len(rows) == 0 {
+		return []ShowListItem{}, 0
+	}
+	items := make([]ShowListItem, len(rows))
+	for i, r := range rows {
+		items[i] = convertShowRowToListItem(showRowData{
+			ID: r.ID, Title: r.Title, ImageUrl: r.ImageUrl, Date: r.Date,
+			DoorsTime: r.DoorsTime, ShowTime: r.ShowTime, PriceMin: r.PriceMin,
+			PriceMax: r.PriceMax, TicketUrl: r.TicketUrl, AgeRestriction: r.AgeRestriction,
+			Status: r.Status, VenueID: r.VenueID, VenueName: r.VenueName,
+			VenueSlug: r.VenueSlug, VenueRegion: r.VenueRegion,
+			VenueAddress: r.VenueAddress, VenueImageUrl: r.VenueImageUrl,
+		})
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+func convertNotificationsToItems(rows []db.ListUserNotificationsRow) ([]NotificationItem, int) {
+	if len(rows) == 0 {
+		return []NotificationItem{}, 0
+	}
+	items := make([]NotificationItem, len(rows))
+	for i, r := range rows {
+		n := notify.Notification{
+			ID:        r.ID,
+			Kind:      r.Kind,
+			ShowID:    r.ShowID,
+			ShowName:  notify.ShowName(r.ShowTitle, r.HeadlinerName),
+			ShowDate:  r.ShowDate.Time,
+			VenueName: r.VenueName,
+			BandName:  stringValue(r.BandName),
+		}
+		items[i] = NotificationItem{
+			ID:        r.ID,
+			Kind:      r.Kind,
+			Message:   n.Message(),
+			ShowID:    r.ShowID,
+			ShowName:  n.ShowName,
+			ShowDate:  formatTimestamp(r.ShowDate),
+			VenueName: r.VenueName,
+			VenueSlug: r.VenueSlug,
+			BandName:  r.BandName,
+			BandSlug:  r.BandSlug,
+			CreatedAt: formatTimestamp(r.CreatedAt),
+			ReadAt:    formatTimestampPtr(r.ReadAt),
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
+
+func convertWebhookDeliveriesToItems(rows []db.ListWebhookDeliveriesRow) ([]WebhookDeliveryItem, int) {
+	if len(rows) == 0 {
+		return []WebhookDeliveryItem{}, 0
+	}
+	items := make([]WebhookDeliveryItem, len(rows))
+	for i, r := range rows {
+		items[i] = WebhookDeliveryItem{
+			ID:             r.ID,
+			SubscriptionID: r.SubscriptionID,
+			EventID:        r.EventID,
+			EventType:      r.EventType,
+			Status:         r.Status,
+			AttemptCount:   r.AttemptCount,
+			LastAttemptAt:  formatTimestampPtr(r.LastAttemptAt),
+			ResponseStatus: r.ResponseStatus,
+			LastError:      r.LastError,
+			ReplayOf:       r.ReplayOf,
+			CreatedAt:      formatTimestamp(r.CreatedAt),
+			DeliveredAt:    formatTimestampPtr(r.DeliveredAt),
+		}
+		if r.Status == "pending" {
+			items[i].NextAttemptAt = formatTimestampPtr(r.NextAttemptAt)
+		}
+	}
+	return items, int(rows[0].TotalCount)
+}
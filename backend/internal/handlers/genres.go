@@ -1,10 +1,12 @@
 package handlers
 
 import (
-	"log/slog"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/db"
 )
 
 // ListGenres handles GET /api/genres with show counts.
@@ -13,7 +15,7 @@ func (h *Handler) ListGenres(c *gin.Context) {
 
 	rows, err := h.queries.ListGenresWithShowCount(ctx)
 	if err != nil {
-		slog.Error("failed to list genres", "error", err)
+		logger(c).Error("failed to list genres", "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -25,9 +27,130 @@ func (h *Handler) ListGenres(c *gin.Context) {
 			Name:        r.Name,
 			Slug:        r.Slug,
 			Description: r.Description,
+			ParentID:    r.ParentID,
 			ShowCount:   r.ShowCount,
 		}
 	}
 
+	h.setCatalogLastModified(c)
 	respondJSON(c, http.StatusOK, genres)
 }
+
+// GetGenre handles GET /api/genres/:slug.
+// Returns the genre with its parent and subgenres, a page of its bands and
+// its upcoming shows. Bands and shows include those of all subgenres.
+func (h *Handler) GetGenre(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	slug := c.Param("slug")
+	if slug == "" {
+		respondInvalidParam(c, "slug", "genre slug is required")
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	genre, err := h.queries.GetGenreBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondNotFound(c, "Genre")
+			return
+		}
+		logger(c).Error("failed to get genre", "slug", slug, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	var parent *GenreBasic
+	if genre.ParentID != nil {
+		parent = &GenreBasic{
+			ID:   *genre.ParentID,
+			Name: stringValue(genre.ParentName),
+			Slug: stringValue(genre.ParentSlug),
+		}
+	}
+
+	childRows, err := h.queries.ListGenreChildren(ctx, &genre.ID)
+	if err != nil {
+		logger(c).Error("failed to list genre children", "genre_id", genre.ID, "error", err)
+		childRows = []db.ListGenreChildrenRow{}
+	}
+
+	children := make([]GenreBasic, len(childRows))
+	for i, r := range childRows {
+		children[i] = GenreBasic{
+			ID:   r.ID,
+			Name: r.Name,
+			Slug: r.Slug,
+		}
+	}
+
+	slugs := []string{genre.Slug}
+
+	bandRows, err := h.queries.ListBandsByGenre(ctx, db.ListBandsByGenreParams{
+		Column1: slugs,
+		Limit:   int32(perPage),
+		Offset:  int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list bands by genre", "genre_id", genre.ID, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	bandCount, err := h.queries.CountBandsByGenre(ctx, slugs)
+	if err != nil {
+		logger(c).Error("failed to count bands by genre", "genre_id", genre.ID, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	bands := convertGenreBandsToListItems(bandRows)
+	if len(bands) > 0 {
+		h.attachGenresToBands(ctx, bands)
+	}
+
+	showRows, err := h.queries.ListShowsByGenre(ctx, db.ListShowsByGenreParams{
+		Column1: slugs,
+		Limit:   GenreUpcomingShowsLimit,
+		Offset:  0,
+	})
+	if err != nil {
+		logger(c).Error("failed to list shows by genre", "genre_id", genre.ID, "error", err)
+		showRows = []db.ListShowsByGenreRow{}
+	}
+
+	shows := convertGenreShowsToListItems(showRows)
+	if len(shows) > 0 {
+		h.attachBandsToShows(ctx, shows)
+	}
+
+	detail := GenreDetail{
+		ID:            genre.ID,
+		Name:          genre.Name,
+		Slug:          genre.Slug,
+		Description:   genre.Description,
+		Parent:        parent,
+		Children:      children,
+		Bands:         bands,
+		UpcomingShows: shows,
+	}
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      int(bandCount),
+		TotalPages: calculateTotalPages(int(bandCount), perPage),
+	}
+
+	h.setCatalogLastModified(c)
+	respondJSONWithMeta(c, http.StatusOK, detail, meta)
+}
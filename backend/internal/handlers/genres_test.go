@@ -0,0 +1,202 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// setupGenresTestRouter creates a test router with the genres handlers
+func setupGenresTestRouter(tdb *testutil.TestDB) *gin.Engine {
+	h := handlers.New(tdb.Queries)
+	router := gin.New()
+	router.GET("/api/genres", h.ListGenres)
+	router.GET("/api/genres/:slug", h.GetGenre)
+	router.GET("/api/shows", h.ListShows)
+	return router
+}
+
+type genreDetailResponse struct {
+	Data struct {
+		ID     int32  `json:"id"`
+		Name   string `json:"name"`
+		Slug   string `json:"slug"`
+		Parent *struct {
+			Slug string `json:"slug"`
+		} `json:"parent"`
+		Children []struct {
+			Slug string `json:"slug"`
+		} `json:"children"`
+		Bands []struct {
+			ID   int32  `json:"id"`
+			Slug string `json:"slug"`
+		} `json:"bands"`
+		UpcomingShows []struct {
+			ID int32 `json:"id"`
+		} `json:"upcoming_shows"`
+	} `json:"data"`
+	Meta struct {
+		Page  int `json:"page"`
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+func TestGetGenre_Hierarchy(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupGenresTestRouter(tdb)
+
+	get := func(slug string) genreDetailResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/genres/"+slug, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp genreDetailResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return resp
+	}
+
+	americana := get("americana")
+	if americana.Data.Parent != nil {
+		t.Errorf("expected americana to be a top-level genre, got parent %s", americana.Data.Parent.Slug)
+	}
+
+	found := false
+	for _, child := range americana.Data.Children {
+		if child.Slug == "bluegrass" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected bluegrass among americana subgenres, got %+v", americana.Data.Children)
+	}
+
+	bluegrass := get("bluegrass")
+	if bluegrass.Data.Parent == nil || bluegrass.Data.Parent.Slug != "americana" {
+		t.Errorf("expected bluegrass parent americana, got %+v", bluegrass.Data.Parent)
+	}
+	if bluegrass.Data.Bands == nil || bluegrass.Data.UpcomingShows == nil || bluegrass.Data.Children == nil {
+		t.Error("bands, upcoming_shows and children should not be nil")
+	}
+	if bluegrass.Meta.Page != 1 {
+		t.Errorf("expected meta page 1, got %d", bluegrass.Meta.Page)
+	}
+}
+
+func TestGetGenre_IncludesSubgenres(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	var bluegrassID int32
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id FROM genres WHERE slug = 'bluegrass'`).Scan(&bluegrassID); err != nil {
+		t.Skipf("bluegrass genre not seeded: %v", err)
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Subgenre Pickers", "test-band-subgenre-pickers")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	if err := tdb.AddGenreToBand(ctx, bandID, bluegrassID); err != nil {
+		t.Fatalf("failed to add genre to band: %v", err)
+	}
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 3), "Subgenre Show")
+	if err != nil {
+		t.Fatalf("failed to insert test show: %v", err)
+	}
+	if err := tdb.LinkBandToShow(ctx, showID, bandID, true, 1); err != nil {
+		t.Fatalf("failed to link band to show: %v", err)
+	}
+
+	router := setupGenresTestRouter(tdb)
+
+	// Genre detail for the parent genre includes the subgenre band and show
+	req := httptest.NewRequest(http.MethodGet, "/api/genres/americana?per_page=100", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var detail genreDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	foundBand := false
+	for _, b := range detail.Data.Bands {
+		if b.ID == bandID {
+			foundBand = true
+		}
+	}
+	if !foundBand {
+		t.Error("expected bluegrass band in americana genre bands")
+	}
+
+	// Show filter by the parent genre includes the subgenre show
+	req = httptest.NewRequest(http.MethodGet, "/api/shows?genre=americana&per_page=100", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var list struct {
+		Data []struct {
+			ID int32 `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	foundShow := false
+	for _, s := range list.Data {
+		if s.ID == showID {
+			foundShow = true
+		}
+	}
+	if !foundShow {
+		t.Error("expected bluegrass show when filtering shows by americana")
+	}
+}
+
+func TestGetGenre_NotFound(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupGenresTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/genres/not-a-genre", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
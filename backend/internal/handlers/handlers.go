@@ -1,17 +1,132 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/mail"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/stream"
 )
 
 // Handler contains all HTTP handlers and their dependencies
 type Handler struct {
-	queries *db.Queries
+	queries           *db.Queries
+	similarityWeights similarity.Weights
+	mailer            mail.Mailer
+	appURL            string
+	vapidPublicKey    string
+	streamHub         *stream.Hub
+	streamHeartbeat   time.Duration
+	pool              TxBeginner
+	database          Database
+	scraperStaleAfter time.Duration
+}
+
+// TxBeginner starts the transactions write handlers run in:
+// *pgxpool.Pool satisfies it
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Database is what readiness checks need from the connection pool:
+// *pgxpool.Pool satisfies it
+type Database interface {
+	db.DBTX
+	Ping(ctx context.Context) error
+}
+
+// Option configures optional Handler dependencies
+type Option func(*Handler)
+
+// WithSimilarityWeights sets the weights used when similar bands are scored live
+func WithSimilarityWeights(w similarity.Weights) Option {
+	return func(h *Handler) {
+		h.similarityWeights = w
+	}
+}
+
+// WithMailer sets the mailer used to deliver sign-in links
+func WithMailer(m mail.Mailer) Option {
+	return func(h *Handler) {
+		h.mailer = m
+	}
+}
+
+// WithAppURL sets the frontend base URL that emailed links point to
+func WithAppURL(url string) Option {
+	return func(h *Handler) {
+		h.appURL = url
+	}
+}
+
+// WithVAPIDPublicKey sets the Web Push application server key handed to
+// browsers (empty disables Web Push subscriptions)
+func WithVAPIDPublicKey(key string) Option {
+	return func(h *Handler) {
+		h.vapidPublicKey = key
+	}
+}
+
+// WithPool sets the pool write handlers run their transactions on
+func WithPool(pool TxBeginner) Option {
+	return func(h *Handler) {
+		h.pool = pool
+	}
+}
+
+// WithShowStream sets the hub that wakes /api/stream/shows clients and how
+// often idle clients get a heartbeat (nil disables the stream)
+func WithShowStream(hub *stream.Hub, heartbeat time.Duration) Option {
+	return func(h *Handler) {
+		h.streamHub = hub
+		h.streamHeartbeat = heartbeat
+	}
+}
+
+// WithReadiness sets the pool /readyz pings and checks the schema version on,
+// and how old the last successful scrape may be before the scraper is
+// reported degraded (0 skips the freshness check)
+func WithReadiness(database Database, scraperStaleAfter time.Duration) Option {
+	return func(h *Handler) {
+		h.database = database
+		h.scraperStaleAfter = scraperStaleAfter
+	}
 }
 
 // New creates a new Handler with the given dependencies
-func New(queries *db.Queries) *Handler {
-	return &Handler{
-		queries: queries,
+func New(queries *db.Queries, opts ...Option) *Handler {
+	h := &Handler{
+		queries:           queries,
+		similarityWeights: similarity.DefaultWeights,
+		mailer:            mail.LogMailer{},
+		appURL:            DefaultAppURL,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// inTx runs fn with queries bound to a new transaction, committing when fn
+// succeeds and rolling back otherwise.
+func (h *Handler) inTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	if h.pool == nil {
+		return errors.New("no database pool configured for transactions")
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(h.queries.WithTx(tx)); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
 }
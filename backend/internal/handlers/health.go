@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/version"
+	"github.com/paulsena/asheville-setlist/migrations"
+)
+
+// Healthz handles GET /healthz. It is a liveness check: it answers as long as
+// the process can serve requests and touches no dependencies.
+func (h *Handler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse(HealthStatusOK))
+}
+
+// Readyz handles GET /readyz. The API is ready when the database answers a
+// ping and has a clean migration version; otherwise it returns 503 so load
+// balancers stop routing to it. Pending migrations and a stale scraper only
+// degrade readiness: the API still serves, with old data or missing features.
+func (h *Handler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ReadinessTimeout)
+	defer cancel()
+
+	checks := map[string]HealthCheck{
+		"database":   h.checkDatabase(ctx, c),
+		"migrations": {Status: HealthStatusSkipped},
+		"scraper":    {Status: HealthStatusSkipped},
+	}
+	if checks["database"].Status == HealthStatusOK {
+		checks["migrations"] = h.checkMigrations(ctx, c)
+		checks["scraper"] = h.checkScraper(ctx, c)
+	}
+
+	status, code := HealthStatusOK, http.StatusOK
+	for _, check := range checks {
+		switch {
+		case check.Status == HealthStatusFail:
+			status, code = HealthStatusUnavailable, http.StatusServiceUnavailable
+		case check.Status == HealthStatusDegraded && status == HealthStatusOK:
+			status = HealthStatusDegraded
+		}
+	}
+
+	c.JSON(code, ReadinessResponse{
+		HealthResponse: healthResponse(status),
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Checks:         checks,
+	})
+}
+
+// checkDatabase pings the pool.
+func (h *Handler) checkDatabase(ctx context.Context, c *gin.Context) HealthCheck {
+	if h.database == nil {
+		return failedCheck("not configured")
+	}
+
+	start := time.Now()
+	if err := h.database.Ping(ctx); err != nil {
+		logger(c).Warn("readiness: database ping failed", "error", err)
+		return failedCheck("ping failed")
+	}
+	latency := time.Since(start).Milliseconds()
+	return HealthCheck{Status: HealthStatusOK, LatencyMS: &latency}
+}
+
+// checkMigrations fails when no migration has been applied or the last one
+// failed partway, and reports degraded when the schema is older than the
+// migrations embedded in this build.
+func (h *Handler) checkMigrations(ctx context.Context, c *gin.Context) HealthCheck {
+	current, dirty, err := migrations.CurrentVersion(ctx, h.database)
+	if errors.Is(err, migrations.ErrNoVersion) {
+		return failedCheck("no migrations applied")
+	}
+	if err != nil {
+		logger(c).Warn("readiness: failed to read schema version", "error", err)
+		return failedCheck("version unavailable")
+	}
+
+	expected := migrations.Latest()
+	check := HealthCheck{Status: HealthStatusOK, Version: &current, Expected: &expected, Dirty: &dirty}
+	switch {
+	case dirty:
+		message := "last migration failed partway"
+		check.Status = HealthStatusFail
+		check.Error = &message
+	case current < expected:
+		message := "pending migrations"
+		check.Status = HealthStatusDegraded
+		check.Error = &message
+	}
+	return check
+}
+
+// checkScraper reports degraded when the last successful scrape is older
+// than the configured window, and unknown when nothing was ever scraped.
+func (h *Handler) checkScraper(ctx context.Context, c *gin.Context) HealthCheck {
+	if h.scraperStaleAfter <= 0 {
+		return HealthCheck{Status: HealthStatusSkipped}
+	}
+
+	staleAfter := h.scraperStaleAfter.String()
+	lastScrape, err := h.queries.GetLastScrapeAt(ctx)
+	if err != nil {
+		logger(c).Warn("readiness: failed to get last scrape time", "error", err)
+		return HealthCheck{Status: HealthStatusUnknown, StaleAfter: &staleAfter}
+	}
+	if !lastScrape.Valid {
+		return HealthCheck{Status: HealthStatusUnknown, StaleAfter: &staleAfter}
+	}
+
+	check := HealthCheck{
+		Status:        HealthStatusOK,
+		LastSuccessAt: formatTimestampPtr(lastScrape),
+		StaleAfter:    &staleAfter,
+	}
+	if time.Since(lastScrape.Time) > h.scraperStaleAfter {
+		check.Status = HealthStatusDegraded
+	}
+	return check
+}
+
+func healthResponse(status string) HealthResponse {
+	return HealthResponse{
+		Status:  status,
+		Service: ServiceName,
+		Version: version.Version,
+		Commit:  version.Commit,
+	}
+}
+
+func failedCheck(message string) HealthCheck {
+	return HealthCheck{Status: HealthStatusFail, Error: &message}
+}
@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/migrations"
+)
+
+// fakeDatabase answers the readiness queries without Postgres.
+type fakeDatabase struct {
+	pingErr    error
+	version    int64
+	dirty      bool
+	lastScrape pgtype.Timestamptz
+}
+
+func (f *fakeDatabase) Ping(ctx context.Context) error { return f.pingErr }
+
+func (f *fakeDatabase) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (f *fakeDatabase) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeDatabase) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if strings.Contains(sql, "schema_migrations") {
+		return fakeRow(func(dest ...any) error {
+			*dest[0].(*int64) = f.version
+			*dest[1].(*bool) = f.dirty
+			return nil
+		})
+	}
+	return fakeRow(func(dest ...any) error {
+		*dest[0].(*pgtype.Timestamptz) = f.lastScrape
+		return nil
+	})
+}
+
+func (f *fakeDatabase) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+type fakeRow func(dest ...any) error
+
+func (r fakeRow) Scan(dest ...any) error { return r(dest...) }
+
+func setupHealthTestRouter(database *fakeDatabase) *gin.Engine {
+	h := handlers.New(db.New(database), handlers.WithReadiness(database, 24*time.Hour))
+	router := gin.New()
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
+	return router
+}
+
+func TestHealthz(t *testing.T) {
+	router := setupHealthTestRouter(&fakeDatabase{pingErr: errors.New("down")})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Liveness does not depend on the database
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp handlers.HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Status != handlers.HealthStatusOK || resp.Service != handlers.ServiceName || resp.Version == "" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	latest := int64(migrations.Latest())
+	recent := pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	stale := pgtype.Timestamptz{Time: time.Now().Add(-48 * time.Hour), Valid: true}
+
+	tests := []struct {
+		name           string
+		database       *fakeDatabase
+		expectedStatus int
+		expectedHealth string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "ready",
+			database:       &fakeDatabase{version: latest, lastScrape: recent},
+			expectedStatus: http.StatusOK,
+			expectedHealth: handlers.HealthStatusOK,
+			expectedChecks: map[string]string{"database": "ok", "migrations": "ok", "scraper": "ok"},
+		},
+		{
+			name:           "database down",
+			database:       &fakeDatabase{pingErr: errors.New("connection refused")},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHealth: handlers.HealthStatusUnavailable,
+			expectedChecks: map[string]string{"database": "fail", "migrations": "skipped", "scraper": "skipped"},
+		},
+		{
+			name:           "dirty schema",
+			database:       &fakeDatabase{version: latest, dirty: true, lastScrape: recent},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHealth: handlers.HealthStatusUnavailable,
+			expectedChecks: map[string]string{"database": "ok", "migrations": "fail", "scraper": "ok"},
+		},
+		{
+			name:           "pending migrations",
+			database:       &fakeDatabase{version: latest - 1, lastScrape: recent},
+			expectedStatus: http.StatusOK,
+			expectedHealth: handlers.HealthStatusDegraded,
+			expectedChecks: map[string]string{"database": "ok", "migrations": "degraded", "scraper": "ok"},
+		},
+		{
+			name:           "stale scraper",
+			database:       &fakeDatabase{version: latest, lastScrape: stale},
+			expectedStatus: http.StatusOK,
+			expectedHealth: handlers.HealthStatusDegraded,
+			expectedChecks: map[string]string{"database": "ok", "migrations": "ok", "scraper": "degraded"},
+		},
+		{
+			name:           "never scraped",
+			database:       &fakeDatabase{version: latest},
+			expectedStatus: http.StatusOK,
+			expectedHealth: handlers.HealthStatusOK,
+			expectedChecks: map[string]string{"database": "ok", "migrations": "ok", "scraper": "unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupHealthTestRouter(tt.database)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			var resp handlers.ReadinessResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Status != tt.expectedHealth {
+				t.Errorf("expected status %q, got %q", tt.expectedHealth, resp.Status)
+			}
+			for name, want := range tt.expectedChecks {
+				if got := resp.Checks[name].Status; got != want {
+					t.Errorf("expected %s check %q, got %q", name, want, got)
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,15 @@ func formatTimestamp(ts pgtype.Timestamptz) string {
 	return ts.Time.Format(time.RFC3339)
 }
 
+// formatTimestampPtr converts a pgtype.Timestamptz to an RFC3339 string pointer, nil if not set.
+func formatTimestampPtr(ts pgtype.Timestamptz) *string {
+	if !ts.Valid {
+		return nil
+	}
+	s := formatTimestamp(ts)
+	return &s
+}
+
 // formatTime converts a pgtype.Time to HH:MM:SS string.
 func formatTime(t pgtype.Time) *string {
 	if !t.Valid {
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// setShowsLastModified sets Last-Modified from the newest updated_at of the
+// shows, their venues and their lineups.
+func (h *Handler) setShowsLastModified(c *gin.Context, showIDs []int32) {
+	if len(showIDs) == 0 {
+		return
+	}
+	ts, err := h.queries.GetShowsLastModified(c.Request.Context(), showIDs)
+	if err != nil {
+		logger(c).Warn("failed to get shows last modified", "error", err)
+		return
+	}
+	setLastModified(c, ts)
+}
+
+// setVenuesLastModified sets Last-Modified from the newest updated_at of the
+// venues, their shows and the bands on them.
+func (h *Handler) setVenuesLastModified(c *gin.Context, venueIDs []int32) {
+	if len(venueIDs) == 0 {
+		return
+	}
+	ts, err := h.queries.GetVenuesLastModified(c.Request.Context(), venueIDs)
+	if err != nil {
+		logger(c).Warn("failed to get venues last modified", "error", err)
+		return
+	}
+	setLastModified(c, ts)
+}
+
+// setCatalogLastModified sets Last-Modified from the newest updated_at of any
+// show, venue or band, for responses aggregating the whole catalog.
+func (h *Handler) setCatalogLastModified(c *gin.Context) {
+	ts, err := h.queries.GetCatalogLastModified(c.Request.Context())
+	if err != nil {
+		logger(c).Warn("failed to get catalog last modified", "error", err)
+		return
+	}
+	setLastModified(c, ts)
+}
+
+// setLastModified sets the Last-Modified header; NULL timestamps leave it unset.
+func setLastModified(c *gin.Context, ts pgtype.Timestamptz) {
+	if !ts.Valid {
+		return
+	}
+	c.Header("Last-Modified", ts.Time.UTC().Format(http.TimeFormat))
+}
+
+// showListIDs returns the IDs of show list items.
+func showListIDs(shows []ShowListItem) []int32 {
+	ids := make([]int32, len(shows))
+	for i, s := range shows {
+		ids[i] = s.ID
+	}
+	return ids
+}
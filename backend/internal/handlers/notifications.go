@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/auth"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+)
+
+// maxChannelTargetLength bounds webhook URLs and push endpoints.
+const maxChannelTargetLength = 2048
+
+// ListNotifications handles GET /api/me/notifications.
+// Lists the user's notifications, newest first, with pagination.
+func (h *Handler) ListNotifications(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	rows, err := h.queries.ListUserNotifications(c.Request.Context(), db.ListUserNotificationsParams{
+		UserID: user.ID,
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list notifications", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	items, total := convertNotificationsToItems(rows)
+
+	respondJSONWithMeta(c, http.StatusOK, items, &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	})
+}
+
+// MarkNotificationsRead handles POST /api/me/notifications/read.
+// Marks all of the user's unread notifications read.
+func (h *Handler) MarkNotificationsRead(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.queries.MarkNotificationsRead(c.Request.Context(), user.ID)
+	if err != nil {
+		logger(c).Error("failed to mark notifications read", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"updated": count,
+	})
+}
+
+// ListNotificationChannels handles GET /api/me/notification-channels.
+func (h *Handler) ListNotificationChannels(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	rows, err := h.queries.ListNotificationChannels(c.Request.Context(), user.ID)
+	if err != nil {
+		logger(c).Error("failed to list notification channels", "error", err, "user_id", user.ID)
+		respondInternalError(c)
+		return
+	}
+
+	channels := make([]NotificationChannelItem, len(rows))
+	for i, r := range rows {
+		channels[i] = NotificationChannelItem{
+			ID:         r.ID,
+			Kind:       r.Kind,
+			Target:     r.Target,
+			Frequency:  r.Frequency,
+			LastSentAt: formatTimestampPtr(r.LastSentAt),
+			CreatedAt:  formatTimestamp(r.CreatedAt),
+		}
+	}
+
+	respondJSON(c, http.StatusOK, channels)
+}
+
+// CreateNotificationChannel handles POST /api/me/notification-channels.
+// Adds an email, webhook or Web Push channel. Adding a channel that already
+// exists updates its frequency (and push keys) and re-enables it.
+func (h *Handler) CreateNotificationChannel(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	frequency := req.Frequency
+	if frequency == "" {
+		frequency = notify.FrequencyInstant
+	}
+	if frequency != notify.FrequencyInstant && frequency != notify.FrequencyDaily {
+		respondValidationError(c, "Invalid frequency", map[string]any{
+			"frequency": "must be 'instant' or 'daily'",
+		})
+		return
+	}
+
+	params := db.UpsertNotificationChannelParams{
+		UserID:    user.ID,
+		Kind:      req.Kind,
+		Frequency: frequency,
+	}
+
+	switch req.Kind {
+	case notify.ChannelEmail:
+		params.Target = user.Email
+
+	case notify.ChannelWebhook:
+		if !validChannelURL(req.URL, "http", "https") {
+			respondValidationError(c, "Invalid webhook URL", map[string]any{
+				"url": "must be an http or https URL",
+			})
+			return
+		}
+		params.Target = req.URL
+
+	case notify.ChannelWebPush:
+		if h.vapidPublicKey == "" {
+			respondValidationError(c, "Web Push is not enabled", map[string]any{
+				"kind": "web_push is not available on this server",
+			})
+			return
+		}
+		sub := req.Subscription
+		if sub == nil || !validChannelURL(sub.Endpoint, "https") {
+			respondValidationError(c, "Invalid push subscription", map[string]any{
+				"subscription": "endpoint must be an https URL",
+			})
+			return
+		}
+		if err := notify.ValidatePushSubscription(sub.Keys.P256dh, sub.Keys.Auth); err != nil {
+			respondValidationError(c, "Invalid push subscription", map[string]any{
+				"subscription": err.Error(),
+			})
+			return
+		}
+		params.Target = sub.Endpoint
+		params.P256dh = &sub.Keys.P256dh
+		params.AuthSecret = &sub.Keys.Auth
+
+	default:
+		respondValidationError(c, "Invalid channel kind", map[string]any{
+			"kind": "must be one of: email, webhook, web_push",
+		})
+		return
+	}
+
+	token, _, err := auth.NewToken()
+	if err != nil {
+		logger(c).Error("failed to generate unsubscribe token", "error", err)
+		respondInternalError(c)
+		return
+	}
+	params.UnsubscribeToken = token
+
+	row, err := h.queries.UpsertNotificationChannel(c.Request.Context(), params)
+	if err != nil {
+		logger(c).Error("failed to create notification channel", "error", err, "user_id", user.ID, "kind", req.Kind)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, NotificationChannelItem{
+		ID:         row.ID,
+		Kind:       row.Kind,
+		Target:     row.Target,
+		Frequency:  row.Frequency,
+		LastSentAt: formatTimestampPtr(row.LastSentAt),
+		CreatedAt:  formatTimestamp(row.CreatedAt),
+	})
+}
+
+// DeleteNotificationChannel handles DELETE /api/me/notification-channels/:id.
+func (h *Handler) DeleteNotificationChannel(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	count, err := h.queries.DeleteNotificationChannel(c.Request.Context(), db.DeleteNotificationChannelParams{
+		ID:     int32(id),
+		UserID: user.ID,
+	})
+	if err != nil {
+		logger(c).Error("failed to delete notification channel", "error", err, "user_id", user.ID, "channel_id", id)
+		respondInternalError(c)
+		return
+	}
+	if count == 0 {
+		respondNotFound(c, "Notification channel")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebPushKey handles GET /api/notifications/web-push-key.
+// Returns the VAPID public key browsers pass to pushManager.subscribe.
+func (h *Handler) GetWebPushKey(c *gin.Context) {
+	if h.vapidPublicKey == "" {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Web Push is not enabled")
+		return
+	}
+	respondJSON(c, http.StatusOK, WebPushKeyResponse{PublicKey: h.vapidPublicKey})
+}
+
+// Unsubscribe handles POST /api/notifications/unsubscribe.
+// Disables the channel an emailed or posted unsubscribe link belongs to. No
+// session is needed; the token identifies the channel.
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	var req UnsubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	row, err := h.queries.DisableNotificationChannelByToken(c.Request.Context(), req.Token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, "Notification channel")
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to unsubscribe", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, UnsubscribeResponse{
+		Kind:   row.Kind,
+		Target: row.Target,
+	})
+}
+
+// validChannelURL reports whether raw is an absolute URL with one of the
+// given schemes and a reasonable length.
+func validChannelURL(raw string, schemes ...string) bool {
+	if raw == "" || len(raw) > maxChannelTargetLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, s := range schemes {
+		if u.Scheme == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/middleware"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// setupNotificationsTestRouter creates a test router with sign-in and notification handlers
+func setupNotificationsTestRouter(tdb *testutil.TestDB, box *outbox) *gin.Engine {
+	h := handlers.New(tdb.Queries,
+		handlers.WithMailer(box),
+		handlers.WithAppURL("http://app.test"),
+		handlers.WithVAPIDPublicKey("test-vapid-key"),
+	)
+	router := gin.New()
+	router.POST("/api/auth/magic-link", h.RequestMagicLink)
+	router.POST("/api/auth/verify", h.VerifyLogin)
+	router.GET("/api/notifications/web-push-key", h.GetWebPushKey)
+	router.POST("/api/notifications/unsubscribe", h.Unsubscribe)
+
+	me := router.Group("/api/me", middleware.UserAuth(tdb.Queries))
+	me.PUT("/follows/bands/:slug", h.FollowBand)
+	me.GET("/notifications", h.ListNotifications)
+	me.POST("/notifications/read", h.MarkNotificationsRead)
+	me.GET("/notification-channels", h.ListNotificationChannels)
+	me.POST("/notification-channels", h.CreateNotificationChannel)
+	me.DELETE("/notification-channels/:id", h.DeleteNotificationChannel)
+	return router
+}
+
+// pushSubscriptionJSON returns a browser-style push subscription with fresh keys
+func pushSubscriptionJSON(t *testing.T, endpoint string) string {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return fmt.Sprintf(`{"endpoint":%q,"keys":{"p256dh":%q,"auth":%q}}`,
+		endpoint,
+		base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(auth),
+	)
+}
+
+func TestNotificationChannels(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	box := &outbox{}
+	router := setupNotificationsTestRouter(tdb, box)
+	token := signIn(t, router, box, "channels"+testutil.TestEmailDomain)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"email", `{"kind":"email","frequency":"daily"}`, http.StatusCreated},
+		{"webhook", `{"kind":"webhook","url":"https://hooks.example.com/setlist"}`, http.StatusCreated},
+		{"web push", `{"kind":"web_push","subscription":` + pushSubscriptionJSON(t, "https://push.example.com/sub/1") + `}`, http.StatusCreated},
+		{"missing kind", `{}`, http.StatusBadRequest},
+		{"unknown kind", `{"kind":"sms"}`, http.StatusBadRequest},
+		{"bad frequency", `{"kind":"email","frequency":"hourly"}`, http.StatusBadRequest},
+		{"webhook without url", `{"kind":"webhook"}`, http.StatusBadRequest},
+		{"webhook ftp url", `{"kind":"webhook","url":"ftp://example.com"}`, http.StatusBadRequest},
+		{"push over http", `{"kind":"web_push","subscription":` + pushSubscriptionJSON(t, "http://push.example.com/sub/2") + `}`, http.StatusBadRequest},
+		{"push bad keys", `{"kind":"web_push","subscription":{"endpoint":"https://push.example.com/sub/3","keys":{"p256dh":"abc","auth":"def"}}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(router, http.MethodPost, "/api/me/notification-channels", tt.body, token)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := doJSON(router, http.MethodGet, "/api/me/notification-channels", "", token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			ID        int32  `json:"id"`
+			Kind      string `json:"kind"`
+			Target    string `json:"target"`
+			Frequency string `json:"frequency"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Data) != 3 {
+		t.Fatalf("expected 3 channels, got %d", len(resp.Data))
+	}
+	email := resp.Data[0]
+	if email.Kind != notify.ChannelEmail || email.Target != "channels"+testutil.TestEmailDomain || email.Frequency != notify.FrequencyDaily {
+		t.Errorf("unexpected email channel %+v", email)
+	}
+
+	// Unsubscribe links work without a session and disable only their channel
+	var unsubscribeToken string
+	if err := tdb.Pool.QueryRow(ctx, `SELECT unsubscribe_token FROM notification_channels WHERE id = $1`, email.ID).Scan(&unsubscribeToken); err != nil {
+		t.Fatalf("failed to read unsubscribe token: %v", err)
+	}
+	w = doJSON(router, http.MethodPost, "/api/notifications/unsubscribe", `{"token":"`+unsubscribeToken+`"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unsubscribe: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = doJSON(router, http.MethodPost, "/api/notifications/unsubscribe", `{"token":"no-such-token"}`, "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown unsubscribe token: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	path := fmt.Sprintf("/api/me/notification-channels/%d", resp.Data[1].ID)
+	if w := doJSON(router, http.MethodDelete, path, "", token); w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := doJSON(router, http.MethodDelete, path, "", token); w.Code != http.StatusNotFound {
+		t.Errorf("delete again: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = doJSON(router, http.MethodGet, "/api/me/notification-channels", "", token)
+	resp.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Kind != notify.ChannelWebPush {
+		t.Errorf("expected only the web push channel to remain, got %+v", resp.Data)
+	}
+}
+
+func TestListNotifications(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Inbox", "test-band-inbox")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+
+	box := &outbox{}
+	router := setupNotificationsTestRouter(tdb, box)
+	token := signIn(t, router, box, "inbox"+testutil.TestEmailDomain)
+
+	if w := doJSON(router, http.MethodPut, "/api/me/follows/bands/test-band-inbox", "", token); w.Code != http.StatusNoContent {
+		t.Fatalf("follow: expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 10), "Inbox Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	tdb.LinkBandToShow(ctx, showID, bandID, true, 1)
+	if _, err := notify.ShowAnnounced(ctx, tdb.Queries, showID); err != nil {
+		t.Fatalf("ShowAnnounced failed: %v", err)
+	}
+
+	w := doJSON(router, http.MethodGet, "/api/me/notifications", "", token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			Kind     string  `json:"kind"`
+			Message  string  `json:"message"`
+			ShowID   int32   `json:"show_id"`
+			BandName *string `json:"band_name"`
+			ReadAt   *string `json:"read_at"`
+		} `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Meta.Total != 1 || len(resp.Data) != 1 {
+		t.Fatalf("expected 1 notification, got %d", resp.Meta.Total)
+	}
+	n := resp.Data[0]
+	if n.Kind != notify.KindShowAnnounced || n.ShowID != showID || n.BandName == nil || *n.BandName != "Test Band Inbox" {
+		t.Errorf("unexpected notification %+v", n)
+	}
+	if n.Message == "" || n.ReadAt != nil {
+		t.Errorf("expected an unread notification with a message, got %+v", n)
+	}
+
+	w = doJSON(router, http.MethodPost, "/api/me/notifications/read", "", token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("read: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, http.MethodGet, "/api/me/notifications", "", token)
+	resp.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].ReadAt == nil {
+		t.Errorf("expected notification to be read, got %+v", resp.Data)
+	}
+}
+
+func TestGetWebPushKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	enabled := gin.New()
+	enabled.GET("/key", handlers.New(nil, handlers.WithVAPIDPublicKey("test-vapid-key")).GetWebPushKey)
+	w := doJSON(enabled, http.MethodGet, "/key", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp struct {
+		Data struct {
+			PublicKey string `json:"public_key"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Data.PublicKey != "test-vapid-key" {
+		t.Errorf("unexpected response %s", w.Body.String())
+	}
+
+	disabled := gin.New()
+	disabled.GET("/key", handlers.New(nil).GetWebPushKey)
+	if w := doJSON(disabled, http.MethodGet, "/key", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("disabled: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Recommendation evidence weights. A show's score combines its evidence as
+// 1 - Π(1 - weight), so several weak matches can outrank one.
+const (
+	recommendSeedBandWeight       = 1.0  // A seed band is on the bill
+	recommendSimilarBandWeight    = 0.8  // Scaled by the band's similarity score
+	recommendGenreHeadlinerWeight = 0.5  // The headliner plays a seed genre
+	recommendGenreSupportWeight   = 0.35 // A support act plays a seed genre
+)
+
+// GetShowRecommendations handles GET /api/recommendations/shows.
+// Ranks upcoming shows by similarity to seed bands and genres.
+func (h *Handler) GetShowRecommendations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	bandSlugs := parseSlugList(c, "bands")
+	genreSlugs := parseSlugList(c, "genres")
+	if len(bandSlugs) == 0 && len(genreSlugs) == 0 {
+		respondMissingParam(c, "bands or genres")
+		return
+	}
+	if len(bandSlugs) > MaxRecommendationSeeds {
+		respondInvalidParam(c, "bands", fmt.Sprintf("at most %d bands allowed", MaxRecommendationSeeds))
+		return
+	}
+	if len(genreSlugs) > MaxRecommendationSeeds {
+		respondInvalidParam(c, "genres", fmt.Sprintf("at most %d genres allowed", MaxRecommendationSeeds))
+		return
+	}
+
+	limit := DefaultRecommendationsLimit
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 {
+			respondInvalidParam(c, "limit", "must be a positive integer")
+			return
+		}
+		limit = min(parsed, MaxRecommendationsLimit)
+	}
+
+	// Unknown slugs are ignored so stale seeds (e.g. from local storage) still work
+	bandIDs := []int32{}
+	if len(bandSlugs) > 0 {
+		bands, err := h.queries.GetBandsBySlugs(ctx, bandSlugs)
+		if err != nil {
+			logger(c).Error("failed to resolve seed bands", "error", err)
+			respondInternalError(c)
+			return
+		}
+		for _, b := range bands {
+			bandIDs = append(bandIDs, b.ID)
+		}
+	}
+
+	genreIDs := []int32{}
+	if len(genreSlugs) > 0 {
+		genres, err := h.queries.GetGenresBySlugs(ctx, genreSlugs)
+		if err != nil {
+			logger(c).Error("failed to resolve seed genres", "error", err)
+			respondInternalError(c)
+			return
+		}
+		for _, g := range genres {
+			genreIDs = append(genreIDs, g.ID)
+		}
+	}
+
+	if len(bandIDs) == 0 && len(genreIDs) == 0 {
+		respondJSON(c, http.StatusOK, []ShowRecommendation{})
+		return
+	}
+
+	evidence, err := h.queries.ListRecommendationEvidence(ctx, db.ListRecommendationEvidenceParams{
+		GenreIds: genreIDs,
+		BandIds:  bandIDs,
+	})
+	if err != nil {
+		logger(c).Error("failed to list recommendation evidence", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	ranked := rankRecommendations(evidence)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	if len(ranked) == 0 {
+		respondJSON(c, http.StatusOK, []ShowRecommendation{})
+		return
+	}
+
+	showIDs := make([]int32, len(ranked))
+	for i, r := range ranked {
+		showIDs[i] = r.showID
+	}
+
+	rows, err := h.queries.ListShowsByIDs(ctx, showIDs)
+	if err != nil {
+		logger(c).Error("failed to load recommended shows", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	shows := convertShowsByIDsToListItems(rows)
+	h.attachBandsToShows(ctx, shows)
+
+	byID := make(map[int32]ShowListItem, len(shows))
+	for _, s := range shows {
+		byID[s.ID] = s
+	}
+
+	recommendations := make([]ShowRecommendation, 0, len(ranked))
+	for _, r := range ranked {
+		show, ok := byID[r.showID]
+		if !ok {
+			continue
+		}
+		recommendations = append(recommendations, ShowRecommendation{
+			ShowListItem: show,
+			Score:        roundScore(r.score),
+			Reasons:      r.reasons,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, recommendations)
+}
+
+// rankedShow is a recommended show with its combined score and reasons.
+type rankedShow struct {
+	showID  int32
+	score   float64
+	reasons []string
+}
+
+// rankRecommendations combines evidence rows into one score per show and
+// returns shows best first, each with its strongest distinct reasons.
+func rankRecommendations(rows []db.ListRecommendationEvidenceRow) []rankedShow {
+	type reason struct {
+		text   string
+		weight float64
+	}
+
+	evidence := make(map[int32][]reason)
+	var order []int32
+	for _, r := range rows {
+		weight, text := explainEvidence(r)
+		if weight <= 0 {
+			continue
+		}
+		if _, seen := evidence[r.ShowID]; !seen {
+			order = append(order, r.ShowID)
+		}
+		evidence[r.ShowID] = append(evidence[r.ShowID], reason{text: text, weight: weight})
+	}
+
+	ranked := make([]rankedShow, 0, len(order))
+	for _, showID := range order {
+		reasons := evidence[showID]
+		slices.SortStableFunc(reasons, func(a, b reason) int {
+			return cmp.Compare(b.weight, a.weight)
+		})
+
+		miss := 1.0
+		texts := []string{}
+		for _, r := range reasons {
+			miss *= 1 - min(r.weight, 1)
+			if len(texts) < MaxRecommendationReasons && !slices.Contains(texts, r.text) {
+				texts = append(texts, r.text)
+			}
+		}
+
+		ranked = append(ranked, rankedShow{
+			showID:  showID,
+			score:   1 - miss,
+			reasons: texts,
+		})
+	}
+
+	slices.SortStableFunc(ranked, func(a, b rankedShow) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.showID, b.showID)
+	})
+
+	return ranked
+}
+
+// explainEvidence weighs a single piece of evidence and describes it.
+func explainEvidence(r db.ListRecommendationEvidenceRow) (float64, string) {
+	switch r.Kind {
+	case "seed_band":
+		if r.IsHeadliner {
+			return recommendSeedBandWeight, r.SeedName + " is headlining"
+		}
+		return recommendSeedBandWeight, r.SeedName + " is on the bill"
+
+	case "similar_band":
+		weight := recommendSimilarBandWeight * r.Score
+		switch {
+		case r.SharedShowCount > 0 && r.IsHeadliner:
+			return weight, r.SeedName + " played with this headliner"
+		case r.SharedShowCount > 0:
+			return weight, r.SeedName + " played with " + r.BandName
+		case r.GenreName != "":
+			return weight, fmt.Sprintf("%s shares %s with %s", r.BandName, strings.ToLower(r.GenreName), r.SeedName)
+		default:
+			return weight, r.BandName + " is similar to " + r.SeedName
+		}
+
+	case "genre":
+		weight := recommendGenreSupportWeight
+		if r.IsHeadliner {
+			weight = recommendGenreHeadlinerWeight
+		}
+		return weight, fmt.Sprintf("%s plays %s", r.BandName, strings.ToLower(r.GenreName))
+	}
+
+	return 0, ""
+}
+
+// parseSlugList reads a comma-separated (or repeated) slug list parameter,
+// trimming, lowercasing and de-duplicating entries.
+func parseSlugList(c *gin.Context, param string) []string {
+	slugs := []string{}
+	for _, value := range c.QueryArray(param) {
+		for _, slug := range strings.Split(value, ",") {
+			slug = strings.ToLower(strings.TrimSpace(slug))
+			if slug != "" && !slices.Contains(slugs, slug) {
+				slugs = append(slugs, slug)
+			}
+		}
+	}
+	return slugs
+}
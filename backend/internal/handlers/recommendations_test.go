@@ -0,0 +1,170 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// setupRecommendationsTestRouter creates a test router with the recommendations handler
+func setupRecommendationsTestRouter(tdb *testutil.TestDB) *gin.Engine {
+	h := handlers.New(tdb.Queries)
+	router := gin.New()
+	router.GET("/api/recommendations/shows", h.GetShowRecommendations)
+	return router
+}
+
+type recommendationsResponse struct {
+	Data []struct {
+		ID      int32    `json:"id"`
+		Score   float64  `json:"score"`
+		Reasons []string `json:"reasons"`
+		Venue   struct {
+			ID int32 `json:"id"`
+		} `json:"venue"`
+		Bands []struct {
+			ID int32 `json:"id"`
+		} `json:"bands"`
+	} `json:"data"`
+}
+
+func TestGetShowRecommendations(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	var genreID int32
+	var genreSlug, otherGenreSlug string
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id, slug FROM genres WHERE slug = 'funk'`).Scan(&genreID, &genreSlug); err != nil {
+		t.Skipf("genre not seeded: %v", err)
+	}
+	var otherGenreID int32
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id, slug FROM genres WHERE slug = 'gospel'`).Scan(&otherGenreID, &otherGenreSlug); err != nil {
+		t.Skipf("genre not seeded: %v", err)
+	}
+
+	band := func(name, slug string, genre int32) int32 {
+		id, err := tdb.InsertTestBand(ctx, name, slug)
+		if err != nil {
+			t.Fatalf("failed to insert band: %v", err)
+		}
+		if err := tdb.AddGenreToBand(ctx, id, genre); err != nil {
+			t.Fatalf("failed to add genre: %v", err)
+		}
+		return id
+	}
+	show := func(title string, days int, lineup ...int32) int32 {
+		id, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, days), title)
+		if err != nil {
+			t.Fatalf("failed to insert show: %v", err)
+		}
+		for i, b := range lineup {
+			tdb.LinkBandToShow(ctx, id, b, i == 0, len(lineup)-i)
+		}
+		return id
+	}
+
+	seed := band("Test Band Rec Seed", "test-band-rec-seed", genreID)
+	mate := band("Test Band Rec Mate", "test-band-rec-mate", genreID)
+	gospel := band("Test Band Rec Gospel", "test-band-rec-gospel", otherGenreID)
+
+	seedShow := show("Rec Seed Show", 3, seed, mate)
+	mateShow := show("Rec Mate Show", 5, mate)
+	genreShow := show("Rec Genre Show", 7, gospel)
+
+	if _, err := similarity.Refresh(ctx, tdb.Pool, similarity.DefaultWeights); err != nil {
+		t.Fatalf("failed to refresh similarity: %v", err)
+	}
+
+	router := setupRecommendationsTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/recommendations/shows?bands=test-band-rec-seed,unknown-band&genres="+otherGenreSlug+"&limit=50", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp recommendationsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	position := map[int32]int{}
+	reasons := map[int32][]string{}
+	for i, r := range resp.Data {
+		position[r.ID] = i
+		reasons[r.ID] = r.Reasons
+		if r.Score <= 0 || r.Score > 1 {
+			t.Errorf("show %d: score %f out of range", r.ID, r.Score)
+		}
+	}
+
+	for _, id := range []int32{seedShow, mateShow, genreShow} {
+		if _, ok := position[id]; !ok {
+			t.Fatalf("expected show %d to be recommended, got %+v", id, resp.Data)
+		}
+	}
+	if position[seedShow] != 0 {
+		t.Errorf("expected the seed band's show first, got position %d", position[seedShow])
+	}
+	if !slices.Contains(reasons[seedShow], "Test Band Rec Seed is headlining") {
+		t.Errorf("unexpected seed show reasons %v", reasons[seedShow])
+	}
+	if !slices.Contains(reasons[mateShow], "Test Band Rec Seed played with this headliner") {
+		t.Errorf("unexpected co-billed show reasons %v", reasons[mateShow])
+	}
+	if !slices.Contains(reasons[genreShow], "Test Band Rec Gospel plays gospel") {
+		t.Errorf("unexpected genre show reasons %v", reasons[genreShow])
+	}
+}
+
+func TestGetShowRecommendations_InvalidParams(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupRecommendationsTestRouter(tdb)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"no seeds", "", http.StatusBadRequest},
+		{"blank seeds", "?bands=,&genres=", http.StatusBadRequest},
+		{"too many bands", "?bands=a,b,c,d,e,f,g,h,i,j,k", http.StatusBadRequest},
+		{"invalid limit", "?genres=rock&limit=0", http.StatusBadRequest},
+		{"unknown seeds", "?bands=no-such-band&genres=no-such-genre", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/recommendations/shows"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/logging"
 )
 
 // Response envelope for successful responses
@@ -38,9 +40,17 @@ const (
 	ErrCodeInvalidParam = "INVALID_PARAMETER"
 	ErrCodeMissingParam = "MISSING_PARAMETER"
 	ErrCodeNotFound     = "NOT_FOUND"
+	ErrCodeUnauthorized = "UNAUTHORIZED"
+	ErrCodeDuplicate    = "DUPLICATE_SUBMISSION"
+	ErrCodeRateLimited  = "RATE_LIMITED"
 	ErrCodeInternal     = "INTERNAL_ERROR"
 )
 
+// logger returns the request's logger, which carries its request ID and route
+func logger(c *gin.Context) *slog.Logger {
+	return logging.FromContext(c.Request.Context())
+}
+
 // respondJSON sends a successful JSON response
 func respondJSON(c *gin.Context, status int, data any) {
 	c.JSON(status, Response{Data: data})
@@ -72,6 +82,12 @@ func respondErrorWithDetails(c *gin.Context, status int, code, message string, d
 	})
 }
 
+// RateLimited sends the 429 response for clients over a rate limit; it is
+// passed to middleware.RateLimit
+func RateLimited(c *gin.Context) {
+	respondError(c, http.StatusTooManyRequests, ErrCodeRateLimited, "Too many requests, please try again later")
+}
+
 // respondNotFound sends a 404 not found response
 func respondNotFound(c *gin.Context, resource string) {
 	respondError(c, http.StatusNotFound, ErrCodeNotFound, resource+" not found")
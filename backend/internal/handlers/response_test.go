@@ -0,0 +1,46 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/middleware"
+)
+
+func TestRateLimited(t *testing.T) {
+	router := gin.New()
+	router.SetTrustedProxies(nil)
+	router.Use(middleware.RateLimit(1, time.Minute, handlers.RateLimited))
+	router.GET("/api/shows", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// A client can't get a fresh bucket by claiming another address
+	do := func(forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/shows", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	do("198.51.100.1")
+	w := do("198.51.100.2")
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+
+	var resp handlers.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Error.Code != handlers.ErrCodeRateLimited || resp.Error.Message == "" {
+		t.Errorf("unexpected error envelope %s", w.Body.String())
+	}
+}
@@ -1,15 +1,22 @@
 package handlers
 
 import (
-	"log/slog"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/paulsena/asheville-setlist/internal/db"
 )
 
-// Search handles GET /api/search for global search across shows, bands, and venues.
+// Search handles GET /api/search for global search across shows, bands, venues and articles.
+// Each term matches as a prefix ("avet" finds "Avett") and results are ranked by relevance.
+// With highlight=true, each result says which field matched and carries a snippet of it.
+// With mode=unified, or when type, page or per_page is given, returns a single paginated,
+// ranked list instead of per-type groups (see searchUnified).
 func (h *Handler) Search(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -24,6 +31,43 @@ func (h *Handler) Search(c *gin.Context) {
 		return
 	}
 
+	highlight := false
+	if hl := c.Query("highlight"); hl != "" {
+		parsed, err := strconv.ParseBool(hl)
+		if err != nil {
+			respondInvalidParam(c, "highlight", "must be true or false")
+			return
+		}
+		highlight = parsed
+	}
+
+	unifiedParam := ""
+	for _, param := range []string{"type", "page", "per_page"} {
+		if c.Query(param) != "" {
+			unifiedParam = param
+			break
+		}
+	}
+
+	switch c.Query("mode") {
+	case "":
+		if unifiedParam != "" {
+			h.searchUnified(c, query, highlight)
+			return
+		}
+	case SearchModeGrouped:
+		if unifiedParam != "" {
+			respondInvalidParam(c, unifiedParam, "not supported with mode=grouped")
+			return
+		}
+	case SearchModeUnified:
+		h.searchUnified(c, query, highlight)
+		return
+	default:
+		respondInvalidParam(c, "mode", "must be one of: "+SearchModeGrouped+", "+SearchModeUnified)
+		return
+	}
+
 	limit := DefaultSearchLimit
 	if l := c.Query("limit"); l != "" {
 		parsed, err := strconv.Atoi(l)
@@ -37,69 +81,215 @@ func (h *Handler) Search(c *gin.Context) {
 		limit = parsed
 	}
 
-	// Search shows
+	result := SearchResult{
+		Shows:    []SearchShowItem{},
+		Bands:    []SearchBandItem{},
+		Venues:   []SearchVenueItem{},
+		Articles: []SearchArticleItem{},
+	}
+
+	tsQuery := prefixTSQuery(query)
+	if tsQuery == "" {
+		// Nothing searchable (e.g. only punctuation)
+		respondJSON(c, http.StatusOK, result)
+		return
+	}
+
+	// Search shows (title, lineup and venue name)
 	showRows, err := h.queries.GlobalSearchShows(ctx, db.GlobalSearchShowsParams{
-		PlaintoTsquery: query,
-		Limit:          int32(limit),
+		Query:           tsQuery,
+		Highlight:       highlight,
+		HeadlineOptions: SearchHeadlineOptions,
+		Limit:           int32(limit),
 	})
 	if err != nil {
-		slog.Error("failed to search shows", "error", err)
+		logger(c).Error("failed to search shows", "error", err)
 		showRows = []db.GlobalSearchShowsRow{}
 	}
 
-	shows := make([]SearchShowItem, len(showRows))
-	for i, r := range showRows {
-		shows[i] = SearchShowItem{
+	for _, r := range showRows {
+		result.Shows = append(result.Shows, SearchShowItem{
 			ID:        r.ID,
 			Title:     r.Title,
 			Date:      formatTimestamp(r.Date),
 			VenueName: r.VenueName,
-		}
+			BandNames: r.BandNames,
+			Highlight: searchHighlight(highlight, r.MatchedField, r.Snippet),
+		})
 	}
 
 	// Search bands
 	bandRows, err := h.queries.GlobalSearchBands(ctx, db.GlobalSearchBandsParams{
-		PlaintoTsquery: query,
-		Limit:          int32(limit),
+		Query:           tsQuery,
+		Highlight:       highlight,
+		HeadlineOptions: SearchHeadlineOptions,
+		Limit:           int32(limit),
 	})
 	if err != nil {
-		slog.Error("failed to search bands", "error", err)
+		logger(c).Error("failed to search bands", "error", err)
 		bandRows = []db.GlobalSearchBandsRow{}
 	}
 
-	bands := make([]SearchBandItem, len(bandRows))
-	for i, r := range bandRows {
-		bands[i] = SearchBandItem{
-			ID:   r.ID,
-			Name: r.Name,
-			Slug: r.Slug,
-		}
+	for _, r := range bandRows {
+		result.Bands = append(result.Bands, SearchBandItem{
+			ID:        r.ID,
+			Name:      r.Name,
+			Slug:      r.Slug,
+			Highlight: searchHighlight(highlight, r.MatchedField, r.Snippet),
+		})
 	}
 
 	// Search venues
 	venueRows, err := h.queries.GlobalSearchVenues(ctx, db.GlobalSearchVenuesParams{
-		PlaintoTsquery: query,
-		Limit:          int32(limit),
+		Query:           tsQuery,
+		Highlight:       highlight,
+		HeadlineOptions: SearchHeadlineOptions,
+		Limit:           int32(limit),
 	})
 	if err != nil {
-		slog.Error("failed to search venues", "error", err)
+		logger(c).Error("failed to search venues", "error", err)
 		venueRows = []db.GlobalSearchVenuesRow{}
 	}
 
-	venues := make([]SearchVenueItem, len(venueRows))
-	for i, r := range venueRows {
-		venues[i] = SearchVenueItem{
-			ID:   r.ID,
-			Name: r.Name,
-			Slug: r.Slug,
-		}
+	for _, r := range venueRows {
+		result.Venues = append(result.Venues, SearchVenueItem{
+			ID:        r.ID,
+			Name:      r.Name,
+			Slug:      r.Slug,
+			Highlight: searchHighlight(highlight, r.MatchedField, r.Snippet),
+		})
 	}
 
-	result := SearchResult{
-		Shows:  shows,
-		Bands:  bands,
-		Venues: venues,
+	// Search articles
+	articleRows, err := h.queries.GlobalSearchArticles(ctx, db.GlobalSearchArticlesParams{
+		Query:           tsQuery,
+		Highlight:       highlight,
+		HeadlineOptions: SearchHeadlineOptions,
+		Limit:           int32(limit),
+	})
+	if err != nil {
+		logger(c).Error("failed to search articles", "error", err)
+		articleRows = []db.GlobalSearchArticlesRow{}
+	}
+
+	for _, r := range articleRows {
+		result.Articles = append(result.Articles, SearchArticleItem{
+			ID:          r.ID,
+			Title:       r.Title,
+			Slug:        r.Slug,
+			Excerpt:     r.Excerpt,
+			PublishedAt: formatTimestampPtr(r.PublishedAt),
+			Highlight:   searchHighlight(highlight, r.MatchedField, r.Snippet),
+		})
 	}
 
 	respondJSON(c, http.StatusOK, result)
 }
+
+// searchUnified serves the unified search mode of GET /api/search: a single
+// relevance-ranked list of typed results, filtered by type and paginated with
+// page and per_page in place of limit.
+func (h *Handler) searchUnified(c *gin.Context, query string, highlight bool) {
+	ctx := c.Request.Context()
+
+	if c.Query("limit") != "" {
+		respondInvalidParam(c, "limit", "not supported with mode=unified; use per_page")
+		return
+	}
+
+	types, err := parseSearchTypes(c.Query("type"))
+	if err != nil {
+		respondInvalidParam(c, "type", "must be a comma-separated list of: "+strings.Join(SearchTypes, ", "))
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	items := []SearchResultItem{}
+	total := 0
+
+	if tsQuery := prefixTSQuery(query); tsQuery != "" {
+		rows, err := h.queries.SearchAll(ctx, db.SearchAllParams{
+			Query:           tsQuery,
+			Types:           types,
+			Highlight:       highlight,
+			HeadlineOptions: SearchHeadlineOptions,
+			Limit:           int32(perPage),
+			Offset:          int32(calculateOffset(page, perPage)),
+		})
+		if err != nil {
+			logger(c).Error("failed to search", "error", err)
+			respondInternalError(c)
+			return
+		}
+		items, total = convertSearchAllRowsToItems(rows, highlight)
+	}
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, items, meta)
+}
+
+// parseSearchTypes parses a comma-separated type filter, defaulting to all types.
+func parseSearchTypes(param string) ([]string, error) {
+	if param == "" {
+		return SearchTypes, nil
+	}
+
+	var types []string
+	for _, t := range strings.Split(param, ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if t == "" {
+			continue
+		}
+		if !slices.Contains(SearchTypes, t) {
+			return nil, fmt.Errorf("unknown search type %q", t)
+		}
+		types = append(types, t)
+	}
+
+	if len(types) == 0 {
+		return SearchTypes, nil
+	}
+	return types, nil
+}
+
+// searchHighlight builds the highlight for a result, or nil when not requested.
+func searchHighlight(enabled bool, field, snippet string) *SearchHighlight {
+	if !enabled {
+		return nil
+	}
+	return &SearchHighlight{
+		Field:   field,
+		Snippet: snippet,
+	}
+}
+
+// prefixTSQuery builds a to_tsquery expression that matches every word of the
+// input as a prefix, e.g. "avet bro" -> "avet:* & bro:*". Only letters and
+// digits are kept, so user input can't inject tsquery operators. Returns ""
+// when the input has no searchable words.
+func prefixTSQuery(input string) string {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = w + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -152,7 +153,7 @@ func TestSearch_ResponseStructure(t *testing.T) {
 		t.Fatal("response should have a 'data' object")
 	}
 
-	expectedFields := []string{"shows", "bands", "venues"}
+	expectedFields := []string{"shows", "bands", "venues", "articles"}
 	for _, field := range expectedFields {
 		if _, exists := data[field]; !exists {
 			t.Errorf("missing expected field: %s", field)
@@ -474,3 +475,382 @@ func TestSearch_MixedResults(t *testing.T) {
 		t.Log("Expected to find at least one result matching 'MixedTest' - may be due to full-text search indexing")
 	}
 }
+
+func TestSearch_PrefixMatch(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	_, err := tdb.InsertTestBand(ctx, "Test Band Quixotical", "test-band-quixotical")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+
+	router := setupSearchTestRouter(tdb)
+
+	// A partial word should match as a prefix
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=quixot", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Bands []struct {
+				Slug string `json:"slug"`
+			} `json:"bands"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(resp.Data.Bands) == 0 || resp.Data.Bands[0].Slug != "test-band-quixotical" {
+		t.Errorf("expected prefix search to rank test band first, got %+v", resp.Data.Bands)
+	}
+}
+
+func TestSearch_FindsShowByLineup(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Lineup Search Show")
+	if err != nil {
+		t.Fatalf("failed to insert test show: %v", err)
+	}
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Zephyrine", "test-band-zephyrine")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	if err := tdb.LinkBandToShow(ctx, showID, bandID, true, 1); err != nil {
+		t.Fatalf("failed to link band to show: %v", err)
+	}
+
+	router := setupSearchTestRouter(tdb)
+
+	// Only the band name matches, not the show title
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=zephyrine", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Shows []struct {
+				ID        int32    `json:"id"`
+				BandNames []string `json:"band_names"`
+			} `json:"shows"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	found := false
+	for _, show := range resp.Data.Shows {
+		if show.ID == showID {
+			found = true
+			if len(show.BandNames) != 1 || show.BandNames[0] != "Test Band Zephyrine" {
+				t.Errorf("expected band_names [Test Band Zephyrine], got %v", show.BandNames)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected show %d to match on lineup", showID)
+	}
+}
+
+func TestSearch_PunctuationOnly(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupSearchTestRouter(tdb)
+
+	// tsquery operators in user input must not cause a syntax error
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=%21%26%7C", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestSearch_Highlight(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Highlight", "test-band-highlight")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	_, err = tdb.Pool.Exec(ctx, `UPDATE bands SET bio = $2 WHERE id = $1`,
+		bandID, "A string band known for the marvelously strange <b>theremolin</b> solos & <script>alert(1)</script>.")
+	if err != nil {
+		t.Fatalf("failed to set band bio: %v", err)
+	}
+
+	router := setupSearchTestRouter(tdb)
+
+	type searchResponse struct {
+		Data struct {
+			Bands []struct {
+				Slug      string `json:"slug"`
+				Highlight *struct {
+					Field   string `json:"field"`
+					Snippet string `json:"snippet"`
+				} `json:"highlight"`
+			} `json:"bands"`
+		} `json:"data"`
+	}
+
+	search := func(query string) searchResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/search"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp searchResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return resp
+	}
+
+	// Highlight reports the bio match with the term marked
+	resp := search("?q=theremolin&highlight=true")
+	if len(resp.Data.Bands) == 0 {
+		t.Fatal("expected test band in results")
+	}
+	hl := resp.Data.Bands[0].Highlight
+	if hl == nil {
+		t.Fatal("expected highlight when highlight=true")
+	}
+	if hl.Field != "bio" {
+		t.Errorf("expected matched field bio, got %s", hl.Field)
+	}
+	if !strings.Contains(hl.Snippet, "<mark>theremolin</mark>") {
+		t.Errorf("expected marked term in snippet, got %q", hl.Snippet)
+	}
+	// Markup in the bio is escaped; only the <mark> tags are HTML
+	if strings.Contains(hl.Snippet, "<b>") || strings.Contains(hl.Snippet, "<script>") || !strings.Contains(hl.Snippet, "&lt;b&gt;") {
+		t.Errorf("expected bio markup to be escaped in snippet, got %q", hl.Snippet)
+	}
+
+	// No highlight unless requested
+	resp = search("?q=theremolin")
+	if len(resp.Data.Bands) == 0 {
+		t.Fatal("expected test band in results")
+	}
+	if resp.Data.Bands[0].Highlight != nil {
+		t.Error("expected no highlight by default")
+	}
+}
+
+func TestSearch_HighlightAcrossFields(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Marblewick Night")
+	if err != nil {
+		t.Fatalf("failed to insert test show: %v", err)
+	}
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Quorvane", "test-band-quorvane")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	if err := tdb.LinkBandToShow(ctx, showID, bandID, true, 1); err != nil {
+		t.Fatalf("failed to link band to show: %v", err)
+	}
+
+	router := setupSearchTestRouter(tdb)
+
+	// One term is in the title, the other in the lineup
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=marblewick+quorvane&highlight=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Shows []struct {
+				ID        int32 `json:"id"`
+				Highlight *struct {
+					Field   string `json:"field"`
+					Snippet string `json:"snippet"`
+				} `json:"highlight"`
+			} `json:"shows"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(resp.Data.Shows) == 0 || resp.Data.Shows[0].ID != showID {
+		t.Fatalf("expected show %d in results, got %+v", showID, resp.Data.Shows)
+	}
+	hl := resp.Data.Shows[0].Highlight
+	if hl == nil || hl.Field != "title" || !strings.Contains(hl.Snippet, "<mark>Marblewick</mark>") {
+		t.Errorf("expected the title to be reported as the matched field, got %+v", hl)
+	}
+}
+
+func TestSearch_InvalidHighlight(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupSearchTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=test&highlight=maybe", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
+
+func TestSearch_Unified(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	if _, err := tdb.InsertTestBand(ctx, "Test Band UnifiedSearch One", "test-band-unifiedsearch-one"); err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+	if _, err := tdb.InsertTestBand(ctx, "Test Band UnifiedSearch Two", "test-band-unifiedsearch-two"); err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+
+	router := setupSearchTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=unifiedsearch&type=band,venue&page=1&per_page=1&highlight=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			Type      string  `json:"type"`
+			ID        int32   `json:"id"`
+			Name      string  `json:"name"`
+			Slug      *string `json:"slug"`
+			Rank      float32 `json:"rank"`
+			Highlight *struct {
+				Field   string `json:"field"`
+				Snippet string `json:"snippet"`
+			} `json:"highlight"`
+		} `json:"data"`
+		Meta struct {
+			Page       int `json:"page"`
+			PerPage    int `json:"per_page"`
+			Total      int `json:"total"`
+			TotalPages int `json:"total_pages"`
+		} `json:"meta"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Meta.Total != 2 || resp.Meta.TotalPages != 2 {
+		t.Errorf("expected 2 results over 2 pages, got total %d, pages %d", resp.Meta.Total, resp.Meta.TotalPages)
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected 1 result on page 1, got %d", len(resp.Data))
+	}
+	if resp.Data[0].Type != "band" || resp.Data[0].Slug == nil {
+		t.Errorf("expected a band result with a slug, got %+v", resp.Data[0])
+	}
+	if hl := resp.Data[0].Highlight; hl == nil || hl.Field != "name" || !strings.Contains(hl.Snippet, "<mark>") {
+		t.Errorf("expected a name highlight with a marked term, got %+v", hl)
+	}
+}
+
+func TestSearch_UnifiedValidation(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupSearchTestRouter(tdb)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"pagination selects unified", "?q=test&page=1", http.StatusOK},
+		{"type selects unified", "?q=test&type=band,venue,show,article&page=2", http.StatusOK},
+		{"pagination in grouped mode", "?q=test&mode=grouped&per_page=10", http.StatusBadRequest},
+		{"type in grouped mode", "?q=test&mode=grouped&type=band", http.StatusBadRequest},
+		{"all types", "?q=test&mode=unified&page=1", http.StatusOK},
+		{"type filter", "?q=test&mode=unified&type=show,article", http.StatusOK},
+		{"highlight", "?q=test&mode=unified&highlight=true", http.StatusOK},
+		{"unknown mode", "?q=test&mode=flat", http.StatusBadRequest},
+		{"unknown type", "?q=test&mode=unified&type=band,album", http.StatusBadRequest},
+		{"invalid page", "?q=test&mode=unified&type=band&page=0", http.StatusBadRequest},
+		{"per_page too large", "?q=test&mode=unified&per_page=500", http.StatusBadRequest},
+		{"limit in unified mode", "?q=test&mode=unified&limit=5", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
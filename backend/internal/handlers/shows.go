@@ -3,13 +3,13 @@ package handlers
 import (
 	"context"
 	"errors"
-	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/logging"
 )
 
 // ListShows handles GET /api/shows with various filter options.
@@ -38,17 +38,18 @@ func (h *Handler) ListShows(c *gin.Context) {
 	case "tonight":
 		rows, err := h.queries.ListShowsTonight(ctx)
 		if err != nil {
-			slog.Error("failed to list shows tonight", "error", err)
+			logger(c).Error("failed to list shows tonight", "error", err)
 			respondInternalError(c)
 			return
 		}
 		shows = convertTonightShowsToListItems(rows)
 		total = len(shows)
+		c.Header("Cache-Control", CachePolicyShort)
 
 	case "this-weekend":
 		rows, err := h.queries.ListShowsThisWeekend(ctx)
 		if err != nil {
-			slog.Error("failed to list shows this weekend", "error", err)
+			logger(c).Error("failed to list shows this weekend", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -61,7 +62,7 @@ func (h *Handler) ListShows(c *gin.Context) {
 			Offset: int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list free shows", "error", err)
+			logger(c).Error("failed to list free shows", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -78,6 +79,7 @@ func (h *Handler) ListShows(c *gin.Context) {
 	if len(shows) > 0 {
 		h.attachBandsToShows(ctx, shows)
 	}
+	h.setShowsLastModified(c, showListIDs(shows))
 
 	meta := &Meta{
 		Page:       page,
@@ -104,7 +106,7 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 			Offset:  int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list shows by venue", "error", err)
+			logger(c).Error("failed to list shows by venue", "error", err)
 			respondInternalError(c)
 			return nil, 0, err
 		}
@@ -119,7 +121,7 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 			Offset:  int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list shows by region", "error", err)
+			logger(c).Error("failed to list shows by region", "error", err)
 			respondInternalError(c)
 			return nil, 0, err
 		}
@@ -134,13 +136,13 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 			Offset:  int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list shows by genre", "error", err)
+			logger(c).Error("failed to list shows by genre", "error", err)
 			respondInternalError(c)
 			return nil, 0, err
 		}
 		count, err := h.queries.CountShowsByGenre(ctx, genres)
 		if err != nil {
-			slog.Error("failed to count shows by genre", "error", err)
+			logger(c).Error("failed to count shows by genre", "error", err)
 			respondInternalError(c)
 			return nil, 0, err
 		}
@@ -160,7 +162,7 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 			Offset: int32(offset),
 		})
 		if err != nil {
-			slog.Error("failed to list shows by date range", "error", err)
+			logger(c).Error("failed to list shows by date range", "error", err)
 			respondInternalError(c)
 			return nil, 0, err
 		}
@@ -174,7 +176,7 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 		Offset: int32(offset),
 	})
 	if err != nil {
-		slog.Error("failed to list upcoming shows", "error", err)
+		logger(c).Error("failed to list upcoming shows", "error", err)
 		respondInternalError(c)
 		return nil, 0, err
 	}
@@ -184,14 +186,9 @@ func (h *Handler) listShowsWithFilters(ctx context.Context, c *gin.Context, perP
 
 // attachBandsToShows loads and attaches bands to show list items.
 func (h *Handler) attachBandsToShows(ctx context.Context, shows []ShowListItem) {
-	showIDs := make([]int32, len(shows))
-	for i, s := range shows {
-		showIDs[i] = s.ID
-	}
-
-	bandsMap, err := h.loadBandsForShows(ctx, showIDs)
+	bandsMap, err := h.loadBandsForShows(ctx, showListIDs(shows))
 	if err != nil {
-		slog.Error("failed to load bands for shows", "error", err)
+		logging.FromContext(ctx).Error("failed to load bands for shows", "error", err)
 		return // Continue without bands rather than failing
 	}
 
@@ -219,14 +216,14 @@ func (h *Handler) GetShow(c *gin.Context) {
 			respondNotFound(c, "Show")
 			return
 		}
-		slog.Error("failed to get show", "id", id, "error", err)
+		logger(c).Error("failed to get show", "id", id, "error", err)
 		respondInternalError(c)
 		return
 	}
 
 	bandRows, err := h.queries.GetShowBands(ctx, int32(id))
 	if err != nil {
-		slog.Error("failed to get show bands", "show_id", id, "error", err)
+		logger(c).Error("failed to get show bands", "show_id", id, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -236,7 +233,7 @@ func (h *Handler) GetShow(c *gin.Context) {
 	for i, b := range bandRows {
 		genres, err := h.queries.GetBandGenresForShow(ctx, b.ID)
 		if err != nil {
-			slog.Error("failed to get band genres", "band_id", b.ID, "error", err)
+			logger(c).Error("failed to get band genres", "band_id", b.ID, "error", err)
 			genres = []db.GetBandGenresForShowRow{}
 		}
 
@@ -254,6 +251,14 @@ func (h *Handler) GetShow(c *gin.Context) {
 		}
 	}
 
+	var rescheduledTo *ShowRef
+	if show.RescheduledTo != nil {
+		rescheduledTo = &ShowRef{
+			ID:   *show.RescheduledTo,
+			Date: formatTimestamp(show.RescheduledDate),
+		}
+	}
+
 	detail := ShowDetail{
 		ID:             show.ID,
 		Title:          show.Title,
@@ -267,6 +272,7 @@ func (h *Handler) GetShow(c *gin.Context) {
 		TicketURL:      show.TicketUrl,
 		AgeRestriction: show.AgeRestriction,
 		Status:         stringValue(show.Status),
+		RescheduledTo:  rescheduledTo,
 		Venue: VenueForShow{
 			ID:       show.VenueID,
 			Name:     show.VenueName,
@@ -279,9 +285,66 @@ func (h *Handler) GetShow(c *gin.Context) {
 		Bands: bands,
 	}
 
+	h.setShowsLastModified(c, []int32{show.ID})
 	respondJSON(c, http.StatusOK, detail)
 }
 
+// GetShowHistory handles GET /api/shows/:id/history.
+// Returns the field-level change log for a show, most recent first.
+func (h *Handler) GetShowHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	exists, err := h.queries.ShowExists(ctx, int32(id))
+	if err != nil {
+		logger(c).Error("failed to check show exists", "id", id, "error", err)
+		respondInternalError(c)
+		return
+	}
+	if !exists {
+		respondNotFound(c, "Show")
+		return
+	}
+
+	rows, err := h.queries.ListShowRevisions(ctx, db.ListShowRevisionsParams{
+		ShowID: int32(id),
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	})
+	if err != nil {
+		logger(c).Error("failed to list show revisions", "show_id", id, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	revisions, total := convertShowRevisionsToItems(rows)
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, revisions, meta)
+}
+
 // loadBandsForShows loads bands for multiple shows.
 // TODO: Optimize with batch query using GetShowBandsForVenue once available.
 func (h *Handler) loadBandsForShows(ctx context.Context, showIDs []int32) (map[int32][]BandBasic, error) {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/revision"
 	"github.com/paulsena/asheville-setlist/internal/testutil"
 )
 
@@ -21,10 +22,11 @@ func init() {
 
 // setupShowsTestRouter creates a test router with the shows handler
 func setupShowsTestRouter(tdb *testutil.TestDB) *gin.Engine {
-	h := handlers.New(tdb.Queries)
+	h := handlers.New(tdb.Queries, handlers.WithPool(tdb.Pool))
 	router := gin.New()
 	router.GET("/api/shows", h.ListShows)
 	router.GET("/api/shows/:id", h.GetShow)
+	router.GET("/api/shows/:id/history", h.GetShowHistory)
 	router.POST("/api/shows", h.CreateShow)
 	return router
 }
@@ -356,6 +358,107 @@ func TestGetShow_InvalidID(t *testing.T) {
 	}
 }
 
+func TestGetShowHistory(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	// Clean up before and after
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "History Show")
+	if err != nil {
+		t.Fatalf("failed to insert test show: %v", err)
+	}
+
+	var diff revision.Diff
+	diff.Add(revision.FieldStatus, "scheduled", "cancelled")
+	diff.Add(revision.FieldPriceMin, nil, 15.5)
+	actor := revision.Actor{Name: "test-source", Source: revision.SourceScraped}
+	if err := revision.Record(ctx, tdb.Queries, showID, actor, &diff); err != nil {
+		t.Fatalf("failed to record revisions: %v", err)
+	}
+
+	router := setupShowsTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/shows/%d/history", showID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []struct {
+			Field    string          `json:"field"`
+			OldValue json.RawMessage `json:"old_value"`
+			NewValue json.RawMessage `json:"new_value"`
+			Actor    *string         `json:"actor"`
+			Source   string          `json:"source"`
+		} `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if resp.Meta.Total != 2 || len(resp.Data) != 2 {
+		t.Fatalf("expected 2 revisions, got %d (total %d)", len(resp.Data), resp.Meta.Total)
+	}
+
+	// Most recent first; both share a timestamp so the later insert wins
+	price := resp.Data[0]
+	if price.Field != revision.FieldPriceMin {
+		t.Errorf("expected field %s, got %s", revision.FieldPriceMin, price.Field)
+	}
+	if string(price.OldValue) != "null" || string(price.NewValue) != "15.5" {
+		t.Errorf("expected null -> 15.5, got %s -> %s", price.OldValue, price.NewValue)
+	}
+	if price.Actor == nil || *price.Actor != "test-source" || price.Source != revision.SourceScraped {
+		t.Errorf("unexpected actor/source: %v/%s", price.Actor, price.Source)
+	}
+}
+
+func TestGetShowHistory_Errors(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupShowsTestRouter(tdb)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"show not found", "/api/shows/999999/history", http.StatusNotFound},
+		{"invalid id", "/api/shows/invalid/history", http.StatusBadRequest},
+		{"invalid page", "/api/shows/1/history?page=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestCreateShow_Success(t *testing.T) {
 	tdb := testutil.SetupTestDB(t)
 	defer tdb.Close()
@@ -414,6 +517,83 @@ func TestCreateShow_Success(t *testing.T) {
 	if resp.Data.Status != "scheduled" {
 		t.Errorf("expected status 'scheduled', got '%s'", resp.Data.Status)
 	}
+
+	// The submission is the show's first revision, without the submitter's address
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/shows/%d/history", resp.Data.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var history struct {
+		Data []struct {
+			Field  string  `json:"field"`
+			Actor  *string `json:"actor"`
+			Source string  `json:"source"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to parse history: %v", err)
+	}
+	if len(history.Data) != 1 || history.Data[0].Field != revision.FieldStatus || history.Data[0].Source != revision.SourceBandSubmitted {
+		t.Fatalf("expected one band_submitted status revision, got %s", w.Body.String())
+	}
+	if history.Data[0].Actor != nil {
+		t.Errorf("expected no actor for a band submission, got %q", *history.Data[0].Actor)
+	}
+
+	// Submitting the same night again (any lineup band, any case) is a duplicate
+	dup := fmt.Sprintf(`{"venue_id": %d, "date": "%s", "bands": [{"name": "test band opener"}]}`, venueID, futureDate)
+	req = httptest.NewRequest(http.MethodPost, "/api/shows", strings.NewReader(dup))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d for duplicate, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	var dupResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				ShowID int32 `json:"show_id"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &dupResp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if dupResp.Error.Code != handlers.ErrCodeDuplicate || dupResp.Error.Details.ShowID != resp.Data.ID {
+		t.Errorf("expected duplicate of show %d, got %s", resp.Data.ID, w.Body.String())
+	}
+}
+
+func TestCreateShow_Honeypot(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	router := setupShowsTestRouter(tdb)
+
+	futureDate := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	body := fmt.Sprintf(`{"venue_id": %d, "date": "%s", "bands": [{"name": "Test Band Honeypot"}], "website": "http://spam.example"}`, venueID, futureDate)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shows", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Bots see a success, but nothing is stored
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if _, err := tdb.Queries.GetBandByName(ctx, "Test Band Honeypot"); err == nil {
+		t.Error("expected honeypot submission not to create bands")
+	}
 }
 
 func TestCreateShow_ValidationErrors(t *testing.T) {
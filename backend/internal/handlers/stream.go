@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// streamBatchSize is the most events read per query when catching a client up.
+const streamBatchSize = 100
+
+// StreamShows handles GET /api/stream/shows.
+// Streams newly announced and changed shows as Server-Sent Events, optionally
+// filtered by venue, region and genre like GET /api/shows. Idle connections
+// get a heartbeat comment. Reconnecting clients send Last-Event-ID to receive
+// the events they missed.
+func (h *Handler) StreamShows(c *gin.Context) {
+	if h.streamHub == nil {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Show stream is not enabled")
+		return
+	}
+	ctx := c.Request.Context()
+
+	params := db.ListShowStreamEventsParams{
+		Venues:  c.QueryArray("venue"),
+		Regions: c.QueryArray("region"),
+		Genres:  c.QueryArray("genre"),
+		Limit:   streamBatchSize,
+	}
+
+	// Subscribe before reading the cursor so no event can fall in between
+	wake, unsubscribe := h.streamHub.Subscribe()
+	defer unsubscribe()
+
+	if lastID := c.GetHeader("Last-Event-ID"); lastID != "" {
+		after, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || after < 0 {
+			respondInvalidParam(c, "Last-Event-ID", "must be a non-negative integer")
+			return
+		}
+		params.AfterID = after
+	} else {
+		latest, err := h.queries.GetLatestShowStreamEventID(ctx)
+		if err != nil {
+			logger(c).Error("failed to get show stream cursor", "error", err)
+			respondInternalError(c)
+			return
+		}
+		params.AfterID = latest
+	}
+
+	// The stream outlives the server's write timeout (not every writer
+	// supports deadlines, e.g. in tests)
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		// Send everything after the cursor: the backlog first, then each wake-up
+		if err := h.sendShowEvents(c, &params); err != nil {
+			if ctx.Err() == nil {
+				logger(c).Error("failed to stream shows", "error", err)
+			}
+			return
+		}
+
+		if !h.waitForShowEvents(c, wake, heartbeat.C) {
+			return
+		}
+	}
+}
+
+// waitForShowEvents blocks until new events may be available, writing
+// heartbeats meanwhile. It returns false when the stream should end.
+func (h *Handler) waitForShowEvents(c *gin.Context, wake <-chan struct{}, heartbeat <-chan time.Time) bool {
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-h.streamHub.Done():
+			return false
+		case <-wake:
+			return true
+		case <-heartbeat:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return false
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// sendShowEvents writes the events after params.AfterID as SSE messages and
+// advances the cursor past the last one sent.
+func (h *Handler) sendShowEvents(c *gin.Context, params *db.ListShowStreamEventsParams) error {
+	ctx := c.Request.Context()
+
+	for {
+		rows, err := h.queries.ListShowStreamEvents(ctx, *params)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		shows := convertStreamEventsToListItems(rows)
+		h.attachBandsToShows(ctx, shows)
+
+		for i, r := range rows {
+			err := sse.Encode(c.Writer, sse.Event{
+				Id:    strconv.FormatInt(r.EventID, 10),
+				Event: r.EventType,
+				Data:  shows[i],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		params.AfterID = rows[len(rows)-1].EventID
+
+		if len(rows) < int(params.Limit) {
+			return nil
+		}
+	}
+}
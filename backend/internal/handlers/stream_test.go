@@ -0,0 +1,267 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/handlers"
+	"github.com/paulsena/asheville-setlist/internal/stream"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+// sseMessage is one Server-Sent Events message, or a comment when Comment is set.
+type sseMessage struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment string
+}
+
+// sseClient reads messages from an open stream.
+type sseClient struct {
+	messages chan sseMessage
+	cancel   context.CancelFunc
+}
+
+func openShowStream(t *testing.T, server *httptest.Server, query, lastEventID string) *sseClient {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/stream/shows"+query, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		cancel()
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	client := &sseClient{messages: make(chan sseMessage, 16), cancel: cancel}
+	go func() {
+		defer resp.Body.Close()
+		defer close(client.messages)
+		scanner := bufio.NewScanner(resp.Body)
+		var msg sseMessage
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if msg != (sseMessage{}) {
+					client.messages <- msg
+				}
+				msg = sseMessage{}
+			case strings.HasPrefix(line, ":"):
+				msg.Comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id:"):
+				msg.ID = strings.TrimSpace(line[3:])
+			case strings.HasPrefix(line, "event:"):
+				msg.Event = strings.TrimSpace(line[6:])
+			case strings.HasPrefix(line, "data:"):
+				msg.Data = strings.TrimSpace(line[5:])
+			}
+		}
+	}()
+	t.Cleanup(cancel)
+	return client
+}
+
+// next returns the next message, failing the test after a timeout.
+func (c *sseClient) next(t *testing.T) sseMessage {
+	t.Helper()
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for stream message")
+	}
+	return sseMessage{}
+}
+
+// nextEvent skips heartbeats and returns the next event.
+func (c *sseClient) nextEvent(t *testing.T) sseMessage {
+	t.Helper()
+	for {
+		if msg := c.next(t); msg.Comment == "" {
+			return msg
+		}
+	}
+}
+
+func setupStreamTestServer(t *testing.T, tdb *testutil.TestDB) *httptest.Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := stream.NewHub(tdb.Pool)
+	go hub.Run(ctx)
+
+	h := handlers.New(tdb.Queries, handlers.WithShowStream(hub, 50*time.Millisecond))
+	router := gin.New()
+	router.GET("/api/stream/shows", h.StreamShows)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+	return server
+}
+
+func TestStreamShows_Disabled(t *testing.T) {
+	h := handlers.New(nil)
+	router := gin.New()
+	router.GET("/api/stream/shows", h.StreamShows)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stream/shows", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestStreamShows_InvalidLastEventID(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	h := handlers.New(tdb.Queries, handlers.WithShowStream(stream.NewHub(tdb.Pool), time.Second))
+	router := gin.New()
+	router.GET("/api/stream/shows", h.StreamShows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stream/shows", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestStreamShows_Live(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+	var venueSlug string
+	if err := tdb.Pool.QueryRow(ctx, `SELECT slug FROM venues WHERE id = $1`, venueID).Scan(&venueSlug); err != nil {
+		t.Fatalf("failed to get venue slug: %v", err)
+	}
+
+	server := setupStreamTestServer(t, tdb)
+	all := openShowStream(t, server, "", "")
+	atVenue := openShowStream(t, server, "?venue="+venueSlug, "")
+	elsewhere := openShowStream(t, server, "?venue=no-such-venue", "")
+
+	// Heartbeats arrive while nothing happens
+	if msg := all.next(t); msg.Comment != "heartbeat" {
+		t.Errorf("expected heartbeat, got %+v", msg)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 3), "Stream Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	if err := stream.Publish(ctx, tdb.Queries, stream.EventShowCreated, showID); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	for name, client := range map[string]*sseClient{"unfiltered": all, "venue filter": atVenue} {
+		msg := client.nextEvent(t)
+		if msg.Event != stream.EventShowCreated || msg.ID == "" {
+			t.Errorf("%s: unexpected message %+v", name, msg)
+			continue
+		}
+		var show struct {
+			ID    int32   `json:"id"`
+			Title *string `json:"title"`
+			Venue struct {
+				Slug string `json:"slug"`
+			} `json:"venue"`
+		}
+		if err := json.Unmarshal([]byte(msg.Data), &show); err != nil {
+			t.Fatalf("%s: failed to parse show: %v", name, err)
+		}
+		if show.ID != showID || show.Title == nil || *show.Title != "[TEST] Stream Show" || show.Venue.Slug != venueSlug {
+			t.Errorf("%s: unexpected show %+v", name, show)
+		}
+	}
+
+	// A filtered-out client only sees heartbeats
+	for range 3 {
+		if msg := elsewhere.next(t); msg.Comment == "" {
+			t.Errorf("expected only heartbeats for another venue, got %+v", msg)
+		}
+	}
+}
+
+func TestStreamShows_Resume(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	cursor, err := tdb.Queries.GetLatestShowStreamEventID(ctx)
+	if err != nil {
+		t.Fatalf("failed to get cursor: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 3), "Resumed Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	if err := stream.Publish(ctx, tdb.Queries, stream.EventShowCreated, showID); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if err := stream.Publish(ctx, tdb.Queries, stream.EventShowCancelled, showID); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	server := setupStreamTestServer(t, tdb)
+
+	// Missed events are replayed in order
+	client := openShowStream(t, server, "", strconv.FormatInt(cursor, 10))
+	first := client.nextEvent(t)
+	second := client.nextEvent(t)
+	if first.Event != stream.EventShowCreated || second.Event != stream.EventShowCancelled {
+		t.Fatalf("expected created then cancelled, got %q then %q", first.Event, second.Event)
+	}
+
+	// Resuming after the first event only replays the second
+	client = openShowStream(t, server, "", first.ID)
+	if msg := client.nextEvent(t); msg.ID != second.ID {
+		t.Errorf("expected event %s, got %+v", second.ID, msg)
+	}
+}
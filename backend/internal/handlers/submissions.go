@@ -1,15 +1,23 @@
 package handlers
 
 import (
-	"log/slog"
+	"errors"
+	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/cache"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/inference"
+	"github.com/paulsena/asheville-setlist/internal/ingest"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/revision"
+	"github.com/paulsena/asheville-setlist/internal/stream"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
 )
 
 // CreateShow handles POST /api/shows for band submissions.
@@ -24,10 +32,20 @@ func (h *Handler) CreateShow(c *gin.Context) {
 		return
 	}
 
+	// Bots fill in the hidden honeypot field; pretend to accept their show
+	if req.Website != "" {
+		logger(c).Warn("dropped show submission with honeypot field", "ip", c.ClientIP())
+		respondJSON(c, http.StatusCreated, CreateShowResponse{
+			Status:    "scheduled",
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
 	// Validate venue exists
 	exists, err := h.queries.VenueExists(ctx, req.VenueID)
 	if err != nil {
-		slog.Error("failed to check venue exists", "error", err)
+		logger(c).Error("failed to check venue exists", "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -96,6 +114,29 @@ func (h *Handler) CreateShow(c *gin.Context) {
 		}
 	}
 
+	// Reject shows already listed for the venue that night
+	bandNames := make([]string, len(req.Bands))
+	for i, band := range req.Bands {
+		bandNames[i] = strings.ToLower(strings.TrimSpace(band.Name))
+	}
+	local := showDate.Time.In(showLocation())
+	duplicateID, err := h.queries.FindDuplicateShow(ctx, db.FindDuplicateShowParams{
+		VenueID:   req.VenueID,
+		Day:       pgtype.Date{Time: time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		BandNames: bandNames,
+	})
+	if err == nil {
+		respondErrorWithDetails(c, http.StatusConflict, ErrCodeDuplicate, "This show is already listed", map[string]any{
+			"show_id": duplicateID,
+		})
+		return
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger(c).Error("failed to check for duplicate show", "error", err)
+		respondInternalError(c)
+		return
+	}
+
 	doorsTime := parseTimeString(req.DoorsTime)
 	showTime := parseTimeString(req.ShowTime)
 	priceMin := floatToNumeric(req.PriceMin)
@@ -105,58 +146,81 @@ func (h *Handler) CreateShow(c *gin.Context) {
 	status := "scheduled"
 	source := "band_submitted"
 
-	showRow, err := h.queries.CreateShow(ctx, db.CreateShowParams{
-		VenueID:        req.VenueID,
-		Title:          nil, // Title derived from bands
-		ImageUrl:       req.ImageURL,
-		Date:           showDate,
-		DoorsTime:      doorsTime,
-		ShowTime:       showTime,
-		PriceMin:       priceMin,
-		PriceMax:       priceMax,
-		TicketUrl:      req.TicketURL,
-		AgeRestriction: req.AgeRestriction,
-		Status:         &status,
-		Source:         &source,
-	})
-	if err != nil {
-		slog.Error("failed to create show", "error", err)
-		respondInternalError(c)
-		return
-	}
+	// The show, its first revision, lineup and notifications commit together
+	var showRow db.CreateShowRow
+	err = h.inTx(ctx, func(q *db.Queries) error {
+		showRow, err = q.CreateShow(ctx, db.CreateShowParams{
+			VenueID:        req.VenueID,
+			Title:          nil, // Title derived from bands
+			ImageUrl:       req.ImageURL,
+			Date:           showDate,
+			DoorsTime:      doorsTime,
+			ShowTime:       showTime,
+			PriceMin:       priceMin,
+			PriceMax:       priceMax,
+			TicketUrl:      req.TicketURL,
+			AgeRestriction: req.AgeRestriction,
+			Status:         &status,
+			Source:         &source,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create show: %w", err)
+		}
 
-	// Process bands
-	for _, bandReq := range req.Bands {
-		bandName := strings.TrimSpace(bandReq.Name)
+		// Record the submission as the show's first revision
+		var created revision.Diff
+		if err := created.Add(revision.FieldStatus, nil, status); err != nil {
+			return err
+		}
+		if err := revision.Record(ctx, q, showRow.ID, revision.BandSubmission, &created); err != nil {
+			return err
+		}
 
-		existingBand, err := h.queries.GetBandByName(ctx, bandName)
-		var bandID int32
+		// Process bands
+		var createdBands []int32
+		for _, bandReq := range req.Bands {
+			bandID, created, err := ingest.FindOrCreateBand(ctx, q, strings.TrimSpace(bandReq.Name))
+			if err != nil {
+				return err
+			}
+			if created {
+				createdBands = append(createdBands, bandID)
+			}
 
-		if err != nil {
-			// Create new band
-			slug := generateSlug(bandName)
-			newBand, err := h.queries.CreateBand(ctx, db.CreateBandParams{
-				Name: bandName,
-				Slug: slug,
+			err = q.CreateShowBand(ctx, db.CreateShowBandParams{
+				ShowID:           showRow.ID,
+				BandID:           bandID,
+				IsHeadliner:      bandReq.IsHeadliner,
+				PerformanceOrder: bandReq.PerformanceOrder,
 			})
 			if err != nil {
-				slog.Error("failed to create band", "name", bandName, "error", err)
-				continue
+				return fmt.Errorf("failed to link band %d: %w", bandID, err)
 			}
-			bandID = newBand.ID
-		} else {
-			bandID = existingBand.ID
 		}
 
-		err = h.queries.CreateShowBand(ctx, db.CreateShowBandParams{
-			ShowID:           showRow.ID,
-			BandID:           bandID,
-			IsHeadliner:      bandReq.IsHeadliner,
-			PerformanceOrder: bandReq.PerformanceOrder,
-		})
-		if err != nil {
-			slog.Error("failed to link band to show", "show_id", showRow.ID, "band_id", bandID, "error", err)
+		// Propose genres for new bands once the whole lineup is linked
+		for _, bandID := range createdBands {
+			if _, err := inference.InferBand(ctx, q, bandID, nil); err != nil {
+				return err
+			}
+		}
+
+		// Tell followers of the lineup and venue
+		if _, err := notify.ShowAnnounced(ctx, q, showRow.ID); err != nil {
+			return err
 		}
+		if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowCreated, showRow.ID); err != nil {
+			return err
+		}
+		if err := stream.Publish(ctx, q, stream.EventShowCreated, showRow.ID); err != nil {
+			return err
+		}
+		return cache.Invalidate(ctx, q, cache.TagShows)
+	})
+	if err != nil {
+		logger(c).Error("failed to create show", "error", err)
+		respondInternalError(c)
+		return
 	}
 
 	response := CreateShowResponse{
@@ -183,11 +247,7 @@ func parseShowDate(dateStr string) (pgtype.Timestamptz, error) {
 	// Try date only (default to 8 PM Eastern)
 	t, err = time.Parse("2006-01-02", dateStr)
 	if err == nil {
-		loc, err := time.LoadLocation("America/New_York")
-		if err != nil {
-			loc = time.FixedZone("EST", -5*60*60)
-		}
-		t = time.Date(t.Year(), t.Month(), t.Day(), 20, 0, 0, 0, loc)
+		t = time.Date(t.Year(), t.Month(), t.Day(), 20, 0, 0, 0, showLocation())
 		result.Time = t
 		result.Valid = true
 		return result, nil
@@ -196,6 +256,15 @@ func parseShowDate(dateStr string) (pgtype.Timestamptz, error) {
 	return result, err
 }
 
+// showLocation is the time zone of the venues, which show dates are local to.
+func showLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
 // parseTimeString parses a time string (HH:MM or HH:MM:SS) to pgtype.Time.
 func parseTimeString(timeStr *string) pgtype.Time {
 	var result pgtype.Time
@@ -233,22 +302,3 @@ func floatToNumeric(f *float64) pgtype.Numeric {
 	}
 	return result
 }
-
-// generateSlug creates a URL-friendly slug from a string.
-func generateSlug(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-
-	reg := regexp.MustCompile(`[^a-z0-9-]`)
-	s = reg.ReplaceAllString(s, "")
-
-	reg = regexp.MustCompile(`-+`)
-	s = reg.ReplaceAllString(s, "-")
-
-	s = strings.Trim(s, "-")
-
-	if s == "" {
-		return "band"
-	}
-	return s
-}
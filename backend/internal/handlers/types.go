@@ -1,5 +1,7 @@
 package handlers
 
+import "encoding/json"
+
 // Response types for API endpoints.
 // These types define the JSON structure returned by handlers.
 
@@ -34,10 +36,36 @@ type ShowDetail struct {
 	TicketURL      *string       `json:"ticket_url"`
 	AgeRestriction *string       `json:"age_restriction"`
 	Status         string        `json:"status"`
+	RescheduledTo  *ShowRef      `json:"rescheduled_to"`
 	Venue          VenueForShow  `json:"venue"`
 	Bands          []BandForShow `json:"bands"`
 }
 
+// ShowRecommendation represents a recommended show and why it was picked.
+type ShowRecommendation struct {
+	ShowListItem
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// ShowRef references another show, e.g. the new date of a postponed show.
+type ShowRef struct {
+	ID   int32  `json:"id"`
+	Date string `json:"date"`
+}
+
+// ShowRevisionItem represents a single field change in a show's history.
+// Old and new values are raw JSON; null means the field was unset.
+type ShowRevisionItem struct {
+	ID        int32           `json:"id"`
+	Field     string          `json:"field"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	Actor     *string         `json:"actor"`
+	Source    string          `json:"source"`
+	CreatedAt string          `json:"created_at"`
+}
+
 // VenueBasic represents minimal venue info embedded in other responses.
 type VenueBasic struct {
 	ID       int32   `json:"id"`
@@ -87,18 +115,54 @@ type VenueDetail struct {
 	Phone         *string         `json:"phone"`
 	ImageURL      *string         `json:"image_url"`
 	UpcomingShows []VenueShowItem `json:"upcoming_shows"`
+	Stats         VenueStats      `json:"stats"`
 }
 
-// VenueShowItem represents a show in venue detail response.
+// VenueShowItem represents a show in venue detail and venue shows responses.
 type VenueShowItem struct {
 	ID       int32       `json:"id"`
 	Title    *string     `json:"title"`
 	Date     string      `json:"date"`
 	PriceMin *float64    `json:"price_min"`
 	PriceMax *float64    `json:"price_max"`
+	Status   string      `json:"status"`
 	Bands    []BandBasic `json:"bands"`
 }
 
+// VenueStats represents show history statistics for a venue.
+type VenueStats struct {
+	TotalShows    int64            `json:"total_shows"`
+	UpcomingShows int64            `json:"upcoming_shows"`
+	PastShows     int64            `json:"past_shows"`
+	AvgPriceMin   *float64         `json:"avg_price_min"`
+	AvgPriceMax   *float64         `json:"avg_price_max"`
+	ShowsPerMonth []MonthCount     `json:"shows_per_month"`
+	TopBands      []BandPlayCount  `json:"top_bands"`
+	GenreMix      []GenreShowCount `json:"genre_mix"`
+}
+
+// MonthCount represents the number of shows in a calendar month (YYYY-MM).
+type MonthCount struct {
+	Month     string `json:"month"`
+	ShowCount int64  `json:"show_count"`
+}
+
+// BandPlayCount represents a band with the number of shows it has played.
+type BandPlayCount struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ShowCount int64  `json:"show_count"`
+}
+
+// GenreShowCount represents a genre with the number of shows featuring it.
+type GenreShowCount struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	ShowCount int64  `json:"show_count"`
+}
+
 // BandBasic represents minimal band info embedded in other responses.
 type BandBasic struct {
 	ID               int32   `json:"id"`
@@ -149,24 +213,85 @@ type BandDetail struct {
 	BandcampURL   *string        `json:"bandcamp_url"`
 	Genres        []GenreBasic   `json:"genres"`
 	UpcomingShows []BandShowItem `json:"upcoming_shows"`
+	History       BandHistory    `json:"history"`
 }
 
-// BandShowItem represents a show in band detail response.
+// BandShowItem represents a show in band detail and band shows responses.
 type BandShowItem struct {
 	ID          int32      `json:"id"`
+	Title       *string    `json:"title"`
 	Date        string     `json:"date"`
+	Status      string     `json:"status"`
 	Venue       VenueBasic `json:"venue"`
 	IsHeadliner bool       `json:"is_headliner"`
 }
 
-// SimilarBandItem represents a similar band with shared genre information.
+// BandHistory summarizes the shows a band has already played.
+type BandHistory struct {
+	TotalShows      int64            `json:"total_shows"`
+	HeadlinerCount  int64            `json:"headliner_count"`
+	SupportCount    int64            `json:"support_count"`
+	FirstSeen       *string          `json:"first_seen"`
+	LastSeen        *string          `json:"last_seen"`
+	VenuePlayCounts []VenuePlayCount `json:"venue_play_counts"`
+}
+
+// VenuePlayCount represents how many times a band has played a venue.
+type VenuePlayCount struct {
+	ID         int32   `json:"id"`
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	ShowCount  int64   `json:"show_count"`
+	LastPlayed *string `json:"last_played"`
+}
+
+// SimilarBandItem represents a similar band with its score and why it matched.
 type SimilarBandItem struct {
-	ID               int32        `json:"id"`
-	Name             string       `json:"name"`
-	Slug             string       `json:"slug"`
-	ImageURL         *string      `json:"image_url"`
-	SharedGenreCount int64        `json:"shared_genre_count"`
-	SharedGenres     []GenreBasic `json:"shared_genres"`
+	ID               int32                `json:"id"`
+	Name             string               `json:"name"`
+	Slug             string               `json:"slug"`
+	ImageURL         *string              `json:"image_url"`
+	Score            float64              `json:"score"`
+	Components       SimilarityComponents `json:"components"`
+	SharedGenreCount int64                `json:"shared_genre_count"`
+	SharedGenres     []GenreBasic         `json:"shared_genres"`
+	SharedShowCount  int64                `json:"shared_show_count"`
+	SharedVenueCount int64                `json:"shared_venue_count"`
+	Reasons          []string             `json:"reasons"`
+}
+
+// SimilarityComponents are the unweighted (0-1) parts of a similarity score.
+type SimilarityComponents struct {
+	Genre    float64 `json:"genre"`
+	CoBill   float64 `json:"co_bill"`
+	Venue    float64 `json:"venue"`
+	Hometown float64 `json:"hometown"`
+}
+
+// BandRef identifies a band in admin responses.
+type BandRef struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// GenreSuggestionItem represents an inferred band genre awaiting admin review.
+type GenreSuggestionItem struct {
+	Band       BandRef    `json:"band"`
+	Genre      GenreBasic `json:"genre"`
+	Source     string     `json:"source"`
+	Confidence *float64   `json:"confidence"`
+	CreatedAt  string     `json:"created_at"`
+}
+
+// GenreConfirmation represents an inferred band genre accepted by an admin.
+type GenreConfirmation struct {
+	BandID      int32    `json:"band_id"`
+	GenreID     int32    `json:"genre_id"`
+	Source      string   `json:"source"`
+	Confidence  *float64 `json:"confidence"`
+	ConfirmedAt string   `json:"confirmed_at"`
+	ConfirmedBy *string  `json:"confirmed_by"`
 }
 
 // GenreBasic represents minimal genre info embedded in other responses.
@@ -182,36 +307,95 @@ type GenreListItem struct {
 	Name        string  `json:"name"`
 	Slug        string  `json:"slug"`
 	Description *string `json:"description"`
+	ParentID    *int32  `json:"parent_id"`
 	ShowCount   int64   `json:"show_count,omitempty"`
 }
 
+// GenreDetail represents a genre in detail response with its place in the
+// genre hierarchy, bands (paginated via Meta) and upcoming shows.
+type GenreDetail struct {
+	ID            int32          `json:"id"`
+	Name          string         `json:"name"`
+	Slug          string         `json:"slug"`
+	Description   *string        `json:"description"`
+	Parent        *GenreBasic    `json:"parent"`
+	Children      []GenreBasic   `json:"children"`
+	Bands         []BandListItem `json:"bands"`
+	UpcomingShows []ShowListItem `json:"upcoming_shows"`
+}
+
 // SearchResult represents the global search response with categorized results.
 type SearchResult struct {
-	Shows  []SearchShowItem  `json:"shows"`
-	Bands  []SearchBandItem  `json:"bands"`
-	Venues []SearchVenueItem `json:"venues"`
+	Shows    []SearchShowItem    `json:"shows"`
+	Bands    []SearchBandItem    `json:"bands"`
+	Venues   []SearchVenueItem   `json:"venues"`
+	Articles []SearchArticleItem `json:"articles"`
+}
+
+// SearchResultItem represents a single result in unified search.
+type SearchResultItem struct {
+	Type      string           `json:"type"` // band, venue, show or article
+	ID        int32            `json:"id"`
+	Name      string           `json:"name"`     // Show title (or lineup), article title
+	Slug      *string          `json:"slug"`     // nil for shows (linked by ID)
+	Subtitle  *string          `json:"subtitle"` // Show venue, band hometown, venue region, article excerpt
+	Date      *string          `json:"date"`     // Show date or article publish date
+	Rank      float32          `json:"rank"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchHighlight explains why a search result matched: the field that
+// matched and a ts_headline snippet of it with matched terms in <mark> tags.
+// The snippet is HTML-escaped text, so it is safe to render as HTML.
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
 }
 
 // SearchShowItem represents a show in search results.
 type SearchShowItem struct {
-	ID        int32   `json:"id"`
-	Title     *string `json:"title"`
-	Date      string  `json:"date"`
-	VenueName string  `json:"venue_name"`
+	ID        int32            `json:"id"`
+	Title     *string          `json:"title"`
+	Date      string           `json:"date"`
+	VenueName string           `json:"venue_name"`
+	BandNames []string         `json:"band_names"` // Lineup, headliner first
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
 }
 
 // SearchBandItem represents a band in search results.
 type SearchBandItem struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID        int32            `json:"id"`
+	Name      string           `json:"name"`
+	Slug      string           `json:"slug"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
 }
 
 // SearchVenueItem represents a venue in search results.
 type SearchVenueItem struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID        int32            `json:"id"`
+	Name      string           `json:"name"`
+	Slug      string           `json:"slug"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchArticleItem represents an article in search results.
+type SearchArticleItem struct {
+	ID          int32            `json:"id"`
+	Title       string           `json:"title"`
+	Slug        string           `json:"slug"`
+	Excerpt     *string          `json:"excerpt"`
+	PublishedAt *string          `json:"published_at"`
+	Highlight   *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// AutocompleteItem represents a single autocomplete suggestion.
+type AutocompleteItem struct {
+	Type  string  `json:"type"` // band, venue, genre or show
+	ID    int32   `json:"id"`
+	Name  string  `json:"name"`
+	Slug  *string `json:"slug"` // nil for shows (linked by ID)
+	Date  *string `json:"date"` // shows only
+	Score float32 `json:"score"`
 }
 
 // CreateShowRequest represents the request body for creating a show submission.
@@ -226,6 +410,8 @@ type CreateShowRequest struct {
 	TicketURL      *string          `json:"ticket_url"`
 	AgeRestriction *string          `json:"age_restriction"`
 	Bands          []CreateShowBand `json:"bands" binding:"required,min=1"`
+	// Website is a honeypot: the form hides it from people, so only bots fill it in
+	Website string `json:"website"`
 }
 
 // CreateShowBand represents a band in the create show request.
@@ -241,3 +427,200 @@ type CreateShowResponse struct {
 	Status    string `json:"status"`
 	CreatedAt string `json:"created_at"`
 }
+
+// MagicLinkRequest represents the request body for requesting a sign-in link.
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// VerifyLoginRequest represents the request body for exchanging a sign-in link token.
+type VerifyLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// SessionResponse represents a newly issued bearer session.
+type SessionResponse struct {
+	Token     string   `json:"token"`
+	ExpiresAt string   `json:"expires_at"`
+	User      UserItem `json:"user"`
+}
+
+// UserItem represents a user account.
+type UserItem struct {
+	ID          int32   `json:"id"`
+	Email       string  `json:"email"`
+	CreatedAt   string  `json:"created_at"`
+	LastLoginAt *string `json:"last_login_at"`
+}
+
+// UserProfile represents the signed-in user with counts of what they save and follow.
+type UserProfile struct {
+	UserItem
+	SavedShowCount     int32 `json:"saved_show_count"`
+	FollowedBandCount  int32 `json:"followed_band_count"`
+	FollowedVenueCount int32 `json:"followed_venue_count"`
+}
+
+// VenueRef references a venue by ID, name and slug.
+type VenueRef struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// FollowsResponse lists the bands and venues a user follows.
+type FollowsResponse struct {
+	Bands  []BandRef  `json:"bands"`
+	Venues []VenueRef `json:"venues"`
+}
+
+// FeedItem represents an upcoming show in a user's feed and why it is there.
+type FeedItem struct {
+	ShowListItem
+	Saved         bool     `json:"saved"`
+	FollowedVenue bool     `json:"followed_venue"`
+	FollowedBands []string `json:"followed_bands"`
+}
+
+// NotificationItem represents a notification in the user's inbox.
+type NotificationItem struct {
+	ID        int32   `json:"id"`
+	Kind      string  `json:"kind"`
+	Message   string  `json:"message"`
+	ShowID    int32   `json:"show_id"`
+	ShowName  string  `json:"show_name"`
+	ShowDate  string  `json:"show_date"`
+	VenueName string  `json:"venue_name"`
+	VenueSlug string  `json:"venue_slug"`
+	BandName  *string `json:"band_name"`
+	BandSlug  *string `json:"band_slug"`
+	CreatedAt string  `json:"created_at"`
+	ReadAt    *string `json:"read_at"`
+}
+
+// NotificationChannelRequest represents the request body for adding a
+// notification channel. Email channels deliver to the account's address,
+// webhook channels need url and Web Push channels need subscription.
+type NotificationChannelRequest struct {
+	Kind         string            `json:"kind" binding:"required"`
+	Frequency    string            `json:"frequency"`
+	URL          string            `json:"url"`
+	Subscription *PushSubscription `json:"subscription"`
+}
+
+// PushSubscription is a browser PushSubscription serialized with toJSON().
+type PushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// NotificationChannelItem represents one of a user's notification channels.
+type NotificationChannelItem struct {
+	ID         int32   `json:"id"`
+	Kind       string  `json:"kind"`
+	Target     string  `json:"target"`
+	Frequency  string  `json:"frequency"`
+	LastSentAt *string `json:"last_sent_at"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// UnsubscribeRequest represents the request body for an unsubscribe link.
+type UnsubscribeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UnsubscribeResponse describes the channel an unsubscribe link disabled.
+type UnsubscribeResponse struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
+
+// WebPushKeyResponse carries the VAPID public key browsers subscribe with.
+type WebPushKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+// WebhookSubscriptionRequest represents the request body for creating a webhook subscription.
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Description *string  `json:"description"`
+}
+
+// WebhookSubscriptionUpdate represents the request body for changing a
+// webhook subscription. Omitted fields are left unchanged.
+type WebhookSubscriptionUpdate struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookSubscriptionItem represents a webhook subscription.
+type WebhookSubscriptionItem struct {
+	ID              int32    `json:"id"`
+	URL             string   `json:"url"`
+	Description     *string  `json:"description"`
+	Events          []string `json:"events"`
+	Active          bool     `json:"active"`
+	PendingCount    *int32   `json:"pending_count,omitempty"`
+	FailedCount     *int32   `json:"failed_count,omitempty"`
+	LastDeliveredAt *string  `json:"last_delivered_at,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+// WebhookSubscriptionCreated is a new subscription with its signing secret,
+// which is only ever returned here.
+type WebhookSubscriptionCreated struct {
+	WebhookSubscriptionItem
+	Secret string `json:"secret"`
+}
+
+// HealthResponse represents the response for GET /healthz.
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// ReadinessResponse represents the response for GET /readyz.
+type ReadinessResponse struct {
+	HealthResponse
+	Timestamp string                 `json:"timestamp"`
+	Checks    map[string]HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the result of one readiness check. Which optional fields
+// are set depends on the check.
+type HealthCheck struct {
+	Status        string  `json:"status"`
+	Error         *string `json:"error,omitempty"`
+	LatencyMS     *int64  `json:"latency_ms,omitempty"`      // database
+	Version       *uint   `json:"version,omitempty"`         // migrations
+	Expected      *uint   `json:"expected,omitempty"`        // migrations
+	Dirty         *bool   `json:"dirty,omitempty"`           // migrations
+	LastSuccessAt *string `json:"last_success_at,omitempty"` // scraper
+	StaleAfter    *string `json:"stale_after,omitempty"`     // scraper
+}
+
+// WebhookDeliveryItem represents one entry in the webhook delivery log.
+type WebhookDeliveryItem struct {
+	ID             int64   `json:"id"`
+	SubscriptionID int32   `json:"subscription_id"`
+	EventID        int64   `json:"event_id"`
+	EventType      string  `json:"event_type,omitempty"`
+	Status         string  `json:"status"`
+	AttemptCount   int32   `json:"attempt_count"`
+	NextAttemptAt  *string `json:"next_attempt_at"` // Set while pending
+	LastAttemptAt  *string `json:"last_attempt_at"`
+	ResponseStatus *int32  `json:"response_status"`
+	LastError      *string `json:"last_error"`
+	ReplayOf       *int64  `json:"replay_of"`
+	CreatedAt      string  `json:"created_at"`
+	DeliveredAt    *string `json:"delivered_at"`
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"errors"
-	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/logging"
 )
 
 // ListVenues handles GET /api/venues with optional region filter.
@@ -21,7 +24,7 @@ func (h *Handler) ListVenues(c *gin.Context) {
 	if len(regions) > 0 {
 		rows, err := h.queries.ListVenuesByRegion(ctx, regions)
 		if err != nil {
-			slog.Error("failed to list venues by region", "error", err)
+			logger(c).Error("failed to list venues by region", "error", err)
 			respondInternalError(c)
 			return
 		}
@@ -29,13 +32,19 @@ func (h *Handler) ListVenues(c *gin.Context) {
 	} else {
 		rows, err := h.queries.ListVenuesWithShowCount(ctx)
 		if err != nil {
-			slog.Error("failed to list venues", "error", err)
+			logger(c).Error("failed to list venues", "error", err)
 			respondInternalError(c)
 			return
 		}
 		venues = convertVenuesToListItems(rows)
 	}
 
+	venueIDs := make([]int32, len(venues))
+	for i, v := range venues {
+		venueIDs[i] = v.ID
+	}
+	h.setVenuesLastModified(c, venueIDs)
+
 	respondJSON(c, http.StatusOK, venues)
 }
 
@@ -55,7 +64,7 @@ func (h *Handler) GetVenue(c *gin.Context) {
 			respondNotFound(c, "Venue")
 			return
 		}
-		slog.Error("failed to get venue", "slug", slug, "error", err)
+		logger(c).Error("failed to get venue", "slug", slug, "error", err)
 		respondInternalError(c)
 		return
 	}
@@ -66,50 +75,29 @@ func (h *Handler) GetVenue(c *gin.Context) {
 		Limit:   VenueUpcomingShowsLimit,
 	})
 	if err != nil {
-		slog.Error("failed to get venue shows", "venue_id", venue.ID, "error", err)
+		logger(c).Error("failed to get venue shows", "venue_id", venue.ID, "error", err)
 		respondInternalError(c)
 		return
 	}
 
-	// Batch load bands for all shows
-	showIDs := make([]int32, len(showRows))
-	for i, s := range showRows {
-		showIDs[i] = s.ID
-	}
-
-	bandsMap := make(map[int32][]BandBasic)
-	if len(showIDs) > 0 {
-		bandRows, err := h.queries.GetShowBandsForVenue(ctx, showIDs)
-		if err != nil {
-			slog.Error("failed to get bands for venue shows", "error", err)
-		} else {
-			for _, b := range bandRows {
-				bandsMap[b.ShowID] = append(bandsMap[b.ShowID], BandBasic{
-					ID:          b.ID,
-					Name:        b.Name,
-					Slug:        b.Slug,
-					IsHeadliner: boolValue(b.IsHeadliner),
-				})
-			}
-		}
-	}
-
-	// Build upcoming shows list
 	upcomingShows := make([]VenueShowItem, len(showRows))
 	for i, s := range showRows {
-		bands := bandsMap[s.ID]
-		if bands == nil {
-			bands = []BandBasic{}
-		}
 		upcomingShows[i] = VenueShowItem{
 			ID:       s.ID,
 			Title:    s.Title,
 			Date:     formatTimestamp(s.Date),
 			PriceMin: numericToFloat(s.PriceMin),
 			PriceMax: numericToFloat(s.PriceMax),
-			Bands:    bands,
+			Status:   stringValue(s.Status),
+			Bands:    []BandBasic{},
 		}
 	}
+	h.attachBandsToVenueShows(ctx, upcomingShows)
+
+	stats, err := h.loadVenueStats(ctx, venue.ID)
+	if err != nil {
+		logger(c).Error("failed to load venue stats", "venue_id", venue.ID, "error", err)
+	}
 
 	detail := VenueDetail{
 		ID:            venue.ID,
@@ -125,7 +113,190 @@ func (h *Handler) GetVenue(c *gin.Context) {
 		Phone:         venue.Phone,
 		ImageURL:      venue.ImageUrl,
 		UpcomingShows: upcomingShows,
+		Stats:         stats,
 	}
 
+	h.setVenuesLastModified(c, []int32{venue.ID})
 	respondJSON(c, http.StatusOK, detail)
 }
+
+// GetVenueShows handles GET /api/venues/:slug/shows with when=upcoming|past and pagination.
+func (h *Handler) GetVenueShows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	slug := c.Param("slug")
+	if slug == "" {
+		respondInvalidParam(c, "slug", "venue slug is required")
+		return
+	}
+
+	when := c.DefaultQuery("when", "upcoming")
+	if when != "upcoming" && when != "past" {
+		respondInvalidParam(c, "when", "must be one of: upcoming, past")
+		return
+	}
+
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	offset := calculateOffset(page, perPage)
+
+	venue, err := h.queries.GetVenueBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondNotFound(c, "Venue")
+			return
+		}
+		logger(c).Error("failed to get venue", "slug", slug, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	var shows []VenueShowItem
+	var total int
+
+	if when == "past" {
+		rows, err := h.queries.ListVenuePastShows(ctx, db.ListVenuePastShowsParams{
+			VenueID: venue.ID,
+			Limit:   int32(perPage),
+			Offset:  int32(offset),
+		})
+		if err != nil {
+			logger(c).Error("failed to list venue past shows", "venue_id", venue.ID, "error", err)
+			respondInternalError(c)
+			return
+		}
+		shows, total = convertVenueShowsToItems(venuePastShowRows(rows))
+	} else {
+		rows, err := h.queries.ListVenueUpcomingShows(ctx, db.ListVenueUpcomingShowsParams{
+			VenueID: venue.ID,
+			Limit:   int32(perPage),
+			Offset:  int32(offset),
+		})
+		if err != nil {
+			logger(c).Error("failed to list venue upcoming shows", "venue_id", venue.ID, "error", err)
+			respondInternalError(c)
+			return
+		}
+		shows, total = convertVenueShowsToItems(venueUpcomingShowRows(rows))
+	}
+
+	h.attachBandsToVenueShows(ctx, shows)
+	h.setVenuesLastModified(c, []int32{venue.ID})
+
+	meta := &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	}
+
+	respondJSONWithMeta(c, http.StatusOK, shows, meta)
+}
+
+// attachBandsToVenueShows batch loads and attaches bands to venue show items.
+func (h *Handler) attachBandsToVenueShows(ctx context.Context, shows []VenueShowItem) {
+	if len(shows) == 0 {
+		return
+	}
+
+	showIDs := make([]int32, len(shows))
+	for i, s := range shows {
+		showIDs[i] = s.ID
+	}
+
+	bandRows, err := h.queries.GetShowBandsForVenue(ctx, showIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to get bands for venue shows", "error", err)
+		return // Continue without bands rather than failing
+	}
+
+	bandsMap := make(map[int32][]BandBasic)
+	for _, b := range bandRows {
+		bandsMap[b.ShowID] = append(bandsMap[b.ShowID], BandBasic{
+			ID:          b.ID,
+			Name:        b.Name,
+			Slug:        b.Slug,
+			IsHeadliner: boolValue(b.IsHeadliner),
+		})
+	}
+
+	for i := range shows {
+		if bands, ok := bandsMap[shows[i].ID]; ok {
+			shows[i].Bands = bands
+		}
+	}
+}
+
+// loadVenueStats loads show history statistics for a venue.
+// On error the partially filled stats are returned alongside the error.
+func (h *Handler) loadVenueStats(ctx context.Context, venueID int32) (VenueStats, error) {
+	stats := VenueStats{
+		ShowsPerMonth: []MonthCount{},
+		TopBands:      []BandPlayCount{},
+		GenreMix:      []GenreShowCount{},
+	}
+
+	summary, err := h.queries.GetVenueShowSummary(ctx, venueID)
+	if err != nil {
+		return stats, err
+	}
+	stats.TotalShows = summary.TotalShows
+	stats.UpcomingShows = summary.UpcomingShows
+	stats.PastShows = summary.PastShows
+	stats.AvgPriceMin = numericToFloat(summary.AvgPriceMin)
+	stats.AvgPriceMax = numericToFloat(summary.AvgPriceMax)
+
+	since := time.Now().AddDate(0, -VenueStatsMonths, 0)
+	monthRows, err := h.queries.GetVenueShowsPerMonth(ctx, db.GetVenueShowsPerMonthParams{
+		VenueID: venueID,
+		Date:    pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return stats, err
+	}
+	for _, m := range monthRows {
+		stats.ShowsPerMonth = append(stats.ShowsPerMonth, MonthCount{
+			Month:     m.Month,
+			ShowCount: m.ShowCount,
+		})
+	}
+
+	bandRows, err := h.queries.GetVenueTopBands(ctx, db.GetVenueTopBandsParams{
+		VenueID: venueID,
+		Limit:   VenueStatsTopBandsLimit,
+	})
+	if err != nil {
+		return stats, err
+	}
+	for _, b := range bandRows {
+		stats.TopBands = append(stats.TopBands, BandPlayCount{
+			ID:        b.ID,
+			Name:      b.Name,
+			Slug:      b.Slug,
+			ShowCount: b.ShowCount,
+		})
+	}
+
+	genreRows, err := h.queries.GetVenueGenreMix(ctx, venueID)
+	if err != nil {
+		return stats, err
+	}
+	for _, g := range genreRows {
+		stats.GenreMix = append(stats.GenreMix, GenreShowCount{
+			ID:        g.ID,
+			Name:      g.Name,
+			Slug:      g.Slug,
+			ShowCount: g.ShowCount,
+		})
+	}
+
+	return stats, nil
+}
@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,6 +20,7 @@ func setupVenuesTestRouter(tdb *testutil.TestDB) *gin.Engine {
 	router := gin.New()
 	router.GET("/api/venues", h.ListVenues)
 	router.GET("/api/venues/:slug", h.GetVenue)
+	router.GET("/api/venues/:slug/shows", h.GetVenueShows)
 	return router
 }
 
@@ -350,7 +352,7 @@ func TestGetVenue_ResponseStructure(t *testing.T) {
 
 	expectedFields := []string{
 		"id", "name", "slug", "address", "city", "state",
-		"region", "website", "upcoming_shows",
+		"region", "website", "upcoming_shows", "stats",
 	}
 
 	for _, field := range expectedFields {
@@ -359,3 +361,215 @@ func TestGetVenue_ResponseStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestGetVenueShows_WhenParam(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	router := setupVenuesTestRouter(tdb)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "default is upcoming",
+			path:           "/api/venues/the-orange-peel/shows",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "past shows",
+			path:           "/api/venues/the-orange-peel/shows?when=past",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid when",
+			path:           "/api/venues/the-orange-peel/shows?when=someday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid pagination",
+			path:           "/api/venues/the-orange-peel/shows?per_page=150",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown venue",
+			path:           "/api/venues/non-existent-venue/shows",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetVenueShows_PastAndUpcoming(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, _, err := tdb.GetVenueBySlug(ctx, "the-orange-peel")
+	if err != nil {
+		t.Skipf("the-orange-peel venue not found: %v", err)
+	}
+
+	pastID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, -7), "Test Venue Past Show")
+	if err != nil {
+		t.Fatalf("failed to insert past show: %v", err)
+	}
+	upcomingID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Test Venue Upcoming Show")
+	if err != nil {
+		t.Fatalf("failed to insert upcoming show: %v", err)
+	}
+
+	router := setupVenuesTestRouter(tdb)
+
+	fetchIDs := func(when string) []int32 {
+		req := httptest.NewRequest(http.MethodGet, "/api/venues/the-orange-peel/shows?per_page=100&when="+when, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp struct {
+			Data []struct {
+				ID int32 `json:"id"`
+			} `json:"data"`
+			Meta struct {
+				Total int `json:"total"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		ids := make([]int32, len(resp.Data))
+		for i, s := range resp.Data {
+			ids[i] = s.ID
+		}
+		return ids
+	}
+
+	contains := func(ids []int32, id int32) bool {
+		for _, v := range ids {
+			if v == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	past := fetchIDs("past")
+	if !contains(past, pastID) {
+		t.Errorf("expected past show %d in past results", pastID)
+	}
+	if contains(past, upcomingID) {
+		t.Errorf("upcoming show %d should not be in past results", upcomingID)
+	}
+
+	upcoming := fetchIDs("upcoming")
+	if !contains(upcoming, upcomingID) {
+		t.Errorf("expected upcoming show %d in upcoming results", upcomingID)
+	}
+	if contains(upcoming, pastID) {
+		t.Errorf("past show %d should not be in upcoming results", pastID)
+	}
+}
+
+func TestGetVenue_Stats(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, _, err := tdb.GetVenueBySlug(ctx, "the-grey-eagle")
+	if err != nil {
+		t.Skipf("the-grey-eagle venue not found: %v", err)
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Venue Stats", "test-band-venue-stats")
+	if err != nil {
+		t.Fatalf("failed to insert test band: %v", err)
+	}
+
+	for i, offset := range []int{-30, -14, 14} {
+		showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, offset), fmt.Sprintf("Test Venue Stats Show %d", i))
+		if err != nil {
+			t.Fatalf("failed to insert test show: %v", err)
+		}
+		if err := tdb.LinkBandToShow(ctx, showID, bandID, true, 1); err != nil {
+			t.Fatalf("failed to link band to show: %v", err)
+		}
+	}
+
+	router := setupVenuesTestRouter(tdb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/venues/the-grey-eagle", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Stats struct {
+				TotalShows    int64 `json:"total_shows"`
+				PastShows     int64 `json:"past_shows"`
+				ShowsPerMonth []struct {
+					Month     string `json:"month"`
+					ShowCount int64  `json:"show_count"`
+				} `json:"shows_per_month"`
+				TopBands []struct {
+					ID        int32 `json:"id"`
+					ShowCount int64 `json:"show_count"`
+				} `json:"top_bands"`
+				GenreMix []interface{} `json:"genre_mix"`
+			} `json:"stats"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	stats := resp.Data.Stats
+	if stats.TotalShows < 3 {
+		t.Errorf("expected at least 3 total shows, got %d", stats.TotalShows)
+	}
+	if stats.PastShows < 2 {
+		t.Errorf("expected at least 2 past shows, got %d", stats.PastShows)
+	}
+	if len(stats.ShowsPerMonth) == 0 {
+		t.Error("expected shows_per_month to be populated")
+	}
+	if stats.GenreMix == nil {
+		t.Error("genre_mix should not be nil")
+	}
+
+	for _, b := range stats.TopBands {
+		if b.ID == bandID && b.ShowCount != 3 {
+			t.Errorf("expected test band show_count 3, got %d", b.ShowCount)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+// webhookDeliveryStatuses are the accepted values of the status filter.
+var webhookDeliveryStatuses = []string{"pending", "succeeded", "failed"}
+
+// ListWebhookSubscriptions handles GET /api/admin/webhooks.
+// Lists all subscriptions with their pending and failed delivery counts.
+func (h *Handler) ListWebhookSubscriptions(c *gin.Context) {
+	rows, err := h.queries.ListWebhookSubscriptions(c.Request.Context())
+	if err != nil {
+		logger(c).Error("failed to list webhook subscriptions", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	subscriptions := make([]WebhookSubscriptionItem, len(rows))
+	for i, r := range rows {
+		subscriptions[i] = WebhookSubscriptionItem{
+			ID:              r.ID,
+			URL:             r.Url,
+			Description:     r.Description,
+			Events:          r.Events,
+			Active:          r.Active,
+			PendingCount:    &r.PendingCount,
+			FailedCount:     &r.FailedCount,
+			LastDeliveredAt: formatTimestampPtr(r.LastDeliveredAt),
+			CreatedAt:       formatTimestamp(r.CreatedAt),
+			UpdatedAt:       formatTimestamp(r.UpdatedAt),
+		}
+	}
+
+	respondJSON(c, http.StatusOK, subscriptions)
+}
+
+// CreateWebhookSubscription handles POST /api/admin/webhooks.
+// Subscribes a URL to event types and returns the signing secret once.
+func (h *Handler) CreateWebhookSubscription(c *gin.Context) {
+	var req WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if !validChannelURL(req.URL, "http", "https") {
+		respondValidationError(c, "Invalid webhook URL", map[string]any{
+			"url": "must be an http or https URL",
+		})
+		return
+	}
+	events, ok := parseWebhookEvents(c, req.Events)
+	if !ok {
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		logger(c).Error("failed to generate webhook secret", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	row, err := h.queries.CreateWebhookSubscription(c.Request.Context(), db.CreateWebhookSubscriptionParams{
+		Url:         req.URL,
+		Description: req.Description,
+		Events:      events,
+		Secret:      secret,
+	})
+	if err != nil {
+		logger(c).Error("failed to create webhook subscription", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, WebhookSubscriptionCreated{
+		WebhookSubscriptionItem: WebhookSubscriptionItem{
+			ID:          row.ID,
+			URL:         row.Url,
+			Description: row.Description,
+			Events:      row.Events,
+			Active:      row.Active,
+			CreatedAt:   formatTimestamp(row.CreatedAt),
+			UpdatedAt:   formatTimestamp(row.UpdatedAt),
+		},
+		Secret: secret,
+	})
+}
+
+// UpdateWebhookSubscription handles PATCH /api/admin/webhooks/:id.
+// Changes the URL, events, description or active flag. Pausing a
+// subscription holds its pending deliveries until it is reactivated.
+func (h *Handler) UpdateWebhookSubscription(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	var req WebhookSubscriptionUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "Invalid request body", map[string]any{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	params := db.UpdateWebhookSubscriptionParams{
+		ID:          int32(id),
+		Url:         req.URL,
+		Description: req.Description,
+		Active:      req.Active,
+	}
+	if req.URL != nil && !validChannelURL(*req.URL, "http", "https") {
+		respondValidationError(c, "Invalid webhook URL", map[string]any{
+			"url": "must be an http or https URL",
+		})
+		return
+	}
+	if req.Events != nil {
+		events, ok := parseWebhookEvents(c, req.Events)
+		if !ok {
+			return
+		}
+		params.Events = events
+	}
+
+	row, err := h.queries.UpdateWebhookSubscription(c.Request.Context(), params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, "Webhook subscription")
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to update webhook subscription", "id", id, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, WebhookSubscriptionItem{
+		ID:          row.ID,
+		URL:         row.Url,
+		Description: row.Description,
+		Events:      row.Events,
+		Active:      row.Active,
+		CreatedAt:   formatTimestamp(row.CreatedAt),
+		UpdatedAt:   formatTimestamp(row.UpdatedAt),
+	})
+}
+
+// DeleteWebhookSubscription handles DELETE /api/admin/webhooks/:id.
+// Removes the subscription and its delivery log.
+func (h *Handler) DeleteWebhookSubscription(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	count, err := h.queries.DeleteWebhookSubscription(c.Request.Context(), int32(id))
+	if err != nil {
+		logger(c).Error("failed to delete webhook subscription", "id", id, "error", err)
+		respondInternalError(c)
+		return
+	}
+	if count == 0 {
+		respondNotFound(c, "Webhook subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /api/admin/webhook-deliveries.
+// Lists the delivery log newest first, optionally filtered by subscription_id
+// and status, with pagination.
+func (h *Handler) ListWebhookDeliveries(c *gin.Context) {
+	page, perPage, err := parsePagination(c)
+	if err != nil {
+		if pe, ok := err.(*paramError); ok {
+			respondInvalidParam(c, pe.param, pe.message)
+			return
+		}
+		respondInternalError(c)
+		return
+	}
+
+	params := db.ListWebhookDeliveriesParams{
+		Limit:  int32(perPage),
+		Offset: int32(calculateOffset(page, perPage)),
+	}
+	if s := c.Query("subscription_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			respondInvalidParam(c, "subscription_id", "must be a valid integer")
+			return
+		}
+		subscriptionID := int32(id)
+		params.SubscriptionID = &subscriptionID
+	}
+	if s := c.Query("status"); s != "" {
+		if !slices.Contains(webhookDeliveryStatuses, s) {
+			respondInvalidParam(c, "status", "must be one of: "+strings.Join(webhookDeliveryStatuses, ", "))
+			return
+		}
+		params.Status = &s
+	}
+
+	rows, err := h.queries.ListWebhookDeliveries(c.Request.Context(), params)
+	if err != nil {
+		logger(c).Error("failed to list webhook deliveries", "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	deliveries, total := convertWebhookDeliveriesToItems(rows)
+
+	respondJSONWithMeta(c, http.StatusOK, deliveries, &Meta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: calculateTotalPages(total, perPage),
+	})
+}
+
+// ReplayWebhookDelivery handles POST /api/admin/webhook-deliveries/:id/replay.
+// Queues the delivery's event to its subscription again, whatever the
+// outcome of the original.
+func (h *Handler) ReplayWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondInvalidParam(c, "id", "must be a valid integer")
+		return
+	}
+
+	row, err := h.queries.ReplayWebhookDelivery(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondNotFound(c, "Webhook delivery")
+		return
+	}
+	if err != nil {
+		logger(c).Error("failed to replay webhook delivery", "id", id, "error", err)
+		respondInternalError(c)
+		return
+	}
+
+	replayOf := id
+	respondJSON(c, http.StatusAccepted, WebhookDeliveryItem{
+		ID:             row.ID,
+		SubscriptionID: row.SubscriptionID,
+		EventID:        row.EventID,
+		Status:         row.Status,
+		AttemptCount:   row.AttemptCount,
+		NextAttemptAt:  formatTimestampPtr(row.NextAttemptAt),
+		ReplayOf:       &replayOf,
+		CreatedAt:      formatTimestamp(row.CreatedAt),
+	})
+}
+
+// parseWebhookEvents validates and de-duplicates subscription event types,
+// responding with 400 and returning false if any is unknown.
+func parseWebhookEvents(c *gin.Context, events []string) ([]string, bool) {
+	var result []string
+	for _, e := range events {
+		if !webhook.ValidEventType(e) {
+			respondValidationError(c, "Invalid event type", map[string]any{
+				"events": "must be one or more of: " + strings.Join(webhook.EventTypes, ", "),
+			})
+			return nil, false
+		}
+		if !slices.Contains(result, e) {
+			result = append(result, e)
+		}
+	}
+	if len(result) == 0 {
+		respondValidationError(c, "Invalid event type", map[string]any{
+			"events": "at least one event type is required",
+		})
+		return nil, false
+	}
+	return result, true
+}
@@ -0,0 +1,202 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	router := setupAdminTestRouter(tdb)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing url", `{"events":["show.created"]}`, http.StatusBadRequest},
+		{"invalid url", `{"url":"not a url","events":["show.created"]}`, http.StatusBadRequest},
+		{"no events", `{"url":"https://partner.example.com/hook","events":[]}`, http.StatusBadRequest},
+		{"unknown event", `{"url":"https://partner.example.com/hook","events":["show.deleted"]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(router, http.MethodPost, "/api/admin/webhooks", tt.body, testAdminToken)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := doJSON(router, http.MethodPost, "/api/admin/webhooks",
+		`{"url":"https://partner.example.com/hook","events":["show.created","show.created","band.created"],"description":"[TEST] partner"}`,
+		testAdminToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var created struct {
+		Data struct {
+			ID     int32    `json:"id"`
+			Events []string `json:"events"`
+			Active bool     `json:"active"`
+			Secret string   `json:"secret"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !strings.HasPrefix(created.Data.Secret, "whsec_") || !created.Data.Active {
+		t.Errorf("unexpected subscription %+v", created.Data)
+	}
+	if len(created.Data.Events) != 2 {
+		t.Errorf("expected duplicate events to be dropped, got %v", created.Data.Events)
+	}
+
+	path := fmt.Sprintf("/api/admin/webhooks/%d", created.Data.ID)
+	w = doJSON(router, http.MethodPatch, path, `{"active":false,"events":["show.cancelled"]}`, testAdminToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, http.MethodGet, "/api/admin/webhooks", "", testAdminToken)
+	var list struct {
+		Data []struct {
+			ID     int32    `json:"id"`
+			URL    string   `json:"url"`
+			Events []string `json:"events"`
+			Active bool     `json:"active"`
+			Secret *string  `json:"secret"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	found := false
+	for _, s := range list.Data {
+		if s.ID != created.Data.ID {
+			continue
+		}
+		found = true
+		if s.Active || len(s.Events) != 1 || s.Events[0] != webhook.EventShowCancelled || s.URL != "https://partner.example.com/hook" {
+			t.Errorf("update not applied: %+v", s)
+		}
+		if s.Secret != nil {
+			t.Error("secret must only be returned on create")
+		}
+	}
+	if !found {
+		t.Fatal("subscription missing from list")
+	}
+
+	if w := doJSON(router, http.MethodDelete, path, "", testAdminToken); w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := doJSON(router, http.MethodDelete, path, "", testAdminToken); w.Code != http.StatusNotFound {
+		t.Errorf("delete again: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := doJSON(router, http.MethodPatch, path, `{"active":true}`, testAdminToken); w.Code != http.StatusNotFound {
+		t.Errorf("update deleted: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestWebhookDeliveries_Replay(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	router := setupAdminTestRouter(tdb)
+
+	w := doJSON(router, http.MethodPost, "/api/admin/webhooks",
+		`{"url":"https://partner.example.com/replay","events":["show.created"],"description":"[TEST] replay"}`,
+		testAdminToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created struct {
+		Data struct {
+			ID int32 `json:"id"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 5), "Replay Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	if _, err := webhook.ShowEvent(ctx, tdb.Queries, webhook.EventShowCreated, showID); err != nil {
+		t.Fatalf("ShowEvent failed: %v", err)
+	}
+
+	logPath := fmt.Sprintf("/api/admin/webhook-deliveries?subscription_id=%d", created.Data.ID)
+	w = doJSON(router, http.MethodGet, logPath, "", testAdminToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var deliveries struct {
+		Data []struct {
+			ID        int64  `json:"id"`
+			EventType string `json:"event_type"`
+			Status    string `json:"status"`
+			ReplayOf  *int64 `json:"replay_of"`
+		} `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if deliveries.Meta.Total != 1 || deliveries.Data[0].EventType != webhook.EventShowCreated || deliveries.Data[0].Status != "pending" {
+		t.Fatalf("unexpected delivery log %+v", deliveries)
+	}
+	original := deliveries.Data[0].ID
+
+	w = doJSON(router, http.MethodPost, fmt.Sprintf("/api/admin/webhook-deliveries/%d/replay", original), "", testAdminToken)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("replay: expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	w = doJSON(router, http.MethodGet, logPath, "", testAdminToken)
+	deliveries.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if deliveries.Meta.Total != 2 || deliveries.Data[0].ReplayOf == nil || *deliveries.Data[0].ReplayOf != original {
+		t.Errorf("expected newest delivery to replay %d, got %+v", original, deliveries.Data)
+	}
+
+	for path, status := range map[string]int{
+		"/api/admin/webhook-deliveries/999999999/replay": http.StatusNotFound,
+		"/api/admin/webhook-deliveries/abc/replay":       http.StatusBadRequest,
+	} {
+		if w := doJSON(router, http.MethodPost, path, "", testAdminToken); w.Code != status {
+			t.Errorf("POST %s: expected status %d, got %d", path, status, w.Code)
+		}
+	}
+	if w := doJSON(router, http.MethodGet, "/api/admin/webhook-deliveries?status=bogus", "", testAdminToken); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status filter: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
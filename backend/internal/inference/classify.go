@@ -0,0 +1,221 @@
+// Package inference proposes genres for bands that have none.
+//
+// Bands created from scraped lineups or band submissions start without
+// genres, which hides them from genre filters and similar-band lists. The
+// classifier combines four signals into a confidence per genre: source
+// category tags (e.g. Live Music Asheville event categories), the genre mix
+// of the venues the band plays, the genres of bands it shares bills with and
+// genre keywords in its bio. Inferred genres are written to band_genres with
+// their confidence and strongest signal, and stay unconfirmed until an admin
+// accepts them.
+package inference
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Signal sources, stored in band_genres.source for inferred genres.
+const (
+	SourceManual = "manual"
+	SourceTag    = "lma_tag"
+	SourceVenue  = "venue"
+	SourceCoBill = "co_bill"
+	SourceBio    = "bio"
+)
+
+// Classifier limits.
+const (
+	MinConfidence  = 0.4 // Genres scoring below this are not proposed
+	MaxSuggestions = 3   // At most this many genres are proposed per band
+)
+
+// Weights is how much a full-strength signal contributes to a genre's confidence.
+// Venue and co-bill signals are scaled by the share of bands carrying the genre.
+type Weights struct {
+	Tag    float64
+	Venue  float64
+	CoBill float64
+	Bio    float64
+}
+
+// DefaultWeights favours explicit source tags, then the company a band keeps.
+// Venue tendencies are weakest: most venues book a wide mix of genres.
+var DefaultWeights = Weights{
+	Tag:    0.7,
+	Venue:  0.3,
+	CoBill: 0.6,
+	Bio:    0.45,
+}
+
+// Genre is a genre the classifier can propose.
+type Genre struct {
+	ID   int32
+	Name string
+	Slug string
+}
+
+// Share is the fraction (0-1) of related bands that carry a genre.
+type Share struct {
+	GenreID int32
+	Share   float64
+}
+
+// Signals is the evidence gathered for one band.
+type Signals struct {
+	Tags   []string // Source category tags, e.g. "Bluegrass/Folk"
+	Bio    string   // Band bio
+	Venue  []Share  // Genre mix of other bands at the band's venues
+	CoBill []Share  // Genre mix of bands on the same bills
+}
+
+// Suggestion is a proposed genre with its confidence and strongest signal.
+type Suggestion struct {
+	GenreID    int32
+	Confidence float64
+	Source     string
+}
+
+// genreAliases are extra keywords for genres whose name and slug miss
+// common spellings.
+var genreAliases = map[string][]string{
+	"rock":              {"rock and roll", "rock n roll", "garage rock"},
+	"indie":             {"indie rock", "indie pop"},
+	"metal":             {"heavy metal", "doom", "thrash", "sludge"},
+	"punk":              {"hardcore", "post punk"},
+	"bluegrass":         {"newgrass", "old time", "string band"},
+	"country":           {"honky tonk", "outlaw country", "western swing"},
+	"singer-songwriter": {"songwriter"},
+	"rnb":               {"r&b", "rhythm and blues"},
+	"electronic":        {"edm", "techno", "house music"},
+	"dj":                {"dj set"},
+	"hip-hop":           {"hip hop", "rap", "emcee"},
+	"jam-band":          {"jamband"},
+	"prog-rock":         {"progressive rock", "prog"},
+	"reggae":            {"ska", "dub"},
+	"latin":             {"salsa", "cumbia"},
+	"world":             {"afrobeat"},
+	"classical":         {"orchestra", "symphony", "chamber music"},
+	"cover-band":        {"tribute", "cover band"},
+	"open-mic":          {"open mic", "open jam"},
+}
+
+// Classify scores every genre against the signals and returns up to
+// MaxSuggestions genres scoring at least MinConfidence, most confident first.
+//
+// Each signal contributes an independent probability and they are combined
+// as 1 - Π(1 - p), so agreeing signals raise confidence without exceeding 1.
+func Classify(genres []Genre, s Signals, w Weights) []Suggestion {
+	tags := make([]string, 0, len(s.Tags))
+	for _, tag := range s.Tags {
+		tags = append(tags, normalize(tag))
+	}
+	bio := normalize(s.Bio)
+
+	venue := shareMap(s.Venue)
+	coBill := shareMap(s.CoBill)
+
+	var suggestions []Suggestion
+	for _, g := range genres {
+		keywords := genreKeywords(g)
+
+		evidence := map[string]float64{
+			SourceVenue:  w.Venue * venue[g.ID],
+			SourceCoBill: w.CoBill * coBill[g.ID],
+		}
+		if slices.ContainsFunc(tags, func(tag string) bool { return containsAny(tag, keywords) }) {
+			evidence[SourceTag] = w.Tag
+		}
+		if containsAny(bio, keywords) {
+			evidence[SourceBio] = w.Bio
+		}
+
+		miss := 1.0
+		source, strongest := "", 0.0
+		for _, src := range []string{SourceTag, SourceCoBill, SourceBio, SourceVenue} {
+			p := clamp(evidence[src])
+			miss *= 1 - p
+			if p > strongest {
+				source, strongest = src, p
+			}
+		}
+
+		confidence := math.Round((1-miss)*1000) / 1000
+		if source == "" || confidence < MinConfidence {
+			continue
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			GenreID:    g.ID,
+			Confidence: confidence,
+			Source:     source,
+		})
+	}
+
+	slices.SortFunc(suggestions, func(a, b Suggestion) int {
+		if a.Confidence != b.Confidence {
+			if a.Confidence > b.Confidence {
+				return -1
+			}
+			return 1
+		}
+		return int(a.GenreID - b.GenreID)
+	})
+
+	if len(suggestions) > MaxSuggestions {
+		suggestions = suggestions[:MaxSuggestions]
+	}
+	return suggestions
+}
+
+// genreKeywords returns the normalized name, slug and aliases of a genre.
+func genreKeywords(g Genre) []string {
+	keywords := []string{normalize(g.Name), normalize(g.Slug)}
+	for _, alias := range genreAliases[g.Slug] {
+		keywords = append(keywords, normalize(alias))
+	}
+	return keywords
+}
+
+// normalize lowercases text and reduces it to space-separated words padded
+// with spaces, so keywords can be matched on word boundaries.
+// "&" is kept as part of words so "R&B" survives.
+func normalize(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return " " + strings.Join(words, " ") + " "
+}
+
+// containsAny reports whether normalized text contains any normalized keyword
+// as whole words.
+func containsAny(text string, keywords []string) bool {
+	if text == "" {
+		return false
+	}
+	for _, kw := range keywords {
+		if kw != "" && strings.Contains(text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// shareMap indexes shares by genre ID.
+func shareMap(shares []Share) map[int32]float64 {
+	m := make(map[int32]float64, len(shares))
+	for _, s := range shares {
+		m[s.GenreID] = s.Share
+	}
+	return m
+}
+
+// clamp limits a probability to [0, 1].
+func clamp(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
@@ -0,0 +1,111 @@
+package inference
+
+import "testing"
+
+var testGenres = []Genre{
+	{ID: 1, Name: "Rock", Slug: "rock"},
+	{ID: 2, Name: "Bluegrass", Slug: "bluegrass"},
+	{ID: 3, Name: "Folk", Slug: "folk"},
+	{ID: 4, Name: "R&B", Slug: "rnb"},
+	{ID: 5, Name: "Hip-Hop", Slug: "hip-hop"},
+	{ID: 6, Name: "Jazz", Slug: "jazz"},
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals Signals
+		want    []Suggestion
+	}{
+		{
+			name:    "no signals",
+			signals: Signals{},
+			want:    nil,
+		},
+		{
+			name:    "category tags split on punctuation",
+			signals: Signals{Tags: []string{"Bluegrass/Folk"}},
+			want: []Suggestion{
+				{GenreID: 2, Confidence: 0.7, Source: SourceTag},
+				{GenreID: 3, Confidence: 0.7, Source: SourceTag},
+			},
+		},
+		{
+			name:    "bio keywords match whole words and aliases",
+			signals: Signals{Bio: "Asheville's finest rhythm and blues revue. Not a rocket science act."},
+			want: []Suggestion{
+				{GenreID: 4, Confidence: 0.45, Source: SourceBio},
+			},
+		},
+		{
+			name:    "R&B survives normalization",
+			signals: Signals{Tags: []string{"Soul / R&B"}},
+			want: []Suggestion{
+				{GenreID: 4, Confidence: 0.7, Source: SourceTag},
+			},
+		},
+		{
+			name: "agreeing signals combine",
+			signals: Signals{
+				Bio:    "Hip hop trio",
+				CoBill: []Share{{GenreID: 5, Share: 1}},
+			},
+			want: []Suggestion{
+				// 1 - (1-0.6)(1-0.45)
+				{GenreID: 5, Confidence: 0.78, Source: SourceCoBill},
+			},
+		},
+		{
+			name: "weak venue tendency alone is not proposed",
+			signals: Signals{
+				Venue: []Share{{GenreID: 6, Share: 1}},
+			},
+			want: nil,
+		},
+		{
+			name: "at most MaxSuggestions, most confident first",
+			signals: Signals{
+				Tags:   []string{"Rock, Folk, Jazz, Hip-Hop"},
+				CoBill: []Share{{GenreID: 6, Share: 0.5}},
+			},
+			want: []Suggestion{
+				{GenreID: 6, Confidence: 0.79, Source: SourceTag},
+				{GenreID: 1, Confidence: 0.7, Source: SourceTag},
+				{GenreID: 3, Confidence: 0.7, Source: SourceTag},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(testGenres, tt.signals, DefaultWeights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d suggestions %+v, want %d %+v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("suggestion %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"  --  ", ""},
+		{"Hip-Hop", " hip hop "},
+		{"Soul/R&B", " soul r&b "},
+		{"Rock 'n' Roll!", " rock n roll "},
+	}
+
+	for _, tt := range tests {
+		if got := normalize(tt.in); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package inference
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// InferBand gathers signals for a band, classifies it and writes the
+// suggestions to band_genres, returning those written. Bands that already
+// have manual or confirmed genres are left alone, and genres an admin
+// rejected for the band are dropped. tags are the source category tags of the event the
+// band was found in, if any.
+func InferBand(ctx context.Context, q *db.Queries, bandID int32, tags []string) ([]Suggestion, error) {
+	band, err := q.GetBandInferenceInput(ctx, bandID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get band %d: %w", bandID, err)
+	}
+	if band.TrustedGenreCount > 0 {
+		return nil, nil
+	}
+
+	rows, err := q.ListInferenceGenres(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genres: %w", err)
+	}
+	genres := make([]Genre, 0, len(rows))
+	for _, r := range rows {
+		genres = append(genres, Genre{ID: r.ID, Name: r.Name, Slug: r.Slug})
+	}
+
+	signals := Signals{Tags: tags}
+	if band.Bio != nil {
+		signals.Bio = *band.Bio
+	}
+
+	coBilled, err := q.GetCoBilledGenreShares(ctx, bandID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get co-billed genres: %w", err)
+	}
+	for _, r := range coBilled {
+		signals.CoBill = append(signals.CoBill, Share{GenreID: r.GenreID, Share: r.Share})
+	}
+
+	venue, err := q.GetVenueGenreShares(ctx, bandID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue genres: %w", err)
+	}
+	for _, r := range venue {
+		signals.Venue = append(signals.Venue, Share{GenreID: r.GenreID, Share: r.Share})
+	}
+
+	var suggestions []Suggestion
+	for _, s := range Classify(genres, signals, DefaultWeights) {
+		written, err := q.UpsertInferredBandGenre(ctx, db.UpsertInferredBandGenreParams{
+			BandID:     bandID,
+			GenreID:    s.GenreID,
+			Source:     s.Source,
+			Confidence: confidenceToNumeric(s.Confidence),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write inferred genre: %w", err)
+		}
+		if written > 0 {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	if len(suggestions) > 0 {
+		if err := cache.Invalidate(ctx, q, cache.TagBands); err != nil {
+			return nil, err
+		}
+		slog.Info("inferred band genres", "band_id", bandID, "count", len(suggestions))
+	}
+
+	return suggestions, nil
+}
+
+// InferMissing runs InferBand for every band without genres and returns how
+// many bands received suggestions. Failures are logged and skipped.
+func InferMissing(ctx context.Context, q *db.Queries) (int, error) {
+	ids, err := q.ListBandsWithoutGenres(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list bands without genres: %w", err)
+	}
+
+	count := 0
+	for _, id := range ids {
+		suggestions, err := InferBand(ctx, q, id, nil)
+		if err != nil {
+			slog.Error("failed to infer band genres", "band_id", id, "error", err)
+			continue
+		}
+		if len(suggestions) > 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// confidenceToNumeric converts a confidence to pgtype.Numeric.
+func confidenceToNumeric(f float64) pgtype.Numeric {
+	var result pgtype.Numeric
+	if err := result.Scan(strconv.FormatFloat(f, 'f', 3, 64)); err != nil {
+		return pgtype.Numeric{}
+	}
+	return result
+}
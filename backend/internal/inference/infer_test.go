@@ -0,0 +1,183 @@
+package inference_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/inference"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+func TestInferBand(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	genreID := func(slug string) int32 {
+		var id int32
+		if err := tdb.Pool.QueryRow(ctx, `SELECT id FROM genres WHERE slug = $1`, slug).Scan(&id); err != nil {
+			t.Skipf("genre %s not seeded: %v", slug, err)
+		}
+		return id
+	}
+	jazz, bluegrass, folk := genreID("jazz"), genreID("bluegrass"), genreID("folk")
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Inference Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+
+	known, err := tdb.InsertTestBand(ctx, "Test Band Inference Known", "test-band-inference-known")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+	if err := tdb.AddGenreToBand(ctx, known, jazz); err != nil {
+		t.Fatalf("failed to add genre: %v", err)
+	}
+
+	fresh, err := tdb.InsertTestBand(ctx, "Test Band Inference New", "test-band-inference-new")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+	if _, err := tdb.Pool.Exec(ctx, `UPDATE bands SET bio = 'A bluegrass quartet' WHERE id = $1`, fresh); err != nil {
+		t.Fatalf("failed to set bio: %v", err)
+	}
+
+	tdb.LinkBandToShow(ctx, showID, known, true, 2)
+	tdb.LinkBandToShow(ctx, showID, fresh, false, 1)
+
+	suggestions, err := inference.InferBand(ctx, tdb.Queries, fresh, []string{"Folk"})
+	if err != nil {
+		t.Fatalf("InferBand failed: %v", err)
+	}
+
+	want := map[int32]string{
+		jazz:      inference.SourceCoBill,
+		bluegrass: inference.SourceBio,
+		folk:      inference.SourceTag,
+	}
+	if len(suggestions) != len(want) {
+		t.Fatalf("expected %d suggestions, got %+v", len(want), suggestions)
+	}
+
+	rows, err := tdb.Pool.Query(ctx, `
+		SELECT genre_id, source, confidence::float8, confirmed_at IS NOT NULL
+		FROM band_genres WHERE band_id = $1
+	`, fresh)
+	if err != nil {
+		t.Fatalf("failed to query band genres: %v", err)
+	}
+	defer rows.Close()
+
+	stored := 0
+	for rows.Next() {
+		var genreID int32
+		var source string
+		var confidence float64
+		var confirmed bool
+		if err := rows.Scan(&genreID, &source, &confidence, &confirmed); err != nil {
+			t.Fatalf("failed to scan band genre: %v", err)
+		}
+		stored++
+
+		if want[genreID] != source {
+			t.Errorf("genre %d: expected source %q, got %q", genreID, want[genreID], source)
+		}
+		if confidence < inference.MinConfidence || confidence > 1 {
+			t.Errorf("genre %d: confidence %f out of range", genreID, confidence)
+		}
+		if confirmed {
+			t.Errorf("genre %d: inferred genre should start unconfirmed", genreID)
+		}
+	}
+	if stored != len(want) {
+		t.Errorf("expected %d stored genres, got %d", len(want), stored)
+	}
+
+	// Bands with curated genres are left alone
+	suggestions, err = inference.InferBand(ctx, tdb.Queries, known, []string{"Rock"})
+	if err != nil {
+		t.Fatalf("InferBand failed: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("expected no suggestions for band with manual genres, got %+v", suggestions)
+	}
+}
+
+func TestInferBand_Rejected(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	var bluegrass int32
+	if err := tdb.Pool.QueryRow(ctx, `SELECT id FROM genres WHERE slug = 'bluegrass'`).Scan(&bluegrass); err != nil {
+		t.Skipf("genre bluegrass not seeded: %v", err)
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Inference Rejected", "test-band-inference-rejected")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+	if _, err := tdb.Pool.Exec(ctx, `UPDATE bands SET bio = 'A bluegrass quartet' WHERE id = $1`, bandID); err != nil {
+		t.Fatalf("failed to set bio: %v", err)
+	}
+
+	suggestions, err := inference.InferBand(ctx, tdb.Queries, bandID, nil)
+	if err != nil {
+		t.Fatalf("InferBand failed: %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].GenreID != bluegrass {
+		t.Fatalf("expected a bluegrass suggestion, got %+v", suggestions)
+	}
+
+	rejectedBy := "admin"
+	count, err := tdb.Queries.RejectBandGenre(ctx, db.RejectBandGenreParams{
+		BandID:     bandID,
+		GenreID:    bluegrass,
+		RejectedBy: &rejectedBy,
+	})
+	if err != nil || count != 1 {
+		t.Fatalf("expected to reject 1 genre, got %d (err %v)", count, err)
+	}
+
+	// Re-inferring the band, directly or through the backfill, doesn't bring it back
+	suggestions, err = inference.InferBand(ctx, tdb.Queries, bandID, nil)
+	if err != nil {
+		t.Fatalf("InferBand failed: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("expected rejected genre not to be proposed again, got %+v", suggestions)
+	}
+
+	ids, err := tdb.Queries.ListBandsWithoutGenres(ctx)
+	if err != nil {
+		t.Fatalf("ListBandsWithoutGenres failed: %v", err)
+	}
+	for _, id := range ids {
+		if id == bandID {
+			t.Error("expected band with rejected suggestions to be skipped by the backfill")
+		}
+	}
+	if _, err := inference.InferMissing(ctx, tdb.Queries); err != nil {
+		t.Fatalf("InferMissing failed: %v", err)
+	}
+
+	var stored int
+	tdb.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM band_genres WHERE band_id = $1`, bandID).Scan(&stored)
+	if stored != 0 {
+		t.Errorf("expected no band genres after rejection, got %d", stored)
+	}
+}
@@ -0,0 +1,78 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+var (
+	slugInvalidChars = regexp.MustCompile(`[^a-z0-9-]`)
+	slugDashes       = regexp.MustCompile(`-+`)
+)
+
+// Slugify creates a URL-friendly slug from a string.
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, " ", "-")
+	s = slugInvalidChars.ReplaceAllString(s, "")
+	s = slugDashes.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+
+	if s == "" {
+		return "band"
+	}
+	return s
+}
+
+// FindOrCreateBand returns the band matching name (case-insensitive), creating
+// it if needed. New bands get a unique slug, suffixed -2, -3... on collision,
+// and a band.created webhook event.
+func FindOrCreateBand(ctx context.Context, queries *db.Queries, name string) (id int32, created bool, err error) {
+	name = strings.TrimSpace(name)
+
+	existing, err := queries.GetBandByName(ctx, name)
+	if err == nil {
+		return existing.ID, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, fmt.Errorf("failed to look up band %q: %w", name, err)
+	}
+
+	base := Slugify(name)
+	slug := base
+	for n := 2; ; n++ {
+		exists, err := queries.BandExists(ctx, slug)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to check band slug %q: %w", slug, err)
+		}
+		if !exists {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	band, err := queries.CreateBand(ctx, db.CreateBandParams{
+		Name: name,
+		Slug: slug,
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create band %q: %w", name, err)
+	}
+
+	if _, err := webhook.BandCreated(ctx, queries, band.ID); err != nil {
+		return 0, false, err
+	}
+	if err := cache.Invalidate(ctx, queries, cache.TagBands); err != nil {
+		return 0, false, err
+	}
+
+	return band.ID, true, nil
+}
@@ -0,0 +1,709 @@
+// Package ingest writes scraped source events into the shows catalog.
+//
+// It is the write path shared by all scraper sources: Run scrapes each Source
+// and ingests its Batch. Shows are upserted by their source identity (source
+// name + external event ID), lineups are linked to existing or new bands, and
+// cancellations, postponements and reschedules are detected from title
+// markers and from events vanishing between scrapes.
+// Every change is recorded in show_revisions with the source name as actor.
+// Bands created from a lineup get inferred genres, using the event's category
+// tags as one of the signals. Similar-band scores are refreshed once the
+// catalog has changed: after Ingest, or after all sources in Run. Followers and savers of affected shows are
+// notified, webhook events queued, live stream events published and cached
+// responses invalidated, in the same transaction as the change. With
+// WithMetrics, each batch's duration, event count and outcomes are recorded
+// per source.
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/inference"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/revision"
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/stream"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+// Event is a single normalized event from a scraper source.
+type Event struct {
+	ExternalID  string          // Stable event ID within the source
+	VenueID     int32           // Resolved venue
+	Title       string          // Raw title, may contain CANCELLED/POSTPONED markers
+	Description *string         // Optional event description
+	ImageURL    *string         // Optional poster/image URL
+	Date        time.Time       // Show start time
+	PriceMin    *float64        // Optional minimum ticket price
+	PriceMax    *float64        // Optional maximum ticket price
+	TicketURL   *string         // Optional ticket link
+	Bands       []string        // Lineup in billing order; the first band headlines
+	Categories  []string        // Source category tags, used to infer genres of new bands
+	Raw         json.RawMessage // Original source payload, stored in shows.scraped_data
+}
+
+// Source scrapes one site into a Batch.
+type Source interface {
+	// Name identifies the source; it is the shows' external_source.
+	Name() string
+	Scrape(ctx context.Context) (Batch, error)
+}
+
+// Batch is the result of scraping one source.
+type Batch struct {
+	Source string
+	Events []Event
+
+	// From and To describe the date window the scrape fully covered.
+	// Scheduled shows from this source inside the window that are missing
+	// from Events are cancelled. Leave To zero for partial scrapes.
+	From time.Time
+	To   time.Time
+}
+
+// Result summarizes what an ingestion run changed.
+type Result struct {
+	Created     int
+	Updated     int
+	Cancelled   int
+	Postponed   int
+	Rescheduled int
+	Restored    int
+	Failed      int
+}
+
+// Pipeline ingests scraped batches into the database.
+type Pipeline struct {
+	pool              *pgxpool.Pool
+	queries           *db.Queries
+	similarityWeights similarity.Weights
+	metrics           *metrics.Scraper
+}
+
+// Option configures optional Pipeline settings.
+type Option func(*Pipeline)
+
+// WithSimilarityWeights sets the weights used to refresh similar-band scores.
+func WithSimilarityWeights(w similarity.Weights) Option {
+	return func(p *Pipeline) {
+		p.similarityWeights = w
+	}
+}
+
+// WithMetrics records each batch's outcome per source (nil disables).
+func WithMetrics(m *metrics.Scraper) Option {
+	return func(p *Pipeline) {
+		p.metrics = m
+	}
+}
+
+// New creates a new Pipeline backed by the given connection pool.
+func New(pool *pgxpool.Pool, opts ...Option) *Pipeline {
+	p := &Pipeline{
+		pool:              pool,
+		queries:           db.New(pool),
+		similarityWeights: similarity.DefaultWeights,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Ingest writes a batch to the database and refreshes similar-band scores if
+// it changed anything. Vanished events are cancelled first so that new events
+// in the same batch can be linked to them as reschedules. Individual event
+// failures are logged and counted rather than aborting the run.
+func (p *Pipeline) Ingest(ctx context.Context, batch Batch) (Result, error) {
+	result, err := p.ingest(ctx, batch)
+	p.refreshSimilarity(ctx, result)
+	return result, err
+}
+
+// ingest writes a batch to the database without refreshing similarity.
+func (p *Pipeline) ingest(ctx context.Context, batch Batch) (Result, error) {
+	var result Result
+	start := time.Now()
+
+	if batch.Source == "" {
+		return result, errors.New("batch source is required")
+	}
+
+	cancelled, err := p.cancelVanished(ctx, batch)
+	if err != nil {
+		p.observe(batch, result, time.Since(start), err)
+		return result, err
+	}
+	result.Cancelled += cancelled
+
+	for _, event := range batch.Events {
+		if err := p.ingestEvent(ctx, batch.Source, event, &result); err != nil {
+			slog.Error("failed to ingest event",
+				"source", batch.Source,
+				"external_id", event.ExternalID,
+				"error", err,
+			)
+			result.Failed++
+		}
+	}
+
+	slog.Info("ingested batch",
+		"source", batch.Source,
+		"events", len(batch.Events),
+		"created", result.Created,
+		"updated", result.Updated,
+		"cancelled", result.Cancelled,
+		"postponed", result.Postponed,
+		"rescheduled", result.Rescheduled,
+		"restored", result.Restored,
+		"failed", result.Failed,
+	)
+
+	p.observe(batch, result, time.Since(start), nil)
+	return result, nil
+}
+
+// Run scrapes and ingests each source in turn. A source that fails to
+// scrape is logged, recorded in the metrics and skipped without touching its
+// shows, so one broken site doesn't stop the others; the returned error
+// joins every failure. Similar-band scores are refreshed once, after every
+// source has been ingested.
+func (p *Pipeline) Run(ctx context.Context, sources []Source) (Result, error) {
+	var total Result
+	var errs []error
+
+	for _, source := range sources {
+		start := time.Now()
+		batch, err := source.Scrape(ctx)
+		if err == nil && batch.Source != source.Name() {
+			err = fmt.Errorf("batch source %q does not match source name", batch.Source)
+		}
+		if err != nil {
+			slog.Error("failed to scrape source", "source", source.Name(), "error", err)
+			p.observe(Batch{Source: source.Name()}, Result{}, time.Since(start), err)
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+
+		result, err := p.ingest(ctx, batch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		}
+		total.add(result)
+	}
+
+	p.refreshSimilarity(ctx, total)
+	return total, errors.Join(errs...)
+}
+
+// refreshSimilarity recomputes similar-band scores if result changed any
+// shows, since lineups may have changed. A failed refresh is logged and keeps
+// the previous scores.
+func (p *Pipeline) refreshSimilarity(ctx context.Context, result Result) {
+	if result.Created+result.Updated+result.Cancelled == 0 {
+		return
+	}
+	if _, err := similarity.Refresh(ctx, p.pool, p.similarityWeights); err != nil {
+		slog.Error("failed to refresh band similarity", "error", err)
+	}
+}
+
+// add accumulates another run's counts.
+func (r *Result) add(other Result) {
+	r.Created += other.Created
+	r.Updated += other.Updated
+	r.Cancelled += other.Cancelled
+	r.Postponed += other.Postponed
+	r.Rescheduled += other.Rescheduled
+	r.Restored += other.Restored
+	r.Failed += other.Failed
+}
+
+// observe records a batch run in the pipeline's metrics, if any.
+func (p *Pipeline) observe(batch Batch, result Result, duration time.Duration, err error) {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.ObserveRun(batch.Source, len(batch.Events), duration, err)
+	p.metrics.AddShows(batch.Source, "created", result.Created)
+	p.metrics.AddShows(batch.Source, "updated", result.Updated)
+	p.metrics.AddShows(batch.Source, "cancelled", result.Cancelled)
+	p.metrics.AddShows(batch.Source, "postponed", result.Postponed)
+	p.metrics.AddShows(batch.Source, "rescheduled", result.Rescheduled)
+	p.metrics.AddShows(batch.Source, "restored", result.Restored)
+	p.metrics.AddShows(batch.Source, "failed", result.Failed)
+}
+
+// cancelVanished cancels upcoming shows from the source that were not seen in the batch window.
+func (p *Pipeline) cancelVanished(ctx context.Context, batch Batch) (int, error) {
+	if batch.To.IsZero() {
+		return 0, nil
+	}
+
+	// An empty batch is far more likely a broken scrape than a venue
+	// cancelling everything; cancelling on it would wipe the listings
+	if len(batch.Events) == 0 {
+		slog.Warn("skipping vanished-show cancellation for empty batch", "source", batch.Source)
+		return 0, nil
+	}
+
+	// Past shows drop off source listings naturally; only cancel upcoming ones
+	from := batch.From
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+	if !batch.To.After(from) {
+		return 0, nil
+	}
+
+	seen := make([]string, 0, len(batch.Events))
+	for _, e := range batch.Events {
+		seen = append(seen, e.ExternalID)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := p.queries.WithTx(tx)
+
+	ids, err := q.CancelVanishedShows(ctx, db.CancelVanishedShowsParams{
+		ExternalSource: &batch.Source,
+		Date:           pgtype.Timestamptz{Time: from, Valid: true},
+		Date_2:         pgtype.Timestamptz{Time: batch.To, Valid: true},
+		Column4:        seen,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel vanished shows: %w", err)
+	}
+
+	if err := revision.RecordStatus(ctx, q, ids, StatusScheduled, StatusCancelled, scrapeActor(batch.Source)); err != nil {
+		return 0, err
+	}
+
+	if _, err := notify.StatusChanged(ctx, q, StatusCancelled, ids...); err != nil {
+		return 0, err
+	}
+	if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowCancelled, ids...); err != nil {
+		return 0, err
+	}
+	if err := stream.Publish(ctx, q, stream.EventShowCancelled, ids...); err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		if err := cache.Invalidate(ctx, q, cache.TagShows); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit cancellations: %w", err)
+	}
+
+	for _, id := range ids {
+		slog.Info("cancelled vanished show", "source", batch.Source, "show_id", id)
+	}
+
+	return len(ids), nil
+}
+
+// ingestEvent creates or updates the show for a single event in its own transaction.
+func (p *Pipeline) ingestEvent(ctx context.Context, source string, event Event, result *Result) error {
+	if event.ExternalID == "" {
+		return errors.New("event external ID is required")
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := p.queries.WithTx(tx)
+
+	detected, title := DetectStatus(event.Title)
+
+	existing, err := q.GetShowByExternalID(ctx, db.GetShowByExternalIDParams{
+		ExternalSource: &source,
+		ExternalID:     &event.ExternalID,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if err := createShow(ctx, q, source, event, title, detected, result); err != nil {
+			return err
+		}
+	case err != nil:
+		return fmt.Errorf("failed to look up show: %w", err)
+	default:
+		if err := updateShow(ctx, q, scrapeActor(source), existing, event, title, detected, result); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// createShow inserts a new scraped show and links it to any show it reschedules.
+func createShow(ctx context.Context, q *db.Queries, source string, event Event, title, detected string, result *Result) error {
+	status := StatusScheduled
+	if detected != "" {
+		status = detected
+	}
+
+	row, err := q.CreateScrapedShow(ctx, db.CreateScrapedShowParams{
+		VenueID:        event.VenueID,
+		Title:          optionalString(title),
+		Description:    event.Description,
+		ImageUrl:       event.ImageURL,
+		Date:           pgtype.Timestamptz{Time: event.Date, Valid: true},
+		PriceMin:       floatToNumeric(event.PriceMin),
+		PriceMax:       floatToNumeric(event.PriceMax),
+		TicketUrl:      event.TicketURL,
+		Status:         &status,
+		ScrapedData:    event.Raw,
+		ExternalSource: &source,
+		ExternalID:     &event.ExternalID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create show: %w", err)
+	}
+	result.Created++
+
+	actor := scrapeActor(source)
+
+	var created revision.Diff
+	if err := created.Add(revision.FieldStatus, nil, status); err != nil {
+		return err
+	}
+	if err := revision.Record(ctx, q, row.ID, actor, &created); err != nil {
+		return err
+	}
+
+	headlinerID, err := linkBands(ctx, q, row.ID, event.Bands, event.Categories)
+	if err != nil {
+		return err
+	}
+
+	if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowCreated, row.ID); err != nil {
+		return err
+	}
+	if err := stream.Publish(ctx, q, stream.EventShowCreated, row.ID); err != nil {
+		return err
+	}
+	if err := cache.Invalidate(ctx, q, cache.TagShows); err != nil {
+		return err
+	}
+
+	if status != StatusScheduled {
+		return nil
+	}
+
+	if _, err := notify.ShowAnnounced(ctx, q, row.ID); err != nil {
+		return err
+	}
+
+	if headlinerID == 0 {
+		return nil
+	}
+
+	// A new date for a recently cancelled/postponed show by the same headliner at the same venue
+	old, err := q.FindRescheduleCandidate(ctx, db.FindRescheduleCandidateParams{
+		VenueID:      event.VenueID,
+		BandID:       headlinerID,
+		ID:           row.ID,
+		EarliestDate: pgtype.Timestamptz{Time: event.Date.Add(-RescheduleWindow), Valid: true},
+		LatestDate:   pgtype.Timestamptz{Time: event.Date.Add(RescheduleWindow), Valid: true},
+		ChangedSince: pgtype.Timestamptz{Time: time.Now().Add(-RescheduleWindow), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find reschedule candidate: %w", err)
+	}
+
+	if err := q.LinkRescheduledShow(ctx, db.LinkRescheduledShowParams{
+		ID:            old.ID,
+		RescheduledTo: &row.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to link rescheduled show: %w", err)
+	}
+	result.Rescheduled++
+
+	var linked revision.Diff
+	if err := linked.Add(revision.FieldStatus, old.Status, StatusPostponed); err != nil {
+		return err
+	}
+	if err := linked.Add(revision.FieldRescheduledTo, nil, row.ID); err != nil {
+		return err
+	}
+	if err := revision.Record(ctx, q, old.ID, actor, &linked); err != nil {
+		return err
+	}
+
+	if _, err := notify.StatusChanged(ctx, q, StatusPostponed, old.ID); err != nil {
+		return err
+	}
+	if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowUpdated, old.ID); err != nil {
+		return err
+	}
+	if err := stream.Publish(ctx, q, stream.EventShowUpdated, old.ID); err != nil {
+		return err
+	}
+
+	slog.Info("linked rescheduled show", "show_id", old.ID, "rescheduled_to", row.ID)
+	return nil
+}
+
+// updateShow refreshes an existing scraped show, applies any status change
+// and records the changed fields.
+func updateShow(ctx context.Context, q *db.Queries, actor revision.Actor, existing db.GetShowByExternalIDRow, event Event, title, detected string, result *Result) error {
+	var diff revision.Diff
+	fields := []struct {
+		name     string
+		old, new any
+	}{
+		{revision.FieldTitle, existing.Title, optionalString(title)},
+		{revision.FieldDate, existing.Date.Time, event.Date},
+		{revision.FieldPriceMin, numericToFloat(existing.PriceMin), event.PriceMin},
+		{revision.FieldPriceMax, numericToFloat(existing.PriceMax), event.PriceMax},
+		{revision.FieldTicketURL, existing.TicketUrl, event.TicketURL},
+	}
+	for _, f := range fields {
+		if err := diff.Add(f.name, f.old, f.new); err != nil {
+			return err
+		}
+	}
+
+	err := q.UpdateScrapedShow(ctx, db.UpdateScrapedShowParams{
+		ID:          existing.ID,
+		Title:       optionalString(title),
+		Description: event.Description,
+		ImageUrl:    event.ImageURL,
+		Date:        pgtype.Timestamptz{Time: event.Date, Valid: true},
+		PriceMin:    floatToNumeric(event.PriceMin),
+		PriceMax:    floatToNumeric(event.PriceMax),
+		TicketUrl:   event.TicketURL,
+		ScrapedData: event.Raw,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update show: %w", err)
+	}
+	result.Updated++
+
+	lineupChanged, err := updateLineup(ctx, q, existing.ID, event, &diff)
+	if err != nil {
+		return err
+	}
+
+	current := StatusScheduled
+	if existing.Status != nil {
+		current = *existing.Status
+	}
+
+	status := nextStatus(current, detected, existing.RescheduledTo != nil)
+	if status != current {
+		if err := q.UpdateShowStatus(ctx, db.UpdateShowStatusParams{
+			ID:     existing.ID,
+			Status: &status,
+		}); err != nil {
+			return fmt.Errorf("failed to update show status: %w", err)
+		}
+		if err := diff.Add(revision.FieldStatus, current, status); err != nil {
+			return err
+		}
+
+		switch status {
+		case StatusCancelled:
+			result.Cancelled++
+		case StatusPostponed:
+			result.Postponed++
+		case StatusScheduled:
+			result.Restored++
+		}
+
+		slog.Info("show status changed", "show_id", existing.ID, "from", current, "to", status)
+
+		if _, err := notify.StatusChanged(ctx, q, status, existing.ID); err != nil {
+			return err
+		}
+	} else if lineupChanged && status == StatusScheduled {
+		// Followers of newly added bands hear about the show
+		if _, err := notify.ShowAnnounced(ctx, q, existing.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := revision.Record(ctx, q, existing.ID, actor, &diff); err != nil {
+		return err
+	}
+
+	if diff.Empty() {
+		return nil
+	}
+	if err := cache.Invalidate(ctx, q, cache.TagShows); err != nil {
+		return err
+	}
+
+	if status != current && status == StatusCancelled {
+		if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowCancelled, existing.ID); err != nil {
+			return err
+		}
+		return stream.Publish(ctx, q, stream.EventShowCancelled, existing.ID)
+	}
+	if _, err := webhook.ShowEvent(ctx, q, webhook.EventShowUpdated, existing.ID); err != nil {
+		return err
+	}
+	return stream.Publish(ctx, q, stream.EventShowUpdated, existing.ID)
+}
+
+// updateLineup relinks a show's bands when the scraped lineup differs from
+// the stored one. Names are compared case-insensitively, matching how bands
+// are looked up. An empty scraped lineup leaves the stored one untouched.
+// It reports whether the lineup was relinked.
+func updateLineup(ctx context.Context, q *db.Queries, showID int32, event Event, diff *revision.Diff) (bool, error) {
+	lineup := make([]string, 0, len(event.Bands))
+	for _, name := range event.Bands {
+		if name = strings.TrimSpace(name); name != "" {
+			lineup = append(lineup, name)
+		}
+	}
+	if len(lineup) == 0 {
+		return false, nil
+	}
+
+	bands, err := q.GetShowBands(ctx, showID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get lineup: %w", err)
+	}
+
+	current := make([]string, 0, len(bands))
+	for _, b := range bands {
+		current = append(current, b.Name)
+	}
+
+	if sameLineup(current, lineup) {
+		return false, nil
+	}
+
+	if err := q.DeleteShowBands(ctx, showID); err != nil {
+		return false, fmt.Errorf("failed to clear lineup: %w", err)
+	}
+	if _, err := linkBands(ctx, q, showID, lineup, event.Categories); err != nil {
+		return false, err
+	}
+
+	return true, diff.Add(revision.FieldLineup, current, lineup)
+}
+
+// sameLineup reports whether two lineups list the same bands in the same order.
+func sameLineup(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// scrapeActor attributes a change to a scraper source.
+func scrapeActor(source string) revision.Actor {
+	return revision.Actor{Name: source, Source: revision.SourceScraped}
+}
+
+// linkBands links the lineup to a show and returns the headliner's band ID.
+// Bands are given in billing order; performance_order counts up to the headliner.
+// Newly created bands get inferred genres once the whole lineup is linked, so
+// their co-billed bands count as a signal.
+func linkBands(ctx context.Context, q *db.Queries, showID int32, names []string, tags []string) (int32, error) {
+	var headlinerID int32
+	var created []int32
+
+	order := int32(len(names))
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		bandID, isNew, err := FindOrCreateBand(ctx, q, name)
+		if err != nil {
+			return 0, err
+		}
+		if isNew {
+			created = append(created, bandID)
+		}
+
+		isHeadliner := i == 0
+		performanceOrder := order - int32(i)
+		if err := q.CreateShowBand(ctx, db.CreateShowBandParams{
+			ShowID:           showID,
+			BandID:           bandID,
+			IsHeadliner:      &isHeadliner,
+			PerformanceOrder: &performanceOrder,
+		}); err != nil {
+			return 0, fmt.Errorf("failed to link band %q: %w", name, err)
+		}
+
+		if isHeadliner {
+			headlinerID = bandID
+		}
+	}
+
+	for _, bandID := range created {
+		if _, err := inference.InferBand(ctx, q, bandID, tags); err != nil {
+			return 0, err
+		}
+	}
+
+	return headlinerID, nil
+}
+
+// optionalString returns nil for an empty string.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// numericToFloat converts a pgtype.Numeric to *float64.
+func numericToFloat(n pgtype.Numeric) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// floatToNumeric converts a *float64 to pgtype.Numeric.
+func floatToNumeric(f *float64) pgtype.Numeric {
+	var result pgtype.Numeric
+	if f == nil {
+		return result
+	}
+	if err := result.Scan(strconv.FormatFloat(*f, 'f', -1, 64)); err != nil {
+		return pgtype.Numeric{}
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/ingest"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestIngest_StatusChanges(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	source := "test-source"
+	defer tdb.Pool.Exec(ctx, `DELETE FROM shows WHERE external_source = $1`, source)
+
+	p := ingest.New(tdb.Pool)
+	date := time.Now().AddDate(0, 0, 14)
+	from := time.Now()
+	to := time.Now().AddDate(0, 1, 0)
+
+	event := func(id, title string, d time.Time) ingest.Event {
+		return ingest.Event{
+			ExternalID: id,
+			VenueID:    venueID,
+			Title:      title,
+			Date:       d,
+			Bands:      []string{"Test Band Ingest Headliner", "Test Band Ingest Opener"},
+		}
+	}
+
+	// First scrape creates both shows
+	result, err := p.Ingest(ctx, ingest.Batch{
+		Source: source,
+		Events: []ingest.Event{
+			event("evt-1", "[TEST] Ingest Show", date),
+			event("evt-2", "[TEST] Ingest Vanishing Show", date.AddDate(0, 0, 1)),
+		},
+		From: from,
+		To:   to,
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if result.Created != 2 {
+		t.Errorf("expected 2 shows created, got %d", result.Created)
+	}
+
+	statusOf := func(externalID string) (string, *int32) {
+		var status string
+		var rescheduledTo *int32
+		err := tdb.Pool.QueryRow(ctx, `
+			SELECT status, rescheduled_to FROM shows
+			WHERE external_source = $1 AND external_id = $2
+		`, source, externalID).Scan(&status, &rescheduledTo)
+		if err != nil {
+			t.Fatalf("failed to read show %s: %v", externalID, err)
+		}
+		return status, rescheduledTo
+	}
+
+	// Second scrape marks evt-1 postponed and drops evt-2
+	_, err = p.Ingest(ctx, ingest.Batch{
+		Source: source,
+		Events: []ingest.Event{event("evt-1", "[TEST] Ingest Show (Postponed)", date)},
+		From:   from,
+		To:     to,
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	if status, _ := statusOf("evt-1"); status != ingest.StatusPostponed {
+		t.Errorf("expected evt-1 postponed, got %s", status)
+	}
+	if status, _ := statusOf("evt-2"); status != ingest.StatusCancelled {
+		t.Errorf("expected evt-2 cancelled, got %s", status)
+	}
+
+	// Each status change is recorded with the source as actor
+	var revisions int
+	err = tdb.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM show_revisions r
+		JOIN shows s ON r.show_id = s.id
+		WHERE s.external_source = $1
+		  AND r.field = 'status'
+		  AND r.actor = $1
+		  AND r.source = 'scraped'
+		  AND r.old_value = '"scheduled"'
+	`, source).Scan(&revisions)
+	if err != nil {
+		t.Fatalf("failed to count revisions: %v", err)
+	}
+	if revisions != 2 {
+		t.Errorf("expected 2 status revisions, got %d", revisions)
+	}
+
+	// A new listing by the same headliner at the same venue is linked as the new date
+	result, err = p.Ingest(ctx, ingest.Batch{
+		Source: source,
+		Events: []ingest.Event{event("evt-3", "[TEST] Ingest Show", date.AddDate(0, 0, 10))},
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if result.Rescheduled != 1 {
+		t.Errorf("expected 1 reschedule, got %d", result.Rescheduled)
+	}
+
+	_, rescheduledTo := statusOf("evt-2")
+	if rescheduledTo == nil {
+		_, rescheduledTo = statusOf("evt-1")
+	}
+	if rescheduledTo == nil {
+		t.Error("expected a cancelled/postponed show to be linked to the new date")
+	}
+
+	// The other cancelled/postponed show is too far from a listing a year out to be its new date
+	result, err = p.Ingest(ctx, ingest.Batch{
+		Source: source,
+		Events: []ingest.Event{event("evt-4", "[TEST] Ingest Show", date.AddDate(1, 0, 0))},
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if result.Rescheduled != 0 {
+		t.Errorf("expected no reschedule outside the window, got %d", result.Rescheduled)
+	}
+
+	// An empty scrape of the window cancels nothing
+	if _, err := p.Ingest(ctx, ingest.Batch{Source: source, From: from, To: to.AddDate(1, 0, 0)}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if status, _ := statusOf("evt-3"); status != ingest.StatusScheduled {
+		t.Errorf("expected evt-3 to stay scheduled after an empty scrape, got %s", status)
+	}
+}
+
+// fakeSource returns a fixed batch or error.
+type fakeSource struct {
+	name  string
+	batch ingest.Batch
+	err   error
+}
+
+func (s fakeSource) Name() string { return s.name }
+
+func (s fakeSource) Scrape(ctx context.Context) (ingest.Batch, error) { return s.batch, s.err }
+
+func TestRun_SourceFailures(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := ingest.New(nil, ingest.WithMetrics(metrics.NewScraper(reg)))
+
+	// Neither a failed scrape nor an empty one touches the database
+	sources := []ingest.Source{
+		fakeSource{name: "broken", err: errors.New("timeout")},
+		fakeSource{name: "empty", batch: ingest.Batch{
+			Source: "empty",
+			From:   time.Now(),
+			To:     time.Now().AddDate(0, 1, 0),
+		}},
+	}
+	result, err := p.Run(context.Background(), sources)
+	if err == nil || !strings.Contains(err.Error(), "broken: timeout") {
+		t.Errorf("expected the broken source's error, got %v", err)
+	}
+	if result != (ingest.Result{}) {
+		t.Errorf("expected no changes, got %+v", result)
+	}
+
+	expected := `
+# HELP setlist_scraper_runs_total Source ingestion runs by source and result (success or failure).
+# TYPE setlist_scraper_runs_total counter
+setlist_scraper_runs_total{result="failure",source="broken"} 1
+setlist_scraper_runs_total{result="success",source="empty"} 1
+`
+	if err := promtestutil.GatherAndCompare(reg, strings.NewReader(expected), "setlist_scraper_runs_total"); err != nil {
+		t.Error(err)
+	}
+}
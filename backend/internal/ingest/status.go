@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Show statuses (mirrors check_status_valid in the schema).
+const (
+	StatusScheduled = "scheduled"
+	StatusCancelled = "cancelled"
+	StatusPostponed = "postponed"
+	StatusCompleted = "completed"
+)
+
+// RescheduleWindow bounds reschedule linking: a cancelled or postponed show
+// is only linked to a new show dated within this long of its own date, and
+// only if it was cancelled or postponed within this long.
+const RescheduleWindow = 180 * 24 * time.Hour
+
+// statusMarkers match status keywords venues add to event titles.
+// Postponed is checked first since it implies the show will return.
+var statusMarkers = []struct {
+	status  string
+	pattern *regexp.Regexp
+}{
+	{StatusPostponed, regexp.MustCompile(`(?i)[\[(]?\s*\bpostponed\b\s*[\])]?`)},
+	{StatusCancelled, regexp.MustCompile(`(?i)[\[(]?\s*\bcancell?ed\b\s*[\])]?`)},
+}
+
+// titleSeparators are trimmed from a title after a status marker is removed.
+const titleSeparators = " \t-–—:|!*"
+
+// DetectStatus looks for a cancellation or postponement marker in a scraped
+// event title. It returns the detected status (empty if none) and the title
+// with the marker removed, e.g. "CANCELLED: Band X" -> ("cancelled", "Band X").
+func DetectStatus(title string) (status, cleaned string) {
+	for _, m := range statusMarkers {
+		if m.pattern.MatchString(title) {
+			cleaned = m.pattern.ReplaceAllString(title, " ")
+			cleaned = strings.Join(strings.Fields(cleaned), " ")
+			return m.status, strings.Trim(cleaned, titleSeparators)
+		}
+	}
+	return "", strings.TrimSpace(title)
+}
+
+// nextStatus decides a show's status after it is seen again in a scrape.
+// Completed shows are final; a detected marker wins; a show that was
+// cancelled or postponed but is listed again without a marker (and was not
+// replaced by a rescheduled show) is back on.
+func nextStatus(current, detected string, rescheduled bool) string {
+	switch {
+	case current == StatusCompleted:
+		return current
+	case detected != "":
+		return detected
+	case (current == StatusCancelled || current == StatusPostponed) && !rescheduled:
+		return StatusScheduled
+	default:
+		return current
+	}
+}
@@ -0,0 +1,53 @@
+package ingest
+
+import "testing"
+
+func TestDetectStatus(t *testing.T) {
+	tests := []struct {
+		title       string
+		wantStatus  string
+		wantCleaned string
+	}{
+		{"The Avett Brothers", "", "The Avett Brothers"},
+		{"CANCELLED: The Avett Brothers", StatusCancelled, "The Avett Brothers"},
+		{"The Avett Brothers (Canceled)", StatusCancelled, "The Avett Brothers"},
+		{"[POSTPONED] Band X w/ Band Y", StatusPostponed, "Band X w/ Band Y"},
+		{"Band X - Postponed", StatusPostponed, "Band X"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			status, cleaned := DetectStatus(tt.title)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if cleaned != tt.wantCleaned {
+				t.Errorf("cleaned = %q, want %q", cleaned, tt.wantCleaned)
+			}
+		})
+	}
+}
+
+func TestNextStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		detected    string
+		rescheduled bool
+		want        string
+	}{
+		{"scheduled stays scheduled", StatusScheduled, "", false, StatusScheduled},
+		{"marker cancels", StatusScheduled, StatusCancelled, false, StatusCancelled},
+		{"completed is final", StatusCompleted, StatusCancelled, false, StatusCompleted},
+		{"cancelled show relisted", StatusCancelled, "", false, StatusScheduled},
+		{"rescheduled show stays postponed", StatusPostponed, "", true, StatusPostponed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextStatus(tt.current, tt.detected, tt.rescheduled); got != tt.want {
+				t.Errorf("nextStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package logging configures the application's slog output and carries
+// request-scoped loggers through contexts.
+//
+// Production logs are JSON for the log collector; other environments get
+// slog's text format, which reads better in a terminal. Middleware stores a
+// logger enriched with the request ID, route and client details in the
+// request context, and code that handles the request logs through
+// FromContext so every line can be correlated.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// EnvironmentProduction is the environment that logs JSON.
+const EnvironmentProduction = "production"
+
+// New returns a logger writing to w at level ("debug", "info", "warn" or
+// "error"; anything else means info), as JSON in production and text
+// elsewhere.
+func New(w io.Writer, level, environment string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+	if environment == EnvironmentProduction {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// ParseLevel maps a configured level name to a slog.Level, defaulting to
+// info.
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored by WithLogger, or the default
+// logger when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "warn", EnvironmentProduction)
+	logger.Info("hidden")
+	logger.Warn("shown", "key", "value")
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON line in production, got %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "shown" || line["key"] != "value" {
+		t.Errorf("unexpected log line %v", line)
+	}
+
+	buf.Reset()
+	New(&buf, "debug", "development").Debug("shown", "key", "value")
+	if got := buf.String(); !strings.Contains(got, "level=DEBUG") || !strings.Contains(got, "key=value") {
+		t.Errorf("expected text debug line in development, got %q", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"WARN":  slog.LevelWarn,
+		"error": slog.LevelError,
+		"":      slog.LevelInfo,
+	}
+	for level, want := range tests {
+		if got := ParseLevel(level); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("expected the default logger without one in the context")
+	}
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(WithLogger(context.Background(), logger)) != logger {
+		t.Error("expected the context's logger")
+	}
+}
@@ -0,0 +1,170 @@
+// Package mail sends transactional email such as sign-in links and
+// notification digests.
+//
+// Mailer is the extension point. Production uses SMTPMailer; development
+// setups use LogMailer, which writes messages to the application log, or
+// FileMailer, which appends them to a local file so links can be copied out
+// of it.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mailer kinds accepted by New.
+const (
+	KindLog  = "log"
+	KindFile = "file"
+	KindSMTP = "smtp"
+)
+
+// Config selects and configures a mailer.
+type Config struct {
+	Kind string
+
+	// FilePath is the file the file mailer appends to
+	FilePath string
+
+	// SMTP server settings; Username may be empty for unauthenticated relays
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// From is the sender address for SMTP mail
+	From string
+}
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer selected by cfg.Kind.
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Kind {
+	case KindLog:
+		return LogMailer{}, nil
+	case KindFile:
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("file mailer requires a path")
+		}
+		return &FileMailer{Path: cfg.FilePath}, nil
+	case KindSMTP:
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			return nil, fmt.Errorf("smtp mailer requires a host and a from address")
+		}
+		return &SMTPMailer{
+			Addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+			Host:     cfg.SMTPHost,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q, expected one of: %s, %s, %s", cfg.Kind, KindLog, KindFile, KindSMTP)
+	}
+}
+
+// LogMailer writes messages to the application log instead of sending them.
+type LogMailer struct{}
+
+// Send logs the message.
+func (LogMailer) Send(_ context.Context, msg Message) error {
+	slog.Info("mail",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+	return nil
+}
+
+// FileMailer appends messages to a local file, creating it (and its
+// directory) on first use. It is safe for concurrent use.
+type FileMailer struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Send appends the message to the file.
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	f, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+// SMTPMailer sends messages through an SMTP server, upgrading to TLS with
+// STARTTLS when the server offers it.
+type SMTPMailer struct {
+	Addr     string // host:port
+	Host     string // Server name for TLS and authentication
+	Username string
+	Password string
+	From     string
+}
+
+// Send delivers the message as a plain-text email.
+func (m *SMTPMailer) Send(_ context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	if err := smtp.SendMail(m.Addr, auth, m.From, []string{msg.To}, formatMessage(m.From, msg, time.Now())); err != nil {
+		return fmt.Errorf("failed to send mail via %s: %w", m.Addr, err)
+	}
+	return nil
+}
+
+// formatMessage renders msg as an RFC 5322 message with CRLF line endings.
+func formatMessage(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	header := func(name, value string) {
+		// Header values must not smuggle in extra headers
+		value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", msg.Subject)
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
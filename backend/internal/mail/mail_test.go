@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Kind: KindLog}); err != nil {
+		t.Errorf("log mailer: unexpected error %v", err)
+	}
+	if _, err := New(Config{Kind: KindFile}); err == nil {
+		t.Error("file mailer without path: expected error")
+	}
+	if _, err := New(Config{Kind: KindSMTP, SMTPHost: "smtp.example.com"}); err == nil {
+		t.Error("smtp mailer without from: expected error")
+	}
+	if _, err := New(Config{Kind: "carrier-pigeon"}); err == nil {
+		t.Error("unknown mailer: expected error")
+	}
+}
+
+func TestFileMailer_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail", "outbox.txt")
+	m, err := New(Config{Kind: KindFile, FilePath: path})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := m.Send(context.Background(), Message{To: to, Subject: "Hi", Body: "link"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"To: a@example.com", "To: b@example.com", "Subject: Hi"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mail file missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	date := time.Date(2026, 3, 14, 20, 0, 0, 0, time.UTC)
+	got := string(formatMessage("shows@example.com", Message{
+		To:      "fan@example.com",
+		Subject: "Hi\r\nBcc: victim@example.com",
+		Body:    "line one\nline two",
+	}, date))
+
+	want := "From: shows@example.com\r\n" +
+		"To: fan@example.com\r\n" +
+		"Subject: Hi  Bcc: victim@example.com\r\n" +
+		"Date: Sat, 14 Mar 2026 20:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		"line one\r\nline two\r\n"
+	if got != want {
+		t.Errorf("formatMessage() =\n%q\nwant\n%q", got, want)
+	}
+}
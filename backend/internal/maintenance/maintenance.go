@@ -0,0 +1,111 @@
+// Package maintenance contains periodic database upkeep jobs shared by the API and scraper.
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/stream"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+// CompletePastShows marks scheduled shows whose venue-local date has passed as completed.
+// It is safe to run concurrently from multiple processes.
+func CompletePastShows(ctx context.Context, queries *db.Queries) (int64, error) {
+	start := time.Now()
+
+	count, err := queries.CompletePastShows(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		if err := cache.Invalidate(ctx, queries, cache.TagShows); err != nil {
+			return count, err
+		}
+	}
+
+	slog.Info("marked past shows completed",
+		"count", count,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return count, nil
+}
+
+// PurgeExpiredAuth deletes expired magic-link tokens and sessions.
+func PurgeExpiredAuth(ctx context.Context, queries *db.Queries) (int64, error) {
+	count, err := queries.DeleteExpiredAuthTokens(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		slog.Info("purged expired auth tokens", "count", count)
+	}
+	return count, nil
+}
+
+// PurgeWebhookLog deletes webhook events and their delivery log once they are
+// older than webhook.LogRetention and nothing is left to deliver.
+func PurgeWebhookLog(ctx context.Context, queries *db.Queries) (int64, error) {
+	count, err := queries.DeleteOldWebhookEvents(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-webhook.LogRetention),
+		Valid: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		slog.Info("purged old webhook events", "count", count)
+	}
+	return count, nil
+}
+
+// PurgeShowStream deletes live stream events older than stream.Retention,
+// after which clients can no longer resume from them.
+func PurgeShowStream(ctx context.Context, queries *db.Queries) (int64, error) {
+	count, err := queries.DeleteOldShowStreamEvents(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-stream.Retention),
+		Valid: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		slog.Info("purged old show stream events", "count", count)
+	}
+	return count, nil
+}
+
+// Run executes all maintenance jobs immediately and then on every interval tick
+// until the context is cancelled.
+func Run(ctx context.Context, queries *db.Queries, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := CompletePastShows(ctx, queries); err != nil && ctx.Err() == nil {
+			slog.Error("failed to complete past shows", "error", err)
+		}
+		if _, err := PurgeExpiredAuth(ctx, queries); err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge expired auth tokens", "error", err)
+		}
+		if _, err := PurgeWebhookLog(ctx, queries); err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge webhook log", "error", err)
+		}
+		if _, err := PurgeShowStream(ctx, queries); err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge show stream", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
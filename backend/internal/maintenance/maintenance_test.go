@@ -0,0 +1,65 @@
+package maintenance_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/maintenance"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+func TestCompletePastShows(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	pastID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, -3), "Maintenance Past Show")
+	if err != nil {
+		t.Fatalf("failed to insert past show: %v", err)
+	}
+	futureID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 3), "Maintenance Future Show")
+	if err != nil {
+		t.Fatalf("failed to insert future show: %v", err)
+	}
+
+	count, err := maintenance.CompletePastShows(ctx, tdb.Queries)
+	if err != nil {
+		t.Fatalf("CompletePastShows failed: %v", err)
+	}
+	if count < 1 {
+		t.Errorf("expected at least 1 show completed, got %d", count)
+	}
+
+	statusOf := func(id int32) string {
+		var status string
+		if err := tdb.Pool.QueryRow(ctx, `SELECT status FROM shows WHERE id = $1`, id).Scan(&status); err != nil {
+			t.Fatalf("failed to read show status: %v", err)
+		}
+		return status
+	}
+
+	if got := statusOf(pastID); got != "completed" {
+		t.Errorf("expected past show to be completed, got %s", got)
+	}
+	if got := statusOf(futureID); got != "scheduled" {
+		t.Errorf("expected future show to stay scheduled, got %s", got)
+	}
+
+	// A second run is a no-op for already completed shows
+	count, err = maintenance.CompletePastShows(ctx, tdb.Queries)
+	if err != nil {
+		t.Fatalf("second CompletePastShows failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected second run to complete 0 shows, got %d", count)
+	}
+}
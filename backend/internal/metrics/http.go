@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTP holds the API's request metrics.
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+	panics   prometheus.Counter
+}
+
+// NewHTTP creates the request metrics and registers them with reg.
+func NewHTTP(reg prometheus.Registerer) *HTTP {
+	m := &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method", "route"}),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "response_size_bytes",
+			Help:      "HTTP response body size by method and route pattern.",
+			Buckets:   prometheus.ExponentialBuckets(100, 4, 8), // 100B to ~1.6MB
+		}, []string{"method", "route"}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "panics_total",
+			Help:      "Panics recovered while handling HTTP requests.",
+		}),
+	}
+	reg.MustRegister(m.requests, m.duration, m.size, m.panics)
+	return m
+}
+
+// ObserveRequest records a finished request. route is the matched route
+// pattern, never the raw path, to keep label cardinality bounded.
+func (m *HTTP) ObserveRequest(method, route string, status int, duration time.Duration, size int) {
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(method, route).Observe(duration.Seconds())
+	m.size.WithLabelValues(method, route).Observe(float64(size))
+}
+
+// Panic records a recovered panic.
+func (m *HTTP) Panic() {
+	m.panics.Inc()
+}
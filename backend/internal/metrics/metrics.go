@@ -0,0 +1,58 @@
+// Package metrics defines the Prometheus metrics exported by the API and the
+// scraper.
+//
+// The API serves its registry at /metrics: per-route request counts,
+// latencies and response sizes, recovered panics, connection pool stats and
+// the Go runtime. Scraper commands are short-lived batch runs, so they can't
+// be scraped; Flush pushes their registry to a Pushgateway and/or writes it
+// to a file for node_exporter's textfile collector when they finish.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// namespace prefixes every metric name.
+const namespace = "setlist"
+
+// NewRegistry returns a registry with the Go runtime and process collectors.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
+
+// Flush exports a batch job's metrics: pushed to the Pushgateway at pushURL
+// under job, and written atomically to textfile. Either may be empty to skip
+// it. Pushes add to the job's group rather than replacing it, so a failed run
+// keeps the last success timestamp of the run before it.
+func Flush(ctx context.Context, reg prometheus.Gatherer, job, pushURL, textfile string) error {
+	var errs []error
+	if pushURL != "" {
+		if err := push.New(pushURL, job).Gatherer(reg).AddContext(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to push metrics: %w", err))
+		}
+	}
+	if textfile != "" {
+		if err := prometheus.WriteToTextfile(textfile, reg); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write metrics file: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTP(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewHTTP(reg)
+
+	m.ObserveRequest("GET", "/api/shows/:id", 200, 30*time.Millisecond, 512)
+	m.ObserveRequest("GET", "/api/shows/:id", 200, 10*time.Millisecond, 256)
+	m.ObserveRequest("GET", "/api/shows/:id", 404, time.Millisecond, 64)
+	m.Panic()
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/api/shows/:id", "200")); got != 2 {
+		t.Errorf("expected 2 requests with status 200, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.panics); got != 1 {
+		t.Errorf("expected 1 panic, got %v", got)
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 1 {
+		t.Errorf("expected one latency series per route, got %d", n)
+	}
+}
+
+func TestScraper(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewScraper(reg)
+
+	m.ObserveRun("orange-peel", 40, 3*time.Second, nil)
+	m.ObserveRun("orange-peel", 0, time.Second, errors.New("timeout"))
+	m.AddShows("orange-peel", "created", 5)
+	m.AddShows("orange-peel", "failed", 0)
+
+	if got := testutil.ToFloat64(m.runs.WithLabelValues("orange-peel", "success")); got != 1 {
+		t.Errorf("expected 1 successful run, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.runs.WithLabelValues("orange-peel", "failure")); got != 1 {
+		t.Errorf("expected 1 failed run, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.events.WithLabelValues("orange-peel")); got != 40 {
+		t.Errorf("expected 40 events parsed, got %v", got)
+	}
+	if n := testutil.CollectAndCount(m.shows); n != 1 {
+		t.Errorf("expected zero counts to be skipped, got %d series", n)
+	}
+	if got := testutil.ToFloat64(m.lastSuccess.WithLabelValues("orange-peel")); got == 0 {
+		t.Error("expected last success timestamp to be set")
+	}
+}
+
+func TestFlush_Textfile(t *testing.T) {
+	reg := NewRegistry()
+	m := NewScraper(reg)
+	m.ObserveJob("maintain", 2*time.Second, nil)
+
+	path := filepath.Join(t.TempDir(), "scraper.prom")
+	if err := Flush(context.Background(), reg, "scraper_maintain", "", path); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read metrics file: %v", err)
+	}
+	if !strings.Contains(string(body), `setlist_scraper_job_duration_seconds{job="maintain"} 2`) {
+		t.Errorf("expected job duration in metrics file, got:\n%s", body)
+	}
+}
+
+func TestScraper_ObserveJob(t *testing.T) {
+	m := NewScraper(NewRegistry())
+	m.ObserveJob("notify", time.Second, errors.New("smtp down"))
+
+	if got := testutil.ToFloat64(m.jobLastFailure.WithLabelValues("notify")); got == 0 {
+		t.Error("expected last failure timestamp to be set")
+	}
+	if n := testutil.CollectAndCount(m.jobLastSuccess); n != 0 {
+		t.Errorf("expected no last success for a failed job, got %d series", n)
+	}
+	if got := testutil.ToFloat64(m.jobDuration.WithLabelValues("notify")); got != 1 {
+		t.Errorf("expected job duration 1, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector reads pgxpool statistics at scrape time.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired         *prometheus.Desc
+	idle             *prometheus.Desc
+	total            *prometheus.Desc
+	max              *prometheus.Desc
+	acquires         *prometheus.Desc
+	acquireSeconds   *prometheus.Desc
+	waits            *prometheus.Desc
+	waitSeconds      *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+}
+
+// NewPoolCollector returns a collector for pool's connection statistics.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:             pool,
+		acquired:         desc("acquired_connections", "Connections currently checked out of the pool."),
+		idle:             desc("idle_connections", "Idle connections in the pool."),
+		total:            desc("total_connections", "Open connections, including ones being established."),
+		max:              desc("max_connections", "Maximum size of the pool."),
+		acquires:         desc("acquires_total", "Successful connection acquires."),
+		acquireSeconds:   desc("acquire_duration_seconds_total", "Total time spent in successful acquires."),
+		waits:            desc("empty_acquires_total", "Acquires that waited because the pool had no idle connection."),
+		waitSeconds:      desc("empty_acquire_wait_seconds_total", "Total time acquires spent waiting for a connection."),
+		canceledAcquires: desc("canceled_acquires_total", "Acquires canceled by their context."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.acquireSeconds
+	ch <- c.waits
+	ch <- c.waitSeconds
+	ch <- c.canceledAcquires
+}
+
+// Collect implements prometheus.Collector.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	gauge := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v)
+	}
+	counter := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquired, float64(stat.AcquiredConns()))
+	gauge(c.idle, float64(stat.IdleConns()))
+	gauge(c.total, float64(stat.TotalConns()))
+	gauge(c.max, float64(stat.MaxConns()))
+	counter(c.acquires, float64(stat.AcquireCount()))
+	counter(c.acquireSeconds, stat.AcquireDuration().Seconds())
+	counter(c.waits, float64(stat.EmptyAcquireCount()))
+	counter(c.waitSeconds, stat.EmptyAcquireWaitTime().Seconds())
+	counter(c.canceledAcquires, float64(stat.CanceledAcquireCount()))
+}
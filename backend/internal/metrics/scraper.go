@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Scraper holds the scraper's metrics: per-source ingestion runs and
+// per-command job runs.
+type Scraper struct {
+	runs        *prometheus.CounterVec
+	events      *prometheus.CounterVec
+	shows       *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+
+	jobDuration    *prometheus.GaugeVec
+	jobLastSuccess *prometheus.GaugeVec
+	jobLastFailure *prometheus.GaugeVec
+}
+
+// NewScraper creates the scraper metrics and registers them with reg.
+func NewScraper(reg prometheus.Registerer) *Scraper {
+	m := &Scraper{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "runs_total",
+			Help:      "Source ingestion runs by source and result (success or failure).",
+		}, []string{"source", "result"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "events_parsed_total",
+			Help:      "Events parsed from each source.",
+		}, []string{"source"}),
+		shows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "shows_total",
+			Help:      "Shows changed by ingestion, by source and outcome (created, updated, cancelled, ...).",
+		}, []string{"source", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "run_duration_seconds",
+			Help:      "Duration of source ingestion runs.",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10), // 0.5s to ~4m
+		}, []string{"source"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of each source's last successful run.",
+		}, []string{"source"}),
+		jobDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "job_duration_seconds",
+			Help:      "Duration of the last run of each scraper command.",
+		}, []string{"job"}),
+		jobLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "job_last_success_timestamp_seconds",
+			Help:      "Unix time each scraper command last completed.",
+		}, []string{"job"}),
+		jobLastFailure: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "job_last_failure_timestamp_seconds",
+			Help:      "Unix time each scraper command last failed.",
+		}, []string{"job"}),
+	}
+	reg.MustRegister(m.runs, m.events, m.shows, m.duration, m.lastSuccess,
+		m.jobDuration, m.jobLastSuccess, m.jobLastFailure)
+	return m
+}
+
+// ObserveRun records a source run that parsed events and took duration. A
+// nil err counts as a success.
+func (m *Scraper) ObserveRun(source string, events int, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	} else {
+		m.lastSuccess.WithLabelValues(source).SetToCurrentTime()
+	}
+	m.runs.WithLabelValues(source, result).Inc()
+	m.events.WithLabelValues(source).Add(float64(events))
+	m.duration.WithLabelValues(source).Observe(duration.Seconds())
+}
+
+// AddShows counts n shows from source with the given outcome.
+func (m *Scraper) AddShows(source, outcome string, n int) {
+	if n > 0 {
+		m.shows.WithLabelValues(source, outcome).Add(float64(n))
+	}
+}
+
+// ObserveJob records a scraper command that ran for duration. A nil err
+// counts as a success.
+func (m *Scraper) ObserveJob(job string, duration time.Duration, err error) {
+	m.jobDuration.WithLabelValues(job).Set(duration.Seconds())
+	if err != nil {
+		m.jobLastFailure.WithLabelValues(job).SetToCurrentTime()
+	} else {
+		m.jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
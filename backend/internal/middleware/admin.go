@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth requires an "Authorization: Bearer <token>" header matching token.
+// An empty token rejects every request.
+func AdminAuth(token string) gin.HandlerFunc {
+	expected := []byte(token)
+	return func(c *gin.Context) {
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || len(expected) == 0 || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": gin.H{
+					"code":    "UNAUTHORIZED",
+					"message": "A valid admin token is required",
+				},
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
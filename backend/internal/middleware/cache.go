@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+)
+
+// cacheHeader reports whether a response came from the cache.
+const cacheHeader = "X-Cache"
+
+// cachedHeaders describe the response body and are the only headers stored
+// with and replayed from a cached response. Others, such as request IDs and
+// rate limit quotas, belong to a single request or client.
+var cachedHeaders = []string{"Content-Type", "Cache-Control", "Last-Modified", "ETag"}
+
+// Cache serves GET requests from the store and stores 200 responses for ttl,
+// labelled with tags. Store errors are logged and the request is handled
+// uncached.
+func Cache(store cache.Store, ttl time.Duration, tags ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := cache.Key(c.Request.URL.Path, c.Request.URL.Query())
+
+		entry, ok, err := store.Get(ctx, key)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Warn("failed to read response cache", "key", key, "error", err)
+		}
+		if ok {
+			header := c.Writer.Header()
+			for _, name := range cachedHeaders {
+				if values := entry.Header.Values(name); len(values) > 0 {
+					header[name] = values
+				}
+			}
+			header.Set(cacheHeader, "HIT")
+			c.Status(entry.Status)
+			c.Writer.Write(entry.Body)
+			c.Abort()
+			return
+		}
+
+		c.Header(cacheHeader, "MISS")
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// Restore the writer even if a handler panics, so Recovery's 500
+		// reaches the client through the real response
+		defer func() { c.Writer = recorder.ResponseWriter }()
+		c.Next()
+
+		if recorder.Status() != http.StatusOK {
+			return
+		}
+		header := http.Header{}
+		for _, name := range cachedHeaders {
+			if values := recorder.Header().Values(name); len(values) > 0 {
+				header[name] = values
+			}
+		}
+		err = store.Set(ctx, key, cache.Entry{
+			Status: http.StatusOK,
+			Header: header,
+			Body:   recorder.body.Bytes(),
+		}, ttl, tags)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Warn("failed to write response cache", "key", key, "error", err)
+		}
+	}
+}
+
+// bodyRecorder copies the response body as it is written.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
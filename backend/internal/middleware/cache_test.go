@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/cache"
+)
+
+func TestCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := cache.NewLRU(10)
+
+	calls := 0
+	router := gin.New()
+	router.GET("/api/shows", Cache(store, time.Minute, cache.TagShows), func(c *gin.Context) {
+		calls++
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"calls": calls})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	first := get("/api/shows?venue=a&venue=b")
+	if first.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected MISS, got %q", first.Header().Get("X-Cache"))
+	}
+
+	// Same query in another order is served from the cache
+	second := get("/api/shows?venue=b&venue=a")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() {
+		t.Errorf("expected cached %q, got %s %q", first.Body.String(), second.Header().Get("X-Cache"), second.Body.String())
+	}
+	if ct := second.Header().Get("Content-Type"); ct != first.Header().Get("Content-Type") {
+		t.Errorf("expected cached Content-Type %q, got %q", first.Header().Get("Content-Type"), ct)
+	}
+	if calls != 1 {
+		t.Errorf("expected handler called once, got %d", calls)
+	}
+
+	// Errors are not cached
+	get("/api/shows?fail=1")
+	if w := get("/api/shows?fail=1"); w.Code != http.StatusInternalServerError || calls != 3 {
+		t.Errorf("expected error responses to skip the cache, got %d after %d calls", w.Code, calls)
+	}
+
+	store.InvalidateTags(context.Background(), cache.TagShows)
+	if w := get("/api/shows?venue=a&venue=b"); w.Header().Get("X-Cache") != "MISS" || calls != 4 {
+		t.Errorf("expected MISS after invalidation, got %s after %d calls", w.Header().Get("X-Cache"), calls)
+	}
+}
+
+func TestCache_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/api/venues", Cache(cache.NewLRU(10), time.Minute, cache.TagVenues), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"venues": []string{}})
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/venues", nil))
+		return w
+	}
+
+	first, second := get(), get()
+	if second.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected second request to be a HIT, got %q", second.Header().Get("X-Cache"))
+	}
+	firstID, secondID := first.Header().Get(RequestIDHeader), second.Header().Get(RequestIDHeader)
+	if firstID == "" || secondID == "" || firstID == secondID {
+		t.Errorf("expected each request to keep its own ID, got %q and %q", firstID, secondID)
+	}
+}
+
+func TestCache_RateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limited := func(c *gin.Context) { c.String(http.StatusTooManyRequests, "limited") }
+
+	router := gin.New()
+	router.Use(RateLimit(10, time.Minute, limited))
+	router.GET("/api/venues", Cache(cache.NewLRU(10), time.Minute, cache.TagVenues), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"venues": []string{}})
+	})
+
+	get := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/venues", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The first client spends three requests, caching the response on the first
+	get("192.0.2.1")
+	get("192.0.2.1")
+	if w := get("192.0.2.1"); w.Header().Get("RateLimit-Remaining") != "7" {
+		t.Fatalf("expected first client to have 7 requests left, got %q", w.Header().Get("RateLimit-Remaining"))
+	}
+
+	// A cache hit for another client reports that client's own quota
+	w := get("198.51.100.7")
+	if w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected a HIT, got %q", w.Header().Get("X-Cache"))
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "9" {
+		t.Errorf("expected second client to have 9 requests left, got %q", got)
+	}
+	if ct := w.Header().Get("Content-Type"); ct == "" {
+		t.Error("expected cached Content-Type to be replayed")
+	}
+}
+
+func TestCache_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Recovery(nil))
+	router.GET("/api/venues", Cache(cache.NewLRU(10), time.Minute, cache.TagVenues), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/venues", nil))
+
+	if w.Code != http.StatusInternalServerError || w.Body.Len() == 0 {
+		t.Errorf("expected recovered 500, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Conditional answers conditional GET requests. It buffers the response,
+// tags it with a strong ETag computed from the body (unless the handler set
+// one) and replies 304 Not Modified when If-None-Match matches the ETag or,
+// without If-None-Match, when If-Modified-Since is not before the
+// Last-Modified the handler set. cacheControl becomes the Cache-Control
+// header unless the handler chose its own. Only 200 responses are affected.
+func Conditional(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = buffer
+		// Restore the writer even if a handler panics, so Recovery's 500
+		// reaches the client instead of the discarded buffer
+		defer func() { c.Writer = original }()
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		if buffer.status == http.StatusOK {
+			if header.Get("ETag") == "" {
+				sum := sha256.Sum256(buffer.body.Bytes())
+				header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+			}
+			if header.Get("Cache-Control") == "" && cacheControl != "" {
+				header.Set("Cache-Control", cacheControl)
+			}
+
+			if notModified(c.Request, header) {
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				original.WriteHeader(http.StatusNotModified)
+				original.WriteHeaderNow()
+				return
+			}
+		}
+
+		original.WriteHeader(buffer.status)
+		if c.Request.Method == http.MethodHead {
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(buffer.body.Bytes())
+	}
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when it is
+// absent, against the response's validators (RFC 9110 section 13.2.2).
+func notModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, header.Get("ETag"))
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(ims)
+}
+
+// etagMatches reports whether an If-None-Match list contains etag, using the
+// weak comparison GET requires.
+func etagMatches(list, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the status and body until Conditional decides what
+// to send. Headers go straight to the underlying writer's map, which is not
+// sent until Conditional writes the status.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op: the response is sent once the handler returns.
+func (w *bufferedWriter) Flush() {}
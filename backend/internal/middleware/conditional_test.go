@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.Use(Conditional("public, max-age=300"))
+	router.GET("/api/shows", func(c *gin.Context) {
+		c.Header("Last-Modified", modified.Format(http.TimeFormat))
+		c.JSON(http.StatusOK, gin.H{"data": []string{"a", "b"}})
+	})
+	router.GET("/api/tonight", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+	router.GET("/api/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/api/shows", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", first.Code, etag)
+	}
+	if cc := first.Header().Get("Cache-Control"); cc != "public, max-age=300" {
+		t.Errorf("expected route Cache-Control, got %q", cc)
+	}
+	if first.Body.String() != `{"data":["a","b"]}` {
+		t.Errorf("unexpected body %q", first.Body.String())
+	}
+
+	// The ETag is stable for an unchanged body
+	if again := get("/api/shows", nil); again.Header().Get("ETag") != etag {
+		t.Errorf("expected stable ETag %q, got %q", etag, again.Header().Get("ETag"))
+	}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"wildcard", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"modified since", map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"etag wins over date", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": modified.Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get("/api/shows", tt.header)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, w.Code)
+			}
+			if w.Code == http.StatusNotModified {
+				if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+					t.Errorf("expected empty 304, got %q with Content-Type %q", w.Body.String(), w.Header().Get("Content-Type"))
+				}
+				if w.Header().Get("ETag") != etag || w.Header().Get("Cache-Control") == "" {
+					t.Errorf("expected 304 to carry validators, got %v", w.Header())
+				}
+			}
+		})
+	}
+
+	t.Run("handler Cache-Control wins", func(t *testing.T) {
+		if cc := get("/api/tonight", nil).Header().Get("Cache-Control"); cc != "public, max-age=60" {
+			t.Errorf("expected handler Cache-Control, got %q", cc)
+		}
+	})
+
+	t.Run("errors pass through", func(t *testing.T) {
+		w := get("/api/missing", map[string]string{"If-None-Match": "*"})
+		if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+			t.Errorf("expected untouched 404, got %d %v", w.Code, w.Header())
+		}
+		if w.Body.String() != `{"error":"not found"}` {
+			t.Errorf("unexpected body %q", w.Body.String())
+		}
+	})
+}
+
+func TestConditional_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Recovery(nil), Conditional("public, max-age=300"))
+	router.GET("/api/shows", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/shows", nil))
+
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "INTERNAL_ERROR") {
+		t.Errorf("expected recovered 500, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/logging"
 )
 
-// Logger middleware logs HTTP requests
+// Logger middleware logs HTTP requests through the request's logger (see
+// RequestID), at error level for 5xx responses
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -15,12 +17,15 @@ func Logger() gin.HandlerFunc {
 
 		c.Next()
 
-		slog.Info("request",
-			"method", c.Request.Method,
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request",
 			"path", path,
 			"status", c.Writer.Status(),
 			"duration_ms", time.Since(start).Milliseconds(),
-			"ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
 		)
 	}
 }
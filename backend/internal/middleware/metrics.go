@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so probes for
+// arbitrary paths share one series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records each request's count, latency and response size by method
+// and route pattern
+func Metrics(m *metrics.HTTP) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start), max(c.Writer.Size(), 0))
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	m := metrics.NewHTTP(reg)
+
+	router := gin.New()
+	router.Use(Metrics(m), Recovery(m))
+	router.GET("/api/shows/:id", func(c *gin.Context) { c.String(http.StatusOK, "show") })
+	router.GET("/api/panic", func(c *gin.Context) { panic("boom") })
+
+	for _, path := range []string{"/api/shows/1", "/api/shows/2", "/api/panic", "/wp-login.php"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP setlist_http_panics_total Panics recovered while handling HTTP requests.
+# TYPE setlist_http_panics_total counter
+setlist_http_panics_total 1
+# HELP setlist_http_requests_total HTTP requests by method, route pattern and status code.
+# TYPE setlist_http_requests_total counter
+setlist_http_requests_total{method="GET",route="/api/panic",status="500"} 1
+setlist_http_requests_total{method="GET",route="/api/shows/:id",status="200"} 2
+setlist_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"setlist_http_requests_total", "setlist_http_panics_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows each client IP limit requests per window on each route
+// (method plus route pattern). Buckets hold up to limit tokens and refill
+// continuously, so a client may burst the whole allowance and then continues
+// at limit/window. Every response carries RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers; a client
+// out of tokens gets Retry-After and is answered by limited, which writes the
+// 429 Too Many Requests response.
+func RateLimit(limit int, window time.Duration, limited gin.HandlerFunc) gin.HandlerFunc {
+	return newRateLimiter(limit, window, limited, time.Now).handle
+}
+
+// rateLimiter is a set of token buckets keyed by client IP and route.
+type rateLimiter struct {
+	limit   float64
+	window  time.Duration
+	rate    float64 // tokens per second
+	policy  string
+	limited gin.HandlerFunc
+	now     func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration, limited gin.HandlerFunc, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		limit:     float64(limit),
+		window:    window,
+		rate:      float64(limit) / window.Seconds(),
+		policy:    strconv.Itoa(limit) + ";w=" + strconv.Itoa(int(window.Seconds())),
+		limited:   limited,
+		now:       now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: now(),
+	}
+}
+
+func (l *rateLimiter) handle(c *gin.Context) {
+	key := c.ClientIP() + " " + c.Request.Method + " " + c.FullPath()
+	allowed, remaining, reset, retryAfter := l.take(key)
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(int(l.limit)))
+	header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+	header.Set("RateLimit-Policy", l.policy)
+
+	if !allowed {
+		header.Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+		l.limited(c)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// take spends a token from key's bucket if it has one. It returns the whole
+// tokens left, the time until the bucket is full again and, when denied, the
+// time until the next token.
+func (l *rateLimiter) take(key string) (allowed bool, remaining int, reset, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.limit, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.limit, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retryAfter = l.refillTime(1 - b.tokens)
+	}
+	return allowed, int(b.tokens), l.refillTime(l.limit - b.tokens), retryAfter
+}
+
+// sweep drops buckets idle for a whole window, which have refilled and are
+// indistinguishable from new ones. It runs at most once per window.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// refillTime is how long the bucket takes to gain tokens.
+func (l *rateLimiter) refillTime(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// seconds rounds d up to whole seconds for the RateLimit headers.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limited := func(c *gin.Context) { c.String(http.StatusTooManyRequests, "limited") }
+	limiter := newRateLimiter(3, time.Minute, limited, func() time.Time { return now })
+
+	router := gin.New()
+	router.Use(limiter.handle)
+	router.GET("/api/shows", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/shows", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	do := func(method, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/shows", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i, want := range []string{"2", "1", "0"} {
+		w := do(http.MethodGet, "192.0.2.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != want {
+			t.Errorf("request %d: expected RateLimit-Remaining %s, got %s", i+1, want, got)
+		}
+	}
+
+	w := do(http.MethodGet, "192.0.2.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("RateLimit-Limit") != "3" || w.Header().Get("RateLimit-Policy") != "3;w=60" {
+		t.Errorf("unexpected limit headers %v", w.Header())
+	}
+	// One token refills every 20s; the bucket is full again after 60s
+	if w.Header().Get("Retry-After") != "20" || w.Header().Get("RateLimit-Reset") != "60" {
+		t.Errorf("expected Retry-After 20 and RateLimit-Reset 60, got %s and %s",
+			w.Header().Get("Retry-After"), w.Header().Get("RateLimit-Reset"))
+	}
+	if w.Body.String() != "limited" {
+		t.Errorf("expected the limited handler's response, got %q", w.Body.String())
+	}
+
+	// Other clients and other routes have their own buckets
+	if w := do(http.MethodGet, "192.0.2.2"); w.Code != http.StatusOK {
+		t.Errorf("expected another IP to be allowed, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "192.0.2.1"); w.Code != http.StatusCreated {
+		t.Errorf("expected another route to be allowed, got %d", w.Code)
+	}
+
+	now = now.Add(20 * time.Second)
+	if w := do(http.MethodGet, "192.0.2.1"); w.Code != http.StatusOK {
+		t.Errorf("expected a refilled token after 20s, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "192.0.2.1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 once the refill is spent, got %d", w.Code)
+	}
+
+	// Idle buckets are swept once they have refilled
+	now = now.Add(2 * time.Minute)
+	do(http.MethodGet, "192.0.2.3")
+	if n := len(limiter.buckets); n != 1 {
+		t.Errorf("expected idle buckets swept, %d left", n)
+	}
+}
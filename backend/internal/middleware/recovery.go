@@ -1,18 +1,23 @@
 package middleware
 
 import (
-	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+	"github.com/paulsena/asheville-setlist/internal/metrics"
 )
 
-// Recovery middleware recovers from panics and returns 500
-func Recovery() gin.HandlerFunc {
+// Recovery middleware recovers from panics and returns 500, counting them in
+// m when it is not nil
+func Recovery(m *metrics.HTTP) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				slog.Error("panic recovered", "error", err)
+				if m != nil {
+					m.Panic()
+				}
+				logging.FromContext(c.Request.Context()).Error("panic recovered", "error", err)
 				c.JSON(http.StatusInternalServerError, gin.H{
 					"error": gin.H{
 						"code":    "INTERNAL_ERROR",
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key set by RequestID.
+const requestIDKey = "request_id"
+
+// maxRequestIDLength bounds client-supplied request IDs.
+const maxRequestIDLength = 128
+
+// RequestID tags each request with an ID, reusing the client's
+// X-Request-ID when it is short and printable (so a proxy's or frontend's ID
+// follows the request) and generating one otherwise. The ID is echoed in the
+// response header. A logger carrying the ID, method, route, path parameters,
+// client IP, user agent and trace ID (when Tracing runs first) is stored in
+// the request context for logging.FromContext.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		attrs := []any{
+			"request_id", id,
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			attrs = append(attrs, "trace_id", span.TraceID().String())
+		}
+		if len(c.Params) > 0 {
+			params := make([]any, 0, len(c.Params))
+			for _, p := range c.Params {
+				params = append(params, slog.String(p.Key, p.Value))
+			}
+			attrs = append(attrs, slog.Group("params", params...))
+		}
+		logger := slog.Default().With(attrs...)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID RequestID assigned to the request.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/api/venues/:slug", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handled")
+		c.String(http.StatusOK, GetRequestID(c))
+	})
+
+	get := func(requestID string) (*httptest.ResponseRecorder, map[string]any) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/api/venues/orange-peel", nil)
+		req.Header.Set("User-Agent", "test-agent")
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var line map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatalf("expected one JSON log line, got %q: %v", buf.String(), err)
+		}
+		return w, line
+	}
+
+	w, line := get("abc-123")
+	if w.Header().Get(RequestIDHeader) != "abc-123" || w.Body.String() != "abc-123" {
+		t.Errorf("expected client request ID to be kept, got header %q body %q", w.Header().Get(RequestIDHeader), w.Body.String())
+	}
+	if line["request_id"] != "abc-123" || line["route"] != "/api/venues/:slug" || line["user_agent"] != "test-agent" {
+		t.Errorf("expected request attributes on the log line, got %v", line)
+	}
+	if params, _ := line["params"].(map[string]any); params["slug"] != "orange-peel" {
+		t.Errorf("expected path params on the log line, got %v", line["params"])
+	}
+
+	for _, invalid := range []string{"", "has space", strings.Repeat("x", maxRequestIDLength+1)} {
+		w, line := get(invalid)
+		id := w.Header().Get(RequestIDHeader)
+		if id == invalid || len(id) != 32 || line["request_id"] != id {
+			t.Errorf("expected a generated request ID for %q, got %q (logged %v)", invalid, id, line["request_id"])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the HTTP server tracer.
+const tracerName = "github.com/paulsena/asheville-setlist/internal/middleware"
+
+// Tracing starts a server span per request, named after the method and
+// route pattern, continuing the trace from the request's traceparent header
+// (via the global propagator). The span is stored in the request context so
+// queries and other work become its children.
+func Tracing(tp trace.TracerProvider) gin.HandlerFunc {
+	tracer := tp.Tracer(tracerName)
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method + " " + route
+		if route == "" {
+			name = c.Request.Method
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/paulsena/asheville-setlist/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	queries := tracing.NewQueryTracer(provider)
+
+	router := gin.New()
+	router.Use(Tracing(provider))
+	router.GET("/api/shows/:id", func(c *gin.Context) {
+		// Stand-in for a sqlc query run through the pool's tracer
+		ctx := queries.TraceQueryStart(c.Request.Context(), nil, pgx.TraceQueryStartData{SQL: "-- name: GetShowByID :one\nSELECT 1"})
+		queries.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+		c.Status(http.StatusOK)
+	})
+	router.GET("/api/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/shows/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected request and query spans, got %d", len(spans))
+	}
+	query, server := spans[0], spans[1]
+
+	if server.Name != "GET /api/shows/:id" || server.SpanKind != trace.SpanKindServer {
+		t.Errorf("expected server span named after the route, got %s %v", server.Name, server.SpanKind)
+	}
+	if server.SpanContext.TraceID().String() != traceID || server.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("expected the trace to continue from traceparent, got trace %s parent %s",
+			server.SpanContext.TraceID(), server.Parent.SpanID())
+	}
+	if query.Name != "GetShowByID" || query.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("expected GetShowByID as a child of the request span, got %s with parent %s", query.Name, query.Parent.SpanID())
+	}
+	var status int64
+	for _, kv := range server.Attributes {
+		if kv.Key == "http.response.status_code" {
+			status = kv.Value.AsInt64()
+		}
+	}
+	if status != http.StatusOK {
+		t.Errorf("expected status code attribute 200, got %d", status)
+	}
+
+	exporter.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/fail", nil))
+	spans = exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error || spans[0].Parent.IsValid() {
+		t.Errorf("expected one root error span for a 500, got %v", spans)
+	}
+}
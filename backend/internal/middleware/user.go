@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/paulsena/asheville-setlist/internal/auth"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/logging"
+)
+
+// Context keys set by UserAuth.
+const (
+	userKey         = "user"
+	sessionTokenKey = "session_token"
+)
+
+// UserAuth requires an "Authorization: Bearer <session token>" header for a
+// live session and stores the signed-in user for CurrentUser.
+func UserAuth(queries *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+		user, err := auth.Authenticate(c.Request.Context(), queries, token)
+		if errors.Is(err, auth.ErrInvalidToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": gin.H{
+					"code":    "UNAUTHORIZED",
+					"message": "A valid session token is required",
+				},
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to authenticate session", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": gin.H{
+					"code":    "INTERNAL_ERROR",
+					"message": "An unexpected error occurred",
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(userKey, user)
+		c.Set(sessionTokenKey, token)
+		c.Next()
+	}
+}
+
+// CurrentUser returns the user signed in by UserAuth.
+func CurrentUser(c *gin.Context) (db.User, bool) {
+	user, ok := c.Get(userKey)
+	if !ok {
+		return db.User{}, false
+	}
+	u, ok := user.(db.User)
+	return u, ok
+}
+
+// SessionToken returns the session token accepted by UserAuth.
+func SessionToken(c *gin.Context) string {
+	return c.GetString(sessionTokenKey)
+}
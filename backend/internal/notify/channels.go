@@ -0,0 +1,214 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/mail"
+)
+
+// DeliveryTimeout bounds each outbound webhook or push request.
+const DeliveryTimeout = 10 * time.Second
+
+// VAPIDConfig holds the application server keys for Web Push (base64url).
+// An empty PrivateKey disables the Web Push channel.
+type VAPIDConfig struct {
+	PublicKey  string
+	PrivateKey string
+	Subject    string // mailto: or https: contact for push services
+}
+
+// NewChannels returns the standard channels: email through mailer, webhooks,
+// and Web Push when VAPID keys are configured.
+func NewChannels(mailer mail.Mailer, vapid VAPIDConfig) (map[string]Channel, error) {
+	channels := map[string]Channel{
+		ChannelEmail:   EmailChannel{Mailer: mailer},
+		ChannelWebhook: &WebhookChannel{},
+	}
+
+	if vapid.PrivateKey != "" {
+		push, err := NewWebPushChannel(vapid, nil)
+		if err != nil {
+			return nil, err
+		}
+		channels[ChannelWebPush] = push
+	}
+
+	return channels, nil
+}
+
+// EmailChannel sends digests as plain-text email.
+type EmailChannel struct {
+	Mailer mail.Mailer
+}
+
+// Send emails the digest to the destination address.
+func (c EmailChannel) Send(ctx context.Context, dest Destination, digest Digest) error {
+	return c.Mailer.Send(ctx, mail.Message{
+		To:      dest.Target,
+		Subject: digest.Subject(),
+		Body:    digest.Text(),
+	})
+}
+
+// WebhookChannel POSTs digests as JSON to a user-supplied URL.
+type WebhookChannel struct {
+	// Client defaults to NewPublicHTTPClient, which refuses internal addresses
+	Client *http.Client
+
+	once sync.Once
+}
+
+// webhookPayload is the JSON body of a webhook delivery.
+type webhookPayload struct {
+	Subject        string                `json:"subject"`
+	Notifications  []webhookNotification `json:"notifications"`
+	UnsubscribeURL string                `json:"unsubscribe_url"`
+}
+
+type webhookNotification struct {
+	ID      int32  `json:"id"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	ShowID  int32  `json:"show_id"`
+	ShowURL string `json:"show_url"`
+	Date    string `json:"date"`
+}
+
+// Send posts the digest. 404 and 410 responses mean the endpoint is gone.
+func (c *WebhookChannel) Send(ctx context.Context, dest Destination, digest Digest) error {
+	c.once.Do(func() {
+		if c.Client == nil {
+			c.Client = NewPublicHTTPClient(DeliveryTimeout)
+		}
+	})
+
+	payload := webhookPayload{
+		Subject:        digest.Subject(),
+		Notifications:  make([]webhookNotification, len(digest.Notifications)),
+		UnsubscribeURL: digest.UnsubscribeURL,
+	}
+	for i, n := range digest.Notifications {
+		payload.Notifications[i] = webhookNotification{
+			ID:      n.ID,
+			Kind:    n.Kind,
+			Message: n.Message(),
+			ShowID:  n.ShowID,
+			ShowURL: digest.ShowURL(n),
+			Date:    n.ShowDate.Format(time.RFC3339),
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dest.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AshevilleSetlist-Notifications/1.0")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return checkDeliveryStatus(resp.StatusCode)
+}
+
+// checkDeliveryStatus maps an HTTP response status to a delivery result.
+func checkDeliveryStatus(status int) error {
+	switch {
+	case status >= 200 && status < 300:
+		return nil
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return ErrChannelGone
+	default:
+		return fmt.Errorf("delivery failed with status %d", status)
+	}
+}
+
+// NewPublicHTTPClient returns an HTTP client that refuses to connect to
+// loopback, private, link-local and other non-public addresses, so URLs
+// supplied by users cannot reach internal services. The check runs on the
+// resolved address, which also covers redirects and DNS tricks.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("refusing to connect to non-public address %s", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address.
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast()
+}
+
+// FakeChannel records digests instead of delivering them. It is meant for
+// tests; set Err to simulate failed deliveries.
+type FakeChannel struct {
+	Err error
+
+	mu         sync.Mutex
+	deliveries []FakeDelivery
+}
+
+// FakeDelivery is a digest recorded by FakeChannel.
+type FakeDelivery struct {
+	Destination Destination
+	Digest      Digest
+}
+
+// Send records the digest, or returns Err when set.
+func (c *FakeChannel) Send(_ context.Context, dest Destination, digest Digest) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deliveries = append(c.deliveries, FakeDelivery{Destination: dest, Digest: digest})
+	return nil
+}
+
+// Deliveries returns the recorded digests, oldest first.
+func (c *FakeChannel) Deliveries() []FakeDelivery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]FakeDelivery(nil), c.deliveries...)
+}
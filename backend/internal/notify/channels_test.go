@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookChannel_Send(t *testing.T) {
+	var got webhookPayload
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	channel := &WebhookChannel{Client: server.Client()}
+	digest := Digest{
+		Notifications: []Notification{
+			{ID: 3, Kind: KindShowCancelled, ShowID: 9, ShowName: "Spring Fling", VenueName: "The Grey Eagle", ShowDate: time.Date(2026, 5, 1, 23, 0, 0, 0, time.UTC)},
+		},
+		AppURL:         "http://app.test",
+		UnsubscribeURL: UnsubscribeURL("http://app.test", "tok"),
+	}
+
+	if err := channel.Send(context.Background(), Destination{Target: server.URL}, digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(got.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %+v", got)
+	}
+	n := got.Notifications[0]
+	if n.ID != 3 || n.Kind != KindShowCancelled || n.ShowURL != "http://app.test/shows/9" || n.Date != "2026-05-01T23:00:00Z" {
+		t.Errorf("unexpected notification %+v", n)
+	}
+	if got.UnsubscribeURL != "http://app.test/unsubscribe?token=tok" {
+		t.Errorf("UnsubscribeURL = %q", got.UnsubscribeURL)
+	}
+
+	status = http.StatusGone
+	if err := channel.Send(context.Background(), Destination{Target: server.URL}, digest); !errors.Is(err, ErrChannelGone) {
+		t.Errorf("410 response: expected ErrChannelGone, got %v", err)
+	}
+
+	status = http.StatusInternalServerError
+	if err := channel.Send(context.Background(), Destination{Target: server.URL}, digest); err == nil || errors.Is(err, ErrChannelGone) {
+		t.Errorf("500 response: expected retryable error, got %v", err)
+	}
+}
+
+func TestNewPublicHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached loopback server")
+	}))
+	defer server.Close()
+
+	client := NewPublicHTTPClient(time.Second)
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected loopback request to be refused")
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.5", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// showLocation is the time zone show times are written in.
+var showLocation = loadShowLocation()
+
+func loadShowLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// Notification is a single notification ready to be rendered.
+type Notification struct {
+	ID        int32
+	Kind      string
+	ShowID    int32
+	ShowName  string // Show title, else the headliner
+	ShowDate  time.Time
+	VenueName string
+	BandName  string // Followed band that triggered an announcement, if any
+}
+
+// ShowName names a show by its title, falling back to the headliner.
+func ShowName(title *string, headliner string) string {
+	if title != nil && *title != "" {
+		return *title
+	}
+	return headliner
+}
+
+// Message describes the notification in one line.
+func (n Notification) Message() string {
+	when := n.ShowDate.In(showLocation).Format("Mon, Jan 2 at 3:04 PM")
+	name := n.ShowName
+	if name == "" {
+		name = "A show"
+	}
+
+	switch n.Kind {
+	case KindShowAnnounced:
+		if n.BandName != "" {
+			return fmt.Sprintf("%s announced a show at %s on %s", n.BandName, n.VenueName, when)
+		}
+		return fmt.Sprintf("New at %s: %s on %s", n.VenueName, name, when)
+	case KindShowCancelled:
+		return fmt.Sprintf("Cancelled: %s at %s on %s", name, n.VenueName, when)
+	case KindShowPostponed:
+		return fmt.Sprintf("Postponed: %s at %s on %s", name, n.VenueName, when)
+	case KindShowReminder:
+		return fmt.Sprintf("Coming up: %s at %s on %s", name, n.VenueName, when)
+	default:
+		return fmt.Sprintf("%s at %s on %s", name, n.VenueName, when)
+	}
+}
+
+// Digest is the batch of notifications delivered to one channel at once.
+type Digest struct {
+	Notifications  []Notification
+	AppURL         string // Frontend base URL for show links
+	UnsubscribeURL string // Disables the receiving channel
+}
+
+// Subject summarizes the digest, e.g. as an email subject or push title.
+func (d Digest) Subject() string {
+	if len(d.Notifications) == 1 {
+		return d.Notifications[0].Message()
+	}
+	return fmt.Sprintf("%d updates from The Asheville Setlist", len(d.Notifications))
+}
+
+// ShowURL links to a notification's show on the frontend.
+func (d Digest) ShowURL(n Notification) string {
+	return fmt.Sprintf("%s/shows/%d", strings.TrimRight(d.AppURL, "/"), n.ShowID)
+}
+
+// Text renders the digest as a plain-text message body.
+func (d Digest) Text() string {
+	var b strings.Builder
+	for _, n := range d.Notifications {
+		fmt.Fprintf(&b, "- %s\n  %s\n", n.Message(), d.ShowURL(n))
+	}
+	fmt.Fprintf(&b, "\nYou're receiving this because you follow bands or venues or saved shows on The Asheville Setlist.\n")
+	fmt.Fprintf(&b, "Unsubscribe: %s\n", d.UnsubscribeURL)
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationMessage(t *testing.T) {
+	date := time.Date(2026, 3, 14, 0, 30, 0, 0, time.UTC) // 8:30 PM Eastern on the 13th
+
+	tests := []struct {
+		name string
+		n    Notification
+		want string
+	}{
+		{
+			name: "announced by followed band",
+			n:    Notification{Kind: KindShowAnnounced, ShowName: "Spring Fling", BandName: "Moon Taxi", VenueName: "The Orange Peel", ShowDate: date},
+			want: "Moon Taxi announced a show at The Orange Peel on Fri, Mar 13 at 8:30 PM",
+		},
+		{
+			name: "announced at followed venue",
+			n:    Notification{Kind: KindShowAnnounced, ShowName: "Spring Fling", VenueName: "The Orange Peel", ShowDate: date},
+			want: "New at The Orange Peel: Spring Fling on Fri, Mar 13 at 8:30 PM",
+		},
+		{
+			name: "cancelled without a name",
+			n:    Notification{Kind: KindShowCancelled, VenueName: "Grey Eagle", ShowDate: date},
+			want: "Cancelled: A show at Grey Eagle on Fri, Mar 13 at 8:30 PM",
+		},
+		{
+			name: "reminder",
+			n:    Notification{Kind: KindShowReminder, ShowName: "Spring Fling", VenueName: "The Orange Peel", ShowDate: date},
+			want: "Coming up: Spring Fling at The Orange Peel on Fri, Mar 13 at 8:30 PM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.n.Message(); got != tt.want {
+				t.Errorf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigest(t *testing.T) {
+	n := Notification{ID: 1, Kind: KindShowPostponed, ShowID: 42, ShowName: "Spring Fling", VenueName: "The Orange Peel", ShowDate: time.Now()}
+	d := Digest{
+		Notifications:  []Notification{n},
+		AppURL:         "http://app.test/",
+		UnsubscribeURL: "http://app.test/unsubscribe?token=abc",
+	}
+
+	if d.Subject() != n.Message() {
+		t.Errorf("single-notification subject = %q, want the message", d.Subject())
+	}
+
+	text := d.Text()
+	for _, want := range []string{n.Message(), "http://app.test/shows/42", "Unsubscribe: http://app.test/unsubscribe?token=abc"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() missing %q:\n%s", want, text)
+		}
+	}
+
+	d.Notifications = append(d.Notifications, n)
+	if got, want := d.Subject(), "2 updates from The Asheville Setlist"; got != want {
+		t.Errorf("Subject() = %q, want %q", got, want)
+	}
+}
+
+func TestShowName(t *testing.T) {
+	title := "Spring Fling"
+	empty := ""
+	if got := ShowName(&title, "Headliner"); got != title {
+		t.Errorf("ShowName(title) = %q", got)
+	}
+	if got := ShowName(&empty, "Headliner"); got != "Headliner" {
+		t.Errorf("ShowName(empty title) = %q", got)
+	}
+	if got := ShowName(nil, ""); got != "" {
+		t.Errorf("ShowName(nil) = %q", got)
+	}
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Channel kinds.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelWebPush = "web_push"
+)
+
+// Channel frequencies.
+const (
+	FrequencyInstant = "instant"
+	FrequencyDaily   = "daily"
+)
+
+// MaxDigestSize is the most notifications delivered in one digest; the rest
+// follow in the next dispatch.
+const MaxDigestSize = 50
+
+// UnsubscribePath is the frontend page that exchanges an unsubscribe token.
+const UnsubscribePath = "/unsubscribe"
+
+// ErrChannelGone is returned by a Channel when the destination no longer
+// exists (e.g. an expired push subscription); the channel is disabled.
+var ErrChannelGone = errors.New("notification channel is gone")
+
+// Destination is where a channel delivers.
+type Destination struct {
+	ChannelID  int32
+	Target     string // Email address, webhook URL or push endpoint
+	P256dh     string // Web Push subscription public key (base64url)
+	AuthSecret string // Web Push subscription auth secret (base64url)
+}
+
+// Channel delivers digests of one kind.
+type Channel interface {
+	Send(ctx context.Context, dest Destination, digest Digest) error
+}
+
+// Dispatcher delivers pending notifications through the registered channels.
+type Dispatcher struct {
+	queries  *db.Queries
+	channels map[string]Channel
+	appURL   string
+}
+
+// NewDispatcher creates a Dispatcher. channels maps channel kinds to their
+// implementation; channels of unregistered kinds are left pending.
+func NewDispatcher(queries *db.Queries, appURL string, channels map[string]Channel) *Dispatcher {
+	return &Dispatcher{
+		queries:  queries,
+		channels: channels,
+		appURL:   appURL,
+	}
+}
+
+// Dispatch queues show reminders and sends one digest to every channel with
+// undelivered notifications, returning the number of digests sent. A failed
+// delivery is logged and retried on the next dispatch.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	reminders, err := QueueReminders(ctx, d.queries)
+	if err != nil {
+		return 0, err
+	}
+
+	due, err := d.queries.ListDueNotificationChannels(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due channels: %w", err)
+	}
+
+	var sent, failed int
+	for _, c := range due {
+		channel, ok := d.channels[c.Kind]
+		if !ok {
+			continue
+		}
+
+		if err := d.deliver(ctx, channel, c); err != nil {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			slog.Error("failed to deliver notifications",
+				"channel_id", c.ID,
+				"kind", c.Kind,
+				"error", err,
+			)
+			failed++
+			continue
+		}
+		sent++
+	}
+
+	slog.Info("dispatched notifications",
+		"reminders", reminders,
+		"sent", sent,
+		"failed", failed,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return sent, nil
+}
+
+// deliver sends a channel's pending notifications as one digest and advances
+// its cursor.
+func (d *Dispatcher) deliver(ctx context.Context, channel Channel, c db.ListDueNotificationChannelsRow) error {
+	rows, err := d.queries.ListPendingNotifications(ctx, db.ListPendingNotificationsParams{
+		UserID:  c.UserID,
+		AfterID: c.LastNotificationID,
+		Limit:   MaxDigestSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pending notifications: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	digest := Digest{
+		Notifications:  make([]Notification, len(rows)),
+		AppURL:         d.appURL,
+		UnsubscribeURL: UnsubscribeURL(d.appURL, c.UnsubscribeToken),
+	}
+	for i, r := range rows {
+		var band string
+		if r.BandName != nil {
+			band = *r.BandName
+		}
+		digest.Notifications[i] = Notification{
+			ID:        r.ID,
+			Kind:      r.Kind,
+			ShowID:    r.ShowID,
+			ShowName:  ShowName(r.ShowTitle, r.HeadlinerName),
+			ShowDate:  r.ShowDate.Time,
+			VenueName: r.VenueName,
+			BandName:  band,
+		}
+	}
+
+	dest := Destination{ChannelID: c.ID, Target: c.Target}
+	if c.P256dh != nil {
+		dest.P256dh = *c.P256dh
+	}
+	if c.AuthSecret != nil {
+		dest.AuthSecret = *c.AuthSecret
+	}
+
+	err = channel.Send(ctx, dest, digest)
+	if errors.Is(err, ErrChannelGone) {
+		slog.Info("disabling gone notification channel", "channel_id", c.ID, "kind", c.Kind)
+		return d.queries.DisableNotificationChannel(ctx, c.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return d.queries.AdvanceNotificationChannel(ctx, db.AdvanceNotificationChannelParams{
+		ID:                 c.ID,
+		LastNotificationID: rows[len(rows)-1].ID,
+	})
+}
+
+// Run dispatches immediately and then on every interval tick until the
+// context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to dispatch notifications", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UnsubscribeURL builds the frontend unsubscribe link for a channel token.
+func UnsubscribeURL(appURL, token string) string {
+	return strings.TrimRight(appURL, "/") + UnsubscribePath + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,102 @@
+// Package notify tells users about shows they care about.
+//
+// Notifications are created by the write paths (ingestion and show
+// submissions) inside their transactions: followers of a show's bands or
+// venue hear about new scheduled shows, and users who saved a show hear when
+// it is cancelled or postponed. QueueReminders adds a reminder for saved shows
+// starting within a day.
+//
+// Delivery is separate. Each user registers channels (email, webhook, Web
+// Push); a Dispatcher periodically batches every notification a channel has
+// not seen yet into one Digest and hands it to the Channel implementation for
+// its kind. Daily channels get at most one digest a day. Each channel has its
+// own unsubscribe token.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Notification kinds.
+const (
+	KindShowAnnounced = "show_announced"
+	KindShowCancelled = "show_cancelled"
+	KindShowPostponed = "show_postponed"
+	KindShowReminder  = "show_reminder"
+)
+
+// Show statuses that notify users who saved the show.
+const (
+	statusScheduled = "scheduled"
+	statusCancelled = "cancelled"
+	statusPostponed = "postponed"
+)
+
+// ShowAnnounced notifies followers of the show's bands and venue about it if
+// it is upcoming and scheduled. Users already told about the show are skipped,
+// so it is safe to call again when the lineup changes.
+func ShowAnnounced(ctx context.Context, q *db.Queries, showID int32) (int64, error) {
+	count, err := q.CreateShowAnnouncedNotifications(ctx, showID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create announcement notifications: %w", err)
+	}
+	if count > 0 {
+		slog.Info("notified followers of new show", "show_id", showID, "users", count)
+	}
+	return count, nil
+}
+
+// StatusChanged notifies users after shows change status: savers of cancelled
+// or postponed shows are told, and shows restored to scheduled are announced
+// to followers who have not heard about them yet.
+func StatusChanged(ctx context.Context, q *db.Queries, status string, showIDs ...int32) (int64, error) {
+	var kind string
+	switch status {
+	case statusCancelled:
+		kind = KindShowCancelled
+	case statusPostponed:
+		kind = KindShowPostponed
+	case statusScheduled:
+		var total int64
+		for _, id := range showIDs {
+			count, err := ShowAnnounced(ctx, q, id)
+			if err != nil {
+				return total, err
+			}
+			total += count
+		}
+		return total, nil
+	default:
+		return 0, nil
+	}
+
+	if len(showIDs) == 0 {
+		return 0, nil
+	}
+
+	count, err := q.CreateSavedShowNotifications(ctx, db.CreateSavedShowNotificationsParams{
+		Kind:    kind,
+		ShowIds: showIDs,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s notifications: %w", kind, err)
+	}
+	if count > 0 {
+		slog.Info("notified users of saved show change", "kind", kind, "shows", len(showIDs), "notifications", count)
+	}
+	return count, nil
+}
+
+// QueueReminders adds a reminder for every saved show starting within the
+// next day. Each user is reminded once per show.
+func QueueReminders(ctx context.Context, q *db.Queries) (int64, error) {
+	count, err := q.CreateShowReminders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create show reminders: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,146 @@
+package notify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/notify"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+func TestNotifyAndDispatch(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Notify", "test-band-notify")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+
+	user, err := tdb.Queries.UpsertUserLogin(ctx, "notify"+testutil.TestEmailDomain)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if _, err := tdb.Queries.FollowBand(ctx, db.FollowBandParams{UserID: user.ID, Slug: "test-band-notify"}); err != nil {
+		t.Fatalf("failed to follow band: %v", err)
+	}
+
+	email, err := tdb.Queries.UpsertNotificationChannel(ctx, db.UpsertNotificationChannelParams{
+		UserID:           user.ID,
+		Kind:             notify.ChannelEmail,
+		Target:           user.Email,
+		Frequency:        notify.FrequencyInstant,
+		UnsubscribeToken: "test-notify-email",
+	})
+	if err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if _, err := tdb.Queries.UpsertNotificationChannel(ctx, db.UpsertNotificationChannelParams{
+		UserID:           user.ID,
+		Kind:             notify.ChannelWebhook,
+		Target:           "https://hooks.example.com/notify",
+		Frequency:        notify.FrequencyDaily,
+		UnsubscribeToken: "test-notify-webhook",
+	}); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 14), "Notify Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	tdb.LinkBandToShow(ctx, showID, bandID, true, 1)
+
+	count, err := notify.ShowAnnounced(ctx, tdb.Queries, showID)
+	if err != nil {
+		t.Fatalf("ShowAnnounced failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 announcement, got %d", count)
+	}
+
+	// Announcing again (e.g. after a lineup change) does not repeat it
+	if count, _ := notify.ShowAnnounced(ctx, tdb.Queries, showID); count != 0 {
+		t.Errorf("expected repeat announcement to be skipped, got %d", count)
+	}
+
+	emailChannel, webhookChannel := &notify.FakeChannel{}, &notify.FakeChannel{}
+	dispatcher := notify.NewDispatcher(tdb.Queries, "http://app.test", map[string]notify.Channel{
+		notify.ChannelEmail:   emailChannel,
+		notify.ChannelWebhook: webhookChannel,
+	})
+
+	if _, err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+
+	deliveries := emailChannel.Deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("expected 1 email digest, got %d", len(deliveries))
+	}
+	got := deliveries[0]
+	if got.Destination.ChannelID != email.ID || got.Destination.Target != user.Email {
+		t.Errorf("unexpected destination %+v", got.Destination)
+	}
+	if len(got.Digest.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(got.Digest.Notifications))
+	}
+	n := got.Digest.Notifications[0]
+	if n.Kind != notify.KindShowAnnounced || n.ShowID != showID || n.BandName != "Test Band Notify" {
+		t.Errorf("unexpected notification %+v", n)
+	}
+	if got.Digest.UnsubscribeURL != "http://app.test/unsubscribe?token=test-notify-email" {
+		t.Errorf("UnsubscribeURL = %q", got.Digest.UnsubscribeURL)
+	}
+
+	// The daily channel was just created, so its first digest waits a day
+	if len(webhookChannel.Deliveries()) != 0 {
+		t.Errorf("expected daily channel to wait, got %d digests", len(webhookChannel.Deliveries()))
+	}
+
+	// Delivered notifications are not sent again
+	if _, err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if len(emailChannel.Deliveries()) != 1 {
+		t.Errorf("expected no new digests, got %d", len(emailChannel.Deliveries()))
+	}
+
+	// Cancelling tells users who saved the show
+	if _, err := tdb.Queries.SaveShow(ctx, db.SaveShowParams{UserID: user.ID, ShowID: showID}); err != nil {
+		t.Fatalf("failed to save show: %v", err)
+	}
+	count, err = notify.StatusChanged(ctx, tdb.Queries, "cancelled", showID)
+	if err != nil {
+		t.Fatalf("StatusChanged failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 cancellation notice, got %d", count)
+	}
+
+	// A gone destination disables the channel instead of retrying forever
+	emailChannel.Err = notify.ErrChannelGone
+	if _, err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	channels, err := tdb.Queries.ListNotificationChannels(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("failed to list channels: %v", err)
+	}
+	for _, c := range channels {
+		if c.ID == email.ID {
+			t.Error("expected gone email channel to be disabled")
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Web Push parameters.
+const (
+	// PushTTL is how long push services keep an undelivered message.
+	PushTTL = 24 * time.Hour
+
+	// pushRecordSize is the aes128gcm record size; payloads fit in one record.
+	pushRecordSize = 4096
+
+	// maxPushPayload is the largest plaintext that fits in one record after
+	// the header (86 bytes), padding delimiter and AEAD tag.
+	maxPushPayload = 3993
+
+	// vapidTokenTTL is the lifetime of the signed VAPID token.
+	vapidTokenTTL = 12 * time.Hour
+)
+
+// WebPushChannel delivers digests as Web Push notifications (RFC 8030),
+// encrypted for the subscription (RFC 8291) and identified with VAPID
+// (RFC 8292). The service worker receives JSON with title, body and url.
+type WebPushChannel struct {
+	publicKey  string // base64url uncompressed P-256 point
+	privateKey *ecdsa.PrivateKey
+	subject    string
+	client     *http.Client
+}
+
+// NewWebPushChannel creates a Web Push channel from VAPID keys. A nil client
+// defaults to NewPublicHTTPClient.
+func NewWebPushChannel(vapid VAPIDConfig, client *http.Client) (*WebPushChannel, error) {
+	d, err := decodeBase64URL(vapid.PrivateKey)
+	if err != nil || len(d) != 32 {
+		return nil, errors.New("VAPID private key must be a base64url-encoded 32-byte P-256 key")
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public := key.PublicKey().Bytes()
+
+	if vapid.PublicKey != "" {
+		given, err := decodeBase64URL(vapid.PublicKey)
+		if err != nil || !bytes.Equal(given, public) {
+			return nil, errors.New("VAPID public key does not match the private key")
+		}
+	}
+
+	if !strings.HasPrefix(vapid.Subject, "mailto:") && !strings.HasPrefix(vapid.Subject, "https:") {
+		return nil, errors.New("VAPID subject must be a mailto: or https: URL")
+	}
+
+	if client == nil {
+		client = NewPublicHTTPClient(DeliveryTimeout)
+	}
+
+	return &WebPushChannel{
+		publicKey: base64.RawURLEncoding.EncodeToString(public),
+		privateKey: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(public[1:33]),
+				Y:     new(big.Int).SetBytes(public[33:]),
+			},
+			D: new(big.Int).SetBytes(d),
+		},
+		subject: vapid.Subject,
+		client:  client,
+	}, nil
+}
+
+// PublicKey returns the application server key browsers subscribe with.
+func (c *WebPushChannel) PublicKey() string {
+	return c.publicKey
+}
+
+// pushMessage is the JSON payload handed to the service worker.
+type pushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+}
+
+// Send encrypts the digest for the subscription and posts it to the push
+// service. 404 and 410 responses mean the subscription expired.
+func (c *WebPushChannel) Send(ctx context.Context, dest Destination, digest Digest) error {
+	uaPublic, err := decodeBase64URL(dest.P256dh)
+	if err != nil {
+		return fmt.Errorf("invalid subscription key: %w", err)
+	}
+	authSecret, err := decodeBase64URL(dest.AuthSecret)
+	if err != nil {
+		return fmt.Errorf("invalid subscription auth secret: %w", err)
+	}
+
+	plaintext, err := json.Marshal(pushSummary(digest))
+	if err != nil {
+		return fmt.Errorf("failed to encode push message: %w", err)
+	}
+
+	body, err := encryptPushPayload(plaintext, uaPublic, authSecret)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.vapidToken(dest.Target, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dest.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(PushTTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, c.publicKey))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return checkDeliveryStatus(resp.StatusCode)
+}
+
+// pushSummary condenses a digest into a single notification.
+func pushSummary(digest Digest) pushMessage {
+	msg := pushMessage{
+		Title: "The Asheville Setlist",
+		Body:  digest.Subject(),
+		URL:   strings.TrimRight(digest.AppURL, "/") + "/",
+	}
+	if len(digest.Notifications) == 1 {
+		msg.URL = digest.ShowURL(digest.Notifications[0])
+	} else if len(digest.Notifications) > 1 {
+		msg.Body = fmt.Sprintf("%s and %d more", digest.Notifications[0].Message(), len(digest.Notifications)-1)
+	}
+	return msg
+}
+
+// vapidToken signs an ES256 JWT for the push service's origin.
+func (c *WebPushChannel) vapidToken(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTokenTTL).Unix(),
+		"sub": c.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, c.privateKey, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// encryptPushPayload encrypts plaintext for a subscription with a fresh
+// ephemeral key and salt (RFC 8291, aes128gcm content coding).
+func encryptPushPayload(plaintext, uaPublic, authSecret []byte) ([]byte, error) {
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate push key: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate push salt: %w", err)
+	}
+	return encryptPushPayloadWith(plaintext, uaPublic, authSecret, asPrivate, salt)
+}
+
+// encryptPushPayloadWith is encryptPushPayload with the ephemeral key and
+// salt supplied.
+func encryptPushPayloadWith(plaintext, uaPublic, authSecret []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(plaintext) > maxPushPayload {
+		return nil, fmt.Errorf("push payload is %d bytes, max %d", len(plaintext), maxPushPayload)
+	}
+	if len(authSecret) != 16 {
+		return nil, errors.New("subscription auth secret must be 16 bytes")
+	}
+
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	sharedSecret, err := asPrivate.ECDH(uaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive push secret: %w", err)
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	cek, nonce := pushContentKeys(sharedSecret, authSecret, uaPublic, asPublic, salt)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Single record: plaintext followed by the last-record padding delimiter
+	record := append(append([]byte{}, plaintext...), 0x02)
+
+	header := make([]byte, 0, 21+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, pushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// pushContentKeys derives the content encryption key and nonce (RFC 8291 §3.4).
+func pushContentKeys(sharedSecret, authSecret, uaPublic, asPublic, salt []byte) (cek, nonce []byte) {
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdfExpand(hkdfExtract(authSecret, sharedSecret), keyInfo, 32)
+
+	prk := hkdfExtract(salt, ikm)
+	cek = hkdfExpand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce = hkdfExpand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	return cek, nonce
+}
+
+// hkdfExtract is HKDF-Extract with SHA-256 (RFC 5869).
+func hkdfExtract(salt, ikm []byte) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand is HKDF-Expand with SHA-256 for outputs of at most one block.
+func hkdfExpand(prk, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{0x01})
+	return mac.Sum(nil)[:length]
+}
+
+// ValidatePushSubscription checks the keys of a browser push subscription.
+func ValidatePushSubscription(p256dh, authSecret string) error {
+	key, err := decodeBase64URL(p256dh)
+	if err != nil {
+		return errors.New("p256dh must be base64url")
+	}
+	if _, err := ecdh.P256().NewPublicKey(key); err != nil {
+		return errors.New("p256dh must be an uncompressed P-256 public key")
+	}
+	auth, err := decodeBase64URL(authSecret)
+	if err != nil || len(auth) != 16 {
+		return errors.New("auth must be 16 bytes of base64url")
+	}
+	return nil
+}
+
+// decodeBase64URL decodes base64url with or without padding.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSubscription is a browser-side push subscription.
+type testSubscription struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newTestSubscription(t *testing.T) testSubscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return testSubscription{key: key, auth: auth}
+}
+
+func (s testSubscription) destination(endpoint string) Destination {
+	return Destination{
+		Target:     endpoint,
+		P256dh:     base64.RawURLEncoding.EncodeToString(s.key.PublicKey().Bytes()),
+		AuthSecret: base64.RawURLEncoding.EncodeToString(s.auth),
+	}
+}
+
+// decrypt is the user agent side of RFC 8291.
+func (s testSubscription) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	if len(body) < 21 {
+		t.Fatalf("body too short: %d bytes", len(body))
+	}
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != pushRecordSize {
+		t.Errorf("record size = %d, want %d", rs, pushRecordSize)
+	}
+	idLen := int(body[20])
+	asPublic := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatalf("invalid key id: %v", err)
+	}
+	shared, err := s.key.ECDH(asKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cek, nonce := pushContentKeys(shared, s.auth, s.key.PublicKey().Bytes(), asPublic, salt)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	record, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if record[len(record)-1] != 0x02 {
+		t.Fatalf("missing last-record delimiter")
+	}
+	return record[:len(record)-1]
+}
+
+func newTestVAPID(t *testing.T) VAPIDConfig {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return VAPIDConfig{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		Subject:    "mailto:ops@example.com",
+	}
+}
+
+func TestEncryptPushPayload_RoundTrip(t *testing.T) {
+	sub := newTestSubscription(t)
+	plaintext := []byte(`{"title":"hi"}`)
+
+	body, err := encryptPushPayload(plaintext, sub.key.PublicKey().Bytes(), sub.auth)
+	if err != nil {
+		t.Fatalf("encryptPushPayload: %v", err)
+	}
+	if got := sub.decrypt(t, body); string(got) != string(plaintext) {
+		t.Errorf("decrypted %q, want %q", got, plaintext)
+	}
+
+	if _, err := encryptPushPayload(make([]byte, maxPushPayload+1), sub.key.PublicKey().Bytes(), sub.auth); err == nil {
+		t.Error("expected error for oversized payload")
+	}
+}
+
+func TestNewWebPushChannel_Validation(t *testing.T) {
+	vapid := newTestVAPID(t)
+
+	if _, err := NewWebPushChannel(vapid, nil); err != nil {
+		t.Fatalf("valid keys: %v", err)
+	}
+
+	mismatched := vapid
+	mismatched.PublicKey = newTestVAPID(t).PublicKey
+	if _, err := NewWebPushChannel(mismatched, nil); err == nil {
+		t.Error("mismatched public key: expected error")
+	}
+
+	noSubject := vapid
+	noSubject.Subject = "ops@example.com"
+	if _, err := NewWebPushChannel(noSubject, nil); err == nil {
+		t.Error("subject without scheme: expected error")
+	}
+
+	if _, err := NewWebPushChannel(VAPIDConfig{PrivateKey: "short", Subject: "mailto:a@b.c"}, nil); err == nil {
+		t.Error("malformed private key: expected error")
+	}
+}
+
+func TestWebPushChannel_Send(t *testing.T) {
+	vapid := newTestVAPID(t)
+	sub := newTestSubscription(t)
+
+	var body []byte
+	var header http.Header
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	channel, err := NewWebPushChannel(vapid, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := Digest{
+		Notifications: []Notification{{ID: 1, Kind: KindShowReminder, ShowID: 7, ShowName: "Spring Fling", VenueName: "The Orange Peel", ShowDate: time.Now()}},
+		AppURL:        "http://app.test",
+	}
+	if err := channel.Send(context.Background(), sub.destination(server.URL+"/push/abc"), digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got := header.Get("Content-Encoding"); got != "aes128gcm" {
+		t.Errorf("Content-Encoding = %q", got)
+	}
+	if header.Get("TTL") == "" {
+		t.Error("missing TTL header")
+	}
+
+	var msg pushMessage
+	if err := json.Unmarshal(sub.decrypt(t, body), &msg); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if msg.URL != "http://app.test/shows/7" || msg.Body != digest.Subject() {
+		t.Errorf("unexpected push message %+v", msg)
+	}
+
+	verifyVAPIDHeader(t, header.Get("Authorization"), vapid, server.URL)
+
+	status = http.StatusGone
+	if err := channel.Send(context.Background(), sub.destination(server.URL), digest); !errors.Is(err, ErrChannelGone) {
+		t.Errorf("410 response: expected ErrChannelGone, got %v", err)
+	}
+}
+
+// verifyVAPIDHeader checks the "vapid t=..., k=..." header's key and ES256 signature.
+func verifyVAPIDHeader(t *testing.T, value string, vapid VAPIDConfig, audience string) {
+	t.Helper()
+
+	token, key, ok := strings.Cut(strings.TrimPrefix(value, "vapid t="), ", k=")
+	if !ok || key != vapid.PublicKey {
+		t.Fatalf("unexpected Authorization header %q", value)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts", len(parts))
+	}
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	json.Unmarshal(claimsJSON, &claims)
+	if claims.Aud != audience || claims.Sub != vapid.Subject || claims.Exp <= time.Now().Unix() {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	public, _ := base64.RawURLEncoding.DecodeString(vapid.PublicKey)
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(public[1:33]),
+		Y:     new(big.Int).SetBytes(public[33:]),
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(pub, hash[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		t.Error("VAPID token signature does not verify")
+	}
+}
+
+func TestValidatePushSubscription(t *testing.T) {
+	dest := newTestSubscription(t).destination("")
+	if err := ValidatePushSubscription(dest.P256dh, dest.AuthSecret); err != nil {
+		t.Errorf("valid subscription: %v", err)
+	}
+	if err := ValidatePushSubscription("bm90LWEta2V5", dest.AuthSecret); err == nil {
+		t.Error("bad p256dh: expected error")
+	}
+	if err := ValidatePushSubscription(dest.P256dh, "c2hvcnQ"); err == nil {
+		t.Error("short auth: expected error")
+	}
+}
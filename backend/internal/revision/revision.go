@@ -0,0 +1,161 @@
+// Package revision records field-level changes to shows in show_revisions.
+//
+// Every write path that changes a show (scraper ingestion, band submissions,
+// admin edits and background maintenance) describes its changes as a Diff and
+// records it alongside the change, in the same transaction where possible.
+package revision
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Revision sources (mirrors check_revision_source_valid in the schema).
+const (
+	SourceScraped       = "scraped"
+	SourceBandSubmitted = "band_submitted"
+	SourceAdmin         = "admin"
+	SourceSystem        = "system"
+)
+
+// Tracked show fields.
+const (
+	FieldTitle         = "title"
+	FieldDate          = "date"
+	FieldPriceMin      = "price_min"
+	FieldPriceMax      = "price_max"
+	FieldTicketURL     = "ticket_url"
+	FieldStatus        = "status"
+	FieldLineup        = "lineup"
+	FieldRescheduledTo = "rescheduled_to"
+)
+
+// Actor identifies who made a change: a scraper source name, a submitter or
+// an admin, together with the kind of write path it came through.
+type Actor struct {
+	Name   string
+	Source string
+}
+
+// BandSubmission is the actor for public show submissions. Submitters are
+// anonymous, so nothing identifying them is recorded.
+var BandSubmission = Actor{Name: "band submission", Source: SourceBandSubmitted}
+
+// change is a single changed field with JSON-encoded values (nil = unset).
+type change struct {
+	field    string
+	oldValue []byte
+	newValue []byte
+}
+
+// Diff collects the fields that changed on a show.
+type Diff struct {
+	changes []change
+}
+
+// Add records a field change if old and new differ. Values are compared by
+// their JSON encoding; nil pointers encode as unset and times are compared
+// in UTC at database (microsecond) precision.
+func (d *Diff) Add(field string, oldValue, newValue any) error {
+	oldJSON, err := encode(oldValue)
+	if err != nil {
+		return fmt.Errorf("failed to encode old %s: %w", field, err)
+	}
+	newJSON, err := encode(newValue)
+	if err != nil {
+		return fmt.Errorf("failed to encode new %s: %w", field, err)
+	}
+
+	if bytes.Equal(oldJSON, newJSON) {
+		return nil
+	}
+
+	d.changes = append(d.changes, change{field: field, oldValue: oldJSON, newValue: newJSON})
+	return nil
+}
+
+// Empty reports whether no fields changed.
+func (d *Diff) Empty() bool {
+	return len(d.changes) == 0
+}
+
+// Fields returns the names of the changed fields in the order they were added.
+func (d *Diff) Fields() []string {
+	fields := make([]string, 0, len(d.changes))
+	for _, c := range d.changes {
+		fields = append(fields, c.field)
+	}
+	return fields
+}
+
+// Record writes the diff for a show. Pass queries bound to the transaction
+// that made the change so the revision commits or rolls back with it.
+func Record(ctx context.Context, queries *db.Queries, showID int32, actor Actor, diff *Diff) error {
+	var name *string
+	if actor.Name != "" {
+		name = &actor.Name
+	}
+
+	for _, c := range diff.changes {
+		if err := queries.CreateShowRevision(ctx, db.CreateShowRevisionParams{
+			ShowID:   showID,
+			Field:    c.field,
+			OldValue: c.oldValue,
+			NewValue: c.newValue,
+			Actor:    name,
+			Source:   actor.Source,
+		}); err != nil {
+			return fmt.Errorf("failed to record %s revision for show %d: %w", c.field, showID, err)
+		}
+	}
+
+	return nil
+}
+
+// RecordStatus writes the same status change for many shows.
+func RecordStatus(ctx context.Context, queries *db.Queries, showIDs []int32, oldStatus, newStatus string, actor Actor) error {
+	if len(showIDs) == 0 {
+		return nil
+	}
+
+	var name *string
+	if actor.Name != "" {
+		name = &actor.Name
+	}
+
+	if err := queries.CreateStatusRevisions(ctx, db.CreateStatusRevisionsParams{
+		ShowIds:   showIDs,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Actor:     name,
+		Source:    actor.Source,
+	}); err != nil {
+		return fmt.Errorf("failed to record status revisions: %w", err)
+	}
+
+	return nil
+}
+
+// encode marshals a value for storage, returning nil for unset values.
+func encode(v any) ([]byte, error) {
+	if t, ok := v.(time.Time); ok {
+		if t.IsZero() {
+			return nil, nil
+		}
+		v = t.UTC().Truncate(time.Microsecond)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(b, []byte("null")) {
+		return nil, nil
+	}
+	return b, nil
+}
@@ -0,0 +1,60 @@
+package revision
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffAdd(t *testing.T) {
+	price := 15.0
+	otherPrice := 20.0
+	date := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	loc := time.FixedZone("EDT", -4*60*60)
+
+	tests := []struct {
+		name     string
+		old      any
+		new      any
+		wantDiff bool
+	}{
+		{"same string", "scheduled", "scheduled", false},
+		{"changed string", "scheduled", "cancelled", true},
+		{"same price", &price, &price, false},
+		{"changed price", &price, &otherPrice, true},
+		{"price removed", &price, (*float64)(nil), true},
+		{"both unset", (*float64)(nil), nil, false},
+		{"same instant in another zone", date, date.In(loc), false},
+		{"date moved", date, date.AddDate(0, 0, 7), true},
+		{"same lineup", []string{"A", "B"}, []string{"A", "B"}, false},
+		{"lineup changed", []string{"A", "B"}, []string{"A"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Diff
+			if err := d.Add("field", tt.old, tt.new); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+			if got := !d.Empty(); got != tt.wantDiff {
+				t.Errorf("changed = %v, want %v", got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestDiffAdd_UnsetEncodesAsNil(t *testing.T) {
+	var d Diff
+	if err := d.Add("price_min", nil, 10.5); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if len(d.changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(d.changes))
+	}
+	if d.changes[0].oldValue != nil {
+		t.Errorf("expected nil old value, got %s", d.changes[0].oldValue)
+	}
+	if string(d.changes[0].newValue) != "10.5" {
+		t.Errorf("expected new value 10.5, got %s", d.changes[0].newValue)
+	}
+}
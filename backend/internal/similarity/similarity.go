@@ -0,0 +1,185 @@
+// Package similarity ranks bands by how alike they are.
+//
+// A pair's score blends four components, each between 0 and 1: Jaccard
+// overlap of their genres, how often they share a bill (shared shows over the
+// shows of the less active band), Jaccard overlap of the venues they play and
+// whether they share a hometown. Candidates must share a genre or a show.
+// Scores for every band are precomputed into band_similarity by Refresh,
+// which runs after each scrape that changes shows; Score computes one band's
+// list live for bands the last refresh has not seen yet.
+package similarity
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// MaxPerBand is how many similar bands are stored for each band.
+const MaxPerBand = 50
+
+// Weights controls how much each component contributes to the blended score.
+// Weights are normalized to sum to 1 before scoring.
+type Weights struct {
+	Genre    float64
+	CoBill   float64
+	Venue    float64
+	Hometown float64
+}
+
+// DefaultWeights leans on genres while letting co-billing separate bands
+// within a popular genre.
+var DefaultWeights = Weights{
+	Genre:    0.45,
+	CoBill:   0.3,
+	Venue:    0.15,
+	Hometown: 0.1,
+}
+
+// ParseWeights parses a comma-separated list of component weights such as
+// "genre=0.5,co_bill=0.3,venue=0.15,hometown=0.05". Components left out keep
+// their DefaultWeights value. An empty string returns DefaultWeights.
+func ParseWeights(s string) (Weights, error) {
+	w := DefaultWeights
+	if strings.TrimSpace(s) == "" {
+		return w, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Weights{}, fmt.Errorf("invalid weight %q, expected component=value", part)
+		}
+
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || f < 0 {
+			return Weights{}, fmt.Errorf("weight for %q must be a non-negative number, got %q", key, value)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "genre":
+			w.Genre = f
+		case "co_bill":
+			w.CoBill = f
+		case "venue":
+			w.Venue = f
+		case "hometown":
+			w.Hometown = f
+		default:
+			return Weights{}, fmt.Errorf("unknown weight %q, expected one of: genre, co_bill, venue, hometown", key)
+		}
+	}
+
+	if w.sum() == 0 {
+		return Weights{}, fmt.Errorf("at least one weight must be positive")
+	}
+	return w, nil
+}
+
+// Normalized returns the weights scaled to sum to 1, so scores stay in [0, 1].
+// All-zero weights normalize to DefaultWeights.
+func (w Weights) Normalized() Weights {
+	sum := w.sum()
+	if sum == 0 {
+		return DefaultWeights.Normalized()
+	}
+	return Weights{
+		Genre:    w.Genre / sum,
+		CoBill:   w.CoBill / sum,
+		Venue:    w.Venue / sum,
+		Hometown: w.Hometown / sum,
+	}
+}
+
+func (w Weights) sum() float64 {
+	return w.Genre + w.CoBill + w.Venue + w.Hometown
+}
+
+// Refresh recomputes band_similarity for all bands in one transaction and
+// returns the number of stored pairs. Readers see the previous scores until
+// it commits; concurrent refreshes (e.g. a scrape and `scraper similar`) take
+// an advisory lock and run one at a time.
+func Refresh(ctx context.Context, pool *pgxpool.Pool, w Weights) (int64, error) {
+	start := time.Now()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := db.New(pool).WithTx(tx)
+
+	if err := q.LockBandSimilarityRefresh(ctx); err != nil {
+		return 0, fmt.Errorf("failed to lock band similarities: %w", err)
+	}
+
+	rows, err := q.ScoreSimilarBands(ctx, scoreParams([]int32{}, w, MaxPerBand))
+	if err != nil {
+		return 0, fmt.Errorf("failed to score similar bands: %w", err)
+	}
+
+	if err := q.DeleteBandSimilarities(ctx); err != nil {
+		return 0, fmt.Errorf("failed to clear band similarities: %w", err)
+	}
+
+	params := make([]db.InsertBandSimilaritiesParams, len(rows))
+	for i, r := range rows {
+		params[i] = db.InsertBandSimilaritiesParams{
+			BandID:           r.BandID,
+			SimilarBandID:    r.SimilarBandID,
+			Score:            r.Score,
+			GenreScore:       r.GenreScore,
+			CoBillScore:      r.CoBillScore,
+			VenueScore:       r.VenueScore,
+			HometownScore:    r.HometownScore,
+			SharedGenreCount: r.SharedGenreCount,
+			SharedShowCount:  r.SharedShowCount,
+			SharedVenueCount: r.SharedVenueCount,
+		}
+	}
+
+	count, err := q.InsertBandSimilarities(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store band similarities: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit band similarities: %w", err)
+	}
+
+	slog.Info("refreshed band similarity",
+		"pairs", count,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return count, nil
+}
+
+// Score computes a single band's similar bands without touching the
+// precomputed table, best match first.
+func Score(ctx context.Context, q *db.Queries, bandID int32, w Weights, limit int) ([]db.ScoreSimilarBandsRow, error) {
+	rows, err := q.ScoreSimilarBands(ctx, scoreParams([]int32{bandID}, w, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to score similar bands: %w", err)
+	}
+	return rows, nil
+}
+
+// scoreParams builds ScoreSimilarBands parameters from normalized weights.
+func scoreParams(bandIDs []int32, w Weights, limit int) db.ScoreSimilarBandsParams {
+	n := w.Normalized()
+	return db.ScoreSimilarBandsParams{
+		BandIds:        bandIDs,
+		PerBandLimit:   int32(limit),
+		GenreWeight:    n.Genre,
+		CoBillWeight:   n.CoBill,
+		VenueWeight:    n.Venue,
+		HometownWeight: n.Hometown,
+	}
+}
@@ -0,0 +1,174 @@
+package similarity_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/similarity"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+)
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    similarity.Weights
+		wantErr bool
+	}{
+		{in: "", want: similarity.DefaultWeights},
+		{in: "genre=1,co_bill=0,venue=0,hometown=0", want: similarity.Weights{Genre: 1}},
+		{
+			in: " co_bill = 0.9 ",
+			want: similarity.Weights{
+				Genre:    similarity.DefaultWeights.Genre,
+				CoBill:   0.9,
+				Venue:    similarity.DefaultWeights.Venue,
+				Hometown: similarity.DefaultWeights.Hometown,
+			},
+		},
+		{in: "genre", wantErr: true},
+		{in: "genre=-1", wantErr: true},
+		{in: "genre=abc", wantErr: true},
+		{in: "popularity=1", wantErr: true},
+		{in: "genre=0,co_bill=0,venue=0,hometown=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := similarity.ParseWeights(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightsNormalized(t *testing.T) {
+	n := similarity.Weights{Genre: 2, CoBill: 1, Venue: 1}.Normalized()
+	if n.Genre != 0.5 || n.CoBill != 0.25 || n.Venue != 0.25 || n.Hometown != 0 {
+		t.Errorf("unexpected normalized weights %+v", n)
+	}
+
+	d := similarity.DefaultWeights.Normalized()
+	if sum := d.Genre + d.CoBill + d.Venue + d.Hometown; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expected default weights to sum to 1, got %f", sum)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+	genreID, err := tdb.GetFirstGenreID(ctx)
+	if err != nil {
+		t.Skipf("no genres in database: %v", err)
+	}
+
+	insert := func(name, slug string) int32 {
+		id, err := tdb.InsertTestBand(ctx, name, slug)
+		if err != nil {
+			t.Fatalf("failed to insert band: %v", err)
+		}
+		if err := tdb.AddGenreToBand(ctx, id, genreID); err != nil {
+			t.Fatalf("failed to add genre: %v", err)
+		}
+		return id
+	}
+	source := insert("Test Band Similarity Source", "test-band-similarity-source")
+	touring := insert("Test Band Similarity Touring Mate", "test-band-similarity-touring-mate")
+	genreOnly := insert("Test Band Similarity Genre Only", "test-band-similarity-genre-only")
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Similarity Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	tdb.LinkBandToShow(ctx, showID, source, true, 2)
+	tdb.LinkBandToShow(ctx, showID, touring, false, 1)
+
+	count, err := similarity.Refresh(ctx, tdb.Pool, similarity.DefaultWeights)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if count == 0 {
+		t.Fatal("expected stored band pairs")
+	}
+
+	rows, err := tdb.Pool.Query(ctx, `
+		SELECT similar_band_id, score, co_bill_score, shared_show_count
+		FROM band_similarity
+		WHERE band_id = $1
+		ORDER BY score DESC
+	`, source)
+	if err != nil {
+		t.Fatalf("failed to query band_similarity: %v", err)
+	}
+	defer rows.Close()
+
+	scores := map[int32]float64{}
+	var order []int32
+	for rows.Next() {
+		var id, sharedShows int32
+		var score, coBill float64
+		if err := rows.Scan(&id, &score, &coBill, &sharedShows); err != nil {
+			t.Fatalf("failed to scan row: %v", err)
+		}
+		scores[id] = score
+		order = append(order, id)
+
+		if id == touring && (coBill != 1 || sharedShows != 1) {
+			t.Errorf("expected full co-bill overlap with touring mate, got score %f over %d shows", coBill, sharedShows)
+		}
+		if score < 0 || score > 1 {
+			t.Errorf("band %d: score %f out of range", id, score)
+		}
+	}
+
+	if _, ok := scores[genreOnly]; !ok {
+		t.Fatal("expected genre-only band to be similar")
+	}
+	if len(order) == 0 || order[0] != touring {
+		t.Errorf("expected co-billed band to rank first, got order %v", order)
+	}
+	if scores[touring] <= scores[genreOnly] {
+		t.Errorf("expected co-billing to break the genre tie: %f <= %f", scores[touring], scores[genreOnly])
+	}
+}
+
+func TestRefresh_Concurrent(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+
+	// Overlapping refreshes wait for each other instead of colliding on insert
+	errs := make(chan error, 3)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := similarity.Refresh(ctx, tdb.Pool, similarity.DefaultWeights)
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent Refresh failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reconnectDelay is how long the Hub waits before listening again after
+// losing its connection.
+const reconnectDelay = 5 * time.Second
+
+// Hub listens for stream events on a dedicated connection and wakes every
+// subscriber when one arrives. Wake-ups carry no data and coalesce:
+// subscribers read whatever is new since their own cursor.
+type Hub struct {
+	pool *pgxpool.Pool
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	done        chan struct{}
+}
+
+// NewHub creates a Hub. Call Run to start listening.
+func NewHub(pool *pgxpool.Pool) *Hub {
+	return &Hub{
+		pool:        pool,
+		subscribers: make(map[chan struct{}]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives a value whenever new events may
+// be available, and a function that unsubscribes.
+func (h *Hub) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Done is closed when Run returns, telling subscribers to disconnect.
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Run listens until the context is cancelled, reconnecting after errors.
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+
+	for {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("show stream listener failed", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// listen takes a connection out of the pool, LISTENs on Channel and
+// broadcasts every notification until an error occurs.
+func (h *Hub) listen(ctx context.Context) error {
+	pooled, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// LISTEN state must not leak back into the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+
+	// Events may have been written while the Hub was not listening
+	h.broadcast()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		h.broadcast()
+	}
+}
+
+// broadcast wakes every subscriber without blocking on slow ones.
+func (h *Hub) broadcast() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
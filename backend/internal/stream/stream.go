@@ -0,0 +1,46 @@
+// Package stream pushes newly announced and changed shows to live clients.
+//
+// The write paths (ingestion and show submissions) record an event with
+// Publish inside their transactions. Postgres sends it on the show_stream
+// NOTIFY channel when the transaction commits, and a Hub in every API replica
+// LISTENs on that channel and wakes its subscribers, which read the events
+// (and the current state of their shows) from the database. Events are kept
+// for Retention so clients can resume after a reconnect.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Channel is the Postgres NOTIFY channel events are announced on.
+const Channel = "show_stream"
+
+// Retention is how long events are kept for clients resuming with
+// Last-Event-ID.
+const Retention = 24 * time.Hour
+
+// Event types.
+const (
+	EventShowCreated   = "show.created"
+	EventShowUpdated   = "show.updated"
+	EventShowCancelled = "show.cancelled"
+)
+
+// Publish records a stream event for each show. Listeners are woken when the
+// surrounding transaction commits.
+func Publish(ctx context.Context, q *db.Queries, eventType string, showIDs ...int32) error {
+	for _, id := range showIDs {
+		err := q.CreateShowStreamEvent(ctx, db.CreateShowStreamEventParams{
+			ShowID:    id,
+			EventType: eventType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish %s stream event: %w", eventType, err)
+		}
+	}
+	return nil
+}
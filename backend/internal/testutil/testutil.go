@@ -69,11 +69,39 @@ func (tdb *TestDB) Close() {
 // TestShowTitlePrefix is used to identify test shows for cleanup
 const TestShowTitlePrefix = "[TEST] "
 
+// TestEmailDomain is used to identify test users for cleanup
+const TestEmailDomain = "@test.example.com"
+
 // CleanupTestData removes test data created during tests
-// This deletes shows with test title prefix and bands with "Test Band" prefix
+// This deletes shows with test title prefix, bands with "Test Band" prefix,
+// users with TestEmailDomain addresses and webhook subscriptions described
+// with the test title prefix
 func (tdb *TestDB) CleanupTestData(ctx context.Context) error {
+	// Delete test users (sessions, saved shows and follows cascade) and their login tokens
+	_, err := tdb.Pool.Exec(ctx, `DELETE FROM users WHERE email LIKE '%' || $1`, TestEmailDomain)
+	if err != nil {
+		return fmt.Errorf("failed to delete test users: %w", err)
+	}
+	_, err = tdb.Pool.Exec(ctx, `DELETE FROM login_tokens WHERE email LIKE '%' || $1`, TestEmailDomain)
+	if err != nil {
+		return fmt.Errorf("failed to delete test login tokens: %w", err)
+	}
+
+	// Delete test webhook subscriptions (deliveries cascade) and events about test shows and bands
+	_, err = tdb.Pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE description LIKE '[TEST]%'`)
+	if err != nil {
+		return fmt.Errorf("failed to delete test webhook subscriptions: %w", err)
+	}
+	_, err = tdb.Pool.Exec(ctx, `
+		DELETE FROM webhook_events
+		WHERE payload->>'title' LIKE '[TEST]%' OR payload->>'name' LIKE 'Test Band%'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to delete test webhook events: %w", err)
+	}
+
 	// Delete show_bands for test shows first (due to FK constraints)
-	_, err := tdb.Pool.Exec(ctx, `
+	_, err = tdb.Pool.Exec(ctx, `
 		DELETE FROM show_bands
 		WHERE show_id IN (SELECT id FROM shows WHERE title LIKE '[TEST]%')
 	`)
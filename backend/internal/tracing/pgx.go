@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer is a pgx.QueryTracer that records a client span per query.
+// Queries are only traced inside an existing span (a request), so background
+// jobs and listeners don't produce a root span for every statement.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+var _ pgx.QueryTracer = (*QueryTracer)(nil)
+
+// NewQueryTracer returns a query tracer using tp.
+func NewQueryTracer(tp trace.TracerProvider) *QueryTracer {
+	return &QueryTracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	ctx, _ = t.tracer.Start(ctx, QueryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	span.End()
+}
+
+// QueryName returns the sqlc query name from a "-- name: GetShowByID :one"
+// header, or "db.query" for other SQL.
+func QueryName(sql string) string {
+	header, _, _ := strings.Cut(sql, "\n")
+	name, ok := strings.CutPrefix(strings.TrimSpace(header), "-- name: ")
+	if !ok {
+		return "db.query"
+	}
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
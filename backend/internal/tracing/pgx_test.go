@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"-- name: GetShowByID :one\n\nSELECT 1": "GetShowByID",
+		"-- name: CompletePastShows :execrows":  "CompletePastShows",
+		"SELECT 1":                              "db.query",
+		"":                                      "db.query",
+	}
+	for sql, want := range tests {
+		if got := QueryName(sql); got != want {
+			t.Errorf("QueryName(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	provider, exporter := newTestProvider()
+	tracer := NewQueryTracer(provider)
+	ctx := context.Background()
+
+	// Without a parent span nothing is recorded
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: ListBands :many\nSELECT 1"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+	if n := len(exporter.GetSpans()); n != 0 {
+		t.Fatalf("expected no spans outside a request, got %d", n)
+	}
+
+	ctx, parent := provider.Tracer("test").Start(ctx, "GET /api/bands")
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: ListBands :many\nSELECT 1"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 3")})
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT broken"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("syntax error")})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	list := spans[0]
+	if list.Name != "ListBands" || list.SpanKind != trace.SpanKindClient {
+		t.Errorf("expected client span ListBands, got %s %v", list.Name, list.SpanKind)
+	}
+	if list.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected query span to be a child of the request span")
+	}
+	attrs := map[string]any{}
+	for _, kv := range list.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs["db.system.name"] != "postgresql" || attrs["db.response.rows_affected"] != int64(3) {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+
+	if broken := spans[1]; broken.Name != "db.query" || broken.Status.Code != codes.Error {
+		t.Errorf("expected failed db.query span, got %s %v", broken.Name, broken.Status)
+	}
+}
+
+func TestQueryTracer_Database(t *testing.T) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set, skipping integration test")
+	}
+
+	provider, exporter := newTestProvider()
+	ctx := context.Background()
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("failed to parse database URL: %v", err)
+	}
+	poolConfig.ConnConfig.Tracer = NewQueryTracer(provider)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, parent := provider.Tracer("test").Start(ctx, "GET /api/genres")
+	if _, err := db.New(pool).GetCatalogLastModified(ctx); err != nil {
+		t.Fatalf("GetCatalogLastModified: %v", err)
+	}
+	parent.End()
+
+	var found bool
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "GetCatalogLastModified" && span.Parent.SpanID() == parent.SpanContext().SpanID() {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a GetCatalogLastModified child span, got %v", exporter.GetSpans())
+	}
+}
@@ -0,0 +1,81 @@
+// Package tracing sets up OpenTelemetry tracing for the API.
+//
+// Requests get a server span from middleware.Tracing, which continues the
+// caller's trace when a W3C traceparent header is present, and every query
+// run under it gets a child span from QueryTracer, named after its sqlc
+// query. Spans are exported over OTLP/HTTP (configured through the standard
+// OTEL_EXPORTER_OTLP_* environment variables) or printed to stdout, chosen
+// by Config.Exporter; with no exporter the global provider stays a no-op.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter kinds accepted by Setup.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// instrumentationName identifies the tracers created by this module.
+const instrumentationName = "github.com/paulsena/asheville-setlist"
+
+// Config selects and configures the span exporter.
+type Config struct {
+	Exporter       string  // "none", "stdout" or "otlp"
+	SampleRatio    float64 // Fraction of new traces recorded, 0 to 1
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
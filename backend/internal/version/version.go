@@ -0,0 +1,32 @@
+// Package version reports the build's version and commit.
+//
+// Both are set at build time with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/paulsena/asheville-setlist/internal/version.Version=v1.4.0 \
+//	  -X github.com/paulsena/asheville-setlist/internal/version.Commit=$(git rev-parse --short HEAD)" ./cmd/api
+//
+// Without ldflags, Version is "dev" and Commit falls back to the VCS
+// revision the Go toolchain stamps into binaries built from a checkout.
+package version
+
+import "runtime/debug"
+
+// Set at build time via -ldflags "-X".
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+func init() {
+	if Commit != "" {
+		return
+	}
+	Commit = "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+				Commit = setting.Value[:7]
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Delivery settings.
+const (
+	// DeliveryTimeout bounds each delivery request.
+	DeliveryTimeout = 10 * time.Second
+
+	// MaxAttempts is how many times a delivery is tried before it is marked
+	// failed. It can still be replayed.
+	MaxAttempts = 10
+
+	// LogRetention is how long events and their delivery log are kept.
+	LogRetention = 30 * 24 * time.Hour
+
+	// batchSize is the most deliveries claimed per pass.
+	batchSize = 100
+
+	// maxErrorLength bounds the error (and response excerpt) stored per attempt.
+	maxErrorLength = 1000
+)
+
+// Backoff delays, doubling per failed attempt up to maxBackoff.
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// Backoff returns the delay before retrying after the given number of failed
+// attempts: 30s, 1m, 2m, 4m... capped at 6h.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := baseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Envelope is the JSON body POSTed for every event. ID identifies the event;
+// replays and retries of the same event carry the same ID.
+type Envelope struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Deliverer sends queued webhook deliveries.
+type Deliverer struct {
+	queries *db.Queries
+	client  *http.Client
+}
+
+// NewDeliverer creates a Deliverer. A nil client defaults to one with
+// DeliveryTimeout.
+func NewDeliverer(queries *db.Queries, client *http.Client) *Deliverer {
+	if client == nil {
+		client = &http.Client{Timeout: DeliveryTimeout}
+	}
+	return &Deliverer{
+		queries: queries,
+		client:  client,
+	}
+}
+
+// Deliver sends every due delivery, returning how many succeeded. Failed
+// attempts are recorded and rescheduled with Backoff. Deliveries are leased
+// while in flight, so the API and scraper can run Deliver concurrently.
+func (d *Deliverer) Deliver(ctx context.Context) (int, error) {
+	start := time.Now()
+	var succeeded, failed int
+
+	for {
+		due, err := d.queries.ClaimDueWebhookDeliveries(ctx, batchSize)
+		if err != nil {
+			return succeeded, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+
+		for _, delivery := range due {
+			if err := d.attempt(ctx, delivery); err != nil {
+				if ctx.Err() != nil {
+					return succeeded, ctx.Err()
+				}
+				slog.Warn("webhook delivery failed",
+					"delivery_id", delivery.ID,
+					"event", delivery.EventType,
+					"url", delivery.Url,
+					"attempt", delivery.AttemptCount+1,
+					"error", err,
+				)
+				failed++
+				continue
+			}
+			succeeded++
+		}
+
+		if len(due) < batchSize {
+			break
+		}
+	}
+
+	if succeeded+failed > 0 {
+		slog.Info("delivered webhooks",
+			"succeeded", succeeded,
+			"failed", failed,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+	return succeeded, nil
+}
+
+// attempt POSTs one delivery and records the outcome.
+func (d *Deliverer) attempt(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) error {
+	status, sendErr := d.send(ctx, delivery)
+	if sendErr == nil {
+		code := int32(status)
+		return d.queries.RecordWebhookDeliverySuccess(ctx, db.RecordWebhookDeliverySuccessParams{
+			ID:             delivery.ID,
+			ResponseStatus: &code,
+		})
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and the attempt is retried
+		return sendErr
+	}
+
+	attempts := int(delivery.AttemptCount) + 1
+	params := db.RecordWebhookDeliveryFailureParams{
+		ID:            delivery.ID,
+		GiveUp:        attempts >= MaxAttempts,
+		NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(Backoff(attempts)), Valid: true},
+	}
+	if status != 0 {
+		code := int32(status)
+		params.ResponseStatus = &code
+	}
+	message := truncate(sendErr.Error(), maxErrorLength)
+	params.LastError = &message
+
+	if err := d.queries.RecordWebhookDeliveryFailure(ctx, params); err != nil {
+		return fmt.Errorf("%w (and failed to record it: %v)", sendErr, err)
+	}
+	return sendErr
+}
+
+// send POSTs the signed event and returns the response status, 0 when no
+// response was received. Any 2xx status is success.
+func (d *Deliverer) send(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(Envelope{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.EventCreatedAt.Time.UTC().Format(time.RFC3339),
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AshevilleSetlist-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, fmt.Sprint(delivery.ID))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+	return resp.StatusCode, nil
+}
+
+// Run delivers immediately and then on every interval tick until the context
+// is cancelled.
+func (d *Deliverer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Deliver(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// truncate shortens s to at most n bytes of text Postgres accepts: response
+// bodies may be binary or cut mid-character.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		s = s[:n]
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+}
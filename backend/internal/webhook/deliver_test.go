@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{9, 128 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{50, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("héllo", 2); got != "h" {
+		t.Errorf("expected cut character to be dropped, got %q", got)
+	}
+	if got := truncate("a\x00b\xffc", 100); got != "abc" {
+		t.Errorf("expected NUL and invalid bytes to be dropped, got %q", got)
+	}
+}
+
+func TestValidEventType(t *testing.T) {
+	for _, e := range EventTypes {
+		if !ValidEventType(e) {
+			t.Errorf("ValidEventType(%q) = false", e)
+		}
+	}
+	if ValidEventType("show.deleted") {
+		t.Error("ValidEventType(show.deleted) = true")
+	}
+}
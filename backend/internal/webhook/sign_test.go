@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":1,"type":"show.created"}`)
+	now := time.Unix(1767225600, 0)
+
+	header := Sign(secret, now, body)
+	if !strings.HasPrefix(header, "t=1767225600,v1=") {
+		t.Fatalf("unexpected header %q", header)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		valid  bool
+	}{
+		{"valid", secret, header, body, now, true},
+		{"within tolerance", secret, header, body, now.Add(4 * time.Minute), true},
+		{"extra signatures", secret, header + ",v1=00ff", body, now, true},
+		{"wrong secret", "whsec_other", header, body, now, false},
+		{"tampered body", secret, header, []byte(`{"id":2}`), now, false},
+		{"expired", secret, header, body, now.Add(6 * time.Minute), false},
+		{"from the future", secret, header, body, now.Add(-6 * time.Minute), false},
+		{"missing timestamp", secret, header[strings.Index(header, ",")+1:], body, now, false},
+		{"empty", secret, "", body, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, DefaultTolerance, tt.now)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid signature, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("unexpected secret %q", a)
+	}
+	if a == b {
+		t.Error("expected distinct secrets")
+	}
+}
@@ -0,0 +1,71 @@
+// Package webhook notifies partner systems about show and band lifecycle
+// events.
+//
+// Events are recorded by the write paths (ingestion and show submissions)
+// inside their transactions, together with a snapshot of the show or band, and
+// a delivery is queued for every active subscription to the event type. A
+// Deliverer later POSTs each event as JSON signed with the subscription's
+// secret (see Sign), retrying failures with exponential backoff. Every attempt
+// is kept in a delivery log that admins can inspect and replay from.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+)
+
+// Event types.
+const (
+	EventShowCreated   = "show.created"
+	EventShowUpdated   = "show.updated"
+	EventShowCancelled = "show.cancelled"
+	EventBandCreated   = "band.created"
+)
+
+// EventTypes lists every event type a subscription can receive.
+var EventTypes = []string{EventShowCreated, EventShowUpdated, EventShowCancelled, EventBandCreated}
+
+// ValidEventType reports whether t is a known event type.
+func ValidEventType(t string) bool {
+	return slices.Contains(EventTypes, t)
+}
+
+// ShowEvent records a show event and queues it for subscribers, returning the
+// number of deliveries queued. Call it after the show and its lineup are
+// written so the snapshot is complete.
+func ShowEvent(ctx context.Context, q *db.Queries, eventType string, showIDs ...int32) (int64, error) {
+	var total int64
+	for _, id := range showIDs {
+		count, err := q.CreateShowWebhookEvent(ctx, db.CreateShowWebhookEventParams{
+			EventType: eventType,
+			ShowID:    id,
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to queue %s webhook: %w", eventType, err)
+		}
+		if count > 0 {
+			slog.Debug("queued webhook", "event", eventType, "show_id", id, "deliveries", count)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// BandCreated records a band.created event and queues it for subscribers.
+func BandCreated(ctx context.Context, q *db.Queries, bandID int32) (int64, error) {
+	count, err := q.CreateBandWebhookEvent(ctx, db.CreateBandWebhookEventParams{
+		EventType: EventBandCreated,
+		BandID:    bandID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue %s webhook: %w", EventBandCreated, err)
+	}
+	if count > 0 {
+		slog.Debug("queued webhook", "event", EventBandCreated, "band_id", bandID, "deliveries", count)
+	}
+	return count, nil
+}
@@ -0,0 +1,185 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paulsena/asheville-setlist/internal/db"
+	"github.com/paulsena/asheville-setlist/internal/testutil"
+	"github.com/paulsena/asheville-setlist/internal/webhook"
+)
+
+// receiver records webhook requests and answers with status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func TestShowEventDelivery(t *testing.T) {
+	tdb := testutil.SetupTestDB(t)
+	defer tdb.Close()
+
+	ctx := context.Background()
+	tdb.CleanupTestData(ctx)
+	defer tdb.CleanupTestData(ctx)
+
+	venueID, err := tdb.GetFirstVenueID(ctx)
+	if err != nil {
+		t.Skipf("no venues in database: %v", err)
+	}
+
+	recv := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	description := "[TEST] show events"
+	secret := "whsec_test"
+	sub, err := tdb.Queries.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:         server.URL,
+		Description: &description,
+		Events:      []string{webhook.EventShowCreated, webhook.EventShowCancelled},
+		Secret:      secret,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	showID, err := tdb.InsertTestShow(ctx, venueID, time.Now().AddDate(0, 0, 7), "Webhook Show")
+	if err != nil {
+		t.Fatalf("failed to insert show: %v", err)
+	}
+	bandID, err := tdb.InsertTestBand(ctx, "Test Band Webhook", "test-band-webhook")
+	if err != nil {
+		t.Fatalf("failed to insert band: %v", err)
+	}
+	tdb.LinkBandToShow(ctx, showID, bandID, true, 1)
+
+	count, err := webhook.ShowEvent(ctx, tdb.Queries, webhook.EventShowCreated, showID)
+	if err != nil {
+		t.Fatalf("ShowEvent failed: %v", err)
+	}
+	if count < 1 {
+		t.Fatalf("expected a delivery to be queued, got %d", count)
+	}
+
+	// Not subscribed to updates
+	if count, _ := webhook.ShowEvent(ctx, tdb.Queries, webhook.EventShowUpdated, showID); count != 0 {
+		t.Errorf("expected no deliveries for show.updated, got %d", count)
+	}
+
+	deliverer := webhook.NewDeliverer(tdb.Queries, server.Client())
+	if _, err := deliverer.Deliver(ctx); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	recv.mu.Lock()
+	if len(recv.requests) != 1 {
+		recv.mu.Unlock()
+		t.Fatalf("expected 1 request, got %d", len(recv.requests))
+	}
+	req, body := recv.requests[0], recv.bodies[0]
+	recv.mu.Unlock()
+
+	if req.Header.Get(webhook.HeaderEvent) != webhook.EventShowCreated {
+		t.Errorf("%s = %q", webhook.HeaderEvent, req.Header.Get(webhook.HeaderEvent))
+	}
+	if err := webhook.Verify(secret, req.Header.Get(webhook.HeaderSignature), body, webhook.DefaultTolerance, time.Now()); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	var envelope struct {
+		Type string `json:"type"`
+		Data struct {
+			ID    int32  `json:"id"`
+			Title string `json:"title"`
+			Venue struct {
+				ID int32 `json:"id"`
+			} `json:"venue"`
+			Bands []struct {
+				Name        string `json:"name"`
+				IsHeadliner bool   `json:"is_headliner"`
+			} `json:"bands"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("failed to parse body: %v", err)
+	}
+	if envelope.Type != webhook.EventShowCreated || envelope.Data.ID != showID || envelope.Data.Venue.ID != venueID {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+	if len(envelope.Data.Bands) != 1 || envelope.Data.Bands[0].Name != "Test Band Webhook" || !envelope.Data.Bands[0].IsHeadliner {
+		t.Errorf("unexpected lineup %+v", envelope.Data.Bands)
+	}
+
+	// A failed attempt is logged and retried later, not immediately
+	recv.mu.Lock()
+	recv.status = http.StatusInternalServerError
+	recv.mu.Unlock()
+
+	if _, err := webhook.ShowEvent(ctx, tdb.Queries, webhook.EventShowCancelled, showID); err != nil {
+		t.Fatalf("ShowEvent failed: %v", err)
+	}
+	if _, err := deliverer.Deliver(ctx); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	if _, err := deliverer.Deliver(ctx); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	log, err := tdb.Queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: &sub.ID,
+		Limit:          10,
+	})
+	if err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	if len(log) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(log))
+	}
+	failed, succeeded := log[0], log[1]
+	if succeeded.Status != "succeeded" || succeeded.DeliveredAt.Time.IsZero() {
+		t.Errorf("unexpected first delivery %+v", succeeded)
+	}
+	if failed.Status != "pending" || failed.AttemptCount != 1 || failed.ResponseStatus == nil || *failed.ResponseStatus != 500 {
+		t.Errorf("unexpected failed delivery %+v", failed)
+	}
+	if !failed.NextAttemptAt.Time.After(time.Now().Add(20 * time.Second)) {
+		t.Errorf("expected retry to back off, next attempt at %s", failed.NextAttemptAt.Time)
+	}
+
+	// Replaying queues the same event again
+	replay, err := tdb.Queries.ReplayWebhookDelivery(ctx, succeeded.ID)
+	if err != nil {
+		t.Fatalf("ReplayWebhookDelivery failed: %v", err)
+	}
+	recv.mu.Lock()
+	recv.status = http.StatusNoContent
+	recv.mu.Unlock()
+	if _, err := deliverer.Deliver(ctx); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	last := recv.requests[len(recv.requests)-1]
+	if last.Header.Get(webhook.HeaderEvent) != webhook.EventShowCreated || replay.EventID != succeeded.EventID {
+		t.Errorf("expected replay of show.created, got %s", last.Header.Get(webhook.HeaderEvent))
+	}
+}
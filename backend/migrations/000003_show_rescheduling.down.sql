@@ -0,0 +1,10 @@
+-- The Asheville Setlist - Show Rescheduling Rollback
+
+ALTER TABLE shows DROP CONSTRAINT IF EXISTS check_rescheduled_not_self;
+DROP INDEX IF EXISTS idx_shows_rescheduled_to;
+DROP INDEX IF EXISTS idx_shows_external;
+
+ALTER TABLE shows DROP COLUMN IF EXISTS rescheduled_to;
+ALTER TABLE shows DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE shows DROP COLUMN IF EXISTS external_id;
+ALTER TABLE shows DROP COLUMN IF EXISTS external_source;
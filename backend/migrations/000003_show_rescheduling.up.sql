@@ -0,0 +1,20 @@
+-- The Asheville Setlist - Show Rescheduling
+-- Tracks scraped events by source identity so the ingestion pipeline can
+-- detect cancellations/postponements and link rescheduled shows
+
+-- ============================================
+-- SHOWS: source identity and reschedule linkage
+-- ============================================
+ALTER TABLE shows ADD COLUMN external_source TEXT;
+ALTER TABLE shows ADD COLUMN external_id TEXT;
+ALTER TABLE shows ADD COLUMN last_seen_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE shows ADD COLUMN rescheduled_to INTEGER REFERENCES shows(id) ON DELETE SET NULL;
+
+-- One show per source event
+CREATE UNIQUE INDEX idx_shows_external ON shows(external_source, external_id)
+    WHERE external_id IS NOT NULL;
+CREATE INDEX idx_shows_rescheduled_to ON shows(rescheduled_to)
+    WHERE rescheduled_to IS NOT NULL;
+
+ALTER TABLE shows ADD CONSTRAINT check_rescheduled_not_self
+    CHECK (rescheduled_to IS NULL OR rescheduled_to <> id);
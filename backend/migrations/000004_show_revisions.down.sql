@@ -0,0 +1,3 @@
+-- The Asheville Setlist - Show Revisions Rollback
+
+DROP TABLE IF EXISTS show_revisions;
@@ -0,0 +1,33 @@
+-- The Asheville Setlist - Show Revisions
+-- Field-level audit log of changes to shows, so date moves, price changes,
+-- lineup edits and status changes can be explained and bad scrapes rolled back
+
+-- ============================================
+-- SHOW_REVISIONS
+-- ============================================
+CREATE TABLE show_revisions (
+    id SERIAL PRIMARY KEY,
+
+    -- Relationships
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+
+    -- Change (values are JSON so any field type fits; NULL = unset)
+    field TEXT NOT NULL,
+    old_value JSONB,
+    new_value JSONB,
+
+    -- Who made the change: actor is the scraper source, submitter or admin
+    actor TEXT,
+    source TEXT NOT NULL,
+
+    -- Timestamp (all fields changed in one transaction share it)
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Show_revisions indexes
+CREATE INDEX idx_show_revisions_show ON show_revisions(show_id, created_at DESC);
+CREATE INDEX idx_show_revisions_source ON show_revisions(source, created_at);
+
+-- Show_revisions constraints
+ALTER TABLE show_revisions ADD CONSTRAINT check_revision_source_valid
+    CHECK (source IN ('scraped', 'band_submitted', 'admin', 'system'));
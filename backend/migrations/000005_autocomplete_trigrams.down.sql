@@ -0,0 +1,8 @@
+-- The Asheville Setlist - Autocomplete Trigram Indexes Rollback
+
+DROP INDEX IF EXISTS idx_shows_title_trgm;
+DROP INDEX IF EXISTS idx_genres_name_trgm;
+DROP INDEX IF EXISTS idx_venues_name_trgm;
+DROP INDEX IF EXISTS idx_bands_name_trgm;
+
+-- pg_trgm is left installed; other objects may depend on it
@@ -0,0 +1,14 @@
+-- The Asheville Setlist - Autocomplete Trigram Indexes
+-- Enables typo-tolerant autocomplete ("orang peel" -> "The Orange Peel")
+-- using pg_trgm similarity and prefix matching on names
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- ============================================
+-- TRIGRAM INDEXES (support <%, % and ILIKE)
+-- ============================================
+CREATE INDEX idx_bands_name_trgm ON bands USING GIN(name gin_trgm_ops);
+CREATE INDEX idx_venues_name_trgm ON venues USING GIN(name gin_trgm_ops);
+CREATE INDEX idx_genres_name_trgm ON genres USING GIN(name gin_trgm_ops);
+CREATE INDEX idx_shows_title_trgm ON shows USING GIN(title gin_trgm_ops)
+    WHERE status = 'scheduled';
@@ -0,0 +1,3 @@
+-- The Asheville Setlist - Article Full-Text Search Rollback
+
+DROP INDEX IF EXISTS idx_articles_search_content;
@@ -0,0 +1,6 @@
+-- The Asheville Setlist - Article Full-Text Search
+-- Global search matches article body text, not just title and excerpt
+
+CREATE INDEX idx_articles_search_content ON articles
+    USING GIN(to_tsvector('english', title || ' ' || COALESCE(excerpt, '') || ' ' || content))
+    WHERE is_published = TRUE;
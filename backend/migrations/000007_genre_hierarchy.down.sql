@@ -0,0 +1,5 @@
+-- The Asheville Setlist - Genre Hierarchy Rollback
+
+ALTER TABLE genres DROP CONSTRAINT IF EXISTS check_genre_parent_not_self;
+DROP INDEX IF EXISTS idx_genres_parent;
+ALTER TABLE genres DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,48 @@
+-- The Asheville Setlist - Genre Hierarchy
+-- Adds parent/child genres (e.g. bluegrass under americana) so genre filters
+-- can include subgenres
+
+-- ============================================
+-- GENRES: parent genre
+-- ============================================
+ALTER TABLE genres ADD COLUMN parent_id INTEGER REFERENCES genres(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_genres_parent ON genres(parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE genres ADD CONSTRAINT check_genre_parent_not_self
+    CHECK (parent_id IS NULL OR parent_id <> id);
+
+-- ============================================
+-- SEED: subgenres of the core genres
+-- ============================================
+UPDATE genres c
+SET parent_id = p.id
+FROM (VALUES
+    -- Americana & Roots
+    ('bluegrass', 'americana'),
+    ('folk', 'americana'),
+    ('country', 'americana'),
+    ('singer-songwriter', 'americana'),
+
+    -- Rock & Alternative
+    ('indie', 'rock'),
+    ('alternative', 'rock'),
+    ('punk', 'rock'),
+    ('metal', 'rock'),
+    ('emo', 'rock'),
+    ('psychedelic', 'rock'),
+    ('prog-rock', 'rock'),
+
+    -- Soul & R&B
+    ('soul', 'rnb'),
+    ('motown', 'soul'),
+
+    -- Electronic & Dance
+    ('dj', 'electronic'),
+
+    -- World
+    ('reggae', 'world'),
+    ('latin', 'world')
+) AS tree(child_slug, parent_slug)
+JOIN genres p ON p.slug = tree.parent_slug
+WHERE c.slug = tree.child_slug;
@@ -0,0 +1,12 @@
+-- The Asheville Setlist - Genre Inference Rollback
+
+DROP INDEX IF EXISTS idx_band_genres_unconfirmed;
+
+ALTER TABLE band_genres DROP CONSTRAINT IF EXISTS check_band_genre_confidence_range;
+ALTER TABLE band_genres DROP CONSTRAINT IF EXISTS check_band_genre_source_valid;
+
+ALTER TABLE band_genres
+    DROP COLUMN IF EXISTS confirmed_by,
+    DROP COLUMN IF EXISTS confirmed_at,
+    DROP COLUMN IF EXISTS confidence,
+    DROP COLUMN IF EXISTS source;
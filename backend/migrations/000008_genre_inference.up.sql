@@ -0,0 +1,26 @@
+-- The Asheville Setlist - Genre Inference
+-- Records where each band genre came from so genres proposed by the
+-- classifier can be told apart from curated ones and confirmed by admins
+
+-- ============================================
+-- BAND_GENRES: provenance and confidence
+-- ============================================
+-- source:       'manual' for curated genres, otherwise the strongest signal
+--               behind an inferred genre
+-- confidence:   classifier confidence (0-1), NULL for manual genres
+-- confirmed_at: set when an admin accepts an inferred genre
+ALTER TABLE band_genres
+    ADD COLUMN source TEXT NOT NULL DEFAULT 'manual',
+    ADD COLUMN confidence DECIMAL(4,3),
+    ADD COLUMN confirmed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN confirmed_by TEXT;
+
+ALTER TABLE band_genres ADD CONSTRAINT check_band_genre_source_valid
+    CHECK (source IN ('manual', 'lma_tag', 'venue', 'co_bill', 'bio'));
+
+ALTER TABLE band_genres ADD CONSTRAINT check_band_genre_confidence_range
+    CHECK (confidence IS NULL OR (confidence >= 0 AND confidence <= 1));
+
+-- Review queue: unconfirmed inferred genres, most confident first
+CREATE INDEX idx_band_genres_unconfirmed ON band_genres(confidence DESC)
+    WHERE source <> 'manual' AND confirmed_at IS NULL;
@@ -0,0 +1,3 @@
+-- The Asheville Setlist - Band Similarity Rollback
+
+DROP TABLE IF EXISTS band_similarity;
@@ -0,0 +1,33 @@
+-- The Asheville Setlist - Band Similarity
+-- Precomputed similar-band scores blending genre overlap, co-billing,
+-- shared venues and hometown. Refreshed after each ingestion run.
+
+-- ============================================
+-- BAND_SIMILARITY
+-- ============================================
+-- Component scores are unweighted (0-1); score is the weighted blend used
+-- for ranking. Each band keeps its top matches only.
+CREATE TABLE band_similarity (
+    band_id INTEGER NOT NULL REFERENCES bands(id) ON DELETE CASCADE,
+    similar_band_id INTEGER NOT NULL REFERENCES bands(id) ON DELETE CASCADE,
+
+    -- Blended score and its components
+    score DOUBLE PRECISION NOT NULL,
+    genre_score DOUBLE PRECISION NOT NULL,     -- Jaccard overlap of genres
+    co_bill_score DOUBLE PRECISION NOT NULL,   -- Shared shows / shows of the less active band
+    venue_score DOUBLE PRECISION NOT NULL,     -- Jaccard overlap of venues played
+    hometown_score DOUBLE PRECISION NOT NULL,  -- 1 when hometowns match
+
+    -- Raw overlap counts, used to explain the score
+    shared_genre_count INTEGER NOT NULL,
+    shared_show_count INTEGER NOT NULL,
+    shared_venue_count INTEGER NOT NULL,
+
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (band_id, similar_band_id),
+
+    CONSTRAINT check_band_similarity_not_self CHECK (band_id <> similar_band_id)
+);
+
+CREATE INDEX idx_band_similarity_rank ON band_similarity(band_id, score DESC);
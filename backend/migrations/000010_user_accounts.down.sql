@@ -0,0 +1,8 @@
+-- The Asheville Setlist - User Accounts Rollback
+
+DROP TABLE IF EXISTS followed_venues;
+DROP TABLE IF EXISTS followed_bands;
+DROP TABLE IF EXISTS saved_shows;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS login_tokens;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,72 @@
+-- The Asheville Setlist - User Accounts
+-- Passwordless accounts (email magic links), sessions, saved shows and
+-- followed bands/venues for the personal feed
+
+-- ============================================
+-- USERS
+-- ============================================
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    email TEXT UNIQUE NOT NULL, -- Stored lowercased
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE
+);
+
+-- ============================================
+-- LOGIN_TOKENS (magic links)
+-- ============================================
+-- Only the SHA-256 hash of a token is stored; the token itself is only
+-- ever in the emailed link. Tokens are single use.
+CREATE TABLE login_tokens (
+    token_hash TEXT PRIMARY KEY,
+    email TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_tokens_expires ON login_tokens(expires_at);
+
+-- ============================================
+-- SESSIONS (bearer tokens)
+-- ============================================
+CREATE TABLE sessions (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user ON sessions(user_id);
+CREATE INDEX idx_sessions_expires ON sessions(expires_at);
+
+-- ============================================
+-- SAVED_SHOWS / FOLLOWED_BANDS / FOLLOWED_VENUES
+-- ============================================
+CREATE TABLE saved_shows (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, show_id)
+);
+
+CREATE TABLE followed_bands (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    band_id INTEGER NOT NULL REFERENCES bands(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, band_id)
+);
+
+CREATE TABLE followed_venues (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    venue_id INTEGER NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, venue_id)
+);
+
+-- Reverse lookups (who follows this band/venue) for notifications
+CREATE INDEX idx_saved_shows_show ON saved_shows(show_id);
+CREATE INDEX idx_followed_bands_band ON followed_bands(band_id);
+CREATE INDEX idx_followed_venues_venue ON followed_venues(venue_id);
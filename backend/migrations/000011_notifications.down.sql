@@ -0,0 +1,4 @@
+-- The Asheville Setlist - Notifications Rollback
+
+DROP TABLE IF EXISTS notification_channels;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,65 @@
+-- The Asheville Setlist - Notifications
+-- Per-user notifications about followed bands/venues and saved shows, and
+-- the channels (email, webhook, Web Push) they are delivered through
+
+-- ============================================
+-- NOTIFICATIONS
+-- ============================================
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+
+    -- What the user follows that triggered an announcement
+    band_id INTEGER REFERENCES bands(id) ON DELETE SET NULL,
+    venue_id INTEGER REFERENCES venues(id) ON DELETE SET NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    read_at TIMESTAMP WITH TIME ZONE,
+
+    -- A user hears about each thing once per show
+    CONSTRAINT unique_notification UNIQUE (user_id, kind, show_id),
+    CONSTRAINT check_notification_kind_valid CHECK (
+        kind IN ('show_announced', 'show_cancelled', 'show_postponed', 'show_reminder')
+    )
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, id DESC);
+
+-- ============================================
+-- NOTIFICATION_CHANNELS
+-- ============================================
+-- Each channel keeps its own delivery cursor (last_notification_id), so a
+-- failed delivery is retried without resending to the user's other channels,
+-- and a daily channel batches everything since its last digest.
+CREATE TABLE notification_channels (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    target TEXT NOT NULL, -- Email address, webhook URL or push endpoint
+
+    -- Web Push subscription keys (base64url)
+    p256dh TEXT,
+    auth_secret TEXT,
+
+    frequency TEXT NOT NULL DEFAULT 'instant',
+    unsubscribe_token TEXT UNIQUE NOT NULL,
+
+    -- Delivery state
+    last_notification_id INTEGER NOT NULL DEFAULT 0,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    disabled_at TIMESTAMP WITH TIME ZONE, -- Set by unsubscribe links
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_notification_channel UNIQUE (user_id, kind, target),
+    CONSTRAINT check_channel_kind_valid CHECK (kind IN ('email', 'webhook', 'web_push')),
+    CONSTRAINT check_channel_frequency_valid CHECK (frequency IN ('instant', 'daily')),
+    CONSTRAINT check_web_push_keys CHECK (
+        kind <> 'web_push' OR (p256dh IS NOT NULL AND auth_secret IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_notification_channels_user ON notification_channels(user_id);
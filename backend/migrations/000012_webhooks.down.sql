@@ -0,0 +1,5 @@
+-- The Asheville Setlist - Outbound Webhooks Rollback
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,71 @@
+-- The Asheville Setlist - Outbound Webhooks
+-- Partner subscriptions to show and band lifecycle events, the events
+-- themselves (written by the same transactions as the change) and a log of
+-- every delivery attempt
+
+-- ============================================
+-- WEBHOOK_SUBSCRIPTIONS
+-- ============================================
+CREATE TABLE webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    description TEXT,
+
+    -- Event types delivered, e.g. {show.created, show.cancelled}
+    events TEXT[] NOT NULL,
+
+    -- HMAC-SHA256 signing secret shared with the receiver
+    secret TEXT NOT NULL,
+
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT check_webhook_events_valid CHECK (
+        cardinality(events) > 0
+        AND events <@ ARRAY['show.created', 'show.updated', 'show.cancelled', 'band.created']
+    )
+);
+
+-- ============================================
+-- WEBHOOK_EVENTS
+-- ============================================
+CREATE TABLE webhook_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL, -- Snapshot of the show or band when the event happened
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- ============================================
+-- WEBHOOK_DELIVERIES
+-- ============================================
+-- One row per event per subscription; a replay adds a new row. Attempts are
+-- retried with exponential backoff until they succeed or run out.
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Result of the latest attempt
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    response_status INTEGER,
+    last_error TEXT,
+
+    replay_of BIGINT REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT check_webhook_delivery_status_valid CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id DESC);
@@ -0,0 +1,3 @@
+-- The Asheville Setlist - Live Show Stream Rollback
+
+DROP TABLE IF EXISTS show_stream_events;
@@ -0,0 +1,19 @@
+-- The Asheville Setlist - Live Show Stream
+-- Announcements and changes pushed to SSE clients. Each row is also sent on
+-- the show_stream NOTIFY channel (by the transaction that writes it) so every
+-- API replica wakes its clients; the table lets clients resume from
+-- Last-Event-ID after a reconnect.
+
+-- ============================================
+-- SHOW_STREAM_EVENTS
+-- ============================================
+CREATE TABLE show_stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT check_show_stream_event_type_valid CHECK (event_type IN ('show.created', 'show.updated', 'show.cancelled'))
+);
+
+CREATE INDEX idx_show_stream_events_created_at ON show_stream_events(created_at);
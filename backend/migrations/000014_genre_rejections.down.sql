@@ -0,0 +1,3 @@
+-- The Asheville Setlist - Genre Rejections Rollback
+
+DROP TABLE IF EXISTS band_genre_rejections;
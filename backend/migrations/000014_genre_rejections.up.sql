@@ -0,0 +1,15 @@
+-- The Asheville Setlist - Genre Rejections
+-- Remembers inferred genres an admin rejected so later inference runs don't
+-- propose them again
+
+-- ============================================
+-- BAND_GENRE_REJECTIONS
+-- ============================================
+CREATE TABLE band_genre_rejections (
+    band_id INTEGER NOT NULL REFERENCES bands(id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    rejected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    rejected_by TEXT,
+
+    PRIMARY KEY (band_id, genre_id)
+);
@@ -0,0 +1,3 @@
+-- The Asheville Setlist - HTML Escaping Rollback
+
+DROP FUNCTION IF EXISTS html_escape(TEXT);
@@ -0,0 +1,14 @@
+-- The Asheville Setlist - HTML Escaping
+-- Escapes text for HTML so search snippets can wrap matches in <mark> tags
+-- without passing through markup from bios, descriptions or articles
+
+CREATE FUNCTION html_escape(input TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$
+    SELECT replace(replace(replace(replace(replace(input,
+        '&', '&amp;'),
+        '<', '&lt;'),
+        '>', '&gt;'),
+        '"', '&quot;'),
+        '''', '&#39;')
+$$;
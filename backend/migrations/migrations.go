@@ -0,0 +1,146 @@
+// Package migrations holds the database schema migrations and applies them
+// with golang-migrate.
+//
+// The SQL files are embedded in the binary, so a build always carries the
+// schema its queries were generated against: `api migrate up` brings a
+// database to that schema and Check tells the API at startup whether the
+// database is behind it. Versions are recorded in golang-migrate's
+// schema_migrations table, so the standalone migrate CLI still works against
+// the same database.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"sort"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // registers the pgx5 driver
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Startup schema checks, selected by SCHEMA_CHECK.
+const (
+	// CheckOff skips the check.
+	CheckOff = "off"
+	// CheckWarn logs a warning when the schema is behind.
+	CheckWarn = "warn"
+	// CheckRequire refuses to start when the schema is behind.
+	CheckRequire = "require"
+)
+
+// ErrBehind means the database is missing migrations this build expects.
+var ErrBehind = errors.New("schema is behind")
+
+// ErrDirty means the last migration failed partway and needs fixing by hand.
+var ErrDirty = errors.New("schema is dirty")
+
+// ErrNoVersion means no migration has been applied: the version table is
+// missing or empty.
+var ErrNoVersion = errors.New("no migrations applied")
+
+// Querier runs the version query; *pgxpool.Pool and pgx.Tx satisfy it.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// undefinedTable is the Postgres error code for a missing relation.
+const undefinedTable = "42P01"
+
+// CurrentVersion returns the version golang-migrate recorded in
+// schema_migrations and whether a migration failed partway (dirty).
+func CurrentVersion(ctx context.Context, q Querier) (version uint, dirty bool, err error) {
+	var v int64
+	err = q.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&v, &dirty)
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == undefinedTable) {
+		return 0, false, ErrNoVersion
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(v), dirty, nil
+}
+
+// Migration is one embedded migration.
+type Migration struct {
+	Version uint
+	Name    string // e.g. "show_stream"
+}
+
+// All returns the embedded migrations in version order.
+func All() []Migration {
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		panic(fmt.Sprintf("migrations: %v", err))
+	}
+
+	all := make([]Migration, 0, len(names))
+	for _, name := range names {
+		m, err := source.DefaultParse(name)
+		if err != nil {
+			panic(fmt.Sprintf("migrations: invalid file name %q: %v", name, err))
+		}
+		all = append(all, Migration{Version: m.Version, Name: m.Identifier})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Latest returns the highest embedded migration version, the schema this
+// build expects.
+func Latest() uint {
+	all := All()
+	return all[len(all)-1].Version
+}
+
+// Check compares the database's schema version with Latest. It returns
+// ErrBehind when migrations are pending (including when none has been
+// applied) and ErrDirty when the last one failed; a database ahead of this
+// build is allowed so an older release can be rolled back to.
+func Check(ctx context.Context, q Querier) error {
+	current, dirty, err := CurrentVersion(ctx, q)
+	if errors.Is(err, ErrNoVersion) {
+		return fmt.Errorf("%w: no migrations applied, expected version %d", ErrBehind, Latest())
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w: migration %d failed partway", ErrDirty, current)
+	}
+	if latest := Latest(); current < latest {
+		return fmt.Errorf("%w: at version %d, expected %d", ErrBehind, current, latest)
+	}
+	return nil
+}
+
+// New returns a migrator applying the embedded migrations to the database
+// at databaseURL (a postgres:// URL). Close it when done.
+func New(databaseURL string) (*migrate.Migrate, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database URL: %w", err)
+	}
+	u.Scheme = "pgx5"
+
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestAll(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for i, m := range all {
+		if m.Version != uint(i+1) {
+			t.Errorf("expected migration %d to have version %d, got %d (%s)", i, i+1, m.Version, m.Name)
+		}
+		if _, err := files.ReadFile(fmt.Sprintf("%06d_%s.down.sql", m.Version, m.Name)); err != nil {
+			t.Errorf("migration %d has no down file: %v", m.Version, err)
+		}
+	}
+	if Latest() != all[len(all)-1].Version {
+		t.Errorf("expected Latest %d, got %d", all[len(all)-1].Version, Latest())
+	}
+}
+
+// versionRow returns a fixed schema_migrations row or error.
+type versionRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (r versionRow) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row { return r }
+
+func (r versionRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.version
+	*dest[1].(*bool) = r.dirty
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	latest := int64(Latest())
+
+	tests := []struct {
+		name string
+		row  versionRow
+		want error
+	}{
+		{"current", versionRow{version: latest}, nil},
+		{"ahead", versionRow{version: latest + 1}, nil},
+		{"behind", versionRow{version: latest - 1}, ErrBehind},
+		{"dirty", versionRow{version: latest, dirty: true}, ErrDirty},
+		{"empty table", versionRow{err: pgx.ErrNoRows}, ErrBehind},
+		{"missing table", versionRow{err: &pgconn.PgError{Code: undefinedTable}}, ErrBehind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(context.Background(), tt.row)
+			if tt.want == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := Check(context.Background(), versionRow{err: errors.New("connection reset")}); err == nil ||
+		errors.Is(err, ErrBehind) || errors.Is(err, ErrDirty) {
+		t.Errorf("expected a read error, got %v", err)
+	}
+}
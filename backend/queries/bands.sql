@@ -47,7 +47,13 @@ ORDER BY b.name
 LIMIT $1 OFFSET $2;
 
 -- name: ListBandsByGenre :many
--- List bands filtered by genre slug(s) with pagination
+-- List bands filtered by genre slug(s), including subgenres, with pagination
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT DISTINCT ON (b.name, b.id)
     b.id,
     b.name,
@@ -57,18 +63,22 @@ SELECT DISTINCT ON (b.name, b.id)
     b.image_url
 FROM bands b
 JOIN band_genres bg ON b.id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
-WHERE g.slug = ANY($1::text[])
+WHERE bg.genre_id IN (SELECT id FROM genre_tree)
 ORDER BY b.name, b.id
 LIMIT $2 OFFSET $3;
 
 -- name: CountBandsByGenre :one
--- Count bands by genre (for pagination)
+-- Count bands by genre, including subgenres (for pagination)
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT COUNT(DISTINCT b.id)
 FROM bands b
 JOIN band_genres bg ON b.id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
-WHERE g.slug = ANY($1::text[]);
+WHERE bg.genre_id IN (SELECT id FROM genre_tree);
 
 -- name: GetBandGenres :many
 -- Get genres for a band
@@ -111,23 +121,78 @@ WHERE sb.band_id = $1
   AND s.date >= NOW()
 ORDER BY s.date ASC;
 
--- name: GetSimilarBands :many
--- Find bands with shared genres (similar bands)
--- Excludes the source band and orders by number of shared genres
+-- name: ListBandUpcomingShows :many
+-- List upcoming shows for a band with pagination
 SELECT
-    b.id,
-    b.name,
-    b.slug,
-    b.image_url,
-    COUNT(bg1.genre_id) AS shared_genre_count
-FROM bands b
-JOIN band_genres bg1 ON b.id = bg1.band_id
-JOIN band_genres bg2 ON bg1.genre_id = bg2.genre_id
-WHERE bg2.band_id = $1
-  AND b.id != $1
-GROUP BY b.id, b.name, b.slug, b.image_url
-ORDER BY shared_genre_count DESC, b.name ASC
-LIMIT $2;
+    s.id,
+    s.date,
+    s.title,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    sb.is_headliner,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status = 'scheduled'
+  AND s.date >= NOW()
+ORDER BY s.date ASC, s.id ASC
+LIMIT $2 OFFSET $3;
+
+-- name: ListBandPastShows :many
+-- List past shows for a band with pagination (most recent first)
+SELECT
+    s.id,
+    s.date,
+    s.title,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    sb.is_headliner,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+ORDER BY s.date DESC, s.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetBandShowSummary :one
+-- Get play history totals for a band (past shows only)
+SELECT
+    COUNT(*) AS total_shows,
+    COUNT(*) FILTER (WHERE sb.is_headliner) AS headliner_count,
+    COUNT(*) FILTER (WHERE NOT COALESCE(sb.is_headliner, FALSE)) AS support_count,
+    MIN(s.date)::timestamptz AS first_seen,
+    MAX(s.date)::timestamptz AS last_seen
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW();
+
+-- name: GetBandVenuePlayCounts :many
+-- Count past shows per venue for a band
+SELECT
+    v.id,
+    v.name,
+    v.slug,
+    COUNT(*) AS show_count,
+    MAX(s.date)::timestamptz AS last_played
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE sb.band_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+GROUP BY v.id, v.name, v.slug
+ORDER BY show_count DESC, v.name ASC;
 
 -- name: GetSimilarBandsWithGenres :many
 -- Find similar bands with their shared genre names
@@ -151,7 +216,8 @@ ORDER BY shared_genre_count DESC, b.name ASC
 LIMIT $2;
 
 -- name: SearchBands :many
--- Full-text search on band name and bio
+-- Full-text search on band name and bio, best match first.
+-- The query is a prefix-aware to_tsquery expression built by the handler.
 SELECT
     id,
     name,
@@ -161,9 +227,14 @@ SELECT
     image_url,
     COUNT(*) OVER() AS total_count
 FROM bands
-WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ plainto_tsquery('english', $1)
-ORDER BY name
-LIMIT $2 OFFSET $3;
+WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ to_tsquery('english', @query)
+ORDER BY ts_rank(
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', COALESCE(bio, '')), 'D'),
+        to_tsquery('english', @query)
+    ) DESC,
+    name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: SearchBandsSimple :many
 -- Simple search returning minimal fields (for global search)
@@ -0,0 +1,8 @@
+-- ============================================
+-- CACHE QUERIES
+-- ============================================
+
+-- name: NotifyCacheInvalidation :exec
+-- Tell every API replica to drop cached responses with the tag. The
+-- notification is delivered when the surrounding transaction commits.
+SELECT pg_notify('cache_invalidate', @tag::text);
@@ -8,15 +8,29 @@ SELECT * FROM genres
 WHERE id = $1 LIMIT 1;
 
 -- name: GetGenreBySlug :one
--- Get genre by slug
+-- Get genre by slug with its parent genre
+SELECT
+    g.id,
+    g.name,
+    g.slug,
+    g.description,
+    g.parent_id,
+    p.name AS parent_name,
+    p.slug AS parent_slug,
+    g.created_at
+FROM genres g
+LEFT JOIN genres p ON g.parent_id = p.id
+WHERE g.slug = $1 LIMIT 1;
+
+-- name: ListGenreChildren :many
+-- List direct subgenres of a genre
 SELECT
     id,
     name,
-    slug,
-    description,
-    created_at
+    slug
 FROM genres
-WHERE slug = $1 LIMIT 1;
+WHERE parent_id = $1
+ORDER BY name;
 
 -- name: ListGenres :many
 -- List all genres ordered by name
@@ -30,12 +44,13 @@ FROM genres
 ORDER BY name;
 
 -- name: ListGenresWithShowCount :many
--- List genres with count of upcoming shows
+-- List genres with count of upcoming shows (direct genre only, not subgenres)
 SELECT
     g.id,
     g.name,
     g.slug,
     g.description,
+    g.parent_id,
     COUNT(DISTINCT s.id) AS show_count
 FROM genres g
 LEFT JOIN band_genres bg ON g.id = bg.genre_id
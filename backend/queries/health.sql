@@ -0,0 +1,11 @@
+-- ============================================
+-- HEALTH QUERIES
+-- ============================================
+
+-- name: GetLastScrapeAt :one
+-- When a scrape last succeeded: the newest venue scraper success or show
+-- sighting. NULL when nothing has been scraped yet.
+SELECT GREATEST(
+    (SELECT MAX(last_success_at) FROM venue_scrapers WHERE is_active = TRUE),
+    (SELECT MAX(last_seen_at) FROM shows)
+)::timestamptz AS last_scrape_at;
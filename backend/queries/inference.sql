@@ -0,0 +1,148 @@
+-- ============================================
+-- GENRE INFERENCE QUERIES
+-- ============================================
+
+-- name: GetBandInferenceInput :one
+-- Get the band fields the classifier reads and how many trusted genres it already has
+SELECT
+    b.id,
+    b.bio,
+    (
+        SELECT COUNT(*)
+        FROM band_genres bg
+        WHERE bg.band_id = b.id
+          AND (bg.source = 'manual' OR bg.confirmed_at IS NOT NULL)
+    ) AS trusted_genre_count
+FROM bands b
+WHERE b.id = $1;
+
+-- name: ListInferenceGenres :many
+-- List genres the classifier can propose
+SELECT
+    id,
+    name,
+    slug
+FROM genres
+ORDER BY id;
+
+-- name: GetCoBilledGenreShares :many
+-- Share of the band's co-billed bands (with trusted genres) carrying each genre.
+-- Trusted genres are manual or admin-confirmed, so unconfirmed inferences
+-- never feed back into later ones.
+WITH co_billed AS (
+    SELECT DISTINCT other.band_id
+    FROM show_bands mine
+    JOIN show_bands other ON other.show_id = mine.show_id AND other.band_id <> mine.band_id
+    WHERE mine.band_id = @band_id
+),
+trusted AS (
+    SELECT bg.band_id, bg.genre_id
+    FROM band_genres bg
+    JOIN co_billed cb ON cb.band_id = bg.band_id
+    WHERE bg.source = 'manual' OR bg.confirmed_at IS NOT NULL
+)
+SELECT
+    genre_id,
+    (COUNT(*)::float8 / (SELECT COUNT(DISTINCT band_id) FROM trusted))::float8 AS share
+FROM trusted
+GROUP BY genre_id;
+
+-- name: GetVenueGenreShares :many
+-- Share of other bands (with trusted genres) playing the band's venues carrying each genre
+WITH band_venues AS (
+    SELECT DISTINCT s.venue_id
+    FROM shows s
+    JOIN show_bands sb ON sb.show_id = s.id
+    WHERE sb.band_id = @band_id
+),
+venue_bands AS (
+    SELECT DISTINCT sb.band_id
+    FROM shows s
+    JOIN band_venues bv ON bv.venue_id = s.venue_id
+    JOIN show_bands sb ON sb.show_id = s.id
+    WHERE sb.band_id <> @band_id
+),
+trusted AS (
+    SELECT bg.band_id, bg.genre_id
+    FROM band_genres bg
+    JOIN venue_bands vb ON vb.band_id = bg.band_id
+    WHERE bg.source = 'manual' OR bg.confirmed_at IS NOT NULL
+)
+SELECT
+    genre_id,
+    (COUNT(*)::float8 / (SELECT COUNT(DISTINCT band_id) FROM trusted))::float8 AS share
+FROM trusted
+GROUP BY genre_id;
+
+-- name: UpsertInferredBandGenre :execrows
+-- Write an inferred genre, refreshing earlier unconfirmed inferences.
+-- Manual and confirmed genres are never overwritten, and genres an admin
+-- rejected for the band are never proposed again.
+INSERT INTO band_genres (band_id, genre_id, source, confidence)
+SELECT @band_id::int, @genre_id::int, @source::text, @confidence::numeric
+WHERE NOT EXISTS (
+    SELECT 1 FROM band_genre_rejections r
+    WHERE r.band_id = @band_id::int AND r.genre_id = @genre_id::int
+)
+ON CONFLICT (band_id, genre_id) DO UPDATE
+SET source = EXCLUDED.source,
+    confidence = EXCLUDED.confidence
+WHERE band_genres.source <> 'manual'
+  AND band_genres.confirmed_at IS NULL;
+
+-- name: ListBandsWithoutGenres :many
+-- List bands that have no genres at all (inference backfill). Bands with
+-- rejected suggestions have already been reviewed and are skipped.
+SELECT b.id
+FROM bands b
+WHERE NOT EXISTS (SELECT 1 FROM band_genres bg WHERE bg.band_id = b.id)
+  AND NOT EXISTS (SELECT 1 FROM band_genre_rejections r WHERE r.band_id = b.id)
+ORDER BY b.id;
+
+-- name: ListGenreSuggestions :many
+-- List unconfirmed inferred genres with pagination (most confident first)
+SELECT
+    b.id AS band_id,
+    b.name AS band_name,
+    b.slug AS band_slug,
+    g.id AS genre_id,
+    g.name AS genre_name,
+    g.slug AS genre_slug,
+    bg.source,
+    bg.confidence,
+    bg.created_at,
+    COUNT(*) OVER() AS total_count
+FROM band_genres bg
+JOIN bands b ON b.id = bg.band_id
+JOIN genres g ON g.id = bg.genre_id
+WHERE bg.source <> 'manual'
+  AND bg.confirmed_at IS NULL
+ORDER BY bg.confidence DESC, bg.created_at, b.id, g.id
+LIMIT $1 OFFSET $2;
+
+-- name: ConfirmBandGenre :one
+-- Accept an inferred genre. Confirming twice keeps the first confirmation.
+UPDATE band_genres
+SET confirmed_at = COALESCE(confirmed_at, NOW()),
+    confirmed_by = COALESCE(confirmed_by, @confirmed_by)
+WHERE band_id = @band_id
+  AND genre_id = @genre_id
+  AND source <> 'manual'
+RETURNING band_id, genre_id, source, confidence, confirmed_at, confirmed_by;
+
+-- name: RejectBandGenre :execrows
+-- Remove an unconfirmed inferred genre and remember the rejection so it is
+-- not proposed again
+WITH rejected AS (
+    DELETE FROM band_genres bg
+    WHERE bg.band_id = @band_id
+      AND bg.genre_id = @genre_id
+      AND bg.source <> 'manual'
+      AND bg.confirmed_at IS NULL
+    RETURNING bg.band_id, bg.genre_id
+)
+INSERT INTO band_genre_rejections (band_id, genre_id, rejected_by)
+SELECT rejected.band_id, rejected.genre_id, @rejected_by FROM rejected
+ON CONFLICT (band_id, genre_id) DO UPDATE
+SET rejected_at = NOW(),
+    rejected_by = EXCLUDED.rejected_by;
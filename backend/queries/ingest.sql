@@ -0,0 +1,108 @@
+-- ============================================
+-- INGESTION QUERIES
+-- ============================================
+
+-- name: GetShowByExternalID :one
+-- Find a previously ingested show by its source identity
+SELECT
+    id,
+    venue_id,
+    title,
+    date,
+    price_min,
+    price_max,
+    ticket_url,
+    status,
+    rescheduled_to
+FROM shows
+WHERE external_source = $1
+  AND external_id = $2
+LIMIT 1;
+
+-- name: CreateScrapedShow :one
+-- Create a show from a scraped source event
+INSERT INTO shows (
+    venue_id,
+    title,
+    description,
+    image_url,
+    date,
+    price_min,
+    price_max,
+    ticket_url,
+    status,
+    source,
+    scraped_data,
+    external_source,
+    external_id,
+    last_seen_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, 'scraped', $10, $11, $12, NOW()
+) RETURNING id, status, created_at;
+
+-- name: UpdateScrapedShow :exec
+-- Refresh a scraped show with the latest source data
+UPDATE shows
+SET title = $2,
+    description = $3,
+    image_url = $4,
+    date = $5,
+    price_min = $6,
+    price_max = $7,
+    ticket_url = $8,
+    scraped_data = $9,
+    last_seen_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateShowStatus :exec
+-- Set a show's status
+UPDATE shows
+SET status = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: CancelVanishedShows :many
+-- Cancel scheduled shows from a source that were not seen in a scrape window
+UPDATE shows
+SET status = 'cancelled',
+    updated_at = NOW()
+WHERE external_source = $1
+  AND status = 'scheduled'
+  AND date >= $2
+  AND date <= $3
+  AND NOT (external_id = ANY($4::text[]))
+RETURNING id;
+
+-- name: FindRescheduleCandidate :one
+-- Find a cancelled/postponed show at the same venue with the same headliner
+-- that has not yet been linked to a new date. Only shows dated within the
+-- reschedule window of the new date and cancelled or postponed recently
+-- qualify, so old cancellations are never relinked.
+SELECT s.id, s.status
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+WHERE s.venue_id = @venue_id
+  AND sb.band_id = @band_id
+  AND sb.is_headliner = TRUE
+  AND s.status IN ('cancelled', 'postponed')
+  AND s.rescheduled_to IS NULL
+  AND s.id <> @id
+  AND s.date BETWEEN @earliest_date AND @latest_date
+  AND s.updated_at >= @changed_since
+ORDER BY s.date DESC
+LIMIT 1;
+
+-- name: LinkRescheduledShow :exec
+-- Mark a show as postponed and point it at its new date
+UPDATE shows
+SET status = 'postponed',
+    rescheduled_to = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND rescheduled_to IS NULL;
+
+-- name: DeleteShowBands :exec
+-- Remove a show's lineup (before relinking bands)
+DELETE FROM show_bands
+WHERE show_id = $1;
@@ -0,0 +1,236 @@
+-- ============================================
+-- NOTIFICATION QUERIES
+-- ============================================
+
+-- name: CreateShowAnnouncedNotifications :execrows
+-- Notify followers of the show's bands and venue about an upcoming scheduled
+-- show. Each user gets one notification, attributed to the top-billed band
+-- they follow, else to the venue. Safe to call again after lineup changes.
+WITH target AS (
+    SELECT id, venue_id
+    FROM shows
+    WHERE id = @show_id
+      AND status = 'scheduled'
+      AND date >= NOW()
+),
+followers AS (
+    SELECT
+        fb.user_id,
+        sb.band_id,
+        NULL::int AS venue_id,
+        0 AS rank,
+        COALESCE(sb.performance_order, 0) AS billing
+    FROM target t
+    JOIN show_bands sb ON sb.show_id = t.id
+    JOIN followed_bands fb ON fb.band_id = sb.band_id
+    UNION ALL
+    SELECT
+        fv.user_id,
+        NULL::int AS band_id,
+        fv.venue_id,
+        1 AS rank,
+        0 AS billing
+    FROM target t
+    JOIN followed_venues fv ON fv.venue_id = t.venue_id
+)
+INSERT INTO notifications (user_id, kind, show_id, band_id, venue_id)
+SELECT DISTINCT ON (f.user_id)
+    f.user_id,
+    'show_announced',
+    @show_id,
+    f.band_id,
+    f.venue_id
+FROM followers f
+ORDER BY f.user_id, f.rank, f.billing DESC
+ON CONFLICT (user_id, kind, show_id) DO NOTHING;
+
+-- name: CreateSavedShowNotifications :execrows
+-- Notify everyone who saved the given shows (cancellations, postponements)
+INSERT INTO notifications (user_id, kind, show_id)
+SELECT ss.user_id, @kind::text, ss.show_id
+FROM saved_shows ss
+WHERE ss.show_id = ANY(@show_ids::int[])
+ON CONFLICT (user_id, kind, show_id) DO NOTHING;
+
+-- name: CreateShowReminders :execrows
+-- Remind users about saved shows starting within the next day
+INSERT INTO notifications (user_id, kind, show_id)
+SELECT ss.user_id, 'show_reminder', s.id
+FROM saved_shows ss
+JOIN shows s ON s.id = ss.show_id
+WHERE s.status = 'scheduled'
+  AND s.date > NOW()
+  AND s.date <= NOW() + INTERVAL '1 day'
+ON CONFLICT (user_id, kind, show_id) DO NOTHING;
+
+-- name: ListUserNotifications :many
+-- A user's notifications, newest first
+SELECT
+    n.id,
+    n.kind,
+    n.created_at,
+    n.read_at,
+    s.id AS show_id,
+    s.title AS show_title,
+    s.date AS show_date,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    b.name AS band_name,
+    b.slug AS band_slug,
+    COALESCE((
+        SELECT hb.name
+        FROM show_bands sb
+        JOIN bands hb ON hb.id = sb.band_id
+        WHERE sb.show_id = s.id
+        ORDER BY sb.is_headliner DESC NULLS LAST, sb.performance_order DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS headliner_name,
+    COUNT(*) OVER() AS total_count
+FROM notifications n
+JOIN shows s ON s.id = n.show_id
+JOIN venues v ON v.id = s.venue_id
+LEFT JOIN bands b ON b.id = n.band_id
+WHERE n.user_id = @user_id
+ORDER BY n.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: MarkNotificationsRead :execrows
+-- Mark all of a user's unread notifications read
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- ============================================
+-- DELIVERY
+-- ============================================
+
+-- name: ListDueNotificationChannels :many
+-- Enabled channels with undelivered notifications: instant channels always,
+-- daily channels once a day since their last digest (or creation)
+SELECT
+    c.id,
+    c.user_id,
+    c.kind,
+    c.target,
+    c.p256dh,
+    c.auth_secret,
+    c.unsubscribe_token,
+    c.last_notification_id
+FROM notification_channels c
+WHERE c.disabled_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM notifications n
+      WHERE n.user_id = c.user_id
+        AND n.id > c.last_notification_id
+  )
+  AND (
+      c.frequency = 'instant'
+      OR COALESCE(c.last_sent_at, c.created_at) <= NOW() - INTERVAL '1 day'
+  )
+ORDER BY c.id;
+
+-- name: ListPendingNotifications :many
+-- A user's notifications after a channel's cursor, oldest first
+SELECT
+    n.id,
+    n.kind,
+    s.id AS show_id,
+    s.title AS show_title,
+    s.date AS show_date,
+    v.name AS venue_name,
+    b.name AS band_name,
+    COALESCE((
+        SELECT hb.name
+        FROM show_bands sb
+        JOIN bands hb ON hb.id = sb.band_id
+        WHERE sb.show_id = s.id
+        ORDER BY sb.is_headliner DESC NULLS LAST, sb.performance_order DESC NULLS LAST
+        LIMIT 1
+    ), '')::text AS headliner_name
+FROM notifications n
+JOIN shows s ON s.id = n.show_id
+JOIN venues v ON v.id = s.venue_id
+LEFT JOIN bands b ON b.id = n.band_id
+WHERE n.user_id = @user_id
+  AND n.id > @after_id
+ORDER BY n.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: AdvanceNotificationChannel :exec
+-- Record a successful delivery up to and including a notification
+UPDATE notification_channels
+SET last_notification_id = @last_notification_id,
+    last_sent_at = NOW()
+WHERE id = @id;
+
+-- ============================================
+-- CHANNEL MANAGEMENT
+-- ============================================
+
+-- name: UpsertNotificationChannel :one
+-- Add a channel, or update an existing one with the same target. A new or
+-- re-enabled channel starts after the user's latest notification, so it does
+-- not replay old ones.
+INSERT INTO notification_channels (
+    user_id,
+    kind,
+    target,
+    p256dh,
+    auth_secret,
+    frequency,
+    unsubscribe_token,
+    last_notification_id
+) VALUES (
+    @user_id,
+    @kind,
+    @target,
+    @p256dh,
+    @auth_secret,
+    @frequency,
+    @unsubscribe_token,
+    COALESCE((SELECT MAX(id) FROM notifications WHERE user_id = @user_id), 0)::int
+)
+ON CONFLICT (user_id, kind, target) DO UPDATE SET
+    p256dh = EXCLUDED.p256dh,
+    auth_secret = EXCLUDED.auth_secret,
+    frequency = EXCLUDED.frequency,
+    last_notification_id = CASE
+        WHEN notification_channels.disabled_at IS NOT NULL THEN EXCLUDED.last_notification_id
+        ELSE notification_channels.last_notification_id
+    END,
+    disabled_at = NULL
+RETURNING id, kind, target, frequency, last_sent_at, created_at;
+
+-- name: ListNotificationChannels :many
+-- A user's enabled channels
+SELECT
+    id,
+    kind,
+    target,
+    frequency,
+    last_sent_at,
+    created_at
+FROM notification_channels
+WHERE user_id = $1
+  AND disabled_at IS NULL
+ORDER BY id;
+
+-- name: DeleteNotificationChannel :execrows
+-- Remove one of a user's channels
+DELETE FROM notification_channels
+WHERE id = @id
+  AND user_id = @user_id;
+
+-- name: DisableNotificationChannelByToken :one
+-- Unsubscribe a channel from its unsubscribe token (idempotent)
+UPDATE notification_channels
+SET disabled_at = COALESCE(disabled_at, NOW())
+WHERE unsubscribe_token = $1
+RETURNING kind, target;
+
+-- name: DisableNotificationChannel :exec
+-- Disable a channel whose destination no longer exists (e.g. an expired push subscription)
+UPDATE notification_channels
+SET disabled_at = NOW()
+WHERE id = $1;
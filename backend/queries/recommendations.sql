@@ -0,0 +1,102 @@
+-- ============================================
+-- RECOMMENDATION QUERIES
+-- ============================================
+
+-- name: GetBandsBySlugs :many
+-- Resolve seed band slugs
+SELECT
+    id,
+    name,
+    slug
+FROM bands
+WHERE slug = ANY(@slugs::text[]);
+
+-- name: GetGenresBySlugs :many
+-- Resolve seed genre slugs
+SELECT
+    id,
+    name,
+    slug
+FROM genres
+WHERE slug = ANY(@slugs::text[]);
+
+-- name: ListRecommendationEvidence :many
+-- Collect why each upcoming show matches the seeds, one row per piece of evidence:
+--   seed_band    - a seed band is on the bill
+--   similar_band - a band similar to a seed band (band_similarity) is on the bill
+--   genre        - a band on the bill plays a seed genre or one of its subgenres
+-- The handler combines evidence into a score and reasons per show.
+WITH RECURSIVE genre_tree AS (
+    SELECT id FROM genres WHERE id = ANY(@genre_ids::int[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+),
+upcoming AS (
+    SELECT id
+    FROM shows
+    WHERE date >= NOW()
+      AND status = 'scheduled'
+)
+SELECT
+    sb.show_id,
+    'seed_band'::text AS kind,
+    1::float8 AS score,
+    seed.name AS seed_name,
+    seed.name AS band_name,
+    COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+    ''::text AS genre_name,
+    0::int AS shared_show_count
+FROM show_bands sb
+JOIN upcoming u ON u.id = sb.show_id
+JOIN bands seed ON seed.id = sb.band_id
+WHERE sb.band_id = ANY(@band_ids::int[])
+
+UNION ALL
+
+SELECT
+    sb.show_id,
+    'similar_band'::text AS kind,
+    bs.score,
+    seed.name AS seed_name,
+    b.name AS band_name,
+    COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+    COALESCE((
+        SELECT g.name
+        FROM band_genres sg
+        JOIN band_genres og ON og.genre_id = sg.genre_id
+        JOIN genres g ON g.id = sg.genre_id
+        WHERE sg.band_id = bs.band_id
+          AND og.band_id = bs.similar_band_id
+        ORDER BY g.name
+        LIMIT 1
+    ), '')::text AS genre_name,
+    bs.shared_show_count
+FROM band_similarity bs
+JOIN show_bands sb ON sb.band_id = bs.similar_band_id
+JOIN upcoming u ON u.id = sb.show_id
+JOIN bands seed ON seed.id = bs.band_id
+JOIN bands b ON b.id = bs.similar_band_id
+WHERE bs.band_id = ANY(@band_ids::int[])
+  AND NOT (bs.similar_band_id = ANY(@band_ids::int[]))
+
+UNION ALL
+
+(
+    SELECT DISTINCT ON (sb.show_id, sb.band_id)
+        sb.show_id,
+        'genre'::text AS kind,
+        0::float8 AS score,
+        ''::text AS seed_name,
+        b.name AS band_name,
+        COALESCE(sb.is_headliner, false)::boolean AS is_headliner,
+        g.name AS genre_name,
+        0::int AS shared_show_count
+    FROM show_bands sb
+    JOIN upcoming u ON u.id = sb.show_id
+    JOIN bands b ON b.id = sb.band_id
+    JOIN band_genres bg ON bg.band_id = sb.band_id
+    JOIN genre_tree t ON t.id = bg.genre_id
+    JOIN genres g ON g.id = bg.genre_id
+    ORDER BY sb.show_id, sb.band_id, g.name
+)
+ORDER BY show_id;
@@ -0,0 +1,43 @@
+-- ============================================
+-- SHOW REVISION QUERIES
+-- ============================================
+
+-- name: CreateShowRevision :exec
+-- Record a single field change on a show
+INSERT INTO show_revisions (
+    show_id,
+    field,
+    old_value,
+    new_value,
+    actor,
+    source
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: CreateStatusRevisions :exec
+-- Record the same status change for many shows (bulk cancellations)
+INSERT INTO show_revisions (show_id, field, old_value, new_value, actor, source)
+SELECT
+    unnest(@show_ids::int[]),
+    'status',
+    to_jsonb(@old_status::text),
+    to_jsonb(@new_status::text),
+    @actor,
+    @source;
+
+-- name: ListShowRevisions :many
+-- List changes to a show with pagination (most recent first)
+SELECT
+    id,
+    field,
+    old_value,
+    new_value,
+    actor,
+    source,
+    created_at,
+    COUNT(*) OVER() AS total_count
+FROM show_revisions
+WHERE show_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
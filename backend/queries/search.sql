@@ -3,99 +3,410 @@
 -- ============================================
 
 -- name: SearchAll :many
--- Unified search across shows, bands, and venues
--- Returns results with a type discriminator
+-- Unified, paginated search across shows, bands, venues and articles.
+-- Returns a single relevance-ranked list with a type discriminator, limited to
+-- the entity types in @types. The query is a prefix-aware to_tsquery expression.
+-- With @highlight, also returns (otherwise empty strings) the first field that
+-- matches any query term and a ts_headline snippet of it.
 -- Note: This uses UNION ALL for efficiency (no deduplication needed)
-SELECT
-    'show' AS entity_type,
-    s.id,
-    COALESCE(s.title, '') AS name,
-    v.name AS extra_info,
-    s.date::text AS date_info
-FROM shows s
-JOIN venues v ON s.venue_id = v.id
-WHERE s.date >= NOW()
-  AND s.status = 'scheduled'
-  AND to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
+WITH results AS (
+    SELECT
+        'show'::text AS entity_type,
+        s.id,
+        COALESCE(s.title, NULLIF(lineup.names, ''), v.name) AS name,
+        NULL::text AS slug,
+        v.name AS extra_info,
+        s.date AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', COALESCE(s.title, '')), 'A') ||
+            setweight(to_tsvector('english', lineup.names), 'A') ||
+            setweight(to_tsvector('english', v.name), 'B') ||
+            setweight(to_tsvector('english', COALESCE(s.description, '')), 'C'),
+            to_tsquery('english', @query)
+        ) AS rank,
+        (CASE WHEN @highlight::boolean THEN
+            CASE
+                WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'title'
+                WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'lineup'
+                WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'venue'
+                WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'description'
+            END
+        ELSE '' END)::text AS matched_field,
+        (CASE WHEN @highlight::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN s.title
+                    WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN lineup.names
+                    WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN v.name
+                    WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN s.description
+                END),
+                to_tsquery('english', replace(@query, ' & ', ' | ')),
+                @headline_options::text)
+        ELSE '' END)::text AS snippet
+    FROM shows s
+    LEFT JOIN venues v ON s.venue_id = v.id  -- always matches; LEFT keeps extra_info nullable across the union
+    CROSS JOIN LATERAL (
+        SELECT COALESCE(string_agg(b.name, ', ' ORDER BY sb.performance_order DESC NULLS LAST), '') AS names
+        FROM show_bands sb
+        JOIN bands b ON sb.band_id = b.id
+        WHERE sb.show_id = s.id
+    ) lineup
+    WHERE 'show' = ANY(@types::text[])
+      AND s.date >= NOW()
+      AND s.status = 'scheduled'
+      AND (
+        to_tsvector('english', COALESCE(s.title, '')) ||
+        to_tsvector('english', lineup.names) ||
+        to_tsvector('english', v.name) ||
+        to_tsvector('english', COALESCE(s.description, ''))
+      ) @@ to_tsquery('english', @query)
 
-UNION ALL
+    UNION ALL
 
-SELECT
-    'band' AS entity_type,
-    b.id,
-    b.name,
-    b.slug AS extra_info,
-    NULL AS date_info
-FROM bands b
-WHERE to_tsvector('english', b.name || ' ' || COALESCE(b.bio, '')) @@ plainto_tsquery('english', $1)
+    SELECT
+        'band' AS entity_type,
+        b.id,
+        b.name,
+        b.slug,
+        b.hometown AS extra_info,
+        NULL::timestamptz AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', b.name), 'A') ||
+            setweight(to_tsvector('english', COALESCE(b.bio, '')), 'D'),
+            to_tsquery('english', @query)
+        ) AS rank,
+        (CASE WHEN @highlight::boolean THEN
+            CASE
+                WHEN to_tsvector('english', b.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'name'
+                WHEN to_tsvector('english', COALESCE(b.bio, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'bio'
+            END
+        ELSE '' END)::text,
+        (CASE WHEN @highlight::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', b.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN b.name
+                    WHEN to_tsvector('english', COALESCE(b.bio, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN b.bio
+                END),
+                to_tsquery('english', replace(@query, ' & ', ' | ')),
+                @headline_options::text)
+        ELSE '' END)::text
+    FROM bands b
+    WHERE 'band' = ANY(@types::text[])
+      AND to_tsvector('english', b.name || ' ' || COALESCE(b.bio, '')) @@ to_tsquery('english', @query)
 
-UNION ALL
+    UNION ALL
 
-SELECT
-    'venue' AS entity_type,
-    v.id,
-    v.name,
-    v.slug AS extra_info,
-    NULL AS date_info
-FROM venues v
-WHERE to_tsvector('english', v.name) @@ plainto_tsquery('english', $1)
+    SELECT
+        'venue' AS entity_type,
+        v.id,
+        v.name,
+        v.slug,
+        v.region AS extra_info,
+        NULL::timestamptz AS date_info,
+        ts_rank(setweight(to_tsvector('english', v.name), 'A'), to_tsquery('english', @query)) AS rank,
+        (CASE WHEN @highlight::boolean THEN 'name' ELSE '' END)::text,
+        (CASE WHEN @highlight::boolean THEN
+            ts_headline('english', html_escape(v.name), to_tsquery('english', @query), @headline_options::text)
+        ELSE '' END)::text
+    FROM venues v
+    WHERE 'venue' = ANY(@types::text[])
+      AND to_tsvector('english', v.name) @@ to_tsquery('english', @query)
 
-LIMIT $2;
+    UNION ALL
+
+    SELECT
+        'article' AS entity_type,
+        a.id,
+        a.title,
+        a.slug,
+        a.excerpt AS extra_info,
+        a.published_at AS date_info,
+        ts_rank(
+            setweight(to_tsvector('english', a.title), 'A') ||
+            setweight(to_tsvector('english', COALESCE(a.excerpt, '')), 'B') ||
+            setweight(to_tsvector('english', a.content), 'C'),
+            to_tsquery('english', @query)
+        ) AS rank,
+        (CASE WHEN @highlight::boolean THEN
+            CASE
+                WHEN to_tsvector('english', a.title) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'title'
+                WHEN to_tsvector('english', COALESCE(a.excerpt, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'excerpt'
+                WHEN to_tsvector('english', a.content) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'content'
+            END
+        ELSE '' END)::text,
+        (CASE WHEN @highlight::boolean THEN
+            ts_headline('english', html_escape(
+                CASE
+                    WHEN to_tsvector('english', a.title) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN a.title
+                    WHEN to_tsvector('english', COALESCE(a.excerpt, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN a.excerpt
+                    WHEN to_tsvector('english', a.content) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN a.content
+                END),
+                to_tsquery('english', replace(@query, ' & ', ' | ')),
+                @headline_options::text)
+        ELSE '' END)::text
+    FROM articles a
+    WHERE 'article' = ANY(@types::text[])
+      AND a.is_published = TRUE
+      AND to_tsvector('english', a.title || ' ' || COALESCE(a.excerpt, '') || ' ' || a.content) @@ to_tsquery('english', @query)
+)
+SELECT
+    entity_type,
+    id,
+    name,
+    slug,
+    extra_info,
+    date_info,
+    rank::real AS rank,
+    matched_field,
+    snippet,
+    COUNT(*) OVER() AS total_count
+FROM results
+ORDER BY rank DESC, entity_type, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GlobalSearchShows :many
--- Search shows only (for search endpoint's shows section)
+-- Search upcoming shows by title, lineup band names, venue name and description, best match first.
+-- The query is a to_tsquery expression with prefix terms (e.g. 'avet:*') built by the handler.
+-- With @highlight, also returns (otherwise empty strings) the first field that
+-- matches any query term and a ts_headline snippet of it. A row can match with
+-- its terms spread across fields, so no single field need match the whole query.
 SELECT
     s.id,
     s.title,
     s.date,
-    v.name AS venue_name
+    v.name AS venue_name,
+    lineup.band_names::text[] AS band_names,
+    ts_rank(
+        setweight(to_tsvector('english', COALESCE(s.title, '')), 'A') ||
+        setweight(to_tsvector('english', lineup.names), 'A') ||
+        setweight(to_tsvector('english', v.name), 'B') ||
+        setweight(to_tsvector('english', COALESCE(s.description, '')), 'C'),
+        to_tsquery('english', @query)
+    )::real AS rank,
+    (CASE WHEN @highlight::boolean THEN
+        CASE
+            WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'title'
+            WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'lineup'
+            WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'venue'
+            WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'description'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN @highlight::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', COALESCE(s.title, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN s.title
+                WHEN to_tsvector('english', lineup.names) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN lineup.names
+                WHEN to_tsvector('english', v.name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN v.name
+                WHEN to_tsvector('english', COALESCE(s.description, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN s.description
+            END),
+            to_tsquery('english', replace(@query, ' & ', ' | ')),
+            @headline_options::text)
+    ELSE '' END)::text AS snippet
 FROM shows s
 JOIN venues v ON s.venue_id = v.id
+CROSS JOIN LATERAL (
+    SELECT
+        COALESCE(string_agg(b.name, ' '), '') AS names,
+        COALESCE(array_agg(b.name ORDER BY sb.performance_order DESC NULLS LAST)
+            FILTER (WHERE b.id IS NOT NULL), '{}') AS band_names
+    FROM show_bands sb
+    JOIN bands b ON sb.band_id = b.id
+    WHERE sb.show_id = s.id
+) lineup
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
-ORDER BY s.date ASC
-LIMIT $2;
+  AND (
+    to_tsvector('english', COALESCE(s.title, '')) ||
+    to_tsvector('english', lineup.names) ||
+    to_tsvector('english', v.name) ||
+    to_tsvector('english', COALESCE(s.description, ''))
+  ) @@ to_tsquery('english', @query)
+ORDER BY rank DESC, s.date ASC, s.id ASC
+LIMIT sqlc.arg('limit');
 
 -- name: GlobalSearchBands :many
--- Search bands only (for search endpoint's bands section)
+-- Search bands by name and bio, best match first (name matches outrank bio matches).
+-- With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
 SELECT
     id,
     name,
-    slug
+    slug,
+    ts_rank(
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', COALESCE(bio, '')), 'D'),
+        to_tsquery('english', @query)
+    )::real AS rank,
+    (CASE WHEN @highlight::boolean THEN
+        CASE
+            WHEN to_tsvector('english', name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'name'
+            WHEN to_tsvector('english', COALESCE(bio, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'bio'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN @highlight::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', name) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN name
+                WHEN to_tsvector('english', COALESCE(bio, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN bio
+            END),
+            to_tsquery('english', replace(@query, ' & ', ' | ')),
+            @headline_options::text)
+    ELSE '' END)::text AS snippet
 FROM bands
-WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ plainto_tsquery('english', $1)
-ORDER BY name ASC
-LIMIT $2;
+WHERE to_tsvector('english', name || ' ' || COALESCE(bio, '')) @@ to_tsquery('english', @query)
+ORDER BY rank DESC, name ASC
+LIMIT sqlc.arg('limit');
 
 -- name: GlobalSearchVenues :many
--- Search venues only (for search endpoint's venues section)
+-- Search venues by name, best match first.
+-- With @highlight, also returns (otherwise empty strings) the field and a ts_headline snippet.
 SELECT
     id,
     name,
-    slug
+    slug,
+    ts_rank(to_tsvector('english', name), to_tsquery('english', @query))::real AS rank,
+    (CASE WHEN @highlight::boolean THEN 'name' ELSE '' END)::text AS matched_field,
+    (CASE WHEN @highlight::boolean THEN
+        ts_headline('english', html_escape(name), to_tsquery('english', @query), @headline_options::text)
+    ELSE '' END)::text AS snippet
 FROM venues
-WHERE to_tsvector('english', name) @@ plainto_tsquery('english', $1)
-ORDER BY name ASC
-LIMIT $2;
+WHERE to_tsvector('english', name) @@ to_tsquery('english', @query)
+ORDER BY rank DESC, name ASC
+LIMIT sqlc.arg('limit');
 
--- name: SearchShowsWithBands :many
--- Search shows including band names in the search
--- This finds shows where either the title OR any band name matches
-SELECT DISTINCT ON (s.date, s.id)
-    s.id,
-    s.title,
-    s.date,
-    v.name AS venue_name
-FROM shows s
-JOIN venues v ON s.venue_id = v.id
-LEFT JOIN show_bands sb ON s.id = sb.show_id
-LEFT JOIN bands b ON sb.band_id = b.id
-WHERE s.date >= NOW()
-  AND s.status = 'scheduled'
-  AND (
-    to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
-    OR to_tsvector('english', COALESCE(b.name, '')) @@ plainto_tsquery('english', $1)
-  )
-ORDER BY s.date ASC, s.id ASC
-LIMIT $2;
+-- name: GlobalSearchArticles :many
+-- Search published articles by title, excerpt and content, best match first.
+-- With @highlight, also returns (otherwise empty strings) the first field that matches any query term and a ts_headline snippet of it.
+SELECT
+    id,
+    title,
+    slug,
+    excerpt,
+    published_at,
+    ts_rank(
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', COALESCE(excerpt, '')), 'B') ||
+        setweight(to_tsvector('english', content), 'C'),
+        to_tsquery('english', @query)
+    )::real AS rank,
+    (CASE WHEN @highlight::boolean THEN
+        CASE
+            WHEN to_tsvector('english', title) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'title'
+            WHEN to_tsvector('english', COALESCE(excerpt, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'excerpt'
+            WHEN to_tsvector('english', content) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN 'content'
+        END
+    ELSE '' END)::text AS matched_field,
+    (CASE WHEN @highlight::boolean THEN
+        ts_headline('english', html_escape(
+            CASE
+                WHEN to_tsvector('english', title) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN title
+                WHEN to_tsvector('english', COALESCE(excerpt, '')) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN excerpt
+                WHEN to_tsvector('english', content) @@ to_tsquery('english', replace(@query, ' & ', ' | ')) THEN content
+            END),
+            to_tsquery('english', replace(@query, ' & ', ' | ')),
+            @headline_options::text)
+    ELSE '' END)::text AS snippet
+FROM articles
+WHERE is_published = TRUE
+  AND to_tsvector('english', title || ' ' || COALESCE(excerpt, '') || ' ' || content) @@ to_tsquery('english', @query)
+ORDER BY rank DESC, published_at DESC NULLS LAST
+LIMIT sqlc.arg('limit');
+
+-- name: Autocomplete :many
+-- Typo-tolerant suggestions across upcoming shows, bands, venues and genres.
+-- A name matches when it starts with the input (or has a word that does) or is
+-- trigram-similar to it (pg_trgm word_similarity, so "orang peel" finds
+-- "The Orange Peel"). @prefix is the LIKE-escaped input with a trailing '%'.
+-- Shows match on their display title: the title, or for untitled (e.g.
+-- band-submitted) shows the lineup, headliner first, or else the venue name.
+-- Each type is capped at @per_type rows; prefix matches score above fuzzy ones.
+(
+    SELECT
+        'show'::text AS entity_type,
+        s.id,
+        display.title AS name,
+        NULL::text AS slug,
+        s.date,
+        (CASE
+            WHEN display.title ILIKE @prefix::text THEN 1.0
+            WHEN display.title ILIKE '% ' || @prefix::text THEN 0.9
+            ELSE word_similarity(@q::text, display.title)
+        END)::real AS score
+    FROM shows s
+    JOIN venues v ON s.venue_id = v.id
+    CROSS JOIN LATERAL (
+        SELECT COALESCE(s.title, NULLIF(string_agg(b.name, ', ' ORDER BY sb.performance_order DESC NULLS LAST), ''), v.name)::text AS title
+        FROM show_bands sb
+        JOIN bands b ON sb.band_id = b.id
+        WHERE sb.show_id = s.id
+    ) display
+    WHERE s.status = 'scheduled'
+      AND s.date >= NOW()
+      AND (
+        display.title ILIKE @prefix::text
+        OR display.title ILIKE '% ' || @prefix::text
+        OR @q::text <% display.title
+      )
+    ORDER BY score DESC, s.date
+    LIMIT @per_type::int
+)
+UNION ALL
+(
+    SELECT
+        'band'::text,
+        b.id,
+        b.name,
+        b.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN b.name ILIKE @prefix::text THEN 1.0
+            WHEN b.name ILIKE '% ' || @prefix::text THEN 0.9
+            ELSE word_similarity(@q::text, b.name)
+        END)::real AS score
+    FROM bands b
+    WHERE b.name ILIKE @prefix::text
+       OR b.name ILIKE '% ' || @prefix::text
+       OR @q::text <% b.name
+    ORDER BY score DESC, b.name
+    LIMIT @per_type::int
+)
+UNION ALL
+(
+    SELECT
+        'venue'::text,
+        v.id,
+        v.name,
+        v.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN v.name ILIKE @prefix::text THEN 1.0
+            WHEN v.name ILIKE '% ' || @prefix::text THEN 0.9
+            ELSE word_similarity(@q::text, v.name)
+        END)::real AS score
+    FROM venues v
+    WHERE v.name ILIKE @prefix::text
+       OR v.name ILIKE '% ' || @prefix::text
+       OR @q::text <% v.name
+    ORDER BY score DESC, v.name
+    LIMIT @per_type::int
+)
+UNION ALL
+(
+    SELECT
+        'genre'::text,
+        g.id,
+        g.name,
+        g.slug::text,
+        NULL::timestamptz,
+        (CASE
+            WHEN g.name ILIKE @prefix::text THEN 1.0
+            WHEN g.name ILIKE '% ' || @prefix::text THEN 0.9
+            ELSE word_similarity(@q::text, g.name)
+        END)::real AS score
+    FROM genres g
+    WHERE g.name ILIKE @prefix::text
+       OR g.name ILIKE '% ' || @prefix::text
+       OR @q::text <% g.name
+    ORDER BY score DESC, g.name
+    LIMIT @per_type::int
+)
+ORDER BY score DESC, entity_type, name;
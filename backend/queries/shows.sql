@@ -26,9 +26,12 @@ SELECT
     v.address AS venue_address,
     v.region AS venue_region,
     v.website AS venue_website,
-    v.image_url AS venue_image_url
+    v.image_url AS venue_image_url,
+    s.rescheduled_to,
+    rs.date AS rescheduled_date
 FROM shows s
 JOIN venues v ON s.venue_id = v.id
+LEFT JOIN shows rs ON s.rescheduled_to = rs.id
 WHERE s.id = $1;
 
 -- name: ListUpcomingShows :many
@@ -60,6 +63,30 @@ WHERE s.date >= NOW()
 ORDER BY s.date ASC, s.id ASC
 LIMIT $1 OFFSET $2;
 
+-- name: ListShowsByIDs :many
+-- Get shows by ID (e.g. ranked recommendations); the caller restores its own order
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url
+FROM shows s
+JOIN venues v ON s.venue_id = v.id
+WHERE s.id = ANY(@ids::int[]);
+
 -- name: ListShowsByDateRange :many
 -- Filter shows by date range (inclusive)
 SELECT
@@ -148,7 +175,13 @@ ORDER BY s.date ASC, s.id ASC
 LIMIT $2 OFFSET $3;
 
 -- name: ListShowsByGenre :many
--- Filter shows by genre slug(s) - shows with bands matching any of the genres
+-- Filter shows by genre slug(s) - shows with bands matching any of the genres or their subgenres
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT DISTINCT ON (s.date, s.id)
     s.id,
     s.title,
@@ -171,10 +204,9 @@ FROM shows s
 JOIN venues v ON s.venue_id = v.id
 JOIN show_bands sb ON s.id = sb.show_id
 JOIN band_genres bg ON sb.band_id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND g.slug = ANY($1::text[])
+  AND bg.genre_id IN (SELECT id FROM genre_tree)
 ORDER BY s.date ASC, s.id ASC
 LIMIT $2 OFFSET $3;
 
@@ -290,6 +322,24 @@ WHERE s.date >= DATE_TRUNC('week', NOW()) + INTERVAL '4 days'  -- Friday
   AND s.status = 'scheduled'
 ORDER BY s.date ASC, s.id ASC;
 
+-- name: ShowExists :one
+-- Check if show exists by ID
+SELECT EXISTS(SELECT 1 FROM shows WHERE id = $1);
+
+-- name: FindDuplicateShow :one
+-- Find a show at the venue on a venue-local day (@day) sharing a band
+-- (case-insensitive) with a submission, to reject duplicate submissions
+SELECT s.id
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN bands b ON sb.band_id = b.id
+WHERE s.venue_id = @venue_id
+  AND (s.date AT TIME ZONE 'America/New_York')::date = @day::date
+  AND s.status <> 'cancelled'
+  AND LOWER(b.name) = ANY(@band_names::text[])
+ORDER BY s.id
+LIMIT 1;
+
 -- name: GetShowBands :many
 -- Get all bands for a show with their genres
 SELECT
@@ -319,15 +369,20 @@ WHERE bg.band_id = $1
 ORDER BY g.name;
 
 -- name: CountShowsByGenre :one
--- Count upcoming shows by genre (for genre filter)
+-- Count upcoming shows by genre, including subgenres (for genre filter)
+WITH RECURSIVE genre_tree AS (
+    -- Requested genres and all of their subgenres
+    SELECT id FROM genres WHERE slug = ANY($1::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
 SELECT COUNT(DISTINCT s.id)
 FROM shows s
 JOIN show_bands sb ON s.id = sb.show_id
 JOIN band_genres bg ON sb.band_id = bg.band_id
-JOIN genres g ON bg.genre_id = g.id
 WHERE s.date >= NOW()
   AND s.status = 'scheduled'
-  AND g.slug = ANY($1::text[]);
+  AND bg.genre_id IN (SELECT id FROM genre_tree);
 
 -- name: CreateShow :one
 -- Create a new show (band submission)
@@ -367,3 +422,44 @@ WHERE s.date >= NOW()
   AND to_tsvector('english', COALESCE(s.title, '')) @@ plainto_tsquery('english', $1)
 ORDER BY s.date ASC
 LIMIT $2;
+
+-- name: CompletePastShows :execrows
+-- Mark scheduled shows as completed once their venue-local date has passed,
+-- recording a status revision for each. Returns the number of shows completed.
+-- SKIP LOCKED lets concurrent runs (API ticker + scraper job) proceed without blocking.
+WITH due AS (
+    SELECT id
+    FROM shows
+    WHERE status = 'scheduled'
+      AND (date AT TIME ZONE 'America/New_York')::date < (NOW() AT TIME ZONE 'America/New_York')::date
+    FOR UPDATE SKIP LOCKED
+),
+completed AS (
+    UPDATE shows s
+    SET status = 'completed',
+        updated_at = NOW()
+    FROM due
+    WHERE s.id = due.id
+    RETURNING s.id
+)
+INSERT INTO show_revisions (show_id, field, old_value, new_value, actor, source)
+SELECT id, 'status', '"scheduled"', '"completed"', 'maintenance', 'system'
+FROM completed;
+
+-- name: GetShowsLastModified :one
+-- Newest updated_at across shows, their venues and their lineups (for the
+-- Last-Modified header). NULL when no shows are given.
+SELECT GREATEST(
+    (SELECT MAX(s.updated_at) FROM shows s WHERE s.id = ANY(@ids::int[])),
+    (SELECT MAX(v.updated_at) FROM venues v JOIN shows s ON s.venue_id = v.id WHERE s.id = ANY(@ids::int[])),
+    (SELECT MAX(b.updated_at) FROM bands b JOIN show_bands sb ON sb.band_id = b.id WHERE sb.show_id = ANY(@ids::int[]))
+)::timestamptz AS last_modified;
+
+-- name: GetCatalogLastModified :one
+-- Newest updated_at across all shows, venues and bands, for responses that
+-- aggregate the whole catalog (e.g. genre show counts)
+SELECT GREATEST(
+    (SELECT MAX(updated_at) FROM shows),
+    (SELECT MAX(updated_at) FROM venues),
+    (SELECT MAX(updated_at) FROM bands)
+)::timestamptz AS last_modified;
@@ -0,0 +1,203 @@
+-- ============================================
+-- BAND SIMILARITY QUERIES
+-- ============================================
+
+-- name: ScoreSimilarBands :many
+-- Score similar bands for the given bands (all bands when band_ids is empty).
+-- Candidates share at least one genre or show; venue and hometown overlap
+-- only re-rank them. Weights are applied here so the ranking and per-band
+-- cap use the blended score.
+WITH src AS (
+    SELECT id
+    FROM bands
+    WHERE cardinality(@band_ids::int[]) = 0
+       OR id = ANY(@band_ids::int[])
+),
+genre_counts AS (
+    SELECT band_id, COUNT(*) AS n
+    FROM band_genres
+    GROUP BY band_id
+),
+show_counts AS (
+    SELECT band_id, COUNT(DISTINCT show_id) AS n
+    FROM show_bands
+    GROUP BY band_id
+),
+band_venues AS (
+    SELECT DISTINCT sb.band_id, s.venue_id
+    FROM show_bands sb
+    JOIN shows s ON s.id = sb.show_id
+),
+venue_counts AS (
+    SELECT band_id, COUNT(*) AS n
+    FROM band_venues
+    GROUP BY band_id
+),
+genre_pairs AS (
+    SELECT a.band_id, b.band_id AS other_id, COUNT(*) AS shared
+    FROM band_genres a
+    JOIN band_genres b ON b.genre_id = a.genre_id AND b.band_id <> a.band_id
+    WHERE a.band_id IN (SELECT id FROM src)
+    GROUP BY a.band_id, b.band_id
+),
+show_pairs AS (
+    SELECT a.band_id, b.band_id AS other_id, COUNT(DISTINCT a.show_id) AS shared
+    FROM show_bands a
+    JOIN show_bands b ON b.show_id = a.show_id AND b.band_id <> a.band_id
+    WHERE a.band_id IN (SELECT id FROM src)
+    GROUP BY a.band_id, b.band_id
+),
+candidates AS (
+    SELECT band_id, other_id FROM genre_pairs
+    UNION
+    SELECT band_id, other_id FROM show_pairs
+),
+components AS (
+    SELECT
+        c.band_id,
+        c.other_id,
+        COALESCE(gp.shared, 0)::int AS shared_genre_count,
+        COALESCE(sp.shared, 0)::int AS shared_show_count,
+        (
+            SELECT COUNT(*)
+            FROM band_venues va
+            JOIN band_venues vb ON vb.venue_id = va.venue_id
+            WHERE va.band_id = c.band_id AND vb.band_id = c.other_id
+        )::int AS shared_venue_count,
+        COALESCE(gp.shared::float8 / NULLIF(ga.n + gb.n - gp.shared, 0), 0)::float8 AS genre_score,
+        COALESCE(sp.shared::float8 / NULLIF(LEAST(sa.n, sb.n), 0), 0)::float8 AS co_bill_score,
+        (
+            CASE
+                WHEN ba.hometown IS NOT NULL
+                 AND LOWER(TRIM(ba.hometown)) <> ''
+                 AND LOWER(TRIM(ba.hometown)) = LOWER(TRIM(bb.hometown))
+                THEN 1
+                ELSE 0
+            END
+        )::float8 AS hometown_score,
+        COALESCE(va.n, 0) AS venues_a,
+        COALESCE(vb.n, 0) AS venues_b
+    FROM candidates c
+    JOIN bands ba ON ba.id = c.band_id
+    JOIN bands bb ON bb.id = c.other_id
+    LEFT JOIN genre_pairs gp ON gp.band_id = c.band_id AND gp.other_id = c.other_id
+    LEFT JOIN show_pairs sp ON sp.band_id = c.band_id AND sp.other_id = c.other_id
+    LEFT JOIN genre_counts ga ON ga.band_id = c.band_id
+    LEFT JOIN genre_counts gb ON gb.band_id = c.other_id
+    LEFT JOIN show_counts sa ON sa.band_id = c.band_id
+    LEFT JOIN show_counts sb ON sb.band_id = c.other_id
+    LEFT JOIN venue_counts va ON va.band_id = c.band_id
+    LEFT JOIN venue_counts vb ON vb.band_id = c.other_id
+),
+scored AS (
+    SELECT
+        band_id,
+        other_id,
+        shared_genre_count,
+        shared_show_count,
+        shared_venue_count,
+        genre_score,
+        co_bill_score,
+        COALESCE(shared_venue_count::float8 / NULLIF(venues_a + venues_b - shared_venue_count, 0), 0)::float8 AS venue_score,
+        hometown_score
+    FROM components
+),
+weighted AS (
+    SELECT
+        s.band_id,
+        s.other_id,
+        s.shared_genre_count,
+        s.shared_show_count,
+        s.shared_venue_count,
+        s.genre_score,
+        s.co_bill_score,
+        s.venue_score,
+        s.hometown_score,
+        (
+            sqlc.arg('genre_weight')::float8 * s.genre_score +
+            sqlc.arg('co_bill_weight')::float8 * s.co_bill_score +
+            sqlc.arg('venue_weight')::float8 * s.venue_score +
+            sqlc.arg('hometown_weight')::float8 * s.hometown_score
+        )::float8 AS score
+    FROM scored s
+),
+ranked AS (
+    SELECT
+        w.band_id,
+        w.other_id,
+        w.shared_genre_count,
+        w.shared_show_count,
+        w.shared_venue_count,
+        w.genre_score,
+        w.co_bill_score,
+        w.venue_score,
+        w.hometown_score,
+        w.score,
+        ROW_NUMBER() OVER (PARTITION BY w.band_id ORDER BY w.score DESC, w.other_id) AS position
+    FROM weighted w
+)
+SELECT
+    r.band_id,
+    r.other_id AS similar_band_id,
+    b.name,
+    b.slug,
+    b.image_url,
+    r.score,
+    r.genre_score,
+    r.co_bill_score,
+    r.venue_score,
+    r.hometown_score,
+    r.shared_genre_count,
+    r.shared_show_count,
+    r.shared_venue_count
+FROM ranked r
+JOIN bands b ON b.id = r.other_id
+WHERE r.position <= sqlc.arg('per_band_limit')::int
+ORDER BY r.band_id, r.position;
+
+-- name: LockBandSimilarityRefresh :exec
+-- Serialize full refreshes: the lock is held until the transaction ends, so a
+-- concurrent refresh waits and then scores the committed data
+SELECT pg_advisory_xact_lock(hashtext('band_similarity_refresh'));
+
+-- name: DeleteBandSimilarities :exec
+-- Clear precomputed similarities before a full refresh
+DELETE FROM band_similarity;
+
+-- name: InsertBandSimilarities :copyfrom
+-- Bulk load precomputed similarities
+INSERT INTO band_similarity (
+    band_id,
+    similar_band_id,
+    score,
+    genre_score,
+    co_bill_score,
+    venue_score,
+    hometown_score,
+    shared_genre_count,
+    shared_show_count,
+    shared_venue_count
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+);
+
+-- name: ListBandSimilarities :many
+-- Get a band's precomputed similar bands, best match first
+SELECT
+    b.id,
+    b.name,
+    b.slug,
+    b.image_url,
+    bs.score,
+    bs.genre_score,
+    bs.co_bill_score,
+    bs.venue_score,
+    bs.hometown_score,
+    bs.shared_genre_count,
+    bs.shared_show_count,
+    bs.shared_venue_count
+FROM band_similarity bs
+JOIN bands b ON b.id = bs.similar_band_id
+WHERE bs.band_id = $1
+ORDER BY bs.score DESC, b.name ASC
+LIMIT $2;
@@ -0,0 +1,67 @@
+-- ============================================
+-- SHOW STREAM QUERIES
+-- ============================================
+
+-- name: CreateShowStreamEvent :exec
+-- Record a show event and wake stream listeners on every API replica. The
+-- notification is delivered when the surrounding transaction commits.
+WITH event AS (
+    INSERT INTO show_stream_events (show_id, event_type)
+    VALUES (@show_id, @event_type)
+    RETURNING id
+)
+SELECT pg_notify('show_stream', id::text) FROM event;
+
+-- name: GetLatestShowStreamEventID :one
+-- Cursor for a client that starts without Last-Event-ID
+SELECT COALESCE(MAX(id), 0)::bigint FROM show_stream_events;
+
+-- name: ListShowStreamEvents :many
+-- Events after a cursor with the current state of their shows, optionally
+-- limited to venue slugs, regions and genre slugs (including subgenres).
+-- Empty filters match everything.
+WITH RECURSIVE genre_tree AS (
+    SELECT id FROM genres WHERE slug = ANY(@genres::text[])
+    UNION
+    SELECT g.id FROM genres g JOIN genre_tree t ON g.parent_id = t.id
+)
+SELECT
+    e.id AS event_id,
+    e.event_type,
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url
+FROM show_stream_events e
+JOIN shows s ON s.id = e.show_id
+JOIN venues v ON v.id = s.venue_id
+WHERE e.id > @after_id
+  AND (COALESCE(cardinality(@venues::text[]), 0) = 0 OR v.slug = ANY(@venues::text[]))
+  AND (COALESCE(cardinality(@regions::text[]), 0) = 0 OR v.region = ANY(@regions::text[]))
+  AND (COALESCE(cardinality(@genres::text[]), 0) = 0 OR EXISTS (
+      SELECT 1
+      FROM show_bands sb
+      JOIN band_genres bg ON bg.band_id = sb.band_id
+      WHERE sb.show_id = s.id
+        AND bg.genre_id IN (SELECT id FROM genre_tree)
+  ))
+ORDER BY e.id
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteOldShowStreamEvents :execrows
+-- Events older than the resume window are no longer needed
+DELETE FROM show_stream_events
+WHERE created_at < @before;
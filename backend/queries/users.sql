@@ -0,0 +1,265 @@
+-- ============================================
+-- USER ACCOUNT QUERIES
+-- ============================================
+
+-- name: CreateLoginToken :execrows
+-- Store a magic-link token (hashed) for an email address, unless the email
+-- already has an unused, unexpired one. Returns 0 rows affected in that case.
+INSERT INTO login_tokens (token_hash, email, expires_at)
+SELECT @token_hash, @email, @expires_at
+WHERE NOT EXISTS (
+    SELECT 1 FROM login_tokens
+    WHERE email = @email
+      AND used_at IS NULL
+      AND expires_at > NOW()
+);
+
+-- name: ConsumeLoginToken :one
+-- Mark an unused, unexpired magic-link token used and return its email.
+-- Returns no row when the token is unknown, expired or already used.
+UPDATE login_tokens
+SET used_at = NOW()
+WHERE token_hash = @token_hash
+  AND used_at IS NULL
+  AND expires_at > NOW()
+RETURNING email;
+
+-- name: UpsertUserLogin :one
+-- Create the user on first sign-in and record the login time
+INSERT INTO users (email, last_login_at)
+VALUES (@email, NOW())
+ON CONFLICT (email) DO UPDATE SET last_login_at = NOW()
+RETURNING id, email, created_at, last_login_at;
+
+-- name: CreateSession :exec
+-- Store a bearer session (hashed) for a user
+INSERT INTO sessions (token_hash, user_id, expires_at)
+VALUES (@token_hash, @user_id, @expires_at);
+
+-- name: GetSessionUser :one
+-- Resolve an unexpired session to its user
+SELECT
+    u.id,
+    u.email,
+    u.created_at,
+    u.last_login_at
+FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.token_hash = $1
+  AND s.expires_at > NOW();
+
+-- name: DeleteSession :exec
+-- Sign out a single session
+DELETE FROM sessions WHERE token_hash = $1;
+
+-- name: DeleteExpiredAuthTokens :one
+-- Purge expired magic-link tokens and sessions, returning how many were removed
+WITH expired_tokens AS (
+    DELETE FROM login_tokens WHERE expires_at <= NOW() RETURNING 1
+),
+expired_sessions AS (
+    DELETE FROM sessions WHERE expires_at <= NOW() RETURNING 1
+)
+SELECT ((SELECT COUNT(*) FROM expired_tokens) + (SELECT COUNT(*) FROM expired_sessions))::bigint AS deleted;
+
+-- name: GetUser :one
+-- Get a user with counts of what they save and follow
+SELECT
+    u.id,
+    u.email,
+    u.created_at,
+    u.last_login_at,
+    (SELECT COUNT(*) FROM saved_shows ss WHERE ss.user_id = u.id)::int AS saved_show_count,
+    (SELECT COUNT(*) FROM followed_bands fb WHERE fb.user_id = u.id)::int AS followed_band_count,
+    (SELECT COUNT(*) FROM followed_venues fv WHERE fv.user_id = u.id)::int AS followed_venue_count
+FROM users u
+WHERE u.id = $1;
+
+-- ============================================
+-- SAVED SHOWS
+-- ============================================
+
+-- name: SaveShow :one
+-- Save a show for a user (idempotent). Returns no row when the show does not exist.
+WITH target AS (
+    SELECT id FROM shows WHERE id = @show_id
+),
+saved AS (
+    INSERT INTO saved_shows (user_id, show_id)
+    SELECT @user_id, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target;
+
+-- name: UnsaveShow :exec
+-- Remove a saved show (no-op when not saved)
+DELETE FROM saved_shows
+WHERE user_id = @user_id
+  AND show_id = @show_id;
+
+-- name: ListSavedShows :many
+-- List a user's saved shows, soonest first (past shows included)
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url,
+    COUNT(*) OVER() AS total_count
+FROM saved_shows ss
+JOIN shows s ON s.id = ss.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE ss.user_id = @user_id
+ORDER BY s.date ASC, s.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- ============================================
+-- FOLLOWS
+-- ============================================
+
+-- name: FollowBand :one
+-- Follow a band by slug (idempotent). Returns no row when the band does not exist.
+WITH target AS (
+    SELECT id FROM bands WHERE slug = @slug
+),
+followed AS (
+    INSERT INTO followed_bands (user_id, band_id)
+    SELECT @user_id, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target;
+
+-- name: UnfollowBand :one
+-- Unfollow a band by slug. Returns no row when the band does not exist.
+WITH target AS (
+    SELECT id FROM bands WHERE slug = @slug
+),
+unfollowed AS (
+    DELETE FROM followed_bands fb
+    USING target
+    WHERE fb.user_id = @user_id
+      AND fb.band_id = target.id
+)
+SELECT id FROM target;
+
+-- name: FollowVenue :one
+-- Follow a venue by slug (idempotent). Returns no row when the venue does not exist.
+WITH target AS (
+    SELECT id FROM venues WHERE slug = @slug
+),
+followed AS (
+    INSERT INTO followed_venues (user_id, venue_id)
+    SELECT @user_id, id FROM target
+    ON CONFLICT DO NOTHING
+)
+SELECT id FROM target;
+
+-- name: UnfollowVenue :one
+-- Unfollow a venue by slug. Returns no row when the venue does not exist.
+WITH target AS (
+    SELECT id FROM venues WHERE slug = @slug
+),
+unfollowed AS (
+    DELETE FROM followed_venues fv
+    USING target
+    WHERE fv.user_id = @user_id
+      AND fv.venue_id = target.id
+)
+SELECT id FROM target;
+
+-- name: ListFollowedBands :many
+-- List the bands a user follows, by name
+SELECT
+    b.id,
+    b.name,
+    b.slug
+FROM followed_bands fb
+JOIN bands b ON b.id = fb.band_id
+WHERE fb.user_id = $1
+ORDER BY b.name ASC;
+
+-- name: ListFollowedVenues :many
+-- List the venues a user follows, by name
+SELECT
+    v.id,
+    v.name,
+    v.slug
+FROM followed_venues fv
+JOIN venues v ON v.id = fv.venue_id
+WHERE fv.user_id = $1
+ORDER BY v.name ASC;
+
+-- ============================================
+-- FEED
+-- ============================================
+
+-- name: ListUserFeed :many
+-- Upcoming scheduled shows the user saved, or featuring a band or at a venue
+-- they follow, soonest first. Flags say why each show is in the feed.
+WITH matches AS (
+    SELECT show_id FROM saved_shows WHERE user_id = @user_id
+    UNION
+    SELECT sb.show_id
+    FROM show_bands sb
+    JOIN followed_bands fb ON fb.band_id = sb.band_id
+    WHERE fb.user_id = @user_id
+    UNION
+    SELECT s.id
+    FROM shows s
+    JOIN followed_venues fv ON fv.venue_id = s.venue_id
+    WHERE fv.user_id = @user_id
+)
+SELECT
+    s.id,
+    s.title,
+    s.image_url,
+    s.date,
+    s.doors_time,
+    s.show_time,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    v.id AS venue_id,
+    v.name AS venue_name,
+    v.slug AS venue_slug,
+    v.region AS venue_region,
+    v.address AS venue_address,
+    v.image_url AS venue_image_url,
+    EXISTS(
+        SELECT 1 FROM saved_shows ss
+        WHERE ss.user_id = @user_id AND ss.show_id = s.id
+    )::boolean AS saved,
+    EXISTS(
+        SELECT 1 FROM followed_venues fv
+        WHERE fv.user_id = @user_id AND fv.venue_id = s.venue_id
+    )::boolean AS followed_venue,
+    ARRAY(
+        SELECT b.name
+        FROM show_bands sb
+        JOIN followed_bands fb ON fb.band_id = sb.band_id
+        JOIN bands b ON b.id = sb.band_id
+        WHERE fb.user_id = @user_id AND sb.show_id = s.id
+        ORDER BY sb.performance_order, b.name
+    )::text[] AS followed_bands,
+    COUNT(*) OVER() AS total_count
+FROM matches m
+JOIN shows s ON s.id = m.show_id
+JOIN venues v ON s.venue_id = v.id
+WHERE s.date >= NOW()
+  AND s.status = 'scheduled'
+ORDER BY s.date ASC, s.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
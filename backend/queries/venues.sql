@@ -119,3 +119,110 @@ LIMIT $2;
 -- name: VenueExists :one
 -- Check if venue exists by ID (for validation)
 SELECT EXISTS(SELECT 1 FROM venues WHERE id = $1);
+
+-- name: ListVenueUpcomingShows :many
+-- List upcoming shows for a venue with pagination
+SELECT
+    s.id,
+    s.title,
+    s.date,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status = 'scheduled'
+  AND s.date >= NOW()
+ORDER BY s.date ASC, s.id ASC
+LIMIT $2 OFFSET $3;
+
+-- name: ListVenuePastShows :many
+-- List past shows for a venue with pagination (most recent first)
+SELECT
+    s.id,
+    s.title,
+    s.date,
+    s.price_min,
+    s.price_max,
+    s.ticket_url,
+    s.age_restriction,
+    s.status,
+    COUNT(*) OVER() AS total_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date < NOW()
+ORDER BY s.date DESC, s.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetVenueShowSummary :one
+-- Get show totals and average ticket price for a venue (for venue stats)
+SELECT
+    COUNT(*) AS total_shows,
+    COUNT(*) FILTER (WHERE s.date >= NOW()) AS upcoming_shows,
+    COUNT(*) FILTER (WHERE s.date < NOW()) AS past_shows,
+    AVG(s.price_min)::numeric AS avg_price_min,
+    AVG(s.price_max)::numeric AS avg_price_max
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed');
+
+-- name: GetVenueShowsPerMonth :many
+-- Count shows per calendar month (venue-local time) since the given date
+SELECT
+    TO_CHAR(DATE_TRUNC('month', s.date AT TIME ZONE 'America/New_York'), 'YYYY-MM') AS month,
+    COUNT(*) AS show_count
+FROM shows s
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+  AND s.date >= $2
+GROUP BY month
+ORDER BY month ASC;
+
+-- name: GetVenueTopBands :many
+-- Get the bands that have played a venue most often
+SELECT
+    b.id,
+    b.name,
+    b.slug,
+    COUNT(*) AS show_count
+FROM show_bands sb
+JOIN shows s ON sb.show_id = s.id
+JOIN bands b ON sb.band_id = b.id
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+GROUP BY b.id, b.name, b.slug
+ORDER BY show_count DESC, b.name ASC
+LIMIT $2;
+
+-- name: GetVenueGenreMix :many
+-- Count distinct shows per genre at a venue
+SELECT
+    g.id,
+    g.name,
+    g.slug,
+    COUNT(DISTINCT s.id) AS show_count
+FROM shows s
+JOIN show_bands sb ON s.id = sb.show_id
+JOIN band_genres bg ON sb.band_id = bg.band_id
+JOIN genres g ON bg.genre_id = g.id
+WHERE s.venue_id = $1
+  AND s.status IN ('scheduled', 'completed')
+GROUP BY g.id, g.name, g.slug
+ORDER BY show_count DESC, g.name ASC;
+
+-- name: GetVenuesLastModified :one
+-- Newest updated_at across venues, their shows and the bands on them (for the
+-- Last-Modified header). NULL when no venues are given.
+SELECT GREATEST(
+    (SELECT MAX(v.updated_at) FROM venues v WHERE v.id = ANY(@ids::int[])),
+    (SELECT MAX(s.updated_at) FROM shows s WHERE s.venue_id = ANY(@ids::int[])),
+    (SELECT MAX(b.updated_at)
+     FROM bands b
+     JOIN show_bands sb ON sb.band_id = b.id
+     JOIN shows s ON s.id = sb.show_id
+     WHERE s.venue_id = ANY(@ids::int[]))
+)::timestamptz AS last_modified;
@@ -0,0 +1,239 @@
+-- ============================================
+-- WEBHOOK QUERIES
+-- ============================================
+
+-- name: CreateShowWebhookEvent :execrows
+-- Record a show event with a snapshot of the show and queue a delivery to
+-- every active subscription to the event type. Nothing is stored when no one
+-- subscribes. Returns the number of deliveries queued.
+WITH subscribers AS (
+    SELECT id
+    FROM webhook_subscriptions
+    WHERE active
+      AND @event_type::text = ANY(events)
+),
+event AS (
+    INSERT INTO webhook_events (event_type, payload)
+    SELECT
+        @event_type::text,
+        jsonb_build_object(
+            'id', s.id,
+            'title', s.title,
+            'description', s.description,
+            'image_url', s.image_url,
+            'date', s.date,
+            'doors_time', s.doors_time,
+            'show_time', s.show_time,
+            'price_min', s.price_min,
+            'price_max', s.price_max,
+            'ticket_url', s.ticket_url,
+            'age_restriction', s.age_restriction,
+            'status', s.status,
+            'source', s.source,
+            'rescheduled_to', s.rescheduled_to,
+            'created_at', s.created_at,
+            'updated_at', s.updated_at,
+            'venue', jsonb_build_object(
+                'id', v.id,
+                'name', v.name,
+                'slug', v.slug,
+                'region', v.region
+            ),
+            'bands', COALESCE((
+                SELECT jsonb_agg(jsonb_build_object(
+                    'id', b.id,
+                    'name', b.name,
+                    'slug', b.slug,
+                    'is_headliner', COALESCE(sb.is_headliner, FALSE)
+                ) ORDER BY sb.performance_order DESC NULLS LAST, b.name)
+                FROM show_bands sb
+                JOIN bands b ON b.id = sb.band_id
+                WHERE sb.show_id = s.id
+            ), '[]'::jsonb)
+        )
+    FROM shows s
+    JOIN venues v ON v.id = s.venue_id
+    WHERE s.id = @show_id
+      AND EXISTS (SELECT 1 FROM subscribers)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (subscription_id, event_id)
+SELECT sub.id, e.id
+FROM subscribers sub
+CROSS JOIN event e;
+
+-- name: CreateBandWebhookEvent :execrows
+-- Record a band event with a snapshot of the band and queue deliveries, like
+-- CreateShowWebhookEvent
+WITH subscribers AS (
+    SELECT id
+    FROM webhook_subscriptions
+    WHERE active
+      AND @event_type::text = ANY(events)
+),
+event AS (
+    INSERT INTO webhook_events (event_type, payload)
+    SELECT
+        @event_type::text,
+        jsonb_build_object(
+            'id', b.id,
+            'name', b.name,
+            'slug', b.slug,
+            'bio', b.bio,
+            'hometown', b.hometown,
+            'image_url', b.image_url,
+            'website', b.website,
+            'created_at', b.created_at
+        )
+    FROM bands b
+    WHERE b.id = @band_id
+      AND EXISTS (SELECT 1 FROM subscribers)
+    RETURNING id
+)
+INSERT INTO webhook_deliveries (subscription_id, event_id)
+SELECT sub.id, e.id
+FROM subscribers sub
+CROSS JOIN event e;
+
+-- ============================================
+-- DELIVERY
+-- ============================================
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Lease pending deliveries that are due so concurrent workers (API and
+-- scraper) skip them. An unfinished lease expires after five minutes and the
+-- delivery is picked up again.
+WITH due AS (
+    SELECT d.id
+    FROM webhook_deliveries d
+    JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+    WHERE d.status = 'pending'
+      AND d.next_attempt_at <= NOW()
+      AND ws.active
+    ORDER BY d.next_attempt_at, d.id
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = NOW() + INTERVAL '5 minutes'
+FROM due, webhook_subscriptions ws, webhook_events e
+WHERE d.id = due.id
+  AND ws.id = d.subscription_id
+  AND e.id = d.event_id
+RETURNING
+    d.id,
+    d.attempt_count,
+    ws.url,
+    ws.secret,
+    e.id AS event_id,
+    e.event_type,
+    e.payload,
+    e.created_at AS event_created_at;
+
+-- name: RecordWebhookDeliverySuccess :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded',
+    attempt_count = attempt_count + 1,
+    last_attempt_at = NOW(),
+    delivered_at = NOW(),
+    response_status = @response_status,
+    last_error = NULL
+WHERE id = @id;
+
+-- name: RecordWebhookDeliveryFailure :exec
+-- Record a failed attempt and schedule the next one, or give up
+UPDATE webhook_deliveries
+SET status = CASE WHEN @give_up::bool THEN 'failed' ELSE 'pending' END,
+    attempt_count = attempt_count + 1,
+    last_attempt_at = NOW(),
+    next_attempt_at = @next_attempt_at,
+    response_status = sqlc.narg('response_status'),
+    last_error = @last_error
+WHERE id = @id;
+
+-- name: DeleteOldWebhookEvents :one
+-- Drop events (and their delivery log) older than the retention window once
+-- nothing is left to deliver
+WITH deleted AS (
+    DELETE FROM webhook_events e
+    WHERE e.created_at < @before
+      AND NOT EXISTS (
+          SELECT 1 FROM webhook_deliveries d
+          WHERE d.event_id = e.id
+            AND d.status = 'pending'
+      )
+    RETURNING 1
+)
+SELECT COUNT(*)::bigint AS deleted FROM deleted;
+
+-- ============================================
+-- SUBSCRIPTION MANAGEMENT
+-- ============================================
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (url, description, events, secret)
+VALUES (@url, @description, @events::text[], @secret)
+RETURNING id, url, description, events, active, created_at, updated_at;
+
+-- name: ListWebhookSubscriptions :many
+-- All subscriptions with their delivery backlog
+SELECT
+    ws.id,
+    ws.url,
+    ws.description,
+    ws.events,
+    ws.active,
+    ws.created_at,
+    ws.updated_at,
+    (SELECT COUNT(*) FROM webhook_deliveries d WHERE d.subscription_id = ws.id AND d.status = 'pending')::int AS pending_count,
+    (SELECT COUNT(*) FROM webhook_deliveries d WHERE d.subscription_id = ws.id AND d.status = 'failed')::int AS failed_count,
+    (SELECT MAX(d.delivered_at) FROM webhook_deliveries d WHERE d.subscription_id = ws.id)::timestamptz AS last_delivered_at
+FROM webhook_subscriptions ws
+ORDER BY ws.id;
+
+-- name: UpdateWebhookSubscription :one
+-- Change a subscription; omitted fields keep their value
+UPDATE webhook_subscriptions
+SET url = COALESCE(sqlc.narg('url'), url),
+    description = COALESCE(sqlc.narg('description'), description),
+    events = COALESCE(sqlc.narg('events')::text[], events),
+    active = COALESCE(sqlc.narg('active'), active),
+    updated_at = NOW()
+WHERE id = @id
+RETURNING id, url, description, events, active, created_at, updated_at;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+-- The delivery log, newest first, optionally filtered by subscription and status
+SELECT
+    d.id,
+    d.subscription_id,
+    d.event_id,
+    e.event_type,
+    d.status,
+    d.attempt_count,
+    d.next_attempt_at,
+    d.last_attempt_at,
+    d.response_status,
+    d.last_error,
+    d.replay_of,
+    d.created_at,
+    d.delivered_at,
+    COUNT(*) OVER() AS total_count
+FROM webhook_deliveries d
+JOIN webhook_events e ON e.id = d.event_id
+WHERE (sqlc.narg('subscription_id')::int IS NULL OR d.subscription_id = sqlc.narg('subscription_id')::int)
+  AND (sqlc.narg('status')::text IS NULL OR d.status = sqlc.narg('status')::text)
+ORDER BY d.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReplayWebhookDelivery :one
+-- Queue a fresh delivery of the same event to the same subscription
+INSERT INTO webhook_deliveries AS nd (subscription_id, event_id, replay_of)
+SELECT src.subscription_id, src.event_id, src.id
+FROM webhook_deliveries src
+WHERE src.id = @id
+RETURNING nd.id, nd.subscription_id, nd.event_id, nd.status, nd.attempt_count, nd.next_attempt_at, nd.created_at;